@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+
+	pluginsCommon "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
+	appsConfigDocs "github.com/jfrog/jfrog-cli-security/cli/docs/appsconfig"
+	"github.com/jfrog/jfrog-cli-security/commands/appsconfig"
+	"github.com/jfrog/jfrog-cli-security/jas"
+)
+
+func getAppsConfigNameSpaceCommands() []components.Command {
+	return []components.Command{
+		{
+			Name:        "init",
+			Description: appsConfigDocs.GetInitDescription(),
+			Action:      AppsConfigInitCmd,
+		},
+		{
+			Name:        "validate",
+			Description: appsConfigDocs.GetValidateDescription(),
+			Arguments:   appsConfigDocs.GetValidateArguments(),
+			Action:      AppsConfigValidateCmd,
+		},
+	}
+}
+
+func AppsConfigInitCmd(c *components.Context) error {
+	if len(c.Arguments) != 0 {
+		return pluginsCommon.WrongNumberOfArgumentsHandler(c)
+	}
+	return appsconfig.Init()
+}
+
+func AppsConfigValidateCmd(c *components.Context) error {
+	if len(c.Arguments) > 1 {
+		return pluginsCommon.WrongNumberOfArgumentsHandler(c)
+	}
+	configPath := jas.JFrogAppsConfigPath
+	if len(c.Arguments) == 1 {
+		configPath = c.Arguments[0]
+	}
+	result, err := appsconfig.Validate(configPath)
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		log.Warn(warning)
+	}
+	for _, validationError := range result.Errors {
+		log.Error(validationError)
+	}
+	if !result.IsValid() {
+		return errorutils.CheckErrorf("found %d errors in %s", len(result.Errors), configPath)
+	}
+	log.Info(fmt.Sprintf("%s is valid", configPath))
+	return nil
+}
@@ -18,6 +18,18 @@ func GetJfrogCliSecurityApp() components.App {
 		Commands:    getXrayNameSpaceCommands(),
 		Category:    "Command Namespaces",
 	})
+	app.Subcommands = append(app.Subcommands, components.Namespace{
+		Name:        "security",
+		Description: "Security tools commands.",
+		Commands:    getSecurityNameSpaceCommands(),
+		Category:    "Command Namespaces",
+	})
+	app.Subcommands = append(app.Subcommands, components.Namespace{
+		Name:        "apps-config",
+		Description: "JFrog apps config commands.",
+		Commands:    getAppsConfigNameSpaceCommands(),
+		Category:    "Command Namespaces",
+	})
 	app.Subcommands = append(app.Subcommands, components.Namespace{
 		Name:        "git",
 		Description: "Git commands.",
@@ -0,0 +1,15 @@
+package appsconfig
+
+import "github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+
+func GetInitDescription() string {
+	return "Create a JFrog apps config file (.jfrog/jfrog-apps-config.yml) with the modules that are detected in the current directory."
+}
+
+func GetValidateDescription() string {
+	return "Validate a JFrog apps config file and report schema errors, unknown scanners, missing source roots and exclude patterns that don't match any file."
+}
+
+func GetValidateArguments() []components.Argument {
+	return []components.Argument{{Name: "config path", Optional: true, Description: "The path of the JFrog apps config file. Default: .jfrog/jfrog-apps-config.yml"}}
+}
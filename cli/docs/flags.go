@@ -3,6 +3,7 @@ package docs
 import (
 	"fmt"
 	"github.com/jfrog/jfrog-cli-security/commands/git"
+	toolscmd "github.com/jfrog/jfrog-cli-security/commands/tools"
 	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/cliutils"
@@ -23,6 +24,7 @@ const (
 	CurationAudit        = "curation-audit"
 	GitCountContributors = "count-contributors"
 	Enrich               = "sbom-enrich"
+	Tools                = "tools"
 
 	// TODO: Deprecated commands (remove at next CLI major version)
 	AuditMvn    = "audit-maven"
@@ -44,6 +46,8 @@ const (
 	Pip    = "pip"
 	Pipenv = "pipenv"
 	Poetry = "poetry"
+	Uv     = "uv"
+	Pdm    = "pdm"
 )
 
 const (
@@ -106,6 +110,13 @@ const (
 	buildPrefix         = "build-"
 	BuildVuln           = buildPrefix + Vuln
 	ScanVuln            = scanPrefix + Vuln
+	NoCache             = "no-cache"
+	Dockerfile          = "dockerfile"
+	SuggestBaseImages   = "suggest-base-images"
+	BaseImagesRepo      = "base-images-repo"
+	AllowedLicenses     = "allowed-licenses"
+	DeniedLicenses      = "denied-licenses"
+	LicensePolicy       = "license-policy"
 
 	// Unique audit flags
 	auditPrefix                  = "audit-"
@@ -116,6 +127,26 @@ const (
 	ThirdPartyContextualAnalysis = "third-party-contextual-analysis"
 	RequirementsFile             = "requirements-file"
 	WorkingDirs                  = "working-dirs"
+	DepTreeCache                 = "dep-tree-cache"
+	Scope                        = "scope"
+	ExportGraph                  = "export-graph"
+	GraphFormat                  = "graph-format"
+	GoReachability               = "go-reachability"
+	VulnerableSymbols            = "vulnerable-symbols"
+	GitHistory                   = "git-history"
+	GitHistoryRange              = "git-history-range"
+	ChangedSince                 = "changed-since"
+	SastCodeFlows                = "sast-code-flows"
+	JasTimeout                   = "jas-timeout"
+	JasMemoryLimit               = "jas-memory-limit"
+	ConfigProfileFile            = "config-profile-file"
+	CustomRules                  = "custom-rules"
+
+	// Unique tools flags
+	toolsPrefix = "tools-"
+	toolsFrom   = toolsPrefix + From
+	toolsTarget = toolsPrefix + Target
+	Sha256      = "sha256"
 
 	// Unique curation flags
 	CurationOutput = "curation-format"
@@ -138,6 +169,7 @@ var commandFlags = map[string][]string{
 	XrScan: {
 		url, user, password, accessToken, ServerId, SpecFlag, Threads, scanRecursive, scanRegexp, scanAnt,
 		Project, Watches, RepoPath, Licenses, OutputFormat, Fail, ExtendedTable, BypassArchiveLimits, MinSeverity, FixableOnly, ScanVuln,
+		NoCache, AllowedLicenses, DeniedLicenses, LicensePolicy,
 	},
 	Enrich: {
 		url, user, password, accessToken, ServerId, Threads,
@@ -147,16 +179,20 @@ var commandFlags = map[string][]string{
 	},
 	DockerScan: {
 		ServerId, Project, Watches, RepoPath, Licenses, OutputFormat, Fail, ExtendedTable, BypassArchiveLimits, MinSeverity, FixableOnly, ScanVuln,
+		Dockerfile, SuggestBaseImages, BaseImagesRepo, AllowedLicenses, DeniedLicenses, LicensePolicy,
 	},
 	Audit: {
 		url, user, password, accessToken, ServerId, InsecureTls, Project, Watches, RepoPath, Licenses, OutputFormat, ExcludeTestDeps,
 		useWrapperAudit, DepType, RequirementsFile, Fail, ExtendedTable, WorkingDirs, ExclusionsAudit, Mvn, Gradle, Npm,
-		Pnpm, Yarn, Go, Nuget, Pip, Pipenv, Poetry, MinSeverity, FixableOnly, ThirdPartyContextualAnalysis, Threads,
-		Sca, Iac, Sast, Secrets, WithoutCA, ScanVuln,
+		Pnpm, Yarn, Go, Nuget, Pip, Pipenv, Poetry, Uv, Pdm, MinSeverity, FixableOnly, ThirdPartyContextualAnalysis, Threads,
+		Sca, Iac, Sast, Secrets, WithoutCA, ScanVuln, DepTreeCache, AllowedLicenses, DeniedLicenses, LicensePolicy, Scope, ExportGraph, GraphFormat, GoReachability, VulnerableSymbols, GitHistory, GitHistoryRange, ChangedSince, SastCodeFlows, JasTimeout, JasMemoryLimit, ConfigProfileFile, CustomRules,
 	},
 	CurationAudit: {
 		CurationOutput, WorkingDirs, Threads, RequirementsFile,
 	},
+	Tools: {
+		toolsFrom, Sha256, toolsTarget,
+	},
 	GitCountContributors: {
 		InputFile, ScmType, ScmApiUrl, Token, Owner, RepoName, Months, DetailedSummary,
 	},
@@ -215,19 +251,30 @@ var flagsMap = map[string]components.Flag{
 	Fail:                components.NewBoolFlag(Fail, fmt.Sprintf("When using one of the flags --%s, --%s or --%s and a 'Fail build' rule is matched, the command will return exit code 3. Set to false if you'd like to see violations with exit code 0.", Watches, Project, RepoPath), components.WithBoolDefaultValue(true)),
 	ExtendedTable:       components.NewBoolFlag(ExtendedTable, "Set to true if you'd like the table to include extended fields such as 'CVSS' & 'Xray Issue Id'. Ignored if provided 'format' is not 'table'."),
 	BypassArchiveLimits: components.NewBoolFlag(BypassArchiveLimits, "Set to true to bypass the indexer-app archive limits."),
+	NoCache:             components.NewBoolFlag(NoCache, "Set to true to scan all the files, without using the results of previous scans from the local scan cache."),
+	Dockerfile:          components.NewStringFlag(Dockerfile, "Path to the Dockerfile the image was built from. Used to attribute the findings to the base image or to the Dockerfile instruction that added them. If not provided, a Dockerfile in the current directory is used if exists."),
+	SuggestBaseImages:   components.NewBoolFlag(SuggestBaseImages, fmt.Sprintf("Set to true to scan newer tags of the base image and suggest the ones with fewer vulnerabilities. Requires the --%s option.", BaseImagesRepo)),
+	BaseImagesRepo:      components.NewStringFlag(BaseImagesRepo, fmt.Sprintf("The Artifactory Docker remote repository to fetch the base image tags from, when using the --%s option.", SuggestBaseImages)),
+	AllowedLicenses:     components.NewStringFlag(AllowedLicenses, "A comma-separated list of the allowed licenses (SPDX identifiers, a trailing '*' matches any suffix). Dependencies with other licenses are reported as license violations, without the need for Xray watches."),
+	DeniedLicenses:      components.NewStringFlag(DeniedLicenses, "A comma-separated list of the denied licenses (SPDX identifiers, a trailing '*' matches any suffix). Dependencies with these licenses are reported as license violations, without the need for Xray watches."),
+	LicensePolicy:       components.NewStringFlag(LicensePolicy, "Path to a license policy file (YAML or JSON) with the 'allowed-licenses', 'denied-licenses', 'scopes' and 'severity' of the policy. Combined with the --allowed-licenses and --denied-licenses options, if provided."),
 	MinSeverity:         components.NewStringFlag(MinSeverity, "Set the minimum severity of issues to display. The following values are accepted: Low, Medium, High or Critical."),
 	FixableOnly:         components.NewBoolFlag(FixableOnly, "Set to true if you wish to display issues that have a fixed version only."),
 	Rescan:              components.NewBoolFlag(Rescan, "Set to true when scanning an already successfully scanned build, for example after adding an ignore rule."),
 	BuildVuln:           components.NewBoolFlag(Vuln, "Set to true if you'd like to receive an additional view of all vulnerabilities, regardless of the policy configured in Xray. Ignored if provided 'format' is 'sarif'."),
 	ScanVuln:            components.NewBoolFlag(Vuln, "Set to true if you'd like to receive an additional view of all vulnerabilities, regardless of the policy configured in Xray."),
 	InsecureTls:         components.NewBoolFlag(InsecureTls, "Set to true to skip TLS certificates verification."),
-	ExcludeTestDeps:     components.NewBoolFlag(ExcludeTestDeps, "[Gradle] Set to true if you'd like to exclude Gradle test dependencies from Xray scanning."),
+	ExcludeTestDeps:     components.NewBoolFlag(ExcludeTestDeps, "Set to true if you'd like to exclude test dependencies from Xray scanning. Same as --scope=runtime,dev."),
 	useWrapperAudit: components.NewBoolFlag(
 		UseWrapper,
 		"Set to false if you wish to not use the gradle or maven wrapper.",
 		components.WithBoolDefaultValue(true),
 	),
-	WorkingDirs: components.NewStringFlag(WorkingDirs, "A comma-separated list of relative working directories, to determine audit targets locations."),
+	DepTreeCache: components.NewBoolFlag(DepTreeCache, "Set to true to reuse the dependency trees of previous audits, as long as the project descriptors, lock files and package manager version haven't changed. Can also be enabled by setting the JFROG_CLI_DEP_TREE_CACHE environment variable to true."),
+	Scope:        components.NewStringFlag(Scope, "A comma-separated list of the dependency scopes to audit: runtime, dev, test or all. Dev dependencies include build-time and provided dependencies. Dependencies with an unknown scope are always audited.", components.WithStrDefaultValue("all")),
+	ExportGraph:  components.NewStringFlag(ExportGraph, "Path to a file to export the resolved dependency graph of each technology and module to, with the node types and classifiers. If vulnerabilities were found, the nodes are annotated with their count and highest severity."),
+	GraphFormat:  components.NewStringFlag(GraphFormat, "The format of the exported dependency graph. Acceptable values: json, dot and graphml.", components.WithStrDefaultValue("json")),
+	WorkingDirs:  components.NewStringFlag(WorkingDirs, "A comma-separated list of relative working directories, to determine audit targets locations."),
 	ExclusionsAudit: components.NewStringFlag(
 		Exclusions,
 		"List of exclusions separated by semicolons, utilized to skip sub-projects from undergoing an audit. These exclusions may incorporate the * and ? wildcards.",
@@ -242,6 +289,8 @@ var flagsMap = map[string]components.Flag{
 	Pip:     components.NewBoolFlag(Pip, "Set to true to request audit for a Pip project."),
 	Pipenv:  components.NewBoolFlag(Pipenv, "Set to true to request audit for a Pipenv project."),
 	Poetry:  components.NewBoolFlag(Poetry, "Set to true to request audit for a Poetry project."),
+	Uv:      components.NewBoolFlag(Uv, "Set to true to request audit for a uv project."),
+	Pdm:     components.NewBoolFlag(Pdm, "Set to true to request audit for a PDM project."),
 	Go:      components.NewBoolFlag(Go, "Set to true to request audit for a Go project."),
 	DepType: components.NewStringFlag(DepType, "[npm] Defines npm dependencies type. Possible values are: all, devOnly and prodOnly."),
 	ThirdPartyContextualAnalysis: components.NewBoolFlag(
@@ -249,6 +298,47 @@ var flagsMap = map[string]components.Flag{
 		"[npm] when set, the Contextual Analysis scan also uses the code of the project dependencies to determine the applicability of the vulnerability.",
 		components.SetHiddenBoolFlag(),
 	),
+	GoReachability: components.NewBoolFlag(
+		GoReachability,
+		"[Go] Set to true to determine whether the vulnerable functions of the Go dependencies are reachable from the project code, using the call graph of the project. The results are reported as Contextual Analysis results, and don't require the Advanced Security entitlement.",
+	),
+	VulnerableSymbols: components.NewStringFlag(
+		VulnerableSymbols,
+		fmt.Sprintf("[Go] Path to a JSON file with OSV entries of the Go vulnerability database, which list the vulnerable symbols used by --%s in addition to the symbols provided by Xray.", GoReachability),
+	),
+	GitHistory: components.NewBoolFlag(
+		GitHistory,
+		fmt.Sprintf("Set to true to scan the lines that were added by all the commits of the Git history for secrets, in addition to the current source code. Requires the secrets scan, and can be combined with --%s.", Secrets),
+	),
+	GitHistoryRange: components.NewStringFlag(
+		GitHistoryRange,
+		fmt.Sprintf("Scan the lines that were added by the commits of a revision range of the Git history for secrets, for example --%s=origin/main..HEAD. Implies --%s.", GitHistoryRange, GitHistory),
+		components.WithHelpValue("rev-range"),
+	),
+	ChangedSince: components.NewStringFlag(
+		ChangedSince,
+		fmt.Sprintf("A Git reference, such as the target branch of a pull request. When set, the SAST, IaC and Secrets scanners only scan the directories of the files that were changed since the merge base with the reference, and only report the findings in the changed lines. Use --%s to also report the SAST findings whose code flow passes through the changed lines.", SastCodeFlows),
+	),
+	SastCodeFlows: components.NewBoolFlag(
+		SastCodeFlows,
+		fmt.Sprintf("Set to true to scan the whole module with SAST when --%s is set, and also report the findings whose code flow passes through the changed lines, even if their source or sink is in an unchanged file. The scan takes as long as a full scan.", ChangedSince),
+	),
+	JasTimeout: components.NewStringFlag(
+		JasTimeout,
+		"The timeout of each Advanced Security scan, for example '30m' or '1h30m'. A scan that exceeds the timeout is stopped and reported as an error, without failing the other scans. The timeout can be set per module with 'jas_timeout' in jfrog-apps-config.yml.",
+	),
+	JasMemoryLimit: components.NewStringFlag(
+		JasMemoryLimit,
+		"[Linux] The maximal memory of each Advanced Security scan, in megabytes.",
+	),
+	ConfigProfileFile: components.NewStringFlag(
+		ConfigProfileFile,
+		"Path to a JSON or YAML file with a config profile, in the schema of the JFrog Xray config profiles. The profile determines the modules to scan and the scanners of each module, as if it was fetched from the server.",
+	),
+	CustomRules: components.NewStringFlag(
+		CustomRules,
+		"List of directories of custom SAST and Secrets rules, separated by commas. The rules are defined in YAML files in the directories, and are used in addition to the rules in .jfrog/custom-rules and in the 'custom_rules' directories of the modules in jfrog-apps-config.yml.",
+	),
 	RequirementsFile: components.NewStringFlag(RequirementsFile, "[Pip] Defines pip requirements file name. For example: 'requirements.txt'."),
 	CurationOutput:   components.NewStringFlag(OutputFormat, "Defines the output format of the command. Acceptable values are: table, json.", components.WithStrDefaultValue("table")),
 	Sca:              components.NewBoolFlag(Sca, fmt.Sprintf("Selective scanners mode: Execute SCA (Software Composition Analysis) sub-scan. By default, runs both SCA and Contextual Analysis. Can be combined with --%s, --%s, --%s, and --%s.", Secrets, Sast, Iac, WithoutCA)),
@@ -257,6 +347,14 @@ var flagsMap = map[string]components.Flag{
 	Secrets:          components.NewBoolFlag(Secrets, fmt.Sprintf("Selective scanners mode: Execute Secrets sub-scan. Can be combined with --%s, --%s and --%s.", Sca, Sast, Iac)),
 	WithoutCA:        components.NewBoolFlag(WithoutCA, fmt.Sprintf("Selective scanners mode: Disable Contextual Analysis scanner after SCA. Relevant only with --%s flag.", Sca)),
 
+	// Tools flags
+	toolsFrom: components.NewStringFlag(From, "[install] Path to the tools bundle zip to install."),
+	Sha256: components.NewStringFlag(
+		Sha256,
+		fmt.Sprintf("[install] [Mandatory] The expected SHA-256 checksum of the tools bundle, as printed by the export and written to the %s file next to the bundle.", toolscmd.ChecksumFileSuffix),
+	),
+	toolsTarget: components.NewStringFlag(Target, "[export] Path of the tools bundle zip to export to.", components.WithStrDefaultValue("jfrog-security-tools.zip")),
+
 	// Git flags
 	InputFile:       components.NewStringFlag(InputFile, "Path to an input file in YAML format contains multiple git providers. With this option, all other scm flags will be ignored and only git servers mentioned in the file will be examined.."),
 	ScmType:         components.NewStringFlag(ScmType, fmt.Sprintf("SCM type. Possible values are: %s.", git.NewScmType().GetValidScmTypeString())),
@@ -1,5 +1,7 @@
 package audit
 
+var Usage = []string{"audit why <package name>[@<version>]"}
+
 func GetDescription() string {
-	return "Audit your local project's dependencies by generating a dependency tree and scanning it with Xray."
+	return "Audit your local project's dependencies by generating a dependency tree and scanning it with Xray. Use 'audit why <package name>[@<version>]' to show how a package enters the project, without scanning it."
 }
@@ -2,17 +2,18 @@ package dockerscan
 
 import "github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 
-var Usage = []string{"docker scan <image tag>"}
+var Usage = []string{"docker scan <image tag>", "docker scan <image archive or OCI layout path>"}
 
 func GetDescription() string {
-	return "Scan local docker image using the docker client and Xray."
+	return "Scan a docker image with Xray. The image can be taken from the local Docker daemon, from podman, from an image archive or from an OCI image layout directory."
 }
 
 func GetArguments() []components.Argument {
 	return []components.Argument{
 		{
-			Name:        "image tag",
-			Description: "The docker image tag to scan.",
+			Name: "image tag",
+			Description: "The docker image tag to scan. To scan without a Docker daemon, provide one of: " +
+				"'podman:<image tag>', 'docker-archive:<path>', 'oci-archive:<path>', 'oci:<path>[:<tag>]' or a path to an existing image archive or OCI image layout directory.",
 		},
 	}
 }
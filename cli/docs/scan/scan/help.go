@@ -5,8 +5,10 @@ import (
 	"github.com/jfrog/jfrog-cli-security/cli/docs"
 )
 
+var Usage = []string{"scan cache prune"}
+
 func GetDescription() string {
-	return "Scan files located on the local file-system with Xray."
+	return "Scan files located on the local file-system with Xray. Use 'scan cache prune' to remove the expired entries of the local scan results cache."
 }
 
 func GetArguments() []components.Argument {
@@ -0,0 +1,11 @@
+package tools
+
+import "github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+
+func GetDescription() string {
+	return "Install or export the analyzer manager and the Xray indexer, to provision machines without access to the JFrog releases."
+}
+
+func GetArguments() []components.Argument {
+	return []components.Argument{{Name: "action", Description: "'install' to install the tools from a bundle zip, or 'export' to export the locally installed tools to a bundle zip."}}
+}
@@ -21,8 +21,11 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/urfave/cli"
+	"golang.org/x/exp/slices"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	flags "github.com/jfrog/jfrog-cli-security/cli/docs"
 	auditSpecificDocs "github.com/jfrog/jfrog-cli-security/cli/docs/auditspecific"
@@ -36,6 +39,8 @@ import (
 	"github.com/jfrog/jfrog-cli-security/commands/curation"
 	"github.com/jfrog/jfrog-cli-security/commands/scan"
 	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/graphutils"
+	"github.com/jfrog/jfrog-cli-security/utils/licenseutils"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xsc"
@@ -52,8 +57,11 @@ func getAuditAndScansCommands() []components.Command {
 			Flags:       flags.GetCommandFlags(flags.XrScan),
 			Description: scanDocs.GetDescription(),
 			Arguments:   scanDocs.GetArguments(),
-			Category:    securityCategory,
-			Action:      ScanCmd,
+			UsageOptions: &components.UsageOptions{
+				Usage: scanDocs.Usage,
+			},
+			Category: securityCategory,
+			Action:   ScanCmd,
 		},
 		{
 			Name:        "sbom-enrich",
@@ -91,8 +99,11 @@ func getAuditAndScansCommands() []components.Command {
 			Aliases:     []string{"aud"},
 			Flags:       flags.GetCommandFlags(flags.Audit),
 			Description: auditDocs.GetDescription(),
-			Category:    securityCategory,
-			Action:      AuditCmd,
+			UsageOptions: &components.UsageOptions{
+				Usage: auditDocs.Usage,
+			},
+			Category: securityCategory,
+			Action:   AuditCmd,
 		},
 		{
 			Name:        "curation-audit",
@@ -193,7 +204,15 @@ func EnrichCmd(c *components.Context) error {
 	return commandsCommon.Exec(EnrichCmd)
 }
 
+// 'jf scan cache prune' removes the expired entries of the local scan results cache, without scanning.
+func isScanCachePruneCmd(c *components.Context) bool {
+	return len(c.Arguments) == 2 && c.Arguments[0] == "cache" && c.Arguments[1] == "prune"
+}
+
 func ScanCmd(c *components.Context) error {
+	if isScanCachePruneCmd(c) {
+		return scan.PruneScanCache()
+	}
 	if len(c.Arguments) == 0 && !c.IsFlagSet(flags.SpecFlag) {
 		return pluginsCommon.PrintHelpAndReturnError("providing either a <source pattern> argument or the 'spec' option is mandatory", c)
 	}
@@ -231,6 +250,10 @@ func ScanCmd(c *components.Context) error {
 	if err != nil {
 		return err
 	}
+	licensePolicy, err := getLicensePolicy(c)
+	if err != nil {
+		return err
+	}
 	scanCmd := scan.NewScanCommand().
 		SetServerDetails(serverDetails).
 		SetThreads(threads).
@@ -243,7 +266,9 @@ func ScanCmd(c *components.Context) error {
 		SetPrintExtendedTable(c.GetBoolFlagValue(flags.ExtendedTable)).
 		SetBypassArchiveLimits(c.GetBoolFlagValue(flags.BypassArchiveLimits)).
 		SetFixableOnly(c.GetBoolFlagValue(flags.FixableOnly)).
-		SetMinSeverityFilter(minSeverity)
+		SetMinSeverityFilter(minSeverity).
+		SetNoCache(c.GetBoolFlagValue(flags.NoCache)).
+		SetLicensePolicy(licensePolicy)
 	if c.IsFlagSet(flags.Watches) {
 		scanCmd.SetWatches(splitByCommaAndTrim(c.GetStringFlagValue(flags.Watches)))
 	}
@@ -254,6 +279,29 @@ func createServerDetailsWithConfigOffer(c *components.Context) (*coreConfig.Serv
 	return pluginsCommon.CreateServerDetailsWithConfigOffer(c, true, cliutils.Xr)
 }
 
+// Returns the Artifactory details from the flags, or the configured server if the flags don't provide them.
+// Unlike createServerDetailsWithConfigOffer, never offers to configure a server, for the commands that can run without one.
+func createArtifactoryDetailsWithoutConfigOffer(c *components.Context) (*coreConfig.ServerDetails, error) {
+	details, err := pluginsCommon.CreateServerDetailsFromFlags(c)
+	if err != nil {
+		return nil, err
+	}
+	if details.Url != "" {
+		details.ArtifactoryUrl, details.Url = details.Url, ""
+	}
+	// Either the flags or the config are used, so the connection details are never mixed
+	if details.ArtifactoryUrl != "" || details.User != "" || details.Password != "" || details.AccessToken != "" {
+		return details, nil
+	}
+	configDetails, err := coreConfig.GetSpecificConfig(details.ServerId, true, true)
+	if err != nil {
+		return nil, err
+	}
+	// The TLS setting isn't saved in the config
+	configDetails.InsecureTls = details.InsecureTls
+	return configDetails, nil
+}
+
 func validateXrayContext(c *components.Context, serverDetails *coreConfig.ServerDetails) error {
 	if serverDetails.XrayUrl == "" {
 		return errorutils.CheckErrorf("JFrog Xray URL must be provided in order run this command. Use the 'jf c add' command to set the Xray server details.")
@@ -286,6 +334,44 @@ func getMinimumSeverity(c *components.Context) (severity severityutils.Severity,
 	return
 }
 
+// Create the local license policy from the policy file and the allowed/denied licenses options, if provided.
+func getLicensePolicy(c *components.Context) (*licenseutils.LicensePolicy, error) {
+	policy := &licenseutils.LicensePolicy{}
+	if policyFile := c.GetStringFlagValue(flags.LicensePolicy); policyFile != "" {
+		var err error
+		if policy, err = licenseutils.LoadLicensePolicy(policyFile); err != nil {
+			return nil, err
+		}
+	} else if c.GetStringFlagValue(flags.AllowedLicenses) == "" && c.GetStringFlagValue(flags.DeniedLicenses) == "" {
+		return nil, nil
+	}
+	if allowed := c.GetStringFlagValue(flags.AllowedLicenses); allowed != "" {
+		policy.Allowed = append(policy.Allowed, splitByCommaAndTrim(allowed)...)
+	}
+	if denied := c.GetStringFlagValue(flags.DeniedLicenses); denied != "" {
+		policy.Denied = append(policy.Denied, splitByCommaAndTrim(denied)...)
+	}
+	return policy, policy.Validate()
+}
+
+// Get the dependency scopes to audit from the scope option. Returns an empty list to audit all the dependencies.
+func getDependenciesScopes(c *components.Context) ([]string, error) {
+	scopesValue := c.GetStringFlagValue(flags.Scope)
+	if scopesValue == "" {
+		return nil, nil
+	}
+	scopes := splitByCommaAndTrim(scopesValue)
+	if slices.Contains(scopes, "all") {
+		return nil, nil
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(techutils.DependencyScopes, scope) {
+			return nil, pluginsCommon.PrintHelpAndReturnError(fmt.Sprintf("unsupported dependency scope '%s'. Supported scopes: %s, all", scope, strings.Join(techutils.DependencyScopes, ", ")), c)
+		}
+	}
+	return scopes, nil
+}
+
 func isProjectProvided(c *components.Context) bool {
 	return getProject(c) != ""
 }
@@ -368,27 +454,14 @@ func BuildScan(c *components.Context) error {
 }
 
 func AuditCmd(c *components.Context) error {
+	if isAuditWhyCmd(c) {
+		return AuditWhyCmd(c)
+	}
 	auditCmd, err := CreateAuditCmd(c)
 	if err != nil {
 		return err
 	}
-
-	// Check if user used specific technologies flags
-	allTechnologies := techutils.GetAllTechnologiesList()
-	technologies := []string{}
-	for _, tech := range allTechnologies {
-		var techExists bool
-		if tech == techutils.Maven {
-			// On Maven we use '--mvn' flag
-			techExists = c.GetBoolFlagValue(flags.Mvn)
-		} else {
-			techExists = c.GetBoolFlagValue(tech.String())
-		}
-		if techExists {
-			technologies = append(technologies, tech.String())
-		}
-	}
-	auditCmd.SetTechnologies(technologies)
+	auditCmd.SetTechnologies(getRequestedTechnologies(c))
 
 	if c.GetBoolFlagValue(flags.WithoutCA) && !c.GetBoolFlagValue(flags.Sca) {
 		// No CA flag provided but sca flag is not provided, error
@@ -405,6 +478,15 @@ func AuditCmd(c *components.Context) error {
 	if len(subScans) > 0 {
 		auditCmd.SetScansToPerform(subScans)
 	}
+	if c.GetBoolFlagValue(flags.SastCodeFlows) && c.GetStringFlagValue(flags.ChangedSince) == "" {
+		return pluginsCommon.PrintHelpAndReturnError(fmt.Sprintf("flag '--%s' cannot be used without '--%s'", flags.SastCodeFlows, flags.ChangedSince), c)
+	}
+	if gitHistory := getGitHistoryRevRange(c.GetBoolFlagValue(flags.GitHistory), c.GetStringFlagValue(flags.GitHistoryRange)); gitHistory != "" {
+		if len(subScans) > 0 && !slices.Contains(subScans, utils.SecretsScan) {
+			return pluginsCommon.PrintHelpAndReturnError(fmt.Sprintf("flag '--%s' requires the secrets scan, use it with '--%s'", flags.GitHistory, flags.Secrets), c)
+		}
+		auditCmd.SetGitHistory(gitHistory)
+	}
 
 	threads, err := pluginsCommon.GetThreadsCount(c)
 	if err != nil {
@@ -417,6 +499,66 @@ func AuditCmd(c *components.Context) error {
 	return err
 }
 
+// Check if user used specific technologies flags
+func getRequestedTechnologies(c *components.Context) []string {
+	technologies := []string{}
+	for _, tech := range techutils.GetAllTechnologiesList() {
+		var techExists bool
+		if tech == techutils.Maven {
+			// On Maven we use '--mvn' flag
+			techExists = c.GetBoolFlagValue(flags.Mvn)
+		} else {
+			techExists = c.GetBoolFlagValue(tech.String())
+		}
+		if techExists {
+			technologies = append(technologies, tech.String())
+		}
+	}
+	return technologies
+}
+
+// 'jf audit why <package>[@<version>]'
+func isAuditWhyCmd(c *components.Context) bool {
+	return len(c.Arguments) > 0 && c.Arguments[0] == "why"
+}
+
+// Explain how a package enters the project, by its paths in the dependency trees. Xray isn't required.
+func AuditWhyCmd(c *components.Context) error {
+	if len(c.Arguments) != 2 {
+		return pluginsCommon.PrintHelpAndReturnError("usage: audit why <package name>[@<version>]", c)
+	}
+	// The server details are used for resolving the dependencies from Artifactory only
+	serverDetails, err := createArtifactoryDetailsWithoutConfigOffer(c)
+	if err != nil {
+		return err
+	}
+	format, err := outputFormat.GetOutputFormat(c.GetStringFlagValue(flags.OutputFormat))
+	if err != nil {
+		return err
+	}
+	dependenciesScopes, err := getDependenciesScopes(c)
+	if err != nil {
+		return err
+	}
+	whyCmd := audit.NewAuditWhyCommand().
+		SetPackage(c.Arguments[1]).
+		SetOutputFormat(format)
+	if c.GetStringFlagValue(flags.WorkingDirs) != "" {
+		whyCmd.SetWorkingDirs(splitByCommaAndTrim(c.GetStringFlagValue(flags.WorkingDirs)))
+	}
+	whyCmd.SetServerDetails(serverDetails).
+		SetTechnologies(getRequestedTechnologies(c)).
+		SetExcludeTestDependencies(c.GetBoolFlagValue(flags.ExcludeTestDeps)).
+		SetUseWrapper(c.GetBoolFlagValue(flags.UseWrapper)).
+		SetInsecureTls(c.GetBoolFlagValue(flags.InsecureTls)).
+		SetNpmScope(c.GetStringFlagValue(flags.DepType)).
+		SetPipRequirementsFile(c.GetStringFlagValue(flags.RequirementsFile)).
+		SetExclusions(pluginsCommon.GetStringsArrFlagValue(c, flags.Exclusions)).
+		SetUseDepTreeCache(c.GetBoolFlagValue(flags.DepTreeCache) || audit.IsDepTreeCacheEnabledByEnv()).
+		SetDependenciesScopes(dependenciesScopes)
+	return progressbar.ExecWithProgress(whyCmd)
+}
+
 func shouldAddSubScan(subScan utils.SubScanType, c *components.Context) bool {
 	return c.GetBoolFlagValue(subScan.String()) ||
 		(subScan == utils.ContextualAnalysisScan && c.GetBoolFlagValue(flags.Sca) && !c.GetBoolFlagValue(flags.WithoutCA))
@@ -455,17 +597,49 @@ func CreateAuditCmd(c *components.Context) (*audit.AuditCommand, error) {
 	if err != nil {
 		return nil, err
 	}
+	licensePolicy, err := getLicensePolicy(c)
+	if err != nil {
+		return nil, err
+	}
+	auditCmd.SetLicensePolicy(licensePolicy)
+	dependenciesScopes, err := getDependenciesScopes(c)
+	if err != nil {
+		return nil, err
+	}
+	graphFormat, err := getGraphFormat(c)
+	if err != nil {
+		return nil, err
+	}
+	jasTimeout, jasMemoryLimit, err := getJasLimits(c)
+	if err != nil {
+		return nil, err
+	}
+	if configProfileFile := c.GetStringFlagValue(flags.ConfigProfileFile); configProfileFile != "" {
+		configProfile, err := xsc.LoadConfigProfileFile(configProfileFile)
+		if err != nil {
+			return nil, err
+		}
+		auditCmd.SetConfigProfile(configProfile)
+	}
 	auditCmd.SetAnalyticsMetricsService(xsc.NewAnalyticsMetricsService(serverDetails))
 
 	auditCmd.SetTargetRepoPath(addTrailingSlashToRepoPathIfNeeded(c)).
 		SetProject(getProject(c)).
+		SetExportGraphPath(c.GetStringFlagValue(flags.ExportGraph)).
+		SetGraphFormat(graphFormat).
 		SetIncludeVulnerabilities(c.GetBoolFlagValue(flags.Vuln) || shouldIncludeVulnerabilities(c)).
 		SetIncludeLicenses(c.GetBoolFlagValue(flags.Licenses)).
 		SetFail(c.GetBoolFlagValue(flags.Fail)).
 		SetPrintExtendedTable(c.GetBoolFlagValue(flags.ExtendedTable)).
 		SetMinSeverityFilter(minSeverity).
 		SetFixableOnly(c.GetBoolFlagValue(flags.FixableOnly)).
-		SetThirdPartyApplicabilityScan(c.GetBoolFlagValue(flags.ThirdPartyContextualAnalysis))
+		SetThirdPartyApplicabilityScan(c.GetBoolFlagValue(flags.ThirdPartyContextualAnalysis)).
+		SetGoReachability(c.GetBoolFlagValue(flags.GoReachability)).
+		SetVulnerableSymbolsFile(c.GetStringFlagValue(flags.VulnerableSymbols)).
+		SetChangedSince(c.GetStringFlagValue(flags.ChangedSince)).
+		SetSastCodeFlows(c.GetBoolFlagValue(flags.SastCodeFlows)).
+		SetJasTimeout(jasTimeout).
+		SetJasMemoryLimitMB(jasMemoryLimit)
 
 	if c.GetStringFlagValue(flags.Watches) != "" {
 		auditCmd.SetWatches(splitByCommaAndTrim(c.GetStringFlagValue(flags.Watches)))
@@ -474,6 +648,9 @@ func CreateAuditCmd(c *components.Context) (*audit.AuditCommand, error) {
 	if c.GetStringFlagValue(flags.WorkingDirs) != "" {
 		auditCmd.SetWorkingDirs(splitByCommaAndTrim(c.GetStringFlagValue(flags.WorkingDirs)))
 	}
+	if c.GetStringFlagValue(flags.CustomRules) != "" {
+		auditCmd.SetCustomRulesDirs(splitByCommaAndTrim(c.GetStringFlagValue(flags.CustomRules)))
+	}
 	auditCmd.SetServerDetails(serverDetails).
 		SetExcludeTestDependencies(c.GetBoolFlagValue(flags.ExcludeTestDeps)).
 		SetOutputFormat(format).
@@ -482,10 +659,37 @@ func CreateAuditCmd(c *components.Context) (*audit.AuditCommand, error) {
 		SetInsecureTls(c.GetBoolFlagValue(flags.InsecureTls)).
 		SetNpmScope(c.GetStringFlagValue(flags.DepType)).
 		SetPipRequirementsFile(c.GetStringFlagValue(flags.RequirementsFile)).
-		SetExclusions(pluginsCommon.GetStringsArrFlagValue(c, flags.Exclusions))
+		SetExclusions(pluginsCommon.GetStringsArrFlagValue(c, flags.Exclusions)).
+		SetUseDepTreeCache(c.GetBoolFlagValue(flags.DepTreeCache) || audit.IsDepTreeCacheEnabledByEnv()).
+		SetDependenciesScopes(dependenciesScopes)
 	return auditCmd, err
 }
 
+func getGraphFormat(c *components.Context) (graphutils.GraphFormat, error) {
+	graphFormat := strings.ToLower(strings.TrimSpace(c.GetStringFlagValue(flags.GraphFormat)))
+	if graphFormat == "" {
+		return graphutils.Json, nil
+	}
+	if !slices.Contains(graphutils.GraphFormats, graphFormat) {
+		return "", errorutils.CheckErrorf("invalid --%s value '%s'. Acceptable values: %s", flags.GraphFormat, graphFormat, strings.Join(graphutils.GraphFormats, ", "))
+	}
+	return graphutils.GraphFormat(graphFormat), nil
+}
+
+func getJasLimits(c *components.Context) (timeout time.Duration, memoryLimitMB uint64, err error) {
+	if value := c.GetStringFlagValue(flags.JasTimeout); value != "" {
+		if timeout, err = time.ParseDuration(value); err != nil || timeout <= 0 {
+			return 0, 0, errorutils.CheckErrorf("invalid --%s value '%s'. Expected a positive duration, such as '30m'", flags.JasTimeout, value)
+		}
+	}
+	if value := c.GetStringFlagValue(flags.JasMemoryLimit); value != "" {
+		if memoryLimitMB, err = strconv.ParseUint(value, 10, 64); err != nil || memoryLimitMB == 0 {
+			return 0, 0, errorutils.CheckErrorf("invalid --%s value '%s'. Expected a positive number of megabytes", flags.JasMemoryLimit, value)
+		}
+	}
+	return
+}
+
 func logNonGenericAuditCommandDeprecation(cmdName string) {
 	if cliutils.ShouldLogWarning() {
 		log.Warn(
@@ -690,6 +894,9 @@ func DockerScan(c *components.Context, image string) error {
 		return err
 	}
 	containerScanCommand.SetImageTag(image).
+		SetDockerfilePath(c.GetStringFlagValue(flags.Dockerfile)).
+		SetSuggestBaseImages(c.GetBoolFlagValue(flags.SuggestBaseImages)).
+		SetBaseImagesRepo(c.GetStringFlagValue(flags.BaseImagesRepo)).
 		SetTargetRepoPath(addTrailingSlashToRepoPathIfNeeded(c)).
 		SetServerDetails(serverDetails).
 		SetOutputFormat(format).
@@ -706,5 +913,25 @@ func DockerScan(c *components.Context, image string) error {
 	if c.GetStringFlagValue(flags.Watches) != "" {
 		containerScanCommand.SetWatches(splitByCommaAndTrim(c.GetStringFlagValue(flags.Watches)))
 	}
+	licensePolicy, err := getLicensePolicy(c)
+	if err != nil {
+		return err
+	}
+	containerScanCommand.SetLicensePolicy(licensePolicy)
 	return progressbar.ExecWithProgress(containerScanCommand)
 }
+
+// The Git history is scanned from HEAD when --git-history is used without a revision range
+const defaultGitHistoryRevRange = "HEAD"
+
+// Returns the revision range of the Git history to scan for secrets, or an empty string if the Git history isn't scanned.
+// A revision range (--git-history-range) implies --git-history.
+func getGitHistoryRevRange(gitHistory bool, revRange string) string {
+	if revRange != "" {
+		return revRange
+	}
+	if gitHistory {
+		return defaultGitHistoryRevRange
+	}
+	return ""
+}
@@ -151,3 +151,32 @@ func createCliConfig(t *testing.T, url string, configPath string) string {
 	assert.NoError(t, configCmd.Run())
 	return filepath.Join(configPath, "jfrog-cli.conf.v"+strconv.Itoa(coreutils.GetCliConfigVersion()))
 }
+
+func TestGetGitHistoryRevRange(t *testing.T) {
+	assert.Empty(t, getGitHistoryRevRange(false, ""))
+	assert.Equal(t, "HEAD", getGitHistoryRevRange(true, ""))
+	assert.Equal(t, "origin/main..HEAD", getGitHistoryRevRange(false, "origin/main..HEAD"))
+	assert.Equal(t, "origin/main..HEAD", getGitHistoryRevRange(true, "origin/main..HEAD"))
+}
+
+func TestIsScanCachePruneCmd(t *testing.T) {
+	assert.True(t, isScanCachePruneCmd(&components.Context{Arguments: []string{"cache", "prune"}}))
+	assert.False(t, isScanCachePruneCmd(&components.Context{Arguments: []string{"cache"}}))
+	assert.False(t, isScanCachePruneCmd(&components.Context{Arguments: []string{"cache", "prune", "*.jar"}}))
+	assert.False(t, isScanCachePruneCmd(&components.Context{Arguments: []string{"*.jar"}}))
+}
+
+func TestCreateArtifactoryDetailsWithoutConfigOffer(t *testing.T) {
+	homeDirCallback := clienttestutils.SetEnvWithCallbackAndAssert(t, coreutils.HomeDir, t.TempDir())
+	defer homeDirCallback()
+	// No server is configured, and none is offered to configure
+	serverDetails, err := createArtifactoryDetailsWithoutConfigOffer(&components.Context{})
+	assert.NoError(t, err)
+	assert.Empty(t, serverDetails.ArtifactoryUrl)
+
+	// The configured server is used
+	createCliConfig(t, "http://localhost:8081/artifactory/", "")
+	serverDetails, err = createArtifactoryDetailsWithoutConfigOffer(&components.Context{})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8081/artifactory/", serverDetails.ArtifactoryUrl)
+}
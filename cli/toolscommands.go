@@ -0,0 +1,41 @@
+package cli
+
+import (
+	pluginsCommon "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+
+	flags "github.com/jfrog/jfrog-cli-security/cli/docs"
+	toolsDocs "github.com/jfrog/jfrog-cli-security/cli/docs/tools"
+	"github.com/jfrog/jfrog-cli-security/commands/tools"
+)
+
+func getSecurityNameSpaceCommands() []components.Command {
+	return []components.Command{
+		{
+			Name:        "tools",
+			Flags:       flags.GetCommandFlags(flags.Tools),
+			Description: toolsDocs.GetDescription(),
+			Arguments:   toolsDocs.GetArguments(),
+			Action:      ToolsCmd,
+		},
+	}
+}
+
+func ToolsCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 {
+		return pluginsCommon.WrongNumberOfArgumentsHandler(c)
+	}
+	switch action := c.Arguments[0]; action {
+	case tools.InstallAction:
+		bundlePath := c.GetStringFlagValue(flags.From)
+		if bundlePath == "" {
+			return errorutils.CheckErrorf("the --%s option is mandatory", flags.From)
+		}
+		return tools.Install(bundlePath, c.GetStringFlagValue(flags.Sha256))
+	case tools.ExportAction:
+		return tools.Export(c.GetStringFlagValue(flags.Target))
+	default:
+		return errorutils.CheckErrorf("unsupported tools action '%s'. Possible values are: %s, %s", action, tools.InstallAction, tools.ExportAction)
+	}
+}
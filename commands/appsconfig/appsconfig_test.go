@@ -0,0 +1,137 @@
+package appsconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	clientTestUtils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestProject(t *testing.T) (cleanUp func()) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "api", "tests"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "api", "go.mod"), []byte("module api\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "web", "server"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "web", "package.json"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "web", "server", "package.json"), []byte("{}"), 0644))
+	return clientTestUtils.ChangeDirWithCallback(t, wd, tempDir)
+}
+
+func TestDetectModules(t *testing.T) {
+	cleanUp := createTestProject(t)
+	defer cleanUp()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	// A root project, a project nested in another project, and a project with the same directory name as another one
+	require.NoError(t, os.WriteFile("requirements.txt", []byte("pyyaml\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join("web", "server", "go.mod"), []byte("module server\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join("lib", "api"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("lib", "api", "go.mod"), []byte("module lib\n"), 0644))
+
+	modules, err := detectModules(wd)
+	require.NoError(t, err)
+	assert.Equal(t, []detectedModule{
+		{name: filepath.Base(wd), sourceRoot: ".", technologies: []string{"pip"}, nestedSourceRoots: []string{"api", "lib/api", "web"}},
+		{name: "api", sourceRoot: "api", technologies: []string{"go"}},
+		{name: "lib/api", sourceRoot: "lib/api", technologies: []string{"go"}},
+		{name: "web", sourceRoot: "web", technologies: []string{"npm"}, nestedSourceRoots: []string{"server"}},
+		{name: "server", sourceRoot: "web/server", technologies: []string{"go"}},
+	}, modules)
+}
+
+func TestInit(t *testing.T) {
+	cleanUp := createTestProject(t)
+	defer cleanUp()
+
+	require.NoError(t, Init())
+	config, err := jfrogappsconfig.LoadConfigIfExist()
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, jfrogAppsConfigVersion, config.Version)
+	require.Len(t, config.Modules, 2)
+	assert.Equal(t, "api", config.Modules[0].Name)
+	assert.Equal(t, "api", config.Modules[0].SourceRoot)
+	assert.Equal(t, "web", config.Modules[1].Name)
+	assert.Equal(t, "web", config.Modules[1].SourceRoot)
+	assert.NotEmpty(t, config.Modules[0].ExcludePatterns)
+
+	// The generated config is valid, and the default exclude patterns that don't match any file aren't reported
+	result, err := Validate(jas.JFrogAppsConfigPath)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid(), result.Errors)
+	assert.Empty(t, result.Warnings)
+
+	// An existing config isn't overridden
+	assert.ErrorContains(t, Init(), "already exists")
+}
+
+func TestValidate(t *testing.T) {
+	cleanUp := createTestProject(t)
+	defer cleanUp()
+	projectDir, err := os.Getwd()
+	require.NoError(t, err)
+	configPath := filepath.Join(projectDir, jas.JFrogAppsConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	// The paths are relative to the project of the config, not to the current directory
+	restoreDir := clientTestUtils.ChangeDirWithCallback(t, projectDir, t.TempDir())
+	defer restoreDir()
+
+	testCases := []struct {
+		name             string
+		content          string
+		expectedErrors   []string
+		expectedWarnings []string
+	}{
+		{
+			name:    "valid",
+			content: "version: \"1.0\"\nmodules:\n  - name: api\n    source_root: api\n    exclude_patterns: [\"**/tests/**\"]\n    jas_timeout: 30m\n",
+		},
+		{
+			name:           "unknown field",
+			content:        "version: \"1.0\"\nmodules:\n  - name: api\n    source_rot: api\n",
+			expectedErrors: []string{"Schema error: yaml: unmarshal errors:\n  line 4: field source_rot not found in type appsconfig.strictModule"},
+		},
+		{
+			name:           "missing version and modules",
+			content:        "modules: []\n",
+			expectedErrors: []string{"Schema error: 'version' is required", "Schema error: at least one module is required in 'modules'"},
+		},
+		{
+			name:    "invalid module",
+			content: "version: \"1.0\"\nmodules:\n  - name: api\n    source_root: api\n    exclude_scanners: [sca, sast]\n    jas_timeout: soon\n    iac_helm_values: [values-prod.yaml]\n    custom_rules: [rules]\n  - source_root: mobile\n",
+			expectedErrors: []string{
+				"Module 'api': unknown scanner 'sca' in 'exclude_scanners', the scanners are: applicability, secrets, iac, sast",
+				"Module 'api': invalid 'jas_timeout' 'soon', expected a positive duration such as '30m'",
+				"Module 'api': the Helm values file 'values-prod.yaml' in 'iac_helm_values' doesn't exist in the source root",
+				"Module 'api': the custom rules directory 'rules' in 'custom_rules' doesn't exist in the source root",
+				"Module '#2': the source root 'mobile' doesn't exist",
+			},
+		},
+		{
+			name:             "unmatched patterns",
+			content:          "version: \"1.0\"\nmodules:\n  - name: web\n    source_root: web\n    exclude_patterns: [\"**/server/**\", \"**/dist/**\"]\n    scanners:\n      iac:\n        working_dirs: [server, client]\n        exclude_patterns: [\"*.tf\"]\n",
+			expectedErrors:   []string{"Module 'web': the working directory 'client' of the iac scanner doesn't exist in the source root"},
+			expectedWarnings: []string{"Module 'web': the pattern '**/dist/**' in 'exclude_patterns' doesn't match any file", "Module 'web': the pattern '*.tf' in 'scanners.iac.exclude_patterns' doesn't match any file"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(configPath, []byte(testCase.content), 0644))
+			result, err := Validate(configPath)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, testCase.expectedErrors, result.Errors)
+			assert.ElementsMatch(t, testCase.expectedWarnings, result.Warnings)
+			assert.Equal(t, len(testCase.expectedErrors) == 0, result.IsValid())
+		})
+	}
+
+	_, err = Validate("missing.yml")
+	assert.ErrorContains(t, err, "failed to read the JFrog apps config")
+}
@@ -0,0 +1,199 @@
+package appsconfig
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const jfrogAppsConfigVersion = "1.0"
+
+// A module that was detected in the current directory
+type detectedModule struct {
+	name         string
+	sourceRoot   string
+	technologies []string
+	// The source roots of the modules that are nested in this module, excluded from its scans so each file is scanned by a single module
+	nestedSourceRoots []string
+}
+
+// Creates jfrog-apps-config.yml in the current directory, with a module for each directory that a project was detected in.
+func Init() (err error) {
+	exists, err := fileutils.IsFileExists(jas.JFrogAppsConfigPath, false)
+	if err != nil {
+		return
+	}
+	if exists {
+		return errorutils.CheckErrorf("%s already exists. Validate it with 'jf apps-config validate', or remove it to create it again", jas.JFrogAppsConfigPath)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	modules, err := detectModules(wd)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(jas.JFrogAppsConfigPath), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.WriteFile(jas.JFrogAppsConfigPath, []byte(generateConfig(modules)), 0644); err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Info(fmt.Sprintf("Created %s with %d modules. Review it, and validate it with 'jf apps-config validate' after editing it.", jas.JFrogAppsConfigPath, len(modules)))
+	return
+}
+
+func detectModules(wd string) (modules []detectedModule, err error) {
+	excludePattern := fspatterns.PrepareExcludePathPattern(utils.DefaultScaExcludePatterns, clientutils.WildCardPattern, true)
+	techToWorkingDirs, err := techutils.DetectTechnologiesDescriptors(wd, true, nil, nil, excludePattern)
+	if err != nil {
+		return
+	}
+	dirsTechnologies := map[string][]string{}
+	for tech, workingDirs := range techToWorkingDirs {
+		for workingDir := range workingDirs {
+			dirsTechnologies[workingDir] = append(dirsTechnologies[workingDir], tech.String())
+		}
+	}
+	if len(dirsTechnologies) == 0 {
+		dirsTechnologies[wd] = []string{}
+	}
+	for moduleDir, technologies := range dirsTechnologies {
+		sourceRoot, err := getSourceRoot(wd, moduleDir)
+		if err != nil {
+			return nil, err
+		}
+		module := detectedModule{sourceRoot: sourceRoot, technologies: technologies}
+		sort.Strings(module.technologies)
+		// Each nested project is a module of its own, and is excluded from the modules it is nested in
+		for other := range dirsTechnologies {
+			if other != moduleDir && jas.IsSubPath(moduleDir, other) && !hasModuleBetween(dirsTechnologies, moduleDir, other) {
+				nestedSourceRoot, err := filepath.Rel(moduleDir, other)
+				if err != nil {
+					return nil, errorutils.CheckError(err)
+				}
+				module.nestedSourceRoots = append(module.nestedSourceRoots, filepath.ToSlash(nestedSourceRoot))
+			}
+		}
+		sort.Strings(module.nestedSourceRoots)
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].sourceRoot < modules[j].sourceRoot
+	})
+	setModulesNames(modules, filepath.Base(wd))
+	return
+}
+
+func getSourceRoot(wd, moduleDir string) (string, error) {
+	sourceRoot, err := filepath.Rel(wd, moduleDir)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return filepath.ToSlash(sourceRoot), nil
+}
+
+// Returns true if another module's directory is nested in dir and contains path, so path is excluded from that module instead.
+func hasModuleBetween(modulesDirs map[string][]string, dir, path string) bool {
+	for other := range modulesDirs {
+		if other != dir && other != path && jas.IsSubPath(dir, other) && jas.IsSubPath(other, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Names the modules after their directories, or after their source roots if several directories have the same name.
+// The module of the current directory is named after it.
+func setModulesNames(modules []detectedModule, wdName string) {
+	namesCount := map[string]int{}
+	for i := range modules {
+		modules[i].name = wdName
+		if modules[i].sourceRoot != "." {
+			modules[i].name = path.Base(modules[i].sourceRoot)
+		}
+		namesCount[modules[i].name]++
+	}
+	for i := range modules {
+		if namesCount[modules[i].name] > 1 && modules[i].sourceRoot != "." {
+			modules[i].name = modules[i].sourceRoot
+		}
+	}
+}
+
+// Returns the content of jfrog-apps-config.yml for the modules, with comments that describe the optional settings.
+func generateConfig(modules []detectedModule) string {
+	var content strings.Builder
+	content.WriteString("# JFrog Applications Config, refines the Advanced Security scans of 'jf audit' for the modules of this repository.\n")
+	content.WriteString("# Schema: https://github.com/jfrog/jfrog-apps-config/blob/main/schema.json\n")
+	content.WriteString("# Created by 'jf apps-config init'. Validate it with 'jf apps-config validate' after editing it.\n")
+	content.WriteString(fmt.Sprintf("version: %q\n\nmodules:\n", jfrogAppsConfigVersion))
+	for i, module := range modules {
+		if i > 0 {
+			content.WriteString("\n")
+		}
+		if len(module.technologies) > 0 {
+			content.WriteString(fmt.Sprintf("  # Detected technologies: %s\n", strings.Join(module.technologies, ", ")))
+		}
+		content.WriteString(fmt.Sprintf("  - name: %q\n", module.name))
+		content.WriteString("    # The root directory of the module, relative to this repository\n")
+		content.WriteString(fmt.Sprintf("    source_root: %q\n", module.sourceRoot))
+		content.WriteString("    # Paths to exclude from all the scanners, relative to the source root\n")
+		content.WriteString("    exclude_patterns:\n")
+		for _, excludePattern := range utils.DefaultJasExcludePatterns {
+			content.WriteString(fmt.Sprintf("      - %q\n", excludePattern))
+		}
+		if len(module.nestedSourceRoots) > 0 {
+			content.WriteString("      # The nested modules, scanned separately\n")
+			for _, nestedSourceRoot := range module.nestedSourceRoots {
+				content.WriteString(fmt.Sprintf("      - %q\n", "**/"+nestedSourceRoot+"/**"))
+			}
+		}
+		content.WriteString(fmt.Sprintf("    # Scanners to skip in this module: %s\n", strings.Join(getScannersNames(), ", ")))
+		content.WriteString("    # exclude_scanners:\n")
+		content.WriteString("    #   - \"iac\"\n")
+		content.WriteString("    # Settings of specific scanners, with working directories relative to the source root\n")
+		content.WriteString("    # scanners:\n")
+		content.WriteString("    #   secrets:\n")
+		content.WriteString("    #     working_dirs:\n")
+		content.WriteString("    #       - \"src\"\n")
+		content.WriteString("    #   iac:\n")
+		content.WriteString("    #     exclude_patterns:\n")
+		content.WriteString("    #       - \"**/examples/**\"\n")
+		content.WriteString("    #   sast:\n")
+		content.WriteString("    #     language: \"java\"\n")
+		content.WriteString("    #     excluded_rules:\n")
+		content.WriteString("    #       - \"<rule id>\"\n")
+		content.WriteString("    # Values files to render the Helm charts of the module with before the IaC scan, relative to the source root\n")
+		content.WriteString("    # iac_helm_values:\n")
+		content.WriteString("    #   - \"charts/app/values-prod.yaml\"\n")
+		content.WriteString("    # Directories of custom SAST and Secrets rules to scan the module with, relative to the source root\n")
+		content.WriteString("    # custom_rules:\n")
+		content.WriteString("    #   - \"security/rules\"\n")
+	}
+	return content.String()
+}
+
+// Returns the names of the scanners that can be excluded from a module
+func getScannersNames() []string {
+	return []string{
+		strings.ToLower(jasutils.Applicability.String()),
+		strings.ToLower(jasutils.Secrets.String()),
+		strings.ToLower(jasutils.IaC.String()),
+		strings.ToLower(jasutils.Sast.String()),
+	}
+}
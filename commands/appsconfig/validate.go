@@ -0,0 +1,216 @@
+package appsconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// The JFrog apps config schema, with the settings that the CLI supports in addition to it.
+// The fields that aren't part of the schema are reported as errors.
+type strictJFrogAppsConfig struct {
+	Version *string        `yaml:"version"`
+	Modules []strictModule `yaml:"modules"`
+}
+
+type strictModule struct {
+	jfrogappsconfig.Module `yaml:",inline"`
+	JasTimeout             string   `yaml:"jas_timeout,omitempty"`
+	IacHelmValues          []string `yaml:"iac_helm_values,omitempty"`
+	CustomRules            []string `yaml:"custom_rules,omitempty"`
+}
+
+// The problems that were found in a JFrog apps config file.
+// Errors fail the scans or make them ignore the settings, warnings are settings that have no effect, such as exclude patterns that don't match any file.
+type ValidationResult struct {
+	Errors   []string
+	Warnings []string
+}
+
+func (vr *ValidationResult) IsValid() bool {
+	return len(vr.Errors) == 0
+}
+
+// Validates the JFrog apps config file, and returns the problems that were found in it.
+// The paths in the file are resolved like the scans resolve them, relative to the repository that contains the '.jfrog' directory of the file.
+// Returns an error if the file can't be read.
+func Validate(configPath string) (result *ValidationResult, err error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to read the JFrog apps config: %s", err.Error())
+	}
+	baseDir, err := getConfigBaseDir(configPath)
+	if err != nil {
+		return
+	}
+	result = &ValidationResult{}
+	config := strictJFrogAppsConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		result.Errors = append(result.Errors, fmt.Sprintf("Schema error: %s", err.Error()))
+		return result, nil
+	}
+	if config.Version == nil {
+		result.Errors = append(result.Errors, "Schema error: 'version' is required")
+	} else if *config.Version != jfrogAppsConfigVersion {
+		result.Errors = append(result.Errors, fmt.Sprintf("Unsupported version '%s', the supported version is '%s'", *config.Version, jfrogAppsConfigVersion))
+	}
+	if len(config.Modules) == 0 {
+		result.Errors = append(result.Errors, "Schema error: at least one module is required in 'modules'")
+	}
+	for i, module := range config.Modules {
+		moduleName := module.Name
+		if moduleName == "" {
+			moduleName = fmt.Sprintf("#%d", i+1)
+		}
+		moduleResult := validateModule(module, baseDir)
+		for _, moduleError := range moduleResult.Errors {
+			result.Errors = append(result.Errors, fmt.Sprintf("Module '%s': %s", moduleName, moduleError))
+		}
+		for _, moduleWarning := range moduleResult.Warnings {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Module '%s': %s", moduleName, moduleWarning))
+		}
+	}
+	return result, nil
+}
+
+// Returns the directory that the paths in the config file are relative to: the parent of the '.jfrog' directory of the file, or the directory of the file if it isn't in a '.jfrog' directory.
+func getConfigBaseDir(configPath string) (string, error) {
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	configDir := filepath.Dir(absConfigPath)
+	if filepath.Base(configDir) == filepath.Dir(jas.JFrogAppsConfigPath) {
+		return filepath.Dir(configDir), nil
+	}
+	return configDir, nil
+}
+
+func validateModule(module strictModule, baseDir string) (result ValidationResult) {
+	for _, scanner := range module.ExcludeScanners {
+		if !slices.Contains(getScannersNames(), scanner) {
+			result.Errors = append(result.Errors, fmt.Sprintf("unknown scanner '%s' in 'exclude_scanners', the scanners are: %s", scanner, strings.Join(getScannersNames(), ", ")))
+		}
+	}
+	if module.JasTimeout != "" {
+		if timeout, err := time.ParseDuration(module.JasTimeout); err != nil || timeout <= 0 {
+			result.Errors = append(result.Errors, fmt.Sprintf("invalid 'jas_timeout' '%s', expected a positive duration such as '30m'", module.JasTimeout))
+		}
+	}
+	sourceRoot := filepath.FromSlash(module.SourceRoot)
+	if !filepath.IsAbs(sourceRoot) {
+		sourceRoot = filepath.Join(baseDir, sourceRoot)
+	}
+	if info, err := os.Stat(sourceRoot); err != nil || !info.IsDir() {
+		result.Errors = append(result.Errors, fmt.Sprintf("the source root '%s' doesn't exist", module.SourceRoot))
+		return
+	}
+	for _, valuesFile := range module.IacHelmValues {
+		if info, err := os.Stat(filepath.Join(sourceRoot, filepath.FromSlash(valuesFile))); err != nil || info.IsDir() {
+			result.Errors = append(result.Errors, fmt.Sprintf("the Helm values file '%s' in 'iac_helm_values' doesn't exist in the source root", valuesFile))
+		}
+	}
+	for _, customRulesDir := range module.CustomRules {
+		dir := filepath.Join(sourceRoot, filepath.FromSlash(customRulesDir))
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			result.Errors = append(result.Errors, fmt.Sprintf("the custom rules directory '%s' in 'custom_rules' doesn't exist in the source root", customRulesDir))
+			continue
+		}
+		if _, err := customrules.LoadRules(dir); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+	result.add(validateExcludePatterns("exclude_patterns", module.ExcludePatterns, sourceRoot))
+	scanners := map[string]*jfrogappsconfig.Scanner{"secrets": module.Scanners.Secrets, "iac": module.Scanners.Iac}
+	if module.Scanners.Sast != nil {
+		scanners["sast"] = &module.Scanners.Sast.Scanner
+	}
+	for _, scannerName := range []string{"secrets", "iac", "sast"} {
+		scanner := scanners[scannerName]
+		if scanner == nil {
+			continue
+		}
+		roots := []string{sourceRoot}
+		if len(scanner.WorkingDirs) > 0 {
+			roots = []string{}
+			for _, workingDir := range scanner.WorkingDirs {
+				root := filepath.Join(sourceRoot, filepath.FromSlash(workingDir))
+				if info, err := os.Stat(root); err != nil || !info.IsDir() {
+					result.Errors = append(result.Errors, fmt.Sprintf("the working directory '%s' of the %s scanner doesn't exist in the source root", workingDir, scannerName))
+					continue
+				}
+				roots = append(roots, root)
+			}
+		}
+		result.add(validateExcludePatterns(fmt.Sprintf("scanners.%s.exclude_patterns", scannerName), scanner.ExcludePatterns, roots...))
+	}
+	return
+}
+
+func (vr *ValidationResult) add(other ValidationResult) {
+	vr.Errors = append(vr.Errors, other.Errors...)
+	vr.Warnings = append(vr.Warnings, other.Warnings...)
+}
+
+// Reports the invalid exclude patterns as errors, and the exclude patterns that don't match any file or directory in the roots as warnings.
+// The default exclude patterns, which 'jf apps-config init' adds to every module, aren't reported when they don't match.
+func validateExcludePatterns(field string, excludePatterns []string, roots ...string) (result ValidationResult) {
+	if len(excludePatterns) == 0 || len(roots) == 0 {
+		return
+	}
+	regexes := map[string]*regexp.Regexp{}
+	for _, excludePattern := range excludePatterns {
+		regex, err := regexp.Compile(clientutils.AntToRegex(filepath.FromSlash(excludePattern)))
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("invalid pattern '%s' in '%s': %s", excludePattern, field, err.Error()))
+			continue
+		}
+		regexes[excludePattern] = regex
+	}
+	matched := map[string]bool{}
+	for _, root := range roots {
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			relativePath, err := filepath.Rel(root, path)
+			if err != nil || relativePath == "." {
+				return nil
+			}
+			for excludePattern, regex := range regexes {
+				if !matched[excludePattern] && regex.MatchString(relativePath) {
+					matched[excludePattern] = true
+				}
+			}
+			// The walk can stop once all the patterns matched
+			if len(matched) == len(regexes) {
+				return filepath.SkipAll
+			}
+			return nil
+		})
+	}
+	for _, excludePattern := range excludePatterns {
+		if _, valid := regexes[excludePattern]; valid && !matched[excludePattern] && !slices.Contains(utils.DefaultJasExcludePatterns, excludePattern) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("the pattern '%s' in '%s' doesn't match any file", excludePattern, field))
+		}
+	}
+	return
+}
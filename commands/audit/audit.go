@@ -16,11 +16,14 @@ import (
 	"github.com/jfrog/jfrog-cli-security/jas"
 	"github.com/jfrog/jfrog-cli-security/utils"
 
+	"github.com/jfrog/jfrog-cli-security/utils/graphutils"
 	xrayutils "github.com/jfrog/jfrog-cli-security/utils/xray"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	xscservices "github.com/jfrog/jfrog-client-go/xsc/services"
+	"golang.org/x/exp/slices"
 )
 
 type AuditCommand struct {
@@ -33,6 +36,8 @@ type AuditCommand struct {
 	PrintExtendedTable      bool
 	analyticsMetricsService *xsc.AnalyticsMetricsService
 	Threads                 int
+	exportGraphPath         string
+	graphFormat             graphutils.GraphFormat
 	AuditParams
 }
 
@@ -85,6 +90,16 @@ func (auditCmd *AuditCommand) SetThreads(threads int) *AuditCommand {
 	return auditCmd
 }
 
+func (auditCmd *AuditCommand) SetExportGraphPath(exportGraphPath string) *AuditCommand {
+	auditCmd.exportGraphPath = exportGraphPath
+	return auditCmd
+}
+
+func (auditCmd *AuditCommand) SetGraphFormat(graphFormat graphutils.GraphFormat) *AuditCommand {
+	auditCmd.graphFormat = graphFormat
+	return auditCmd
+}
+
 func (auditCmd *AuditCommand) CreateCommonGraphScanParams() *scangraph.CommonGraphScanParams {
 	commonParams := &scangraph.CommonGraphScanParams{
 		RepoPath: auditCmd.targetRepoPath,
@@ -93,7 +108,8 @@ func (auditCmd *AuditCommand) CreateCommonGraphScanParams() *scangraph.CommonGra
 	}
 	commonParams.ProjectKey = auditCmd.projectKey
 	commonParams.IncludeVulnerabilities = auditCmd.IncludeVulnerabilities
-	commonParams.IncludeLicenses = auditCmd.IncludeLicenses
+	// The licenses are required for evaluating the license policy, even if not requested to be displayed
+	commonParams.IncludeLicenses = auditCmd.IncludeLicenses || auditCmd.licensePolicy != nil
 	commonParams.MultiScanId, commonParams.XscVersion = xsc.GetXscMsiAndVersion(auditCmd.analyticsMetricsService)
 	return commonParams
 }
@@ -106,6 +122,10 @@ func (auditCmd *AuditCommand) Run() (err error) {
 		return
 	}
 
+	// Interrupting the command cancels the JAS scans
+	ctx, stop := jas.NewInterruptibleContext()
+	defer stop()
+
 	// Should be called before creating the audit params, so the params will contain XSC information.
 	auditCmd.analyticsMetricsService.AddGeneralEvent(auditCmd.analyticsMetricsService.CreateGeneralEvent(xscservices.CliProduct, xscservices.CliEventType))
 	auditParams := NewAuditParams().
@@ -115,7 +135,18 @@ func (auditCmd *AuditCommand) Run() (err error) {
 		SetGraphBasicParams(auditCmd.AuditBasicParams).
 		SetCommonGraphScanParams(auditCmd.CreateCommonGraphScanParams()).
 		SetThirdPartyApplicabilityScan(auditCmd.thirdPartyApplicabilityScan).
-		SetThreads(auditCmd.Threads)
+		SetThreads(auditCmd.Threads).
+		SetLicensePolicy(auditCmd.licensePolicy).
+		SetGoReachability(auditCmd.goReachability).
+		SetVulnerableSymbolsFile(auditCmd.vulnerableSymbolsFile).
+		SetGitHistory(auditCmd.gitHistory).
+		SetChangedSince(auditCmd.changedSince).
+		SetSastCodeFlows(auditCmd.sastCodeFlows).
+		SetJasTimeout(auditCmd.jasTimeout).
+		SetJasMemoryLimitMB(auditCmd.jasMemoryLimitMB).
+		SetCustomRulesDirs(auditCmd.customRulesDirs).
+		SetConfigProfile(auditCmd.configProfile).
+		SetContext(ctx)
 	auditParams.SetIsRecursiveScan(isRecursiveScan).SetExclusions(auditCmd.Exclusions())
 
 	auditResults, err := RunAudit(auditParams)
@@ -123,6 +154,11 @@ func (auditCmd *AuditCommand) Run() (err error) {
 		return
 	}
 	auditCmd.analyticsMetricsService.UpdateGeneralEvent(auditCmd.analyticsMetricsService.CreateXscAnalyticsGeneralEventFinalizeFromAuditResults(auditResults))
+	if auditCmd.exportGraphPath != "" {
+		if err = graphutils.ExportDependencyGraphs(graphutils.NewDependencyGraphs(auditResults), auditCmd.graphFormat, auditCmd.exportGraphPath); err != nil {
+			return
+		}
+	}
 	if auditCmd.Progress() != nil {
 		if err = auditCmd.Progress().Quit(); err != nil {
 			return
@@ -141,6 +177,7 @@ func (auditCmd *AuditCommand) Run() (err error) {
 		SetPrintExtendedTable(auditCmd.PrintExtendedTable).
 		SetExtraMessages(messages).
 		SetSubScansPreformed(auditCmd.ScansToPerform()).
+		SetSimpleJsonError(auditResults.ExtendedScanResults.ScanErrors).
 		PrintScanResults(); err != nil {
 		return
 	}
@@ -149,8 +186,8 @@ func (auditCmd *AuditCommand) Run() (err error) {
 		return auditResults.ScansErr
 	}
 
-	// Only in case Xray's context was given (!auditCmd.IncludeVulnerabilities), and the user asked to fail the build accordingly, do so.
-	if auditCmd.Fail && !auditCmd.IncludeVulnerabilities && utils.CheckIfFailBuild(auditResults.GetScaScansXrayResults()) {
+	// Only in case Xray's context (!auditCmd.IncludeVulnerabilities) or a license policy was given, and the user asked to fail the build accordingly, do so.
+	if auditCmd.Fail && (!auditCmd.IncludeVulnerabilities || auditCmd.licensePolicy != nil) && utils.CheckIfFailBuild(auditResults.GetScaScansXrayResults()) {
 		err = utils.NewFailBuildError()
 	}
 	return
@@ -161,7 +198,7 @@ func (auditCmd *AuditCommand) CommandName() string {
 }
 
 func (auditCmd *AuditCommand) HasViolationContext() bool {
-	return len(auditCmd.watches) > 0 || auditCmd.projectKey != "" || auditCmd.targetRepoPath != ""
+	return len(auditCmd.watches) > 0 || auditCmd.projectKey != "" || auditCmd.targetRepoPath != "" || auditCmd.licensePolicy != nil
 }
 
 // Runs an audit scan based on the provided auditParams.
@@ -176,25 +213,37 @@ func RunAudit(auditParams *AuditParams) (results *utils.Results, err error) {
 	}
 	var xrayManager *xray.XrayServicesManager
 	if xrayManager, auditParams.xrayVersion, err = xrayutils.CreateXrayServiceManagerAndGetVersion(serverDetails); err != nil {
-		return
-	}
-	if err = clientutils.ValidateMinimumVersion(clientutils.Xray, auditParams.xrayVersion, scangraph.GraphScanMinXrayVersion); err != nil {
-		return
-	}
-	results.XrayVersion = auditParams.xrayVersion
-	results.ExtendedScanResults.EntitledForJas, err = isEntitledForJas(xrayManager, auditParams)
-	if err != nil {
-		return
+		if !jas.IsOfflineMode() || !isSecretsScanOnly(auditParams) {
+			return
+		}
+		// The built-in secrets detector doesn't require Xray, so a secrets scan can run in offline mode
+		log.Warn(fmt.Sprintf("Couldn't connect to Xray in offline mode (%s), the secrets scan will run with the built-in secrets detector: %s", jas.OfflineModeEnvVar, err.Error()))
+		err = nil
+	} else {
+		if err = clientutils.ValidateMinimumVersion(clientutils.Xray, auditParams.xrayVersion, scangraph.GraphScanMinXrayVersion); err != nil {
+			return
+		}
+		results.XrayVersion = auditParams.xrayVersion
+		results.ExtendedScanResults.EntitledForJas, err = isEntitledForJas(xrayManager, auditParams)
+		if err != nil {
+			return
+		}
 	}
 	results.MultiScanId = auditParams.commonGraphScanParams.MultiScanId
 
 	auditParallelRunner := utils.CreateSecurityParallelRunner(auditParams.threads)
 	auditParallelRunner.ErrWg.Add(1)
-	jfrogAppsConfig, err := jas.CreateJFrogAppsConfig(auditParams.workingDirs)
+	jfrogAppsConfig, err := createJFrogAppsConfig(auditParams)
 	if err != nil {
 		return results, fmt.Errorf("failed to create JFrogAppsConfig: %s", err.Error())
 	}
 	jasScanner := &jas.JasScanner{}
+	if auditParams.UseJas() {
+		// The custom rules are validated before the scans, so invalid rules fail the audit before any scan runs
+		if jasScanner.CustomRules, err = jas.LoadCustomRules(jfrogAppsConfig, auditParams.customRulesDirs); err != nil {
+			return results, fmt.Errorf("failed to load the custom rules: %s", err.Error())
+		}
+	}
 	if results.ExtendedScanResults.EntitledForJas {
 		// Download (if needed) the analyzer manager and run scanners.
 		auditParallelRunner.JasWg.Add(1)
@@ -203,12 +252,27 @@ func RunAudit(auditParams *AuditParams) (results *utils.Results, err error) {
 		}, auditParallelRunner.AddErrorToChan); jasErr != nil {
 			auditParallelRunner.AddErrorToChan(fmt.Errorf("failed to create AM downloading task, skipping JAS scans...: %s", jasErr.Error()))
 		}
+	} else if auditParams.UseJas() && isSecretsScanRequested(auditParams) {
+		// The Secrets scanner requires JAS entitlement, so the secrets scan runs with the built-in secrets detector
+		log.Info("Advanced Security isn't enabled, the secrets scan will run with the built-in secrets detector")
+		if err = runner.AddNativeSecretsScanTasks(auditParallelRunner, results.ExtendedScanResults, jfrogAppsConfig, auditParams.changedSince, jasScanner.CustomRules, auditParallelRunner.AddErrorToChan, auditParams.jasExclusions()...); err != nil {
+			return
+		}
+	}
+	if auditParams.gitHistory != "" {
+		// The Git history is scanned with the built-in secrets detector, which doesn't require JAS entitlement
+		if err = runner.AddGitHistorySecretsScanTasks(auditParallelRunner, results.ExtendedScanResults, jfrogAppsConfig, auditParams.gitHistory, auditParallelRunner.AddErrorToChan, auditParams.jasExclusions()...); err != nil {
+			return
+		}
 	}
 
 	// The sca scan doesn't require the analyzer manager, so it can run separately from the analyzer manager download routine.
 	if scaScanErr := buildDepTreeAndRunScaScan(auditParallelRunner, auditParams, results); scaScanErr != nil {
 		auditParallelRunner.AddErrorToChan(scaScanErr)
 	}
+	if auditParams.goReachability {
+		addGoReachabilityTasks(auditParallelRunner, auditParams, results, jfrogAppsConfig)
+	}
 	go func() {
 		auditParallelRunner.ScaScansWg.Wait()
 		auditParallelRunner.JasWg.Wait()
@@ -236,6 +300,19 @@ func RunAudit(auditParams *AuditParams) (results *utils.Results, err error) {
 	return
 }
 
+// Creates the JFrog apps config that determines the modules to scan and their scanners.
+// If a config profile is used, its modules are scanned. Otherwise, the modules are read from jfrog-apps-config.yml, or created from the working directories.
+func createJFrogAppsConfig(auditParams *AuditParams) (*jfrogappsconfig.JFrogAppsConfig, error) {
+	if auditParams.configProfile == nil {
+		return jas.CreateJFrogAppsConfig(auditParams.workingDirs)
+	}
+	log.Debug(fmt.Sprintf("Using config profile '%s' to determine the modules to scan and their scanners...", auditParams.configProfile.ProfileName))
+	for _, profileModule := range auditParams.configProfile.Modules {
+		log.Debug(jas.DescribeConfigProfileModule(profileModule))
+	}
+	return jas.CreateJFrogAppsConfigFromProfile(auditParams.configProfile, auditParams.Exclusions()...)
+}
+
 func isEntitledForJas(xrayManager *xray.XrayServicesManager, auditParams *AuditParams) (entitled bool, err error) {
 	if !auditParams.UseJas() {
 		// Dry run without JAS
@@ -244,19 +321,45 @@ func isEntitledForJas(xrayManager *xray.XrayServicesManager, auditParams *AuditP
 	return jas.IsEntitledForJas(xrayManager, auditParams.xrayVersion)
 }
 
+// Returns true if only the secrets scan was requested.
+func isSecretsScanOnly(auditParams *AuditParams) bool {
+	return len(auditParams.ScansToPerform()) == 1 && auditParams.ScansToPerform()[0] == utils.SecretsScan
+}
+
+// Returns true if the secrets scan was requested, or no specific scans were requested so all the scans are performed.
+func isSecretsScanRequested(auditParams *AuditParams) bool {
+	return len(auditParams.ScansToPerform()) == 0 || slices.Contains(auditParams.ScansToPerform(), utils.SecretsScan)
+}
+
 func downloadAnalyzerManagerAndRunScanners(auditParallelRunner *utils.SecurityParallelRunner, scanResults *utils.Results,
 	serverDetails *config.ServerDetails, auditParams *AuditParams, scanner *jas.JasScanner, jfrogAppsConfig *jfrogappsconfig.JFrogAppsConfig, threadId int) (err error) {
 	defer func() {
 		auditParallelRunner.JasWg.Done()
 	}()
 	if err = jas.DownloadAnalyzerManagerIfNeeded(threadId); err != nil {
-		return fmt.Errorf("%s failed to download analyzer manager: %s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
+		err = fmt.Errorf("%s failed to download analyzer manager: %s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
+		if !isSecretsScanRequested(auditParams) {
+			return
+		}
+		// The secrets scan doesn't require the analyzer manager, it can run with the built-in secrets detector
+		log.Warn(err.Error() + "\nThe secrets scan will run with the built-in secrets detector")
+		return errors.Join(err, runner.AddNativeSecretsScanTasks(auditParallelRunner, scanResults.ExtendedScanResults, jfrogAppsConfig, auditParams.changedSince, scanner.CustomRules, auditParallelRunner.AddErrorToChan, auditParams.jasExclusions()...))
 	}
-	scanner, err = jas.CreateJasScanner(scanner, jfrogAppsConfig, serverDetails, jas.GetAnalyzerManagerXscEnvVars(auditParams.commonGraphScanParams.MultiScanId, scanResults.GetScaScannedTechnologies()...), auditParams.Exclusions()...)
+	scanner, err = jas.CreateJasScanner(scanner, jfrogAppsConfig, serverDetails, jas.GetAnalyzerManagerXscEnvVars(auditParams.commonGraphScanParams.MultiScanId, scanResults.GetScaScannedTechnologies()...), auditParams.jasExclusions()...)
 	if err != nil {
 		return fmt.Errorf("failed to create jas scanner: %s", err.Error())
 	}
-	if err = runner.AddJasScannersTasks(auditParallelRunner, scanResults, auditParams.DirectDependencies(), serverDetails, auditParams.thirdPartyApplicabilityScan, scanner, applicability.ApplicabilityScannerType, secrets.SecretsScannerType, auditParallelRunner.AddErrorToChan, auditParams.ScansToPerform(), auditParams.configProfile); err != nil {
+	if auditParams.configProfile != nil {
+		if scanner.ModulesTimeouts, err = jas.GetConfigProfileModulesTimeouts(auditParams.configProfile); err != nil {
+			return
+		}
+	}
+	scanner.ChangedSince = auditParams.changedSince
+	scanner.SastCodeFlows = auditParams.sastCodeFlows
+	scanner.AnalyzerManager.Timeout = auditParams.jasTimeout
+	scanner.AnalyzerManager.MemoryLimitMB = auditParams.jasMemoryLimitMB
+	scanner.AnalyzerManager.Context = auditParams.ctx
+	if err = runner.AddJasScannersTasks(auditParallelRunner, scanResults, auditParams.DirectDependencies(), serverDetails, auditParams.thirdPartyApplicabilityScan, scanner, applicability.ApplicabilityScannerType, secrets.SecretsScannerType, auditParallelRunner.AddErrorToChan, auditParams.ScansToPerform()); err != nil {
 		return fmt.Errorf("%s failed to run JAS scanners: %s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
 	}
 	return
@@ -1,17 +1,22 @@
 package audit
 
 import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
 	biutils "github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	coreTests "github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray/scangraph"
+	"github.com/jfrog/jfrog-cli-security/utils/xsc"
 	clientTests "github.com/jfrog/jfrog-client-go/utils/tests"
 	"github.com/jfrog/jfrog-client-go/xsc/services"
 	"github.com/stretchr/testify/assert"
-	"os"
-	"path/filepath"
-	"testing"
 )
 
 // Note: Currently, if a config profile is provided, the scan will use the profile's settings, IGNORING jfrog-apps-config if exists.
@@ -149,3 +154,59 @@ func TestAuditWithConfigProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestAuditCommandWithConfigProfile(t *testing.T) {
+	// The Xray version doesn't support the JAS entitlement, so the secrets scan runs with the built-in secrets detector and doesn't require the analyzer manager
+	graphScanRequested := atomic.Bool{}
+	mockServer, serverDetails := utils.CreateXrayRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/xray/api/v1/system/version":
+			_, err := w.Write([]byte(fmt.Sprintf(`{"xray_version": "%s", "xray_revision": "xxx"}`, scangraph.GraphScanMinXrayVersion)))
+			assert.NoError(t, err)
+		case "/xray/api/v1/scan/graph":
+			graphScanRequested.Store(true)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	defer mockServer.Close()
+
+	tempDirPath, createTempDirCallback := coreTests.CreateTempDirWithCallbackAndAssert(t)
+	defer createTempDirCallback()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "..", "tests", "testdata", "projects", "jas", "jas"), tempDirPath, true, nil))
+	baseWd, err := os.Getwd()
+	assert.NoError(t, err)
+	chdirCallback := clientTests.ChangeDirWithCallback(t, baseWd, tempDirPath)
+	defer chdirCallback()
+
+	auditCmd := NewGenericAuditCommand().SetAnalyticsMetricsService(xsc.NewAnalyticsMetricsService(serverDetails))
+	auditCmd.SetServerDetails(serverDetails).SetOutputFormat(format.SimpleJson).SetUseJas(true)
+	auditCmd.SetConfigProfile(&services.ConfigProfile{
+		ProfileName: "only-secrets",
+		Modules: []services.Module{{
+			ModuleId:     1,
+			ModuleName:   "only-secrets-module",
+			PathFromRoot: ".",
+			ScanConfig: services.ScanConfig{
+				EnableScaScan:        false,
+				SecretsScannerConfig: services.SecretsScannerConfig{EnableSecretsScan: true},
+			},
+		}},
+	})
+	// The project has a requirements.txt file, so the SCA scan runs unless the config profile is applied
+	assert.NoError(t, auditCmd.Run())
+	assert.False(t, graphScanRequested.Load())
+}
+
+func TestIsSecretsScanRequested(t *testing.T) {
+	params := NewAuditParams()
+	// All the scans are performed by default
+	assert.True(t, isSecretsScanRequested(params))
+	assert.False(t, isSecretsScanOnly(params))
+	params.SetScansToPerform([]utils.SubScanType{utils.ScaScan, utils.SecretsScan})
+	assert.True(t, isSecretsScanRequested(params))
+	assert.False(t, isSecretsScanOnly(params))
+	params.SetScansToPerform([]utils.SubScanType{utils.SecretsScan})
+	assert.True(t, isSecretsScanOnly(params))
+	params.SetScansToPerform([]utils.SubScanType{utils.ScaScan})
+	assert.False(t, isSecretsScanRequested(params))
+}
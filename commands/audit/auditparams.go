@@ -1,7 +1,11 @@
 package audit
 
 import (
+	"context"
+	"time"
+
 	xrayutils "github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/licenseutils"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray/scangraph"
 	"github.com/jfrog/jfrog-client-go/xray/services"
@@ -21,6 +25,26 @@ type AuditParams struct {
 	thirdPartyApplicabilityScan bool
 	threads                     int
 	configProfile               *clientservices.ConfigProfile
+	// A license policy that is evaluated locally on the licenses of the dependencies.
+	licensePolicy *licenseutils.LicensePolicy
+	// Determine the applicability of the Go vulnerabilities with the call graph of the project, without the analyzer manager.
+	goReachability bool
+	// An optional file with OSV entries that list the vulnerable symbols used by the Go reachability analysis.
+	vulnerableSymbolsFile string
+	// A revision range of the Git history to scan for secrets, in addition to the secrets scan of the working tree.
+	gitHistory string
+	// A Git reference. If set, the SAST, IaC and Secrets scanners only report the results in the lines that were changed since it.
+	changedSince string
+	// If set with changedSince, SAST scans the whole modules and also reports the results whose code flow passes through the changed lines.
+	sastCodeFlows bool
+	// The timeout of each JAS scan, zero for no timeout
+	jasTimeout time.Duration
+	// The maximal memory of each JAS scan in megabytes, zero for no limit
+	jasMemoryLimitMB uint64
+	// Directories of custom SAST and Secrets rules to scan all the modules with
+	customRulesDirs []string
+	// The context of the command, which cancels the JAS scans when the command is interrupted
+	ctx context.Context
 }
 
 func NewAuditParams() *AuditParams {
@@ -94,11 +118,110 @@ func (params *AuditParams) SetCommonGraphScanParams(commonParams *scangraph.Comm
 	return params
 }
 
+// Returns the exclusions of the Advanced Security scanners.
+// If a config profile is used, the exclusions of the audit are added to the exclude patterns of its modules instead, so both apply.
+func (params *AuditParams) jasExclusions() []string {
+	if params.configProfile != nil {
+		return nil
+	}
+	return params.Exclusions()
+}
+
 func (params *AuditParams) SetConfigProfile(configProfile *clientservices.ConfigProfile) *AuditParams {
 	params.configProfile = configProfile
 	return params
 }
 
+func (params *AuditParams) LicensePolicy() *licenseutils.LicensePolicy {
+	return params.licensePolicy
+}
+
+func (params *AuditParams) SetLicensePolicy(licensePolicy *licenseutils.LicensePolicy) *AuditParams {
+	params.licensePolicy = licensePolicy
+	return params
+}
+
+func (params *AuditParams) GoReachability() bool {
+	return params.goReachability
+}
+
+func (params *AuditParams) SetGoReachability(goReachability bool) *AuditParams {
+	params.goReachability = goReachability
+	return params
+}
+
+func (params *AuditParams) VulnerableSymbolsFile() string {
+	return params.vulnerableSymbolsFile
+}
+
+func (params *AuditParams) SetVulnerableSymbolsFile(vulnerableSymbolsFile string) *AuditParams {
+	params.vulnerableSymbolsFile = vulnerableSymbolsFile
+	return params
+}
+
+func (params *AuditParams) GitHistory() string {
+	return params.gitHistory
+}
+
+func (params *AuditParams) SetGitHistory(gitHistory string) *AuditParams {
+	params.gitHistory = gitHistory
+	return params
+}
+
+func (params *AuditParams) ChangedSince() string {
+	return params.changedSince
+}
+
+func (params *AuditParams) SetChangedSince(changedSince string) *AuditParams {
+	params.changedSince = changedSince
+	return params
+}
+
+func (params *AuditParams) SastCodeFlows() bool {
+	return params.sastCodeFlows
+}
+
+func (params *AuditParams) SetSastCodeFlows(sastCodeFlows bool) *AuditParams {
+	params.sastCodeFlows = sastCodeFlows
+	return params
+}
+
+func (params *AuditParams) JasTimeout() time.Duration {
+	return params.jasTimeout
+}
+
+func (params *AuditParams) SetJasTimeout(jasTimeout time.Duration) *AuditParams {
+	params.jasTimeout = jasTimeout
+	return params
+}
+
+func (params *AuditParams) JasMemoryLimitMB() uint64 {
+	return params.jasMemoryLimitMB
+}
+
+func (params *AuditParams) SetJasMemoryLimitMB(jasMemoryLimitMB uint64) *AuditParams {
+	params.jasMemoryLimitMB = jasMemoryLimitMB
+	return params
+}
+
+func (params *AuditParams) Context() context.Context {
+	return params.ctx
+}
+
+func (params *AuditParams) SetContext(ctx context.Context) *AuditParams {
+	params.ctx = ctx
+	return params
+}
+
+func (params *AuditParams) CustomRulesDirs() []string {
+	return params.customRulesDirs
+}
+
+func (params *AuditParams) SetCustomRulesDirs(customRulesDirs []string) *AuditParams {
+	params.customRulesDirs = customRulesDirs
+	return params
+}
+
 func (params *AuditParams) createXrayGraphScanParams() *services.XrayGraphScanParams {
 	return &services.XrayGraphScanParams{
 		RepoPath:               params.commonGraphScanParams.RepoPath,
@@ -0,0 +1,250 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// Set to true to enable the dependency tree cache, same as the --dep-tree-cache option.
+const DepTreeCacheEnvVar = "JFROG_CLI_DEP_TREE_CACHE"
+
+// The versions of the package managers, which are part of the cache keys. Each version is taken once, when the first project of the technology is looked up in the cache.
+var (
+	techVersions      = map[techutils.Technology]string{}
+	techVersionsMutex sync.Mutex
+)
+
+// A local cache of the dependency trees built by the package managers.
+// Each audited project has a single entry, stored with the key it was built with.
+// A change in the project descriptors, lock files, package manager version or audit parameters changes the key, so the entry is rebuilt and overridden.
+type DepTreeCache struct {
+	cacheDir string
+}
+
+type depTreeCacheEntry struct {
+	Key  string               `json:"key"`
+	Tree DependencyTreeResult `json:"tree"`
+	// The types and classifiers of the nodes, which are not serialized with the trees
+	NodesAttributes map[string]xray.DepTreeNode `json:"nodesAttributes,omitempty"`
+}
+
+func IsDepTreeCacheEnabledByEnv() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(DepTreeCacheEnvVar))
+	return err == nil && enabled
+}
+
+func NewDepTreeCache() (*DepTreeCache, error) {
+	cacheDir, err := utils.GetDepTreeCacheFolder()
+	if err != nil {
+		return nil, err
+	}
+	if err = fileutils.CreateDirIfNotExist(cacheDir); err != nil {
+		return nil, err
+	}
+	return &DepTreeCache{cacheDir: cacheDir}, nil
+}
+
+// Get the cached dependency tree of the project. Returns nil if not exists or built with a different key.
+func (dtc *DepTreeCache) Get(tech techutils.Technology, target, key string) *DependencyTreeResult {
+	content, err := os.ReadFile(dtc.getEntryPath(tech, target))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debug(fmt.Sprintf("Failed to read the dependency tree cache entry of %s: %s", target, err.Error()))
+		}
+		return nil
+	}
+	entry := &depTreeCacheEntry{}
+	if err = json.Unmarshal(content, entry); err != nil {
+		log.Debug(fmt.Sprintf("Failed to parse the dependency tree cache entry of %s: %s", target, err.Error()))
+		return nil
+	}
+	if entry.Key != key {
+		return nil
+	}
+	for _, tree := range append([]*xrayCmdUtils.GraphNode{entry.Tree.FlatTree}, entry.Tree.FullDepTrees...) {
+		setNodesAttributes(tree, entry.NodesAttributes)
+	}
+	return &entry.Tree
+}
+
+func (dtc *DepTreeCache) Set(tech techutils.Technology, target, key string, tree *DependencyTreeResult) error {
+	nodesAttributes := map[string]xray.DepTreeNode{}
+	for _, node := range append([]*xrayCmdUtils.GraphNode{tree.FlatTree}, tree.FullDepTrees...) {
+		getNodesAttributes(node, nodesAttributes)
+	}
+	content, err := json.Marshal(depTreeCacheEntry{Key: key, Tree: *tree, NodesAttributes: nodesAttributes})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	// Write to a unique temp file and rename it, so concurrent audits never read or write a partial entry
+	tempFile, err := os.CreateTemp(dtc.cacheDir, "dep-tree-*.tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = tempFile.Write(content)
+	if e := tempFile.Close(); err == nil {
+		err = e
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), dtc.getEntryPath(tech, target))
+	}
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+	}
+	return errorutils.CheckError(err)
+}
+
+func getNodesAttributes(node *xrayCmdUtils.GraphNode, nodesAttributes map[string]xray.DepTreeNode) {
+	if node == nil {
+		return
+	}
+	if node.Types != nil || node.Classifier != nil {
+		nodesAttributes[node.Id] = xray.DepTreeNode{Types: node.Types, Classifier: node.Classifier}
+	}
+	for _, child := range node.Nodes {
+		getNodesAttributes(child, nodesAttributes)
+	}
+}
+
+func setNodesAttributes(node *xrayCmdUtils.GraphNode, nodesAttributes map[string]xray.DepTreeNode) {
+	if node == nil || len(nodesAttributes) == 0 {
+		return
+	}
+	if attributes, exists := nodesAttributes[node.Id]; exists {
+		node.Types, node.Classifier = attributes.Types, attributes.Classifier
+	}
+	for _, child := range node.Nodes {
+		setNodesAttributes(child, nodesAttributes)
+	}
+}
+
+func (dtc *DepTreeCache) getEntryPath(tech techutils.Technology, target string) string {
+	hash := sha256.Sum256([]byte(tech.String() + "|" + target))
+	return filepath.Join(dtc.cacheDir, hex.EncodeToString(hash[:])+".json")
+}
+
+// Get the cache key of the project dependency tree, from the content of its descriptors, lock files and workspace and wrapper files, the package manager version and the parameters that affect the tree.
+// Returns an empty key if the project can't be cached.
+func getDepTreeCacheKey(params *AuditParams, tech techutils.Technology, target string, descriptors []string) (string, error) {
+	if len(descriptors) == 0 {
+		return "", nil
+	}
+	files := append([]string{}, descriptors...)
+	for _, file := range append(tech.GetLockFiles(), getTreeAffectingFiles(params, tech)...) {
+		filePath := filepath.Join(target, file)
+		if fileutils.IsPathExists(filePath, false) {
+			files = append(files, filePath)
+		}
+	}
+	sort.Strings(files)
+	keyParts := []string{tech.String(), getTechVersion(tech)}
+	for _, file := range files {
+		checksum, err := utils.GetFileSha256(file)
+		if err != nil {
+			return "", err
+		}
+		keyParts = append(keyParts, file+"="+checksum)
+	}
+	keyParts = append(keyParts,
+		params.DepsRepo(),
+		params.PipRequirementsFile(),
+		strconv.FormatBool(params.ExcludeTestDependencies()),
+		strings.Join(params.DependenciesScopes(), ","),
+		strconv.FormatBool(params.UseWrapper()),
+		strings.Join(params.Args(), " "),
+		strings.Join(params.InstallCommandArgs(), " "),
+	)
+	hash := sha256.Sum256([]byte(strings.Join(keyParts, "|")))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Get the paths, relative to the project, of the files other than the descriptors and lock files that affect the dependency tree.
+// The Go workspace file selects the modules the dependencies are resolved with, and the wrapper properties select the build tool version when the wrapper is used.
+func getTreeAffectingFiles(params *AuditParams, tech techutils.Technology) []string {
+	switch tech {
+	case techutils.Go:
+		return []string{techutils.GoWorkFileName, techutils.GoWorkFileName + ".sum"}
+	case techutils.Maven:
+		if params.UseWrapper() {
+			return []string{filepath.Join(".mvn", "wrapper", "maven-wrapper.properties")}
+		}
+	case techutils.Gradle:
+		if params.UseWrapper() {
+			return []string{filepath.Join("gradle", "wrapper", "gradle-wrapper.properties")}
+		}
+	}
+	return nil
+}
+
+// Get the version of the technology package manager. Returns an empty string if the version can't be determined.
+func getTechVersion(tech techutils.Technology) string {
+	techVersionsMutex.Lock()
+	defer techVersionsMutex.Unlock()
+	if version, exists := techVersions[tech]; exists {
+		return version
+	}
+	techVersions[tech] = runTechVersionCmd(tech)
+	return techVersions[tech]
+}
+
+func runTechVersionCmd(tech techutils.Technology) string {
+	versionArg := "--version"
+	if tech == techutils.Go {
+		versionArg = "version"
+	}
+	output, err := exec.Command(tech.GetExecCommandName(), versionArg).CombinedOutput()
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to get the %s version for the dependency tree cache key: %s", tech.ToFormal(), err.Error()))
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Get the dependency tree of the project from the cache, or build it and add it to the cache.
+func buildDependencyTreeWithCache(scan *utils.ScaScanResult, params *AuditParams, buildTree func() (*DependencyTreeResult, error)) (*DependencyTreeResult, error) {
+	// Curation resolves the dependencies into its own cache folder, so the project is always built.
+	// The cache key (and the package manager version in it) is computed only when the cache is used.
+	if !params.UseDepTreeCache() || params.IsCurationCmd() {
+		return buildTree()
+	}
+	depTreeCache, err := NewDepTreeCache()
+	if err != nil {
+		log.Warn(fmt.Sprintf("Failed to initialize the dependency tree cache, building the dependency tree: %s", err.Error()))
+		return buildTree()
+	}
+	key, err := getDepTreeCacheKey(params, scan.Technology, scan.Target, scan.Descriptors)
+	if err != nil || key == "" {
+		log.Debug(fmt.Sprintf("The %s dependency tree of %s can't be cached, building it.", scan.Technology.ToFormal(), scan.Target))
+		return buildTree()
+	}
+	if treeResult := depTreeCache.Get(scan.Technology, scan.Target, key); treeResult != nil {
+		log.Debug(fmt.Sprintf("Dependency tree cache hit for %s in %s", scan.Technology.ToFormal(), scan.Target))
+		return treeResult, nil
+	}
+	log.Debug(fmt.Sprintf("Dependency tree cache miss for %s in %s", scan.Technology.ToFormal(), scan.Target))
+	treeResult, err := buildTree()
+	if err != nil {
+		return nil, err
+	}
+	if e := depTreeCache.Set(scan.Technology, scan.Target, key, treeResult); e != nil {
+		log.Warn(fmt.Sprintf("Failed to store the %s dependency tree of %s in the cache: %s", scan.Technology.ToFormal(), scan.Target, e.Error()))
+	}
+	return treeResult, nil
+}
@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	clientTests "github.com/jfrog/jfrog-client-go/utils/tests"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTreeWithCache(t *testing.T) {
+	cacheDir, cleanUpCache := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUpCache()
+	defer clientTests.SetEnvWithCallbackAndAssert(t, utils.DepTreeCacheDir, cacheDir)()
+	projectDir, cleanUpProject := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUpProject()
+	descriptor := filepath.Join(projectDir, "package.json")
+	assert.NoError(t, os.WriteFile(descriptor, []byte(`{"name":"project"}`), 0644))
+
+	builds := 0
+	buildTree := func() (*DependencyTreeResult, error) {
+		builds++
+		return &DependencyTreeResult{FlatTree: &xrayCmdUtils.GraphNode{Id: "root", Nodes: []*xrayCmdUtils.GraphNode{{Id: "npm://dep:1.0.0", Types: &[]string{"jar"}}}}}, nil
+	}
+	scan := &utils.ScaScanResult{Target: projectDir, Technology: techutils.Npm, Descriptors: []string{descriptor}}
+	params := NewAuditParams()
+
+	// Disabled, the package manager version isn't taken
+	_, err := buildDependencyTreeWithCache(scan, params, buildTree)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, builds)
+	assert.NotContains(t, techVersions, techutils.Npm)
+
+	// Miss and hit
+	params.SetUseDepTreeCache(true)
+	for i := 0; i < 2; i++ {
+		treeResult, err := buildDependencyTreeWithCache(scan, params, buildTree)
+		assert.NoError(t, err)
+		assert.Equal(t, "npm://dep:1.0.0", treeResult.FlatTree.Nodes[0].Id)
+		// The node types are kept in the cache
+		assert.Equal(t, &[]string{"jar"}, treeResult.FlatTree.Nodes[0].Types)
+	}
+	assert.Equal(t, 2, builds)
+	assert.Contains(t, techVersions, techutils.Npm)
+
+	// A new lock file invalidates the entry
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "package-lock.json"), []byte(`{}`), 0644))
+	_, err = buildDependencyTreeWithCache(scan, params, buildTree)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, builds)
+
+	// Audit parameters that affect the tree invalidate the entry
+	params.SetNpmScope("prodOnly")
+	_, err = buildDependencyTreeWithCache(scan, params, buildTree)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, builds)
+}
+
+func TestGetDepTreeCacheKey(t *testing.T) {
+	projectDir, cleanUpProject := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUpProject()
+	descriptor := filepath.Join(projectDir, "go.mod")
+	assert.NoError(t, os.WriteFile(descriptor, []byte("module project\n"), 0644))
+	params := NewAuditParams()
+	key, err := getDepTreeCacheKey(params, techutils.Go, projectDir, []string{descriptor})
+	assert.NoError(t, err)
+
+	// A Go workspace file changes the key
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "go.work"), []byte("go 1.22\n\nuse .\n"), 0644))
+	workspaceKey, err := getDepTreeCacheKey(params, techutils.Go, projectDir, []string{descriptor})
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, workspaceKey)
+
+	// The wrapper properties change the key only when the wrapper is used
+	descriptor = filepath.Join(projectDir, "build.gradle")
+	assert.NoError(t, os.WriteFile(descriptor, []byte("plugins {}\n"), 0644))
+	key, err = getDepTreeCacheKey(params, techutils.Gradle, projectDir, []string{descriptor})
+	assert.NoError(t, err)
+	wrapperProperties := filepath.Join(projectDir, "gradle", "wrapper", "gradle-wrapper.properties")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(wrapperProperties), 0755))
+	assert.NoError(t, os.WriteFile(wrapperProperties, []byte("distributionUrl=https://services.gradle.org/distributions/gradle-8.5-bin.zip\n"), 0644))
+	noWrapperKey, err := getDepTreeCacheKey(params, techutils.Gradle, projectDir, []string{descriptor})
+	assert.NoError(t, err)
+	assert.Equal(t, key, noWrapperKey)
+	params.SetUseWrapper(true)
+	key, err = getDepTreeCacheKey(params, techutils.Gradle, projectDir, []string{descriptor})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(wrapperProperties, []byte("distributionUrl=https://services.gradle.org/distributions/gradle-8.6-bin.zip\n"), 0644))
+	wrapperKey, err := getDepTreeCacheKey(params, techutils.Gradle, projectDir, []string{descriptor})
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, wrapperKey)
+}
+
+func TestIsDepTreeCacheEnabledByEnv(t *testing.T) {
+	assert.False(t, IsDepTreeCacheEnabledByEnv())
+	defer clientTests.SetEnvWithCallbackAndAssert(t, DepTreeCacheEnvVar, "true")()
+	assert.True(t, IsDepTreeCacheEnabledByEnv())
+}
@@ -3,6 +3,9 @@ package _go
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
+
 	biutils "github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/gofrog/datastructures"
 	goartifactoryutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/golang"
@@ -11,8 +14,8 @@ import (
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
-	"strings"
 )
 
 const (
@@ -54,39 +57,65 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.
 		}
 	}
 
-	// Calculate go dependencies graph
-	dependenciesGraph, err := utils.GetDependenciesGraph(currentDir)
+	project, err := getGoProject(currentDir)
+	if err != nil {
+		return
+	}
+	if localDependencies := project.getLocalDependencies(); len(localDependencies) > 0 {
+		log.Info(fmt.Sprintf("The following local Go modules are part of the project and will not be scanned as dependencies: %s", strings.Join(localDependencies, ", ")))
+	}
+	dependenciesGraph, dependenciesList, err := getDependenciesGraphAndList(project, currentDir)
 	if err != nil || len(dependenciesGraph) == 0 {
 		return
 	}
-	// Calculate go dependencies list
-	dependenciesList, err := utils.GetDependenciesList(currentDir, handleCurationGoError)
+	// Parse the dependencies into Xray dependency tree format, with a root per project module
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	for _, module := range project.modules {
+		rootNode := &xrayUtils.GraphNode{
+			Id:    goPackageTypeIdentifier + module.path,
+			Nodes: []*xrayUtils.GraphNode{},
+		}
+		populateGoDependencyTree(rootNode, dependenciesGraph, dependenciesList, project.firstPartyModules, uniqueDepsSet)
+		// In case of curation command, go version is not relevant as it can't be resolved from go repo
+		if !params.IsCurationCmd() {
+			if gotErr := addGoVersionToTree(rootNode, uniqueDepsSet); gotErr != nil {
+				err = gotErr
+				return
+			}
+		}
+		dependencyTree = append(dependencyTree, rootNode)
+	}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+// Calculate the dependencies graph using 'go mod graph' and the dependencies list using 'go list'.
+// In a workspace, the commands run in the first module directory and include the dependencies of all the workspace modules.
+// If the project is vendored, the dependencies list is read from vendor/modules.txt, which is also used as the graph if it can't be calculated.
+func getDependenciesGraphAndList(project *goProject, projectDir string) (dependenciesGraph map[string][]string, dependenciesList map[string]bool, err error) {
+	modulesDir := project.modules[0].dir
+	vendoredList, explicitModules, err := project.getVendoredModules(projectDir)
 	if err != nil {
 		return
 	}
-	// Get root module name
-	rootModuleName, err := goutils.GetModuleName(currentDir)
-	if err != nil {
+	dependenciesGraph, graphErr := utils.GetDependenciesGraph(modulesDir)
+	if vendoredList != nil {
+		log.Debug(fmt.Sprintf("Using the %d vendored modules listed in '%s' as the dependencies list", len(vendoredList), filepath.Join(vendorDirName, vendorModulesFileName)))
+		if graphErr != nil || len(dependenciesGraph) == 0 {
+			log.Debug("Couldn't calculate the dependencies graph, adding the vendored modules as the project dependencies:", graphErr)
+			dependenciesGraph = project.getVendoredDependenciesGraph(vendoredList, explicitModules)
+		}
+		dependenciesList = vendoredList
 		return
 	}
-	// Parse the dependencies into Xray dependency tree format
-	rootNode := &xrayUtils.GraphNode{
-		Id:    goPackageTypeIdentifier + rootModuleName,
-		Nodes: []*xrayUtils.GraphNode{},
+	if err = graphErr; err != nil || len(dependenciesGraph) == 0 {
+		return
 	}
-	uniqueDepsSet := datastructures.MakeSet[string]()
-	populateGoDependencyTree(rootNode, dependenciesGraph, dependenciesList, uniqueDepsSet)
-
-	// In case of curation command, go version is not relevant as it can't be resolved from go repo
-	if !params.IsCurationCmd() {
-		if gotErr := addGoVersionToTree(rootNode, uniqueDepsSet); gotErr != nil {
-			err = gotErr
-			return
-		}
+	if len(project.modules) > 1 {
+		dependenciesList, err = getWorkspaceDependenciesList(projectDir)
+		return
 	}
-
-	dependencyTree = []*xrayUtils.GraphNode{rootNode}
-	uniqueDeps = uniqueDepsSet.ToSlice()
+	dependenciesList, err = utils.GetDependenciesList(modulesDir, handleCurationGoError)
 	return
 }
 
@@ -110,15 +139,33 @@ func handleCurationGoError(err error) (bool, error) {
 	return false, nil
 }
 
-func populateGoDependencyTree(currNode *xrayUtils.GraphNode, dependenciesGraph map[string][]string, dependenciesList map[string]bool, uniqueDepsSet *datastructures.Set[string]) {
+func populateGoDependencyTree(currNode *xrayUtils.GraphNode, dependenciesGraph map[string][]string, dependenciesList map[string]bool, firstPartyModules *datastructures.Set[string], uniqueDepsSet *datastructures.Set[string]) {
 	if currNode.NodeHasLoop() {
 		return
 	}
 	uniqueDepsSet.Add(currNode.Id)
-	currDepChildren := dependenciesGraph[strings.TrimPrefix(currNode.Id, goPackageTypeIdentifier)]
-	// Recursively create & append all node's dependencies.
-	for _, childName := range currDepChildren {
-		if !dependenciesList[childName] {
+	module := strings.TrimPrefix(currNode.Id, goPackageTypeIdentifier)
+	addedLocalModules := datastructures.MakeSet[string]()
+	addedLocalModules.Add(getModulePath(module))
+	addGoDependencies(currNode, module, dependenciesGraph, dependenciesList, firstPartyModules, uniqueDepsSet, addedLocalModules)
+}
+
+// Recursively create & append the module's dependencies to the node.
+// The dependencies of first-party (local) modules are added to the node instead of the modules themselves.
+func addGoDependencies(currNode *xrayUtils.GraphNode, module string, dependenciesGraph map[string][]string, dependenciesList map[string]bool, firstPartyModules *datastructures.Set[string], uniqueDepsSet, addedLocalModules *datastructures.Set[string]) {
+	for _, childName := range dependenciesGraph[module] {
+		if childPath := getModulePath(childName); firstPartyModules.Exists(childPath) {
+			if !addedLocalModules.Exists(childPath) {
+				addedLocalModules.Add(childPath)
+				// Workspace modules appear in the graph without a version
+				addGoDependencies(currNode, childName, dependenciesGraph, dependenciesList, firstPartyModules, uniqueDepsSet, addedLocalModules)
+				if childPath != childName {
+					addGoDependencies(currNode, childPath, dependenciesGraph, dependenciesList, firstPartyModules, uniqueDepsSet, addedLocalModules)
+				}
+			}
+			continue
+		}
+		if !dependenciesList[childName] || hasChild(currNode, goPackageTypeIdentifier+childName) {
 			// 'go list all' is more accurate than 'go graph' so we filter out deps that don't exist in go list
 			continue
 		}
@@ -128,8 +175,17 @@ func populateGoDependencyTree(currNode *xrayUtils.GraphNode, dependenciesGraph m
 			Parent: currNode,
 		}
 		currNode.Nodes = append(currNode.Nodes, childNode)
-		populateGoDependencyTree(childNode, dependenciesGraph, dependenciesList, uniqueDepsSet)
+		populateGoDependencyTree(childNode, dependenciesGraph, dependenciesList, firstPartyModules, uniqueDepsSet)
+	}
+}
+
+func hasChild(node *xrayUtils.GraphNode, childId string) bool {
+	for _, child := range node.Nodes {
+		if child.Id == childId {
+			return true
+		}
 	}
+	return false
 }
 
 func getGoVersionAsDependency() (*xrayUtils.GraphNode, error) {
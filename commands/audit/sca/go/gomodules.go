@@ -0,0 +1,221 @@
+package _go
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	goModFileName = "go.mod"
+	// Created by 'go mod vendor' or 'go work vendor', lists the vendored modules
+	vendorModulesFileName = "modules.txt"
+	vendorDirName         = "vendor"
+	explicitModuleMarker  = "## explicit"
+)
+
+// A Go module of the audited project
+type goModule struct {
+	// The module path, as declared in its go.mod file
+	path string
+	dir  string
+}
+
+// The modules of the audited project and the local modules it depends on.
+type goProject struct {
+	// The workspace modules if the project directory contains a go.work file, otherwise the module in the project directory
+	modules []goModule
+	// The paths of the modules that are part of the project: the workspace modules and the modules replaced by local directories.
+	// These modules are shown as first-party code, and are not sent to Xray.
+	firstPartyModules *datastructures.Set[string]
+}
+
+func getGoProject(projectDir string) (project *goProject, err error) {
+	project = &goProject{firstPartyModules: datastructures.MakeSet[string]()}
+	moduleDirs := []string{projectDir}
+	workFile, err := techutils.ParseGoWorkFile(projectDir)
+	if err != nil {
+		return
+	}
+	if workFile != nil {
+		moduleDirs = []string{}
+		for _, use := range workFile.Use {
+			moduleDirs = append(moduleDirs, filepath.Join(projectDir, use.Path))
+		}
+		project.addLocalReplaces(workFile.Replace)
+	}
+	for _, moduleDir := range moduleDirs {
+		var modFile *modfile.File
+		if modFile, err = parseGoModFile(filepath.Join(moduleDir, goModFileName)); err != nil {
+			return
+		}
+		if modFile.Module == nil {
+			err = errorutils.CheckErrorf("the module path is missing in '%s'", filepath.Join(moduleDir, goModFileName))
+			return
+		}
+		project.modules = append(project.modules, goModule{path: modFile.Module.Mod.Path, dir: moduleDir})
+		project.firstPartyModules.Add(modFile.Module.Mod.Path)
+		project.addLocalReplaces(modFile.Replace)
+	}
+	if len(project.modules) > 1 {
+		log.Debug(fmt.Sprintf("Found a Go workspace with %d modules in '%s'", len(project.modules), projectDir))
+	}
+	return
+}
+
+func parseGoModFile(path string) (*modfile.File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	modFile, err := modfile.Parse(path, content, nil)
+	return modFile, errorutils.CheckError(err)
+}
+
+// Modules that are replaced by a local directory are part of the project sources
+func (gp *goProject) addLocalReplaces(replaces []*modfile.Replace) {
+	for _, replace := range replaces {
+		if replace.New.Version == "" && modfile.IsDirectoryPath(replace.New.Path) {
+			gp.firstPartyModules.Add(replace.Old.Path)
+		}
+	}
+}
+
+// Returns the paths of the local modules that the project depends on, excluding the project modules themselves.
+func (gp *goProject) getLocalDependencies() (localModules []string) {
+	projectModules := datastructures.MakeSet[string]()
+	for _, module := range gp.modules {
+		projectModules.Add(module.path)
+	}
+	for _, modulePath := range gp.firstPartyModules.ToSlice() {
+		if !projectModules.Exists(modulePath) {
+			localModules = append(localModules, modulePath)
+		}
+	}
+	sort.Strings(localModules)
+	return
+}
+
+// Returns the vendored modules as listed in the vendor/modules.txt file, in the same format of 'go list' (<path>:<version>).
+// The modules that are required explicitly by the project are returned separately.
+// The vendored modules which are replaced by local directories are added to the project first-party modules.
+func (gp *goProject) getVendoredModules(projectDir string) (dependenciesList map[string]bool, explicitModules []string, err error) {
+	modulesFilePath := filepath.Join(projectDir, vendorDirName, vendorModulesFileName)
+	if !fileutils.IsPathExists(modulesFilePath, false) {
+		return
+	}
+	modulesFile, err := os.Open(modulesFilePath)
+	if errorutils.CheckError(err) != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(modulesFile.Close()))
+	}()
+	dependenciesList = map[string]bool{}
+	currentModule := ""
+	scanner := bufio.NewScanner(modulesFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, explicitModuleMarker) && currentModule != "" {
+			explicitModules = append(explicitModules, currentModule)
+			continue
+		}
+		// Module lines: '# <path> <version>', '# <path> <version> => <replacement path> [<replacement version>]' or '# <path> => <replacement path>'
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		currentModule = ""
+		fields := strings.Fields(strings.TrimPrefix(line, "# "))
+		replaceIndex := slices.Index(fields, "=>")
+		if replaceIndex >= 0 && len(fields) == replaceIndex+2 && modfile.IsDirectoryPath(fields[replaceIndex+1]) {
+			gp.firstPartyModules.Add(fields[0])
+			continue
+		}
+		if len(fields) < 2 || fields[1] == "=>" {
+			continue
+		}
+		currentModule = fields[0] + ":" + fields[1]
+		dependenciesList[currentModule] = true
+	}
+	err = errorutils.CheckError(scanner.Err())
+	return
+}
+
+// When the dependencies graph can't be calculated offline, the vendored modules are added under each project module.
+// The modules which are required explicitly are added first.
+func (gp *goProject) getVendoredDependenciesGraph(dependenciesList map[string]bool, explicitModules []string) map[string][]string {
+	explicitModulesSet := datastructures.MakeSet[string]()
+	explicitModulesSet.AddElements(explicitModules...)
+	vendoredModules := append([]string{}, explicitModules...)
+	var indirectModules []string
+	for module := range dependenciesList {
+		if !explicitModulesSet.Exists(module) {
+			indirectModules = append(indirectModules, module)
+		}
+	}
+	sort.Strings(indirectModules)
+	vendoredModules = append(vendoredModules, indirectModules...)
+	dependenciesGraph := map[string][]string{}
+	for _, module := range gp.modules {
+		dependenciesGraph[module.path] = vendoredModules
+	}
+	return dependenciesGraph
+}
+
+// Runs 'go list' in the workspace directory and returns the modules of all the packages that the workspace modules depend on.
+// The '-mod=mod' flag, which is used to list the dependencies of a single module, isn't allowed in workspace mode.
+func getWorkspaceDependenciesList(workspaceDir string) (dependenciesList map[string]bool, err error) {
+	listCmd := exec.Command("go", "list", "-e", "-f", "{{with .Module}}{{.Path}}:{{.Version}}{{end}}", "all")
+	listCmd.Dir = workspaceDir
+	listCmd.Env = append(os.Environ(), "GOFLAGS="+removeModFlag(os.Getenv("GOFLAGS")))
+	log.Debug(fmt.Sprintf("Running 'go %s' in %s", strings.Join(listCmd.Args[1:], " "), workspaceDir))
+	output, err := listCmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, errorutils.CheckErrorf("'go list' command failed in the workspace '%s': %s - %s", workspaceDir, err.Error(), exitErr.Stderr)
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	dependenciesList = map[string]bool{}
+	for _, line := range strings.Split(string(output), "\n") {
+		// The workspace modules are listed without a version
+		line = strings.TrimSpace(line)
+		if path, version, found := strings.Cut(line, ":"); found && path != "" && version != "" {
+			dependenciesList[line] = true
+		}
+	}
+	return
+}
+
+// The '-mod' flag may be set in the GOFLAGS environment variable, but only '-mod=readonly' and '-mod=vendor' are allowed in workspace mode
+func removeModFlag(goFlags string) string {
+	var flags []string
+	for _, flag := range strings.Fields(goFlags) {
+		if !strings.HasPrefix(flag, "-mod=") {
+			flags = append(flags, flag)
+		}
+	}
+	return strings.Join(flags, " ")
+}
+
+// Returns the module path of a module in the 'go list' and 'go mod graph' format (<path>:<version>)
+func getModulePath(module string) string {
+	if versionIndex := strings.LastIndex(module, ":"); versionIndex > 0 {
+		return module[:versionIndex]
+	}
+	return module
+}
@@ -0,0 +1,104 @@
+package _go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestGetGoProject(t *testing.T) {
+	projectDir := t.TempDir()
+	writeTestFile(t, filepath.Join(projectDir, techutils.GoWorkFileName), "go 1.21\n\nuse (\n\t./api\n\t./server\n)\n")
+	writeTestFile(t, filepath.Join(projectDir, "api", goModFileName), "module example.com/api\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(projectDir, "server", goModFileName), "module example.com/server\n\ngo 1.21\n\nrequire (\n\texample.com/api v0.0.0\n\texample.com/utils v1.0.0\n\tgithub.com/pkg/errors v0.9.1\n)\n\nreplace example.com/utils => ../utils\n\nreplace github.com/pkg/errors => github.com/pkg/errors v0.9.0\n")
+
+	project, err := getGoProject(projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []goModule{{path: "example.com/api", dir: filepath.Join(projectDir, "api")}, {path: "example.com/server", dir: filepath.Join(projectDir, "server")}}, project.modules)
+	assert.ElementsMatch(t, []string{"example.com/api", "example.com/server", "example.com/utils"}, project.firstPartyModules.ToSlice())
+	assert.Equal(t, []string{"example.com/utils"}, project.getLocalDependencies())
+
+	// Without a workspace, the project directory is the only module
+	project, err = getGoProject(filepath.Join(projectDir, "api"))
+	assert.NoError(t, err)
+	assert.Equal(t, []goModule{{path: "example.com/api", dir: filepath.Join(projectDir, "api")}}, project.modules)
+	assert.Empty(t, project.getLocalDependencies())
+}
+
+func TestGetVendoredModules(t *testing.T) {
+	projectDir := t.TempDir()
+	project := &goProject{modules: []goModule{{path: "example.com/server"}}, firstPartyModules: datastructures.MakeSet[string]()}
+
+	// Not vendored
+	dependenciesList, explicitModules, err := project.getVendoredModules(projectDir)
+	assert.NoError(t, err)
+	assert.Nil(t, dependenciesList)
+	assert.Empty(t, explicitModules)
+
+	modulesTxt := `# example.com/utils v1.0.0 => ../utils
+## explicit; go 1.21
+example.com/utils
+# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# golang.org/x/text v0.3.3
+golang.org/x/text/transform
+# rsc.io/quote v1.5.2 => rsc.io/quote v1.5.1
+## explicit
+rsc.io/quote
+`
+	writeTestFile(t, filepath.Join(projectDir, vendorDirName, vendorModulesFileName), modulesTxt)
+	dependenciesList, explicitModules, err = project.getVendoredModules(projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"github.com/pkg/errors:v0.9.1": true, "golang.org/x/text:v0.3.3": true, "rsc.io/quote:v1.5.2": true}, dependenciesList)
+	assert.Equal(t, []string{"github.com/pkg/errors:v0.9.1", "rsc.io/quote:v1.5.2"}, explicitModules)
+	assert.True(t, project.firstPartyModules.Exists("example.com/utils"))
+
+	dependenciesGraph := project.getVendoredDependenciesGraph(dependenciesList, explicitModules)
+	assert.Equal(t, map[string][]string{"example.com/server": {"github.com/pkg/errors:v0.9.1", "rsc.io/quote:v1.5.2", "golang.org/x/text:v0.3.3"}}, dependenciesGraph)
+}
+
+func TestPopulateGoDependencyTreeWithFirstPartyModules(t *testing.T) {
+	dependenciesGraph := map[string][]string{
+		"example.com/server":       {"example.com/api:v0.0.0", "example.com/utils:v1.0.0", "github.com/pkg/errors:v0.9.1"},
+		"example.com/api":          {"golang.org/x/text:v0.3.3", "example.com/server:v0.0.0"},
+		"example.com/utils:v1.0.0": {"rsc.io/quote:v1.5.2", "github.com/pkg/errors:v0.9.1"},
+		"rsc.io/quote:v1.5.2":      {"rsc.io/sampler:v1.3.0"},
+	}
+	dependenciesList := map[string]bool{
+		"github.com/pkg/errors:v0.9.1": true,
+		"golang.org/x/text:v0.3.3":     true,
+		"rsc.io/quote:v1.5.2":          true,
+		"rsc.io/sampler:v1.3.0":        true,
+	}
+	firstPartyModules := datastructures.MakeSet[string]()
+	firstPartyModules.AddElements("example.com/server", "example.com/api", "example.com/utils")
+	uniqueDeps := datastructures.MakeSet[string]()
+	rootNode := &xrayUtils.GraphNode{Id: goPackageTypeIdentifier + "example.com/server"}
+	populateGoDependencyTree(rootNode, dependenciesGraph, dependenciesList, firstPartyModules, uniqueDeps)
+
+	// The dependencies of the workspace module and the locally replaced module are added to the root, without duplicates
+	var directDependencies []string
+	for _, node := range rootNode.Nodes {
+		directDependencies = append(directDependencies, node.Id)
+	}
+	assert.Equal(t, []string{goPackageTypeIdentifier + "golang.org/x/text:v0.3.3", goPackageTypeIdentifier + "rsc.io/quote:v1.5.2", goPackageTypeIdentifier + "github.com/pkg/errors:v0.9.1"}, directDependencies)
+	assert.Len(t, rootNode.Nodes[1].Nodes, 1)
+	assert.ElementsMatch(t, []string{
+		goPackageTypeIdentifier + "example.com/server",
+		goPackageTypeIdentifier + "golang.org/x/text:v0.3.3",
+		goPackageTypeIdentifier + "rsc.io/quote:v1.5.2",
+		goPackageTypeIdentifier + "rsc.io/sampler:v1.3.0",
+		goPackageTypeIdentifier + "github.com/pkg/errors:v0.9.1",
+	}, uniqueDeps.ToSlice())
+}
@@ -0,0 +1,331 @@
+package _go
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+const (
+	reachabilityDocsUrlSuffix      = "contextual-analysis"
+	reachabilityPackagesLoadMode   = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedModule
+	applicabilityPropertyName      = "applicability"
+	applicableStatusProperty       = "applicable"
+	notApplicableStatusProperty    = "not_applicable"
+	undeterminedStatusProperty     = "undetermined"
+	reachabilityCallStackSeparator = " -> "
+)
+
+// A call of the project code which leads to a vulnerable symbol
+type reachabilityEvidence struct {
+	symbol    string
+	callStack []string
+	position  token.Position
+}
+
+// Determines whether the vulnerable symbols of the Go dependencies in the Xray results are reachable from the entry points of the project, using its call graph.
+// The entry points are the main packages of the project, or the exported functions of its packages if it has no main packages.
+// The results are returned as applicability SARIF runs, the same as the Contextual Analysis scanner results.
+// CVEs without known vulnerable symbols are not included in the results.
+// CVEs whose symbols are only quoted in the Xray research information are undetermined if none of the symbols is reachable, since the quoted symbols may be partial.
+func RunReachabilityAnalysis(projectDir string, xrayResults []services.ScanResponse, osvFilePath string) (runs []*sarif.Run, err error) {
+	cvesSymbols, structuredCves, err := getVulnerableSymbols(xrayResults, osvFilePath)
+	if err != nil {
+		return
+	}
+	cves := getCvesWithSymbols(cvesSymbols)
+	if len(cves) == 0 {
+		log.Debug(fmt.Sprintf("Couldn't find the vulnerable symbols of the Go vulnerabilities in '%s'. Skipping reachability analysis...", projectDir))
+		return
+	}
+	project, err := getGoProject(projectDir)
+	if err != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("Running Go reachability analysis of %d CVEs in '%s'...", len(cves), projectDir))
+	program, projectPackages, err := buildSsaProgram(projectDir, project)
+	if err != nil {
+		return
+	}
+	callGraph := vta.CallGraph(ssautil.AllFunctions(program), cha.CallGraph(program))
+	reachable := getReachableFunctions(callGraph, getEntryPoints(program, projectPackages))
+	projectPackagesPaths := datastructures.MakeSet[string]()
+	for _, projectPackage := range projectPackages {
+		projectPackagesPaths.Add(projectPackage.Pkg.Path())
+	}
+	run := sarif.NewRunWithInformationURI(jasutils.GoReachabilityToolName, utils.BaseDocumentationURL+reachabilityDocsUrlSuffix)
+	run.Invocations = []*sarif.Invocation{sarif.NewInvocation().WithWorkingDirectory(sarif.NewSimpleArtifactLocation(projectDir))}
+	for _, cve := range cves {
+		addReachabilityRule(run, cve, cvesSymbols[cve], structuredCves.Exists(cve), getReachabilityEvidences(program.Fset, reachable, projectPackagesPaths, cvesSymbols[cve]))
+	}
+	runs = append(runs, run)
+	log.Info("Found", sarifutils.GetRulesPropertyCount(applicabilityPropertyName, applicableStatusProperty, runs...), "reachable Go CVEs in", projectDir)
+	return
+}
+
+// Loads the packages of the project modules and their dependencies, and builds their SSA representation.
+// Returns the program and the SSA packages of the project modules.
+func buildSsaProgram(projectDir string, project *goProject) (program *ssa.Program, projectPackages []*ssa.Package, err error) {
+	config := &packages.Config{Mode: reachabilityPackagesLoadMode, Dir: projectDir}
+	if len(project.modules) > 1 {
+		config.Env = append(os.Environ(), "GOFLAGS="+removeModFlag(os.Getenv("GOFLAGS")))
+	}
+	var patterns []string
+	for _, module := range project.modules {
+		patterns = append(patterns, module.path+"/...")
+	}
+	loadedPackages, err := packages.Load(config, patterns...)
+	if err != nil {
+		return nil, nil, errorutils.CheckErrorf("failed to load the Go packages in '%s': %s", projectDir, err.Error())
+	}
+	var loadErrors []error
+	packages.Visit(loadedPackages, nil, func(pkg *packages.Package) {
+		for _, pkgErr := range pkg.Errors {
+			loadErrors = append(loadErrors, pkgErr)
+		}
+	})
+	if len(loadErrors) > 0 {
+		return nil, nil, errorutils.CheckErrorf("failed to load the Go packages in '%s':\n%s", projectDir, errors.Join(loadErrors...).Error())
+	}
+	program, ssaPackages := ssautil.AllPackages(loadedPackages, ssa.InstantiateGenerics)
+	program.Build()
+	for _, ssaPackage := range ssaPackages {
+		if ssaPackage != nil {
+			projectPackages = append(projectPackages, ssaPackage)
+		}
+	}
+	return
+}
+
+// Returns the main and init functions of the main packages of the project.
+// If the project has no main packages, it is a library, and all its exported functions and methods are entry points.
+func getEntryPoints(program *ssa.Program, projectPackages []*ssa.Package) (entryPoints []*ssa.Function) {
+	mainPackages := ssautil.MainPackages(projectPackages)
+	if len(mainPackages) > 0 {
+		for _, mainPackage := range mainPackages {
+			entryPoints = append(entryPoints, mainPackage.Func("main"), mainPackage.Func("init"))
+		}
+		return
+	}
+	for _, projectPackage := range projectPackages {
+		if initFunc := projectPackage.Func("init"); initFunc != nil {
+			entryPoints = append(entryPoints, initFunc)
+		}
+		for name, member := range projectPackage.Members {
+			if !token.IsExported(name) {
+				continue
+			}
+			switch member := member.(type) {
+			case *ssa.Function:
+				entryPoints = append(entryPoints, member)
+			case *ssa.Type:
+				methodSet := program.MethodSets.MethodSet(types.NewPointer(member.Type()))
+				for i := 0; i < methodSet.Len(); i++ {
+					if method := program.MethodValue(methodSet.At(i)); method != nil && method.Object() != nil && method.Object().Exported() {
+						entryPoints = append(entryPoints, method)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// Traverses the call graph from the entry points, and returns the reachable functions with the call edge they were first reached by.
+// The entry points are mapped to a nil edge.
+func getReachableFunctions(callGraph *callgraph.Graph, entryPoints []*ssa.Function) (reachable map[*ssa.Function]*callgraph.Edge) {
+	reachable = map[*ssa.Function]*callgraph.Edge{}
+	var queue []*callgraph.Node
+	for _, entryPoint := range entryPoints {
+		node := callGraph.Nodes[entryPoint]
+		if _, visited := reachable[entryPoint]; node == nil || visited {
+			continue
+		}
+		reachable[entryPoint] = nil
+		queue = append(queue, node)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, edge := range node.Out {
+			if _, visited := reachable[edge.Callee.Func]; visited {
+				continue
+			}
+			reachable[edge.Callee.Func] = edge
+			queue = append(queue, edge.Callee)
+		}
+	}
+	return
+}
+
+// Returns the calls of the project code that lead to the vulnerable symbols, one for each call site.
+func getReachabilityEvidences(fileSet *token.FileSet, reachable map[*ssa.Function]*callgraph.Edge, projectPackagesPaths *datastructures.Set[string], symbols vulnerableSymbols) (evidences []reachabilityEvidence) {
+	positions := datastructures.MakeSet[string]()
+	for function := range reachable {
+		packagePath, symbol := getFunctionSymbol(function)
+		if packagePath == "" || projectPackagesPaths.Exists(packagePath) || !symbols.isVulnerable(packagePath, symbol) {
+			continue
+		}
+		callPath := getCallPath(reachable, function)
+		evidence := reachabilityEvidence{symbol: packagePath + "." + symbol, position: getProjectCallPosition(fileSet, callPath, projectPackagesPaths)}
+		positionKey := fmt.Sprintf("%s:%s", evidence.symbol, evidence.position)
+		if positions.Exists(positionKey) {
+			continue
+		}
+		positions.Add(positionKey)
+		for _, edge := range callPath {
+			if len(evidence.callStack) == 0 {
+				evidence.callStack = append(evidence.callStack, getFunctionDisplayName(edge.Caller.Func))
+			}
+			evidence.callStack = append(evidence.callStack, getFunctionDisplayName(edge.Callee.Func))
+		}
+		evidences = append(evidences, evidence)
+	}
+	sort.Slice(evidences, func(i, j int) bool {
+		if evidences[i].position.Filename != evidences[j].position.Filename {
+			return evidences[i].position.Filename < evidences[j].position.Filename
+		}
+		if evidences[i].position.Line != evidences[j].position.Line {
+			return evidences[i].position.Line < evidences[j].position.Line
+		}
+		return evidences[i].symbol < evidences[j].symbol
+	})
+	return
+}
+
+// Returns the call edges from an entry point to the given function
+func getCallPath(reachable map[*ssa.Function]*callgraph.Edge, function *ssa.Function) (callPath []*callgraph.Edge) {
+	for edge := reachable[function]; edge != nil; edge = reachable[edge.Caller.Func] {
+		callPath = append([]*callgraph.Edge{edge}, callPath...)
+	}
+	return
+}
+
+// Returns the position of the last call in the project code along the call path, which is the call that leads out of the project to the vulnerable code.
+func getProjectCallPosition(fileSet *token.FileSet, callPath []*callgraph.Edge, projectPackagesPaths *datastructures.Set[string]) (position token.Position) {
+	for i := len(callPath) - 1; i >= 0; i-- {
+		if packagePath, _ := getFunctionSymbol(callPath[i].Caller.Func); !projectPackagesPaths.Exists(packagePath) {
+			continue
+		}
+		if position = fileSet.Position(callPath[i].Pos()); position.IsValid() {
+			return
+		}
+		// Implicit calls, such as the initialization of the imported packages, are reported at the calling function
+		if position = fileSet.Position(callPath[i].Caller.Func.Pos()); position.IsValid() {
+			return
+		}
+	}
+	return
+}
+
+// Returns the import path of the package of the function and its symbol name, as used by the Go vulnerability database: <function> or <type>.<method>.
+// Anonymous functions are attributed to the function that declares them. Synthetic wrappers have no symbol.
+func getFunctionSymbol(function *ssa.Function) (packagePath, symbol string) {
+	if origin := function.Origin(); origin != nil {
+		function = origin
+	}
+	for function.Parent() != nil {
+		function = function.Parent()
+	}
+	if function.Pkg == nil {
+		return
+	}
+	packagePath = function.Pkg.Pkg.Path()
+	symbol = function.Name()
+	if receiver := function.Signature.Recv(); receiver != nil {
+		receiverType := receiver.Type()
+		if pointer, ok := receiverType.(*types.Pointer); ok {
+			receiverType = pointer.Elem()
+		}
+		if named, ok := receiverType.(*types.Named); ok {
+			symbol = named.Obj().Name() + "." + symbol
+		}
+	}
+	return
+}
+
+func getFunctionDisplayName(function *ssa.Function) string {
+	if packagePath, symbol := getFunctionSymbol(function); packagePath != "" {
+		return packagePath + "." + symbol
+	}
+	return function.String()
+}
+
+func addReachabilityRule(run *sarif.Run, cve string, symbols vulnerableSymbols, structuredSymbols bool, evidences []reachabilityEvidence) {
+	ruleId := jasutils.CveToApplicabilityRuleId(cve)
+	status := undeterminedStatusProperty
+	switch {
+	case len(evidences) > 0:
+		status = applicableStatusProperty
+	case structuredSymbols:
+		status = notApplicableStatusProperty
+	}
+	rule := run.AddRule(ruleId).WithName(cve).WithShortDescription(sarif.NewMultiformatMessageString("Scanner for " + cve))
+	rule.WithFullDescription(sarif.NewMultiformatMessageString(getReachabilityRuleDescription(symbols)))
+	rule.Properties = sarif.Properties{applicabilityPropertyName: status}
+	for _, evidence := range evidences {
+		result := run.CreateResultForRule(ruleId).WithMessage(sarif.NewTextMessage(fmt.Sprintf("The vulnerable function %s is reachable: %s", evidence.symbol, strings.Join(evidence.callStack, reachabilityCallStackSeparator))))
+		if !evidence.position.IsValid() {
+			continue
+		}
+		physicalLocation := sarifutils.NewPhysicalLocationWithRegion("file://"+evidence.position.Filename, evidence.position.Line, evidence.position.Line, evidence.position.Column, evidence.position.Column)
+		physicalLocation.Region.Snippet = sarif.NewArtifactContent().WithText(getSourceLine(evidence.position))
+		result.AddLocation(sarif.NewLocationWithPhysicalLocation(physicalLocation))
+	}
+}
+
+func getReachabilityRuleDescription(symbols vulnerableSymbols) string {
+	var functions []string
+	for packagePath, packageSymbols := range symbols {
+		if packageSymbols.Size() == 0 {
+			functions = append(functions, fmt.Sprintf("-  Any function of `%s`", packagePath))
+			continue
+		}
+		for _, symbol := range packageSymbols.ToSlice() {
+			functions = append(functions, fmt.Sprintf("-  `%s.%s()`", packagePath, symbol))
+		}
+	}
+	sort.Strings(functions)
+	return "The scanner checks whether any of the following vulnerable functions are reachable from the entry points of the project:\n\n" + strings.Join(functions, "\n")
+}
+
+// Returns the trimmed source line of the position, used as the snippet of the evidence
+func getSourceLine(position token.Position) (line string) {
+	file, err := os.Open(position.Filename)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to read the source line of %s: %s", position, err.Error()))
+		return
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Debug(fmt.Sprintf("Failed to close %s: %s", position.Filename, closeErr.Error()))
+		}
+	}()
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if lineNumber == position.Line {
+			return strings.TrimSpace(scanner.Text())
+		}
+	}
+	return
+}
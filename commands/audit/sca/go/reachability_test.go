@@ -0,0 +1,116 @@
+package _go
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createReachabilityTestProject(t *testing.T) (projectDir string) {
+	rootDir := t.TempDir()
+	projectDir = filepath.Join(rootDir, "app")
+	writeTestFile(t, filepath.Join(projectDir, goModFileName), "module example.com/app\n\ngo 1.21\n\nrequire example.com/vuln v1.0.0\n\nreplace example.com/vuln => ../vuln\n")
+	writeTestFile(t, filepath.Join(projectDir, "main.go"), `package main
+
+import "example.com/vuln"
+
+func main() {
+	run()
+}
+
+func run() {
+	vuln.Parse("input")
+	var decoder vuln.Reader = &vuln.Decoder{}
+	decoder.Read()
+}
+`)
+	writeTestFile(t, filepath.Join(rootDir, "vuln", goModFileName), "module example.com/vuln\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(rootDir, "vuln", "vuln.go"), `package vuln
+
+type Reader interface {
+	Read() string
+}
+
+type Decoder struct{}
+
+func (d *Decoder) Read() string {
+	return "decoded"
+}
+
+func Parse(input string) string {
+	return input
+}
+
+func Unused() {}
+`)
+	return
+}
+
+func TestRunReachabilityAnalysis(t *testing.T) {
+	projectDir := createReachabilityTestProject(t)
+	osvFile := filepath.Join(t.TempDir(), "osv.json")
+	writeTestFile(t, osvFile, `[
+  {"id": "GO-2024-0002", "aliases": ["CVE-2024-0002"], "affected": [{"package": {"name": "example.com/vuln", "ecosystem": "Go"}, "ecosystem_specific": {"imports": [{"path": "example.com/vuln", "symbols": ["Unused"]}]}}]},
+  {"id": "GO-2024-0003", "aliases": ["CVE-2024-0003"], "affected": [{"package": {"name": "example.com/vuln", "ecosystem": "Go"}, "ecosystem_specific": {"imports": [{"path": "example.com/vuln", "symbols": ["Decoder.Read"]}]}}]},
+  {"id": "GO-2024-0005", "aliases": ["CVE-2024-0005"], "affected": [{"package": {"name": "example.com/other", "ecosystem": "Go"}, "ecosystem_specific": {"imports": [{"path": "example.com/other"}]}}]}
+]`)
+	xrayResults := []services.ScanResponse{{Vulnerabilities: []services.Vulnerability{
+		{
+			Cves:                []services.Cve{{Id: "CVE-2024-0001"}},
+			Components:          map[string]services.Component{"go://example.com/vuln:v1.0.0": {}},
+			ExtendedInformation: &services.ExtendedInformation{FullDescription: "Calling `example.com/vuln.Parse` with untrusted input may cause a panic."},
+		},
+		{Cves: []services.Cve{{Id: "CVE-2024-0002"}, {Id: "CVE-2024-0003"}}, Components: map[string]services.Component{"go://example.com/vuln:v1.0.0": {}}},
+		// The quoted symbols may be partial, so an unreachable quoted symbol doesn't make the CVE not applicable
+		{
+			Cves:                []services.Cve{{Id: "CVE-2024-0006"}},
+			Components:          map[string]services.Component{"go://example.com/vuln:v1.0.0": {}},
+			ExtendedInformation: &services.ExtendedInformation{FullDescription: "The `example.com/vuln.Unused` function is vulnerable."},
+		},
+		// Without known vulnerable symbols
+		{Cves: []services.Cve{{Id: "CVE-2024-0004"}}, Components: map[string]services.Component{"go://example.com/vuln:v1.0.0": {}}},
+	}}}
+
+	runs, err := RunReachabilityAnalysis(projectDir, xrayResults, osvFile)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	run := runs[0]
+	assert.Equal(t, jasutils.GoReachabilityToolName, sarifutils.GetRunToolName(run))
+	assert.Equal(t, projectDir, sarifutils.GetInvocationWorkingDirectory(run.Invocations[0]))
+
+	expectedStatuses := map[string]string{"CVE-2024-0001": "applicable", "CVE-2024-0002": "not_applicable", "CVE-2024-0003": "applicable", "CVE-2024-0006": "undetermined"}
+	assert.Len(t, sarifutils.GetRunRules(run), len(expectedStatuses))
+	for cve, status := range expectedStatuses {
+		rule, err := run.GetRuleById(jasutils.CveToApplicabilityRuleId(cve))
+		require.NoError(t, err, cve)
+		assert.Equal(t, status, rule.Properties["applicability"], cve)
+	}
+
+	result, err := run.GetResultByRuleId(jasutils.CveToApplicabilityRuleId("CVE-2024-0001"))
+	require.NoError(t, err)
+	assert.Equal(t, "The vulnerable function example.com/vuln.Parse is reachable: example.com/app.main -> example.com/app.run -> example.com/vuln.Parse", sarifutils.GetResultMsgText(result))
+	require.Len(t, result.Locations, 1)
+	assert.Equal(t, "main.go", sarifutils.GetRelativeLocationFileName(result.Locations[0], run.Invocations))
+	assert.Equal(t, 10, sarifutils.GetLocationStartLine(result.Locations[0]))
+	assert.Equal(t, `vuln.Parse("input")`, sarifutils.GetLocationSnippet(result.Locations[0]))
+
+	// The interface call is resolved by the call graph
+	result, err = run.GetResultByRuleId(jasutils.CveToApplicabilityRuleId("CVE-2024-0003"))
+	require.NoError(t, err)
+	assert.Equal(t, 12, sarifutils.GetLocationStartLine(result.Locations[0]))
+}
+
+func TestGetQuotedSymbols(t *testing.T) {
+	text := "The `golang.org/x/net/html.Parse` and `golang.org/x/net/http2.Server.ServeConn` functions are vulnerable, unlike `golang.org/x/text.Transform`. Also `net/http.ServeContent` and `fmt`."
+	assert.Equal(t, []string{"golang.org/x/net/html.Parse", "golang.org/x/net/http2.Server.ServeConn"}, getQuotedSymbols([]string{"golang.org/x/net"}, text))
+	assert.Equal(t, []string{"net/http.ServeContent"}, getQuotedSymbols([]string{goStdlibModule}, text))
+
+	packagePath, symbol := splitQualifiedSymbol("golang.org/x/net/http2.Server.ServeConn")
+	assert.Equal(t, "golang.org/x/net/http2", packagePath)
+	assert.Equal(t, "Server.ServeConn", symbol)
+}
@@ -0,0 +1,207 @@
+package _go
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	goStdlibModule = "github.com/golang/go"
+	osvStdlibName  = "stdlib"
+)
+
+// Matches a qualified Go symbol quoted in the vulnerability description, for example: `golang.org/x/net/html.Parse` or `net/http.Server.ServeTLS`
+var quotedSymbolRegex = regexp.MustCompile("`([A-Za-z0-9_.\\-~/]+\\.[A-Za-z_][A-Za-z0-9_]*(?:\\.[A-Za-z_][A-Za-z0-9_]*)?)`")
+
+// The vulnerable symbols of a CVE, by the import path of the vulnerable package.
+// A package without symbols means that all the symbols of the package are vulnerable.
+type vulnerableSymbols map[string]*datastructures.Set[string]
+
+func (vs vulnerableSymbols) add(packagePath string, symbols ...string) {
+	if _, exists := vs[packagePath]; !exists {
+		vs[packagePath] = datastructures.MakeSet[string]()
+	}
+	vs[packagePath].AddElements(symbols...)
+}
+
+func (vs vulnerableSymbols) isVulnerable(packagePath, symbol string) bool {
+	symbols, exists := vs[packagePath]
+	return exists && (symbols.Size() == 0 || symbols.Exists(symbol))
+}
+
+// An OSV entry, as published by the Go vulnerability database (https://vuln.go.dev).
+type osvEntry struct {
+	Id       string        `json:"id"`
+	Aliases  []string      `json:"aliases,omitempty"`
+	Affected []osvAffected `json:"affected,omitempty"`
+}
+
+type osvAffected struct {
+	Package           osvPackage           `json:"package"`
+	EcosystemSpecific osvEcosystemSpecific `json:"ecosystem_specific"`
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvEcosystemSpecific struct {
+	Imports []osvImport `json:"imports,omitempty"`
+}
+
+type osvImport struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// Returns the vulnerable symbols of the CVEs of the Go components in the Xray results.
+// The symbols are taken from the OSV entries in the given file, if provided, and from the symbols quoted in the Xray research information.
+// The symbols quoted in the research information may be partial, so only the CVEs with symbols from the OSV entries are returned as structured.
+func getVulnerableSymbols(xrayResults []services.ScanResponse, osvFilePath string) (cvesSymbols map[string]vulnerableSymbols, structuredCves *datastructures.Set[string], err error) {
+	cvesSymbols = map[string]vulnerableSymbols{}
+	structuredCves = datastructures.MakeSet[string]()
+	goCves := datastructures.MakeSet[string]()
+	for _, xrayResult := range xrayResults {
+		for _, vulnerability := range xrayResult.Vulnerabilities {
+			addXrayVulnerableSymbols(cvesSymbols, goCves, vulnerability.Cves, vulnerability.Components, vulnerability.ExtendedInformation)
+		}
+		for _, violation := range xrayResult.Violations {
+			addXrayVulnerableSymbols(cvesSymbols, goCves, violation.Cves, violation.Components, violation.ExtendedInformation)
+		}
+	}
+	if osvFilePath == "" || goCves.Size() == 0 {
+		return
+	}
+	entries, err := readOsvEntries(osvFilePath)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		for _, id := range append([]string{entry.Id}, entry.Aliases...) {
+			if !goCves.Exists(id) {
+				continue
+			}
+			if _, exists := cvesSymbols[id]; !exists {
+				cvesSymbols[id] = vulnerableSymbols{}
+			}
+			for _, affected := range entry.Affected {
+				for _, imported := range affected.EcosystemSpecific.Imports {
+					cvesSymbols[id].add(imported.Path, imported.Symbols...)
+					structuredCves.Add(id)
+				}
+			}
+		}
+	}
+	return
+}
+
+func addXrayVulnerableSymbols(cvesSymbols map[string]vulnerableSymbols, goCves *datastructures.Set[string], cves []services.Cve, components map[string]services.Component, extendedInformation *services.ExtendedInformation) {
+	var modules []string
+	for componentId := range components {
+		if strings.HasPrefix(componentId, goPackageTypeIdentifier) {
+			name, _, _ := utils.SplitComponentId(componentId)
+			modules = append(modules, name)
+		}
+	}
+	if len(modules) == 0 {
+		return
+	}
+	var symbols []string
+	if extendedInformation != nil {
+		symbols = getQuotedSymbols(modules, extendedInformation.FullDescription+"\n"+extendedInformation.Remediation)
+	}
+	for _, cve := range cves {
+		if cve.Id == "" {
+			continue
+		}
+		goCves.Add(cve.Id)
+		for _, symbol := range symbols {
+			packagePath, name := splitQualifiedSymbol(symbol)
+			if _, exists := cvesSymbols[cve.Id]; !exists {
+				cvesSymbols[cve.Id] = vulnerableSymbols{}
+			}
+			cvesSymbols[cve.Id].add(packagePath, name)
+		}
+	}
+}
+
+// Returns the qualified symbols quoted in the text which belong to packages of the given modules.
+func getQuotedSymbols(modules []string, text string) (symbols []string) {
+	for _, match := range quotedSymbolRegex.FindAllStringSubmatch(text, -1) {
+		packagePath, name := splitQualifiedSymbol(match[1])
+		if name == "" {
+			continue
+		}
+		for _, module := range modules {
+			if isModulePackage(module, packagePath) {
+				symbols = append(symbols, match[1])
+				break
+			}
+		}
+	}
+	return
+}
+
+// Splits a qualified symbol to its package import path and the symbol name.
+// The symbol name is the part after the first dot of the last path element, for example: net/http.Server.ServeTLS -> net/http, Server.ServeTLS
+func splitQualifiedSymbol(qualifiedSymbol string) (packagePath, symbol string) {
+	lastSlash := strings.LastIndex(qualifiedSymbol, "/")
+	dot := strings.Index(qualifiedSymbol[lastSlash+1:], ".")
+	if dot < 0 {
+		return qualifiedSymbol, ""
+	}
+	dot += lastSlash + 1
+	return qualifiedSymbol[:dot], qualifiedSymbol[dot+1:]
+}
+
+// The standard library packages are part of the Go module, and their import paths don't start with a domain name
+func isModulePackage(module, packagePath string) bool {
+	if module == goStdlibModule || module == osvStdlibName {
+		return !strings.Contains(strings.Split(packagePath, "/")[0], ".")
+	}
+	return packagePath == module || strings.HasPrefix(packagePath, module+"/")
+}
+
+// Reads the OSV entries from a JSON file that contains a single entry or an array of entries.
+func readOsvEntries(osvFilePath string) (entries []osvEntry, err error) {
+	content, err := os.ReadFile(osvFilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	content = []byte(strings.TrimSpace(string(content)))
+	if len(content) > 0 && content[0] == '[' {
+		err = json.Unmarshal(content, &entries)
+	} else {
+		var entry osvEntry
+		if err = json.Unmarshal(content, &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the vulnerable symbols file '%s': %s", osvFilePath, err.Error())
+	}
+	log.Debug(fmt.Sprintf("Read %d OSV entries from '%s'", len(entries), osvFilePath))
+	return
+}
+
+// Returns the CVEs with known vulnerable symbols, sorted.
+func getCvesWithSymbols(cvesSymbols map[string]vulnerableSymbols) (cves []string) {
+	for cve, symbols := range cvesSymbols {
+		if len(symbols) > 0 {
+			cves = append(cves, cve)
+		}
+	}
+	sort.Strings(cves)
+	return
+}
@@ -11,10 +11,13 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/slices"
 )
 
 const (
 	GavPackageTypeIdentifier = "gav://"
+	mavenProvidedScope       = "provided"
+	mavenTestScope           = "test"
 )
 
 func BuildDependencyTree(depTreeParams DepTreeParams, tech techutils.Technology) ([]*xrayUtils.GraphNode, map[string]*xray.DepTreeNode, error) {
@@ -61,12 +64,75 @@ func getGraphFromDepTree(outputFilePaths string) (depsGraph []*xrayUtils.GraphNo
 		moduleTree, moduleUniqueDeps := GetModuleTreeAndDependencies(module)
 		depsGraph = append(depsGraph, moduleTree)
 		for depToAdd, depTypes := range moduleUniqueDeps {
-			uniqueDepsMap[depToAdd] = depTypes
+			uniqueDepsMap[depToAdd] = mergeDepTreeNodeConfigurations(uniqueDepsMap[depToAdd], depTypes)
 		}
 	}
 	return
 }
 
+// A dependency may be used in several modules, with different scopes/configurations in each.
+func mergeDepTreeNodeConfigurations(existing, toAdd *xray.DepTreeNode) *xray.DepTreeNode {
+	if existing == nil || existing.Configurations == nil || toAdd == nil {
+		return toAdd
+	}
+	merged := *toAdd
+	configurations := append([]string{}, *existing.Configurations...)
+	if toAdd.Configurations != nil {
+		for _, configuration := range *toAdd.Configurations {
+			if !slices.Contains(configurations, configuration) {
+				configurations = append(configurations, configuration)
+			}
+		}
+	}
+	merged.Configurations = &configurations
+	return &merged
+}
+
+// Returns the dependency scopes (runtime, dev or test) of the dependencies, based on their Maven scopes or Gradle configurations.
+func GetDependenciesScopes(uniqueDepsMap map[string]*xray.DepTreeNode, tech techutils.Technology) map[string][]string {
+	dependenciesScopes := map[string][]string{}
+	for dependencyId, dependency := range uniqueDepsMap {
+		if dependency == nil || dependency.Configurations == nil {
+			continue
+		}
+		for _, configuration := range *dependency.Configurations {
+			scope := getMavenScope(configuration)
+			if tech == techutils.Gradle {
+				scope = getGradleScope(configuration)
+			}
+			if !slices.Contains(dependenciesScopes[dependencyId], scope) {
+				dependenciesScopes[dependencyId] = append(dependenciesScopes[dependencyId], scope)
+			}
+		}
+	}
+	return dependenciesScopes
+}
+
+// compile, runtime and system dependencies are packaged with the application, provided dependencies are supplied by the runtime environment.
+func getMavenScope(mavenScope string) string {
+	switch strings.ToLower(mavenScope) {
+	case mavenTestScope:
+		return techutils.TestScope
+	case mavenProvidedScope:
+		return techutils.DevScope
+	default:
+		return techutils.RuntimeScope
+	}
+}
+
+// Gradle configurations are named after their source set, for example: 'runtimeClasspath', 'testRuntimeClasspath' and 'annotationProcessor'.
+func getGradleScope(configuration string) string {
+	configuration = strings.ToLower(configuration)
+	switch {
+	case strings.Contains(configuration, "test"):
+		return techutils.TestScope
+	case strings.Contains(configuration, "runtime"):
+		return techutils.RuntimeScope
+	default:
+		return techutils.DevScope
+	}
+}
+
 // Returns a dependency tree and a flat list of the module's dependencies for the given module
 func GetModuleTreeAndDependencies(module *moduleDepTree) (*xrayUtils.GraphNode, map[string]*xray.DepTreeNode) {
 	moduleTreeMap := make(map[string]xray.DepTreeNode)
@@ -79,9 +145,10 @@ func GetModuleTreeAndDependencies(module *moduleDepTree) (*xrayUtils.GraphNode,
 			childrenList = append(childrenList, childId)
 		}
 		moduleTreeMap[dependencyId] = xray.DepTreeNode{
-			Classifier: dependency.Classifier,
-			Types:      dependency.Types,
-			Children:   childrenList,
+			Classifier:     dependency.Classifier,
+			Types:          dependency.Types,
+			Configurations: dependency.Configurations,
+			Children:       childrenList,
 		}
 	}
 	return xray.BuildXrayDependencyTree(moduleTreeMap, GavPackageTypeIdentifier+module.Root)
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,3 +66,35 @@ func TestGetGradleGraphFromDepTree(t *testing.T) {
 		assert.Equal(t, len(depChild), len(dependency.Nodes))
 	}
 }
+
+func TestGetDependenciesScopes(t *testing.T) {
+	mavenDeps := map[string]*xray.DepTreeNode{
+		"gav://a:a:1.0": {Configurations: &[]string{"compile"}},
+		"gav://b:b:1.0": {Configurations: &[]string{"provided"}},
+		"gav://c:c:1.0": {Configurations: &[]string{"test", "runtime"}},
+		"gav://d:d:1.0": {},
+	}
+	scopes := GetDependenciesScopes(mavenDeps, techutils.Maven)
+	assert.Equal(t, []string{techutils.RuntimeScope}, scopes["gav://a:a:1.0"])
+	assert.Equal(t, []string{techutils.DevScope}, scopes["gav://b:b:1.0"])
+	assert.Equal(t, []string{techutils.TestScope, techutils.RuntimeScope}, scopes["gav://c:c:1.0"])
+	assert.NotContains(t, scopes, "gav://d:d:1.0")
+
+	gradleDeps := map[string]*xray.DepTreeNode{
+		"gav://a:a:1.0": {Configurations: &[]string{"compileClasspath", "runtimeClasspath"}},
+		"gav://b:b:1.0": {Configurations: &[]string{"annotationProcessor"}},
+		"gav://c:c:1.0": {Configurations: &[]string{"testRuntimeClasspath", "testCompileClasspath"}},
+	}
+	scopes = GetDependenciesScopes(gradleDeps, techutils.Gradle)
+	assert.Equal(t, []string{techutils.DevScope, techutils.RuntimeScope}, scopes["gav://a:a:1.0"])
+	assert.Equal(t, []string{techutils.DevScope}, scopes["gav://b:b:1.0"])
+	assert.Equal(t, []string{techutils.TestScope}, scopes["gav://c:c:1.0"])
+}
+
+func TestMergeDepTreeNodeConfigurations(t *testing.T) {
+	existing := &xray.DepTreeNode{Configurations: &[]string{"compile"}}
+	merged := mergeDepTreeNodeConfigurations(existing, &xray.DepTreeNode{Configurations: &[]string{"test", "compile"}})
+	assert.Equal(t, []string{"compile", "test"}, *merged.Configurations)
+	assert.Equal(t, []string{"compile"}, *existing.Configurations)
+	assert.Nil(t, mergeDepTreeNodeConfigurations(nil, nil))
+}
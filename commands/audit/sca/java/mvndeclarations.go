@@ -0,0 +1,540 @@
+package java
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/descriptorutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+const (
+	pomFileName        = "pom.xml"
+	defaultParentPath  = ".." + string(filepath.Separator) + pomFileName
+	mavenImportScope   = "import"
+	mavenPomType       = "pom"
+	maxPomsHierarchies = 10
+)
+
+var (
+	pomPropertyRegex = regexp.MustCompile(`\$\{([^}]+)}`)
+	// Matches the local repository system property, for example: -Dmaven.repo.local=/path/to/repository or -Dmaven.repo.local="/path/to/repository"
+	repoLocalPropertyRegex = regexp.MustCompile(`-Dmaven\.repo\.local=("[^"]*"|\S+)`)
+)
+
+// An element of a pom file, with its trimmed text and location
+type pomElement struct {
+	value    string
+	location formats.Location
+}
+
+type pomDependency struct {
+	groupId    string
+	artifactId string
+	scope      string
+	depType    string
+	version    *pomElement
+}
+
+type pomParent struct {
+	groupId      string
+	artifactId   string
+	version      *pomElement
+	relativePath *string
+}
+
+type pomFile struct {
+	path                string
+	groupId             string
+	artifactId          string
+	version             *pomElement
+	parent              *pomParent
+	properties          map[string]*pomElement
+	dependencies        []*pomDependency
+	managedDependencies []*pomDependency
+	modules             []string
+}
+
+// A pom in the inheritance hierarchy of a module.
+// Poms that aren't part of the project, such as parents from the local repository, can't be edited. Their declarations are located at the reference to them, the parent element of the last project pom.
+type hierarchyPom struct {
+	pom       *pomFile
+	reference *pomElement
+}
+
+// Locates the declarations of the dependencies versions in the poms of a Maven project and in the poms of its parents and imported BOMs.
+type mavenDeclarationsResolver struct {
+	localRepository string
+	// The project poms by their paths
+	projectPoms map[string]*pomFile
+	// The poms from the local repository by their coordinates
+	repositoryPoms map[string]*pomFile
+}
+
+// Returns the location where the version of each direct dependency of the project modules is declared, by the module ID and the dependency ID.
+// The locations are resolved from the module poms, their parents and imported BOMs, which are read from the local Maven repository.
+// A dependency used by several modules may be declared differently in each of them, so the declarations are resolved from each module's own hierarchy.
+func GetMavenDependenciesDeclarations(projectDir string, modulesTrees []*xrayUtils.GraphNode) (declarations map[string]map[string]*formats.Declaration) {
+	declarations = map[string]map[string]*formats.Declaration{}
+	resolver, err := newMavenDeclarationsResolver(projectDir)
+	if err != nil {
+		log.Debug("Couldn't locate the Maven local repository:", err.Error())
+		return
+	}
+	modules, err := resolver.getProjectModules(filepath.Join(projectDir, pomFileName))
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't read the poms of the Maven project in '%s' to locate the dependencies declarations: %s", projectDir, err.Error()))
+	}
+	for _, moduleTree := range modulesTrees {
+		module, exists := modules[strings.TrimPrefix(moduleTree.Id, GavPackageTypeIdentifier)]
+		if !exists {
+			continue
+		}
+		hierarchy := resolver.getHierarchy(module)
+		moduleDeclarations := map[string]*formats.Declaration{}
+		for _, dependency := range moduleTree.Nodes {
+			coordinates := strings.TrimPrefix(dependency.Id, GavPackageTypeIdentifier)
+			if _, isModule := modules[coordinates]; isModule {
+				continue
+			}
+			groupId, artifactId, _ := splitCoordinates(coordinates)
+			if declaration := resolver.getDeclaration(hierarchy, groupId, artifactId); declaration != nil {
+				moduleDeclarations[dependency.Id] = declaration
+			}
+		}
+		if len(moduleDeclarations) > 0 {
+			declarations[moduleTree.Id] = moduleDeclarations
+		}
+	}
+	return
+}
+
+func newMavenDeclarationsResolver(projectDir string) (*mavenDeclarationsResolver, error) {
+	localRepository, err := getMavenLocalRepository(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug(fmt.Sprintf("Reading the poms of the parents and imported BOMs from the Maven local repository: %s", localRepository))
+	return &mavenDeclarationsResolver{
+		localRepository: localRepository,
+		projectPoms:     map[string]*pomFile{},
+		repositoryPoms:  map[string]*pomFile{},
+	}, nil
+}
+
+// Returns the path of the Maven local repository, with the same precedence as Maven:
+// The maven.repo.local system property in the project .mvn/maven.config and .mvn/jvm.config files or in MAVEN_OPTS, then the localRepository of the user settings.xml, then of the global settings.xml, and then the default ~/.m2/repository.
+func getMavenLocalRepository(projectDir string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	options := []string{os.Getenv("MAVEN_OPTS")}
+	for _, configFile := range []string{filepath.Join(projectDir, ".mvn", "maven.config"), filepath.Join(projectDir, ".mvn", "jvm.config")} {
+		if content, readErr := os.ReadFile(configFile); readErr == nil {
+			options = append([]string{string(content)}, options...)
+		}
+	}
+	for _, option := range options {
+		if match := repoLocalPropertyRegex.FindStringSubmatch(option); match != nil {
+			return resolveLocalRepositoryPath(strings.Trim(match[1], `"`), homeDir), nil
+		}
+	}
+	settingsFiles := []string{filepath.Join(homeDir, ".m2", settingsXmlFile)}
+	for _, mavenHomeEnv := range []string{"MAVEN_HOME", "M2_HOME"} {
+		if mavenHome := os.Getenv(mavenHomeEnv); mavenHome != "" {
+			settingsFiles = append(settingsFiles, filepath.Join(mavenHome, "conf", settingsXmlFile))
+			break
+		}
+	}
+	for _, settingsFile := range settingsFiles {
+		if localRepository := readSettingsLocalRepository(settingsFile); localRepository != "" {
+			return resolveLocalRepositoryPath(localRepository, homeDir), nil
+		}
+	}
+	return filepath.Join(homeDir, ".m2", "repository"), nil
+}
+
+// Returns the localRepository of the settings file, or an empty string if the file doesn't exist or doesn't set it.
+func readSettingsLocalRepository(settingsFile string) string {
+	content, err := os.ReadFile(settingsFile)
+	if err != nil {
+		return ""
+	}
+	var settings struct {
+		LocalRepository string `xml:"localRepository"`
+	}
+	if err = xml.Unmarshal(content, &settings); err != nil {
+		log.Debug(fmt.Sprintf("Couldn't read the local repository from %s: %s", settingsFile, err.Error()))
+		return ""
+	}
+	return strings.TrimSpace(settings.LocalRepository)
+}
+
+// Resolves the user home and environment variables references in the local repository path, as Maven does.
+func resolveLocalRepositoryPath(localRepository, homeDir string) string {
+	localRepository = pomPropertyRegex.ReplaceAllStringFunc(localRepository, func(reference string) string {
+		name := reference[2 : len(reference)-1]
+		if name == "user.home" {
+			return homeDir
+		}
+		if envName, isEnv := strings.CutPrefix(name, "env."); isEnv {
+			return os.Getenv(envName)
+		}
+		return reference
+	})
+	if localRepository == "~" || strings.HasPrefix(localRepository, "~/") {
+		localRepository = filepath.Join(homeDir, localRepository[1:])
+	}
+	return filepath.Clean(localRepository)
+}
+
+// Reads the root pom and the poms of its modules, recursively. Returns the poms by their coordinates (groupId:artifactId:version).
+func (mdr *mavenDeclarationsResolver) getProjectModules(rootPomPath string) (modules map[string]*pomFile, err error) {
+	modules = map[string]*pomFile{}
+	pomsToRead := []string{rootPomPath}
+	for len(pomsToRead) > 0 {
+		pomPath := pomsToRead[0]
+		pomsToRead = pomsToRead[1:]
+		if _, read := mdr.projectPoms[pomPath]; read {
+			continue
+		}
+		pom, readErr := mdr.getProjectPom(pomPath)
+		if readErr != nil {
+			err = errors.Join(err, readErr)
+			continue
+		}
+		modules[mdr.getCoordinates(pom)] = pom
+		for _, module := range pom.modules {
+			modulePath := filepath.Join(filepath.Dir(pomPath), module)
+			if !strings.HasSuffix(modulePath, ".xml") {
+				modulePath = filepath.Join(modulePath, pomFileName)
+			}
+			pomsToRead = append(pomsToRead, modulePath)
+		}
+	}
+	return
+}
+
+func (mdr *mavenDeclarationsResolver) getProjectPom(pomPath string) (pom *pomFile, err error) {
+	if pom = mdr.projectPoms[pomPath]; pom != nil {
+		return
+	}
+	if pom, err = parsePomFile(pomPath); err != nil {
+		return
+	}
+	mdr.projectPoms[pomPath] = pom
+	return
+}
+
+// Returns a pom from the local repository, or nil if it doesn't exist there
+func (mdr *mavenDeclarationsResolver) getRepositoryPom(groupId, artifactId, version string) *pomFile {
+	coordinates := strings.Join([]string{groupId, artifactId, version}, ":")
+	if pom, read := mdr.repositoryPoms[coordinates]; read {
+		return pom
+	}
+	pomPath := filepath.Join(mdr.localRepository, filepath.FromSlash(strings.ReplaceAll(groupId, ".", "/")), artifactId, version, fmt.Sprintf("%s-%s.pom", artifactId, version))
+	pom, err := parsePomFile(pomPath)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't read %s from the local repository: %s", coordinates, err.Error()))
+	}
+	mdr.repositoryPoms[coordinates] = pom
+	return pom
+}
+
+// Returns the inheritance hierarchy of the pom, starting with the pom itself.
+func (mdr *mavenDeclarationsResolver) getHierarchy(pom *pomFile) (hierarchy []hierarchyPom) {
+	hierarchy = []hierarchyPom{{pom: pom}}
+	var reference *pomElement
+	for current := pom; current.parent != nil && len(hierarchy) < maxPomsHierarchies; {
+		parent := mdr.getProjectParent(current)
+		if parent == nil {
+			if reference == nil {
+				reference = current.parent.version
+			}
+			parentVersion := ""
+			if current.parent.version != nil {
+				parentVersion = interpolate(hierarchy, current.parent.version.value)
+			}
+			if parent = mdr.getRepositoryPom(current.parent.groupId, current.parent.artifactId, parentVersion); parent == nil {
+				return
+			}
+		}
+		hierarchy = append(hierarchy, hierarchyPom{pom: parent, reference: reference})
+		current = parent
+	}
+	return
+}
+
+// Returns the parent pom if it's part of the project, located by the relative path of the parent.
+func (mdr *mavenDeclarationsResolver) getProjectParent(pom *pomFile) *pomFile {
+	if _, isProjectPom := mdr.projectPoms[pom.path]; !isProjectPom {
+		return nil
+	}
+	relativePath := defaultParentPath
+	if pom.parent.relativePath != nil {
+		relativePath = *pom.parent.relativePath
+	}
+	if relativePath == "" {
+		return nil
+	}
+	parentPath := filepath.Join(filepath.Dir(pom.path), relativePath)
+	if !strings.HasSuffix(parentPath, ".xml") {
+		parentPath = filepath.Join(parentPath, pomFileName)
+	}
+	if !fileutils.IsPathExists(parentPath, false) {
+		return nil
+	}
+	parent, err := mdr.getProjectPom(parentPath)
+	if err != nil {
+		log.Debug(err.Error())
+		return nil
+	}
+	if parent.artifactId != pom.parent.artifactId {
+		return nil
+	}
+	return parent
+}
+
+// Returns where the version of the dependency is declared, following the Maven precedence:
+// The module dependencies and the dependencies inherited from its parents, then their managed dependencies and then the managed dependencies of their imported BOMs.
+func (mdr *mavenDeclarationsResolver) getDeclaration(hierarchy []hierarchyPom, groupId, artifactId string) *formats.Declaration {
+	for i, current := range hierarchy {
+		for _, dependency := range current.pom.dependencies {
+			if dependency.version != nil && isDependency(hierarchy[i:], dependency, groupId, artifactId) {
+				return getVersionDeclaration(hierarchy, i, dependency.version)
+			}
+		}
+	}
+	for i, current := range hierarchy {
+		for _, dependency := range current.pom.managedDependencies {
+			if dependency.version != nil && !isBomImport(dependency) && isDependency(hierarchy[i:], dependency, groupId, artifactId) {
+				return getVersionDeclaration(hierarchy, i, dependency.version)
+			}
+		}
+	}
+	for i, current := range hierarchy {
+		for _, dependency := range current.pom.managedDependencies {
+			if dependency.version == nil || !isBomImport(dependency) {
+				continue
+			}
+			bomGroupId, bomArtifactId, bomVersion := interpolate(hierarchy[i:], dependency.groupId), interpolate(hierarchy[i:], dependency.artifactId), interpolate(hierarchy, dependency.version.value)
+			if mdr.isManagedByBom(bomGroupId, bomArtifactId, bomVersion, groupId, artifactId, 0) {
+				declaration := getVersionDeclaration(hierarchy, i, dependency.version)
+				declaration.Type = formats.BomDeclaration
+				declaration.Bom = strings.Join([]string{bomGroupId, bomArtifactId, bomVersion}, ":")
+				return declaration
+			}
+		}
+	}
+	return nil
+}
+
+// Checks whether the BOM, or the BOMs it imports, manage the version of the dependency
+func (mdr *mavenDeclarationsResolver) isManagedByBom(bomGroupId, bomArtifactId, bomVersion, groupId, artifactId string, depth int) bool {
+	bom := mdr.getRepositoryPom(bomGroupId, bomArtifactId, bomVersion)
+	if bom == nil || depth >= maxPomsHierarchies {
+		return false
+	}
+	hierarchy := mdr.getHierarchy(bom)
+	for i, current := range hierarchy {
+		for _, dependency := range current.pom.managedDependencies {
+			if !isBomImport(dependency) {
+				if isDependency(hierarchy[i:], dependency, groupId, artifactId) {
+					return true
+				}
+				continue
+			}
+			if dependency.version != nil && mdr.isManagedByBom(interpolate(hierarchy[i:], dependency.groupId), interpolate(hierarchy[i:], dependency.artifactId), interpolate(hierarchy, dependency.version.value), groupId, artifactId, depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the declaration of the version element of the pom in the given index of the hierarchy.
+// If the version refers to a property, the property declaration is returned instead. Properties are resolved from the module, as Maven does.
+func getVersionDeclaration(hierarchy []hierarchyPom, index int, version *pomElement) *formats.Declaration {
+	declarationType := formats.ModuleDeclaration
+	if index > 0 {
+		declarationType = formats.ParentDeclaration
+	}
+	element := version
+	if hierarchy[index].reference != nil {
+		element = hierarchy[index].reference
+	} else if match := pomPropertyRegex.FindStringSubmatch(version.value); match != nil && match[0] == version.value {
+		for _, current := range hierarchy {
+			if property, exists := current.pom.properties[match[1]]; exists {
+				declarationType = formats.PropertyDeclaration
+				element = property
+				if current.reference != nil {
+					declarationType = formats.ParentDeclaration
+					element = current.reference
+				}
+				break
+			}
+		}
+	}
+	return &formats.Declaration{Location: element.location, Type: declarationType}
+}
+
+func isDependency(hierarchy []hierarchyPom, dependency *pomDependency, groupId, artifactId string) bool {
+	return interpolate(hierarchy, dependency.artifactId) == artifactId && interpolate(hierarchy, dependency.groupId) == groupId
+}
+
+func isBomImport(dependency *pomDependency) bool {
+	return dependency.scope == mavenImportScope && dependency.depType == mavenPomType
+}
+
+// Replaces the property references in the value with the properties of the hierarchy, and the project coordinates of its first pom.
+func interpolate(hierarchy []hierarchyPom, value string) string {
+	if !strings.Contains(value, "${") || len(hierarchy) == 0 {
+		return value
+	}
+	return pomPropertyRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		name := reference[2 : len(reference)-1]
+		pom := hierarchy[0].pom
+		switch name {
+		case "project.groupId", "pom.groupId", "groupId":
+			return getGroupId(pom)
+		case "project.artifactId", "pom.artifactId", "artifactId":
+			return pom.artifactId
+		case "project.version", "pom.version", "version":
+			return interpolate(hierarchy, getVersion(pom))
+		case "project.parent.version", "parent.version":
+			if pom.parent != nil && pom.parent.version != nil {
+				return pom.parent.version.value
+			}
+		}
+		for _, current := range hierarchy {
+			if property, exists := current.pom.properties[name]; exists {
+				return interpolate(hierarchy, property.value)
+			}
+		}
+		return reference
+	})
+}
+
+func (mdr *mavenDeclarationsResolver) getCoordinates(pom *pomFile) string {
+	hierarchy := mdr.getHierarchy(pom)
+	return strings.Join([]string{interpolate(hierarchy, getGroupId(pom)), pom.artifactId, interpolate(hierarchy, getVersion(pom))}, ":")
+}
+
+// The groupId and version of a pom are inherited from its parent, if not set
+func getGroupId(pom *pomFile) string {
+	if pom.groupId == "" && pom.parent != nil {
+		return pom.parent.groupId
+	}
+	return pom.groupId
+}
+
+func getVersion(pom *pomFile) string {
+	if pom.version != nil {
+		return pom.version.value
+	}
+	if pom.parent != nil && pom.parent.version != nil {
+		return pom.parent.version.value
+	}
+	return ""
+}
+
+func splitCoordinates(coordinates string) (groupId, artifactId, version string) {
+	parts := strings.Split(coordinates, ":")
+	if len(parts) < 3 {
+		return
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+// Parses the elements of the pom that are used to locate the dependencies declarations, along with their locations.
+func parsePomFile(pomPath string) (pom *pomFile, err error) {
+	content, err := os.ReadFile(pomPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	pom = &pomFile{path: pomPath, properties: map[string]*pomElement{}}
+	var dependency *pomDependency
+	if err = descriptorutils.ParsePomElements(pomPath, content, func(elementsPath []string, text string, location formats.Location) {
+		dependency = pom.setElement(elementsPath, &pomElement{value: text, location: location}, dependency)
+	}); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Sets the element in the pom. The elements of a dependency end before the dependency itself, so the dependency that is currently parsed is created by its first element, and returned until the dependency ends.
+func (pom *pomFile) setElement(elementsPath []string, element *pomElement, dependency *pomDependency) *pomDependency {
+	path := strings.Join(elementsPath, "/")
+	switch path {
+	case "project/groupId":
+		pom.groupId = element.value
+	case "project/artifactId":
+		pom.artifactId = element.value
+	case "project/version":
+		pom.version = element
+	case "project/parent/groupId", "project/parent/artifactId", "project/parent/version", "project/parent/relativePath":
+		if pom.parent == nil {
+			pom.parent = &pomParent{}
+		}
+		switch elementsPath[2] {
+		case "groupId":
+			pom.parent.groupId = element.value
+		case "artifactId":
+			pom.parent.artifactId = element.value
+		case "version":
+			pom.parent.version = element
+		case "relativePath":
+			pom.parent.relativePath = &element.value
+		}
+	case "project/modules/module":
+		pom.modules = append(pom.modules, element.value)
+	case "project/dependencies/dependency", "project/dependencyManagement/dependencies/dependency":
+		if dependency == nil {
+			dependency = &pomDependency{}
+		}
+		if path == "project/dependencies/dependency" {
+			pom.dependencies = append(pom.dependencies, dependency)
+		} else {
+			pom.managedDependencies = append(pom.managedDependencies, dependency)
+		}
+		return nil
+	default:
+		// The properties and the dependencies fields are the direct children of their elements
+		name := elementsPath[len(elementsPath)-1]
+		switch strings.TrimSuffix(path, "/"+name) {
+		case "project/properties":
+			pom.properties[name] = element
+		case "project/dependencies/dependency", "project/dependencyManagement/dependencies/dependency":
+			if dependency == nil {
+				dependency = &pomDependency{}
+			}
+			dependency.setElement(name, element)
+		}
+	}
+	return dependency
+}
+
+func (dependency *pomDependency) setElement(name string, element *pomElement) {
+	switch name {
+	case "groupId":
+		dependency.groupId = element.value
+	case "artifactId":
+		dependency.artifactId = element.value
+	case "version":
+		dependency.version = element
+	case "scope":
+		dependency.scope = element.value
+	case "type":
+		dependency.depType = element.value
+	}
+}
@@ -0,0 +1,230 @@
+package java
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/formats"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	declarationsRootPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <groupId>com.example</groupId>
+  <artifactId>root</artifactId>
+  <version>1.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>app</module>
+    <module>lib</module>
+  </modules>
+  <properties>
+    <guava.version>32.0</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-core</artifactId>
+        <version>2.15.0</version>
+      </dependency>
+      <dependency>
+        <groupId>org.acme</groupId>
+        <artifactId>acme-bom</artifactId>
+        <version>2.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>
+`
+	declarationsAppPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>1.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-core</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.acme</groupId>
+      <artifactId>acme-lib</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>commons-io</groupId>
+      <artifactId>commons-io</artifactId>
+      <version>2.11.0</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+	declarationsLibPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>1.0</version>
+  </parent>
+  <artifactId>lib</artifactId>
+  <properties>
+    <commons-io.version>2.11.0</commons-io.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>commons-io</groupId>
+      <artifactId>commons-io</artifactId>
+      <version>${commons-io.version}</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+	declarationsBomPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <groupId>org.acme</groupId>
+  <artifactId>acme-bom</artifactId>
+  <version>2.0</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>${project.groupId}</groupId>
+        <artifactId>acme-lib</artifactId>
+        <version>2.0</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>
+`
+)
+
+func writePomFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestGetMavenDependenciesDeclarations(t *testing.T) {
+	projectDir := t.TempDir()
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("MAVEN_OPTS", "")
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("M2_HOME", "")
+	rootPom := filepath.Join(projectDir, pomFileName)
+	appPom := filepath.Join(projectDir, "app", pomFileName)
+	libPom := filepath.Join(projectDir, "lib", pomFileName)
+	writePomFile(t, rootPom, declarationsRootPom)
+	writePomFile(t, appPom, declarationsAppPom)
+	writePomFile(t, libPom, declarationsLibPom)
+	writePomFile(t, filepath.Join(homeDir, ".m2", "repository", "org", "acme", "acme-bom", "2.0", "acme-bom-2.0.pom"), declarationsBomPom)
+
+	modulesTrees := []*xrayUtils.GraphNode{
+		{Id: GavPackageTypeIdentifier + "com.example:root:1.0"},
+		{Id: GavPackageTypeIdentifier + "com.example:app:1.0", Nodes: []*xrayUtils.GraphNode{
+			{Id: GavPackageTypeIdentifier + "com.google.guava:guava:32.0"},
+			{Id: GavPackageTypeIdentifier + "com.fasterxml.jackson.core:jackson-core:2.15.0"},
+			{Id: GavPackageTypeIdentifier + "org.acme:acme-lib:2.0"},
+			{Id: GavPackageTypeIdentifier + "commons-io:commons-io:2.11.0"},
+			// Resolved by Maven, but its version isn't declared in the project
+			{Id: GavPackageTypeIdentifier + "org.unknown:unknown:1.0"},
+		}},
+		{Id: GavPackageTypeIdentifier + "com.example:lib:1.0", Nodes: []*xrayUtils.GraphNode{
+			{Id: GavPackageTypeIdentifier + "commons-io:commons-io:2.11.0"},
+		}},
+	}
+	declarations := GetMavenDependenciesDeclarations(projectDir, modulesTrees)
+	require.Len(t, declarations, 2)
+	require.Len(t, declarations[GavPackageTypeIdentifier+"com.example:app:1.0"], 4)
+
+	testCases := []struct {
+		module          string
+		dependency      string
+		expectedFile    string
+		expectedType    formats.DeclarationType
+		expectedLine    int
+		expectedSnippet string
+		expectedBom     string
+	}{
+		{module: "com.example:app:1.0", dependency: "com.google.guava:guava:32.0", expectedFile: rootPom, expectedType: formats.PropertyDeclaration, expectedLine: 12, expectedSnippet: "<guava.version>32.0</guava.version>"},
+		{module: "com.example:app:1.0", dependency: "com.fasterxml.jackson.core:jackson-core:2.15.0", expectedFile: rootPom, expectedType: formats.ParentDeclaration, expectedLine: 24, expectedSnippet: "<version>2.15.0</version>"},
+		{module: "com.example:app:1.0", dependency: "org.acme:acme-lib:2.0", expectedFile: rootPom, expectedType: formats.BomDeclaration, expectedLine: 29, expectedSnippet: "<version>2.0</version>", expectedBom: "org.acme:acme-bom:2.0"},
+		{module: "com.example:app:1.0", dependency: "commons-io:commons-io:2.11.0", expectedFile: appPom, expectedType: formats.ModuleDeclaration, expectedLine: 25, expectedSnippet: "<version>2.11.0</version>"},
+		// The same dependency is declared differently in each module
+		{module: "com.example:lib:1.0", dependency: "commons-io:commons-io:2.11.0", expectedFile: libPom, expectedType: formats.PropertyDeclaration, expectedLine: 10, expectedSnippet: "<commons-io.version>2.11.0</commons-io.version>"},
+	}
+	for _, test := range testCases {
+		t.Run(test.module+"/"+test.dependency, func(t *testing.T) {
+			declaration := declarations[GavPackageTypeIdentifier+test.module][GavPackageTypeIdentifier+test.dependency]
+			require.NotNil(t, declaration)
+			assert.Equal(t, test.expectedFile, declaration.File)
+			assert.Equal(t, test.expectedType, declaration.Type)
+			assert.Equal(t, test.expectedLine, declaration.StartLine)
+			assert.Equal(t, test.expectedLine, declaration.EndLine)
+			assert.Equal(t, test.expectedSnippet, declaration.Snippet)
+			assert.Equal(t, test.expectedBom, declaration.Bom)
+		})
+	}
+}
+
+func TestParsePomFileLocations(t *testing.T) {
+	pomPath := filepath.Join(t.TempDir(), pomFileName)
+	writePomFile(t, pomPath, declarationsAppPom)
+	pom, err := parsePomFile(pomPath)
+	require.NoError(t, err)
+	assert.Equal(t, "app", pom.artifactId)
+	assert.Equal(t, "com.example", getGroupId(pom))
+	assert.Equal(t, "1.0", getVersion(pom))
+	require.Len(t, pom.dependencies, 4)
+	version := pom.dependencies[3].version
+	require.NotNil(t, version)
+	assert.Equal(t, "2.11.0", version.value)
+	assert.Equal(t, 25, version.location.StartLine)
+	assert.Equal(t, 7, version.location.StartColumn)
+	assert.Equal(t, 32, version.location.EndColumn)
+}
+
+func TestGetMavenLocalRepository(t *testing.T) {
+	projectDir := t.TempDir()
+	homeDir := t.TempDir()
+	mavenHome := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("MAVEN_OPTS", "")
+	t.Setenv("MAVEN_HOME", mavenHome)
+	t.Setenv("M2_HOME", "")
+	t.Setenv("REPOSITORIES_DIR", filepath.Join(homeDir, "repositories"))
+	assertLocalRepository := func(expected string) {
+		localRepository, err := getMavenLocalRepository(projectDir)
+		require.NoError(t, err)
+		assert.Equal(t, expected, localRepository)
+	}
+
+	assertLocalRepository(filepath.Join(homeDir, ".m2", "repository"))
+	// Global settings
+	writePomFile(t, filepath.Join(mavenHome, "conf", "settings.xml"), "<settings><localRepository>${env.REPOSITORIES_DIR}/global</localRepository></settings>")
+	assertLocalRepository(filepath.Join(homeDir, "repositories", "global"))
+	// User settings
+	writePomFile(t, filepath.Join(homeDir, ".m2", "settings.xml"), "<settings>\n  <localRepository>${user.home}/user-repository</localRepository>\n</settings>")
+	assertLocalRepository(filepath.Join(homeDir, "user-repository"))
+	// System property
+	t.Setenv("MAVEN_OPTS", "-Xmx1g -Dmaven.repo.local=/opts/repository")
+	assertLocalRepository(filepath.Clean("/opts/repository"))
+	writePomFile(t, filepath.Join(projectDir, ".mvn", "maven.config"), `-B -Dmaven.repo.local="/project/repository"`)
+	assertLocalRepository(filepath.Clean("/project/repository"))
+}
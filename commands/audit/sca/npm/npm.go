@@ -9,10 +9,11 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/npm"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
-	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -20,7 +21,7 @@ const (
 	IgnoreScriptsFlag = "--ignore-scripts"
 )
 
-func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, dependenciesScopes map[string][]string, err error) {
 	currentDir, err := coreutils.GetWorkingDirectory()
 	if err != nil {
 		return
@@ -57,9 +58,13 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils
 	for _, dependency := range dependenciesMap {
 		dependenciesList = append(dependenciesList, dependency.Dependency)
 	}
-	// Parse the dependencies into Xray dependency tree format
-	dependencyTree, uniqueDeps := parseNpmDependenciesList(dependenciesList, packageInfo)
-	dependencyTrees = []*xrayUtils.GraphNode{dependencyTree}
+	membersNames, err := GetWorkspaceMembersNames(currentDir)
+	if err != nil {
+		return
+	}
+	// Parse the dependencies into Xray dependency tree format, with a tree for each workspace member
+	dependencyTrees, uniqueDeps = parseNpmDependenciesList(dependenciesList, packageInfo, membersNames)
+	dependenciesScopes = getDependenciesScopes(dependenciesList)
 	return
 }
 
@@ -104,8 +109,9 @@ func addIgnoreScriptsFlag(npmArgs []string) []string {
 	return npmArgs
 }
 
-// Parse the dependencies into an Xray dependency tree format
-func parseNpmDependenciesList(dependencies []buildinfo.Dependency, packageInfo *biutils.PackageInfo) (*xrayUtils.GraphNode, []string) {
+// Parse the dependencies into an Xray dependency tree format.
+// In workspaces, a tree is created for the root package and for each of the workspace members.
+func parseNpmDependenciesList(dependencies []buildinfo.Dependency, packageInfo *biutils.PackageInfo, workspaceMembers []string) ([]*xrayUtils.GraphNode, []string) {
 	treeMap := make(map[string]xray.DepTreeNode)
 	for _, dependency := range dependencies {
 		dependencyId := utils.NpmPackageTypeIdentifier + dependency.Id
@@ -120,8 +126,76 @@ func parseNpmDependenciesList(dependencies []buildinfo.Dependency, packageInfo *
 			treeMap[parent] = depTreeNode
 		}
 	}
-	graph, nodeMapTypes := xray.BuildXrayDependencyTree(treeMap, utils.NpmPackageTypeIdentifier+packageInfo.BuildInfoModuleId())
-	return graph, maps.Keys(nodeMapTypes)
+	return BuildWorkspaceDependencyTrees(treeMap, utils.NpmPackageTypeIdentifier+packageInfo.BuildInfoModuleId(), workspaceMembers)
+}
+
+// Map the npm scopes of the dependencies ('prod' and 'dev') to the dependency scopes.
+func getDependenciesScopes(dependencies []buildinfo.Dependency) map[string][]string {
+	dependenciesScopes := map[string][]string{}
+	for _, dependency := range dependencies {
+		var scopes []string
+		for _, npmScope := range dependency.Scopes {
+			var scope string
+			switch npmScope {
+			case "prod":
+				scope = techutils.RuntimeScope
+			case "dev":
+				scope = techutils.DevScope
+			default:
+				// The package scope of scoped packages (for example: '@types')
+				continue
+			}
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+		if len(scopes) > 0 {
+			dependenciesScopes[utils.NpmPackageTypeIdentifier+dependency.Id] = scopes
+		}
+	}
+	return dependenciesScopes
+}
+
+// Returns the scopes of the direct dependencies, by their declaration in the package.json file of the given directory and of its workspace members.
+// Used by package managers that don't resolve the scope of each dependency (Yarn and pnpm).
+func GetDirectDependenciesScopes(packageJsonDirectory string) (map[string][]string, error) {
+	membersDirs, err := techutils.GetJavaScriptWorkspaceMembers(packageJsonDirectory)
+	if err != nil {
+		return nil, err
+	}
+	directScopes := map[string][]string{}
+	for _, directory := range append([]string{packageJsonDirectory}, membersDirs...) {
+		packageScopes, err := getPackageJsonDirectDependenciesScopes(directory)
+		if err != nil {
+			return nil, err
+		}
+		// Different workspace members may declare the same dependency in different scopes
+		for name, scopes := range packageScopes {
+			for _, scope := range scopes {
+				if !slices.Contains(directScopes[name], scope) {
+					directScopes[name] = append(directScopes[name], scope)
+				}
+			}
+		}
+	}
+	return directScopes, nil
+}
+
+func getPackageJsonDirectDependenciesScopes(packageJsonDirectory string) (map[string][]string, error) {
+	packageInfo, err := biutils.ReadPackageInfoFromPackageJsonIfExists(packageJsonDirectory, nil)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	directScopes := map[string][]string{}
+	for name := range packageInfo.DevDependencies {
+		directScopes[name] = []string{techutils.DevScope}
+	}
+	for _, dependencies := range []map[string]string{packageInfo.Dependencies, packageInfo.PeerDependencies, packageInfo.OptionalDependencies} {
+		for name := range dependencies {
+			directScopes[name] = []string{techutils.RuntimeScope}
+		}
+	}
+	return directScopes, nil
 }
 
 func appendUniqueChild(children []string, candidateDependency string) []string {
@@ -8,12 +8,14 @@ import (
 
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 
 	biutils "github.com/jfrog/build-info-go/build/utils"
 	buildinfo "github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseNpmDependenciesList(t *testing.T) {
@@ -98,7 +100,9 @@ func TestParseNpmDependenciesList(t *testing.T) {
 		},
 	}
 
-	xrayDependenciesTree, uniqueDeps := parseNpmDependenciesList(dependencies, packageInfo)
+	xrayDependenciesTrees, uniqueDeps := parseNpmDependenciesList(dependencies, packageInfo, nil)
+	require.Len(t, xrayDependenciesTrees, 1)
+	xrayDependenciesTree := xrayDependenciesTrees[0]
 	equals := tests.CompareTree(expectedTree, xrayDependenciesTree)
 	if !equals {
 		t.Error("expected:", expectedTree.Nodes, "got:", xrayDependenciesTree.Nodes)
@@ -119,6 +123,38 @@ func TestIgnoreScripts(t *testing.T) {
 	// The package.json file contain a postinstall script running an "exit 1" command.
 	// Without the "--ignore-scripts" flag, the test will fail.
 	params := &utils.AuditBasicParams{}
-	_, _, err := BuildDependencyTree(params)
+	_, _, _, err := BuildDependencyTree(params)
 	assert.NoError(t, err)
 }
+
+func TestGetDependenciesScopes(t *testing.T) {
+	dependencies := []buildinfo.Dependency{
+		{Id: "lodash:4.17.21", Scopes: []string{"prod"}},
+		{Id: "@types/node:20.0.0", Scopes: []string{"dev", "@types"}},
+		{Id: "debug:4.3.4", Scopes: []string{"dev", "prod"}},
+		{Id: "unknown:1.0.0"},
+	}
+	assert.Equal(t, map[string][]string{
+		"npm://lodash:4.17.21":     {techutils.RuntimeScope},
+		"npm://@types/node:20.0.0": {techutils.DevScope},
+		"npm://debug:4.3.4":        {techutils.DevScope, techutils.RuntimeScope},
+	}, getDependenciesScopes(dependencies))
+}
+
+func TestGetDirectDependenciesScopes(t *testing.T) {
+	projectDir := t.TempDir()
+	packageJson := `{"name": "app", "version": "1.0.0", "dependencies": {"lodash": "^4.17.21"}, "devDependencies": {"jest": "^29.0.0", "lodash": "^4.17.21"}, "peerDependencies": {"react": "^18.0.0"}}`
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "package.json"), []byte(packageJson), 0644))
+	scopes, err := GetDirectDependenciesScopes(projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"lodash": {techutils.RuntimeScope},
+		"jest":   {techutils.DevScope},
+		"react":  {techutils.RuntimeScope},
+	}, scopes)
+
+	// No package.json
+	scopes, err = GetDirectDependenciesScopes(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, scopes)
+}
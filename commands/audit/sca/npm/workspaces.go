@@ -0,0 +1,94 @@
+package npm
+
+import (
+	"strings"
+
+	biutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Returns the package names of the members of the npm, Yarn or pnpm workspace in the given directory.
+// Returns an empty list if the directory isn't a workspace root.
+func GetWorkspaceMembersNames(rootDir string) (names []string, err error) {
+	membersDirs, err := techutils.GetJavaScriptWorkspaceMembers(rootDir)
+	if err != nil {
+		return
+	}
+	for _, memberDir := range membersDirs {
+		packageInfo, readErr := biutils.ReadPackageInfoFromPackageJsonIfExists(memberDir, nil)
+		if errorutils.CheckError(readErr) != nil {
+			return nil, readErr
+		}
+		if name := packageInfo.FullName(); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// Builds a dependency tree for the workspace root and for each of the workspace members, from the dependencies of all the workspace packages.
+// The package managers resolve the members as direct dependencies of the root. They are removed from the root tree, so that each dependency is attributed to the member that declares it.
+// Members are matched by their package name, since some package managers report a placeholder version for them.
+func BuildWorkspaceDependencyTrees(treeMap map[string]xray.DepTreeNode, rootId string, membersNames []string) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string) {
+	var membersIds []string
+	for _, name := range membersNames {
+		if memberId := findPackageId(treeMap, name); memberId != "" && memberId != rootId {
+			membersIds = append(membersIds, memberId)
+		}
+	}
+	if rootNode, exists := treeMap[rootId]; exists && len(membersIds) > 0 {
+		var rootChildren []string
+		for _, child := range rootNode.Children {
+			if !slices.Contains(membersIds, child) {
+				rootChildren = append(rootChildren, child)
+			}
+		}
+		rootNode.Children = rootChildren
+		treeMap[rootId] = rootNode
+	}
+	uniqueDepsSet := map[string]bool{}
+	for _, treeRootId := range append([]string{rootId}, membersIds...) {
+		tree, treeDeps := xray.BuildXrayDependencyTree(treeMap, treeRootId)
+		dependencyTrees = append(dependencyTrees, tree)
+		for dependencyId := range treeDeps {
+			uniqueDepsSet[dependencyId] = true
+		}
+	}
+	return dependencyTrees, maps.Keys(uniqueDepsSet)
+}
+
+// Returns the ID of the package with the given name in the dependencies tree map, or an empty string if it doesn't exist.
+func findPackageId(treeMap map[string]xray.DepTreeNode, name string) string {
+	prefix := utils.NpmPackageTypeIdentifier + name + ":"
+	for parentId, node := range treeMap {
+		for _, id := range append([]string{parentId}, node.Children...) {
+			if strings.HasPrefix(id, prefix) {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// Returns the IDs of the roots of the dependency trees that belong to the members of the workspace in the given directory.
+func GetWorkspaceMembersIds(rootDir string, dependencyTrees []*xrayUtils.GraphNode) (membersIds []string, err error) {
+	membersNames, err := GetWorkspaceMembersNames(rootDir)
+	if err != nil || len(membersNames) == 0 {
+		return
+	}
+	for _, tree := range dependencyTrees {
+		for _, name := range membersNames {
+			if strings.HasPrefix(tree.Id, utils.NpmPackageTypeIdentifier+name+":") {
+				membersIds = append(membersIds, tree.Id)
+				break
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package npm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWorkspaceDependencyTrees(t *testing.T) {
+	treeMap := map[string]xray.DepTreeNode{
+		"npm://root:1.0.0":       {Children: []string{"npm://@scope/web:0.1.0", "npm://api:0.0.0", "npm://lodash:4.17.21"}},
+		"npm://@scope/web:0.1.0": {Children: []string{"npm://react:18.2.0", "npm://api:0.0.0"}},
+		"npm://api:0.0.0":        {Children: []string{"npm://express:4.18.2"}},
+		"npm://express:4.18.2":   {Children: []string{"npm://debug:2.6.9"}},
+	}
+	trees, uniqueDeps := BuildWorkspaceDependencyTrees(treeMap, "npm://root:1.0.0", []string{"@scope/web", "api", "missing"})
+	require.Len(t, trees, 3)
+	assert.Equal(t, "npm://root:1.0.0", trees[0].Id)
+	assert.Equal(t, []string{"npm://lodash:4.17.21"}, getNodesIds(trees[0].Nodes))
+	assert.Equal(t, "npm://@scope/web:0.1.0", trees[1].Id)
+	assert.Equal(t, []string{"npm://react:18.2.0", "npm://api:0.0.0"}, getNodesIds(trees[1].Nodes))
+	assert.Equal(t, "npm://api:0.0.0", trees[2].Id)
+	assert.Equal(t, []string{"npm://express:4.18.2"}, getNodesIds(trees[2].Nodes))
+	assert.ElementsMatch(t, []string{
+		"npm://root:1.0.0", "npm://@scope/web:0.1.0", "npm://api:0.0.0", "npm://lodash:4.17.21",
+		"npm://react:18.2.0", "npm://express:4.18.2", "npm://debug:2.6.9",
+	}, uniqueDeps)
+}
+
+func TestGetWorkspaceMembersIds(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "package.json"), []byte(`{"name": "root", "workspaces": ["packages/*"]}`), 0644))
+	for name, dir := range map[string]string{"@scope/web": "web", "api": "api"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "packages", dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, "packages", dir, "package.json"), []byte(`{"name": "`+name+`", "version": "0.1.0"}`), 0644))
+	}
+	membersNames, err := GetWorkspaceMembersNames(rootDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"@scope/web", "api"}, membersNames)
+
+	trees := []*xrayUtils.GraphNode{{Id: "npm://root:1.0.0"}, {Id: "npm://@scope/web:0.1.0"}, {Id: "npm://api:0.1.0"}}
+	membersIds, err := GetWorkspaceMembersIds(rootDir, trees)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"npm://@scope/web:0.1.0", "npm://api:0.1.0"}, membersIds)
+}
+
+func getNodesIds(nodes []*xrayUtils.GraphNode) (ids []string) {
+	for _, node := range nodes {
+		ids = append(ids, node.Id)
+	}
+	return
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/beevik/etree"
 	bidotnet "github.com/jfrog/build-info-go/build/utils/dotnet"
 	"github.com/jfrog/build-info-go/build/utils/dotnet/solution"
 	"github.com/jfrog/build-info-go/entities"
@@ -18,6 +19,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -35,6 +37,7 @@ const (
 	dotnetToolType                     = "dotnet"
 	nugetToolType                      = "nuget"
 	globalPackagesNotFoundErrorMessage = "could not find global packages path at:"
+	privateAssetsSyntax                = "PrivateAssets"
 )
 
 func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
@@ -195,6 +198,49 @@ func getProjectConfigurationFilesPaths(wd string) (projectConfigFilesPaths []str
 	return
 }
 
+// Returns the scopes of the direct dependencies, by their PackageReference declarations in the .csproj files of the given directory.
+// Packages with PrivateAssets="all" (analyzers, build tools, etc.) are not flowed to the consumers of the project, so they are dev dependencies.
+func GetDirectDependenciesScopes(wd string) (directScopes map[string][]string, err error) {
+	projectConfigFilesPaths, err := getProjectConfigurationFilesPaths(wd)
+	if err != nil {
+		return
+	}
+	directScopes = map[string][]string{}
+	for _, path := range projectConfigFilesPaths {
+		if !strings.HasSuffix(path, csprojFileSuffix) {
+			continue
+		}
+		doc := etree.NewDocument()
+		if err = doc.ReadFromFile(path); err != nil {
+			return nil, errorutils.CheckErrorf("failed to parse '%s': %s", path, err.Error())
+		}
+		for _, packageReference := range doc.FindElements("//" + packageReferenceSyntax) {
+			name := strings.ToLower(packageReference.SelectAttrValue("Include", ""))
+			if name == "" {
+				continue
+			}
+			scope := techutils.RuntimeScope
+			if isPrivateAssetsReference(packageReference) {
+				scope = techutils.DevScope
+			}
+			// A package that is a runtime dependency of one of the projects is a runtime dependency of the solution.
+			if scopes, exists := directScopes[name]; !exists || scopes[0] == techutils.DevScope {
+				directScopes[name] = []string{scope}
+			}
+		}
+	}
+	return
+}
+
+// PrivateAssets can be declared as an attribute or as a child element of the PackageReference.
+func isPrivateAssetsReference(packageReference *etree.Element) bool {
+	privateAssets := packageReference.SelectAttrValue(privateAssetsSyntax, "")
+	if element := packageReference.SelectElement(privateAssetsSyntax); element != nil {
+		privateAssets = element.Text()
+	}
+	return strings.EqualFold(strings.TrimSpace(privateAssets), "all")
+}
+
 func runDotnetRestore(wd string, params utils.AuditParams, toolType bidotnet.ToolchainType, commandExtraArgs []string) (err error) {
 	var completeCommandArgs []string
 	if len(params.InstallCommandArgs()) > 0 {
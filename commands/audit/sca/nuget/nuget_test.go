@@ -6,6 +6,7 @@ import (
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca"
 	xrayUtils2 "github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"os"
@@ -146,3 +147,29 @@ func TestRunDotnetRestoreAndLoadSolution(t *testing.T) {
 		assert.NotEmpty(t, sol.GetDependenciesSources())
 	}
 }
+
+func TestGetDirectDependenciesScopes(t *testing.T) {
+	projectDir := t.TempDir()
+	csproj := `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+    <PackageReference Include="StyleCop.Analyzers" Version="1.1.118" PrivateAssets="all" />
+    <PackageReference Include="Microsoft.SourceLink.GitHub" Version="8.0.0">
+      <PrivateAssets>All</PrivateAssets>
+    </PackageReference>
+    <PackageReference Include="Serilog" Version="3.1.1" PrivateAssets="all" />
+  </ItemGroup>
+</Project>`
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "app.csproj"), []byte(csproj), 0644))
+	// Serilog is a runtime dependency of another project in the solution
+	assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, "lib"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "lib", "lib.csproj"), []byte(`<Project><ItemGroup><PackageReference Include="Serilog" Version="3.1.1" /></ItemGroup></Project>`), 0644))
+	scopes, err := GetDirectDependenciesScopes(projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"newtonsoft.json":             {techutils.RuntimeScope},
+		"stylecop.analyzers":          {techutils.DevScope},
+		"microsoft.sourcelink.github": {techutils.DevScope},
+		"serilog":                     {techutils.RuntimeScope},
+	}, scopes)
+}
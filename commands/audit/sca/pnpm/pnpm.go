@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/gofrog/io"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca/npm"
 	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -24,6 +26,11 @@ import (
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 )
 
+const (
+	recursiveFlag      = "--recursive"
+	recursiveShortFlag = "-r"
+)
+
 type pnpmLsDependency struct {
 	From         string                      `json:"from"`
 	Version      string                      `json:"version"`
@@ -126,6 +133,12 @@ func installProjectIfNeeded(pnpmExecPath, workingDir string) (dirForDependencies
 // Run 'pnpm ls ...' command (project must be installed) and parse the returned result to create a dependencies trees for the projects.
 func calculateDependencies(executablePath, workingDir string, params utils.AuditParams) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
 	lsArgs := append([]string{"--depth", "Infinity", "--json", "--long"}, params.Args()...)
+	if isWorkspace, e := fileutils.IsFileExists(filepath.Join(workingDir, techutils.PnpmWorkspaceFileName), false); e != nil {
+		return nil, nil, e
+	} else if isWorkspace && !slices.Contains(lsArgs, recursiveFlag) && !slices.Contains(lsArgs, recursiveShortFlag) {
+		// List the dependencies of each workspace member as a separate project
+		lsArgs = append(lsArgs, recursiveFlag)
+	}
 	npmLsCmdContent, err := getPnpmCmd(executablePath, workingDir, "ls", lsArgs...).RunWithOutput()
 	if err != nil {
 		return
@@ -157,12 +170,18 @@ func createProjectDependenciesTree(project pnpmLsProject) map[string]xray.DepTre
 	directDependencies := []string{}
 	// Handle production-dependencies
 	for depName, dependency := range project.Dependencies {
+		if isWorkspaceLink(dependency) {
+			continue
+		}
 		directDependency := getDependencyId(depName, dependency.Version)
 		directDependencies = append(directDependencies, directDependency)
 		appendTransitiveDependencies(directDependency, dependency.Dependencies, treeMap)
 	}
 	// Handle dev-dependencies
 	for depName, dependency := range project.DevDependencies {
+		if isWorkspaceLink(dependency) {
+			continue
+		}
 		directDependency := getDependencyId(depName, dependency.Version)
 		directDependencies = append(directDependencies, directDependency)
 		appendTransitiveDependencies(directDependency, dependency.Dependencies, treeMap)
@@ -178,8 +197,16 @@ func getDependencyId(depName, version string) string {
 	return utils.NpmPackageTypeIdentifier + depName + ":" + version
 }
 
+// Dependencies on other members of the workspace are linked to their directories. Their dependencies are listed in their own projects.
+func isWorkspaceLink(dependency pnpmLsDependency) bool {
+	return strings.HasPrefix(dependency.Version, "link:")
+}
+
 func appendTransitiveDependencies(parent string, dependencies map[string]pnpmLsDependency, result map[string]xray.DepTreeNode) {
 	for depName, dependency := range dependencies {
+		if isWorkspaceLink(dependency) {
+			continue
+		}
 		dependencyId := getDependencyId(depName, dependency.Version)
 		if node, ok := result[parent]; ok {
 			node.Children = appendUniqueChild(node.Children, dependencyId)
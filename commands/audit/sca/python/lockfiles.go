@@ -0,0 +1,360 @@
+package python
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils/pythonutils"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	uvLockFileName  = "uv.lock"
+	pdmLockFileName = "pdm.lock"
+	// The host of the PyPI packages files, mirrored by Artifactory PyPI remote repositories under the 'packages' path
+	pypiFilesHost = "files.pythonhosted.org"
+)
+
+var (
+	Uv  = pythonutils.PythonTool(techutils.Uv)
+	Pdm = pythonutils.PythonTool(techutils.Pdm)
+
+	// The name of the package in a PEP 508 requirement, for example: 'requests[socks]>=2.31; python_version >= "3.8"'
+	pep508NameRegex   = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	pep503NormalRegex = regexp.MustCompile(`[-_.]+`)
+)
+
+// The sections of uv.lock that describe the resolved packages
+type uvLock struct {
+	Packages []uvPackage `toml:"package"`
+}
+
+type uvPackage struct {
+	Name                 string                    `toml:"name"`
+	Version              string                    `toml:"version"`
+	Source               map[string]any            `toml:"source"`
+	Dependencies         []uvDependency            `toml:"dependencies"`
+	OptionalDependencies map[string][]uvDependency `toml:"optional-dependencies"`
+	DevDependencies      map[string][]uvDependency `toml:"dev-dependencies"`
+	Sdist                *uvDistribution           `toml:"sdist"`
+	Wheels               []uvDistribution          `toml:"wheels"`
+}
+
+type uvDependency struct {
+	Name string `toml:"name"`
+	// Set only if the lock file contains several versions of the package
+	Version string `toml:"version"`
+}
+
+type uvDistribution struct {
+	Url string `toml:"url"`
+}
+
+// The project packages (the workspace members) are installed from their source directory
+func (up uvPackage) isProjectPackage() bool {
+	_, editable := up.Source["editable"]
+	_, virtual := up.Source["virtual"]
+	return editable || virtual
+}
+
+// The sections of pdm.lock that describe the resolved packages
+type pdmLock struct {
+	Packages []pdmPackage `toml:"package"`
+}
+
+type pdmPackage struct {
+	Name         string   `toml:"name"`
+	Version      string   `toml:"version"`
+	Dependencies []string `toml:"dependencies"`
+	Files        []struct {
+		Url string `toml:"url"`
+	} `toml:"files"`
+}
+
+// The sections of pyproject.toml that declare the dependencies of a PEP 621 project, including the PDM and PEP 735 dependency groups
+type pep621Project struct {
+	Project struct {
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	DependencyGroups map[string][]any `toml:"dependency-groups"`
+	Tool             struct {
+		Pdm struct {
+			DevDependencies map[string][]string `toml:"dev-dependencies"`
+		} `toml:"pdm"`
+	} `toml:"tool"`
+}
+
+// Returns the dependencies graph and the direct dependencies of a uv or PDM project, as resolved in its lock file.
+// The lock file is read from the current directory, the dependencies aren't installed.
+func getLockFileDependencies(tool pythonutils.PythonTool) (dependenciesGraph map[string][]string, directDependencies []string, err error) {
+	switch tool {
+	case Uv:
+		lock := &uvLock{}
+		if err = decodeLockFile(uvLockFileName, lock); err != nil {
+			return
+		}
+		dependenciesGraph, directDependencies = getUvDependencies(lock)
+	case Pdm:
+		lock := &pdmLock{}
+		if err = decodeLockFile(pdmLockFileName, lock); err != nil {
+			return
+		}
+		project := &pep621Project{}
+		if _, err = decodeTomlIfExists(pyprojectFileName, project); err != nil {
+			return
+		}
+		dependenciesGraph, directDependencies = getPdmDependencies(lock, project)
+	}
+	return
+}
+
+func decodeLockFile(lockFileName string, target any) error {
+	exists, err := decodeTomlIfExists(lockFileName, target)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errorutils.CheckErrorf("the '%s' lock file wasn't found. Lock the project dependencies and try again", lockFileName)
+	}
+	return nil
+}
+
+func getUvDependencies(lock *uvLock) (dependenciesGraph map[string][]string, directDependencies []string) {
+	packages := map[string][]uvPackage{}
+	for _, pkg := range lock.Packages {
+		name := normalizePackageName(pkg.Name)
+		packages[name] = append(packages[name], pkg)
+	}
+	resolve := func(dependency uvDependency) (uvPackage, bool) {
+		candidates := packages[normalizePackageName(dependency.Name)]
+		for _, candidate := range candidates {
+			if dependency.Version == "" || candidate.Version == dependency.Version {
+				return candidate, true
+			}
+		}
+		return uvPackage{}, false
+	}
+	dependenciesGraph = map[string][]string{}
+	directDependenciesSet := datastructures.MakeSet[string]()
+	for _, pkg := range lock.Packages {
+		var children []string
+		for _, dependency := range getUvPackageDependencies(pkg) {
+			if resolved, found := resolve(dependency); found && !resolved.isProjectPackage() {
+				children = appendIfMissing(children, toPythonDependencyId(resolved.Name, resolved.Version))
+			}
+		}
+		if !pkg.isProjectPackage() {
+			dependenciesGraph[toPythonDependencyId(pkg.Name, pkg.Version)] = children
+			continue
+		}
+		// The dependencies of the project packages are the direct dependencies
+		directDependenciesSet.AddElements(children...)
+	}
+	directDependencies = directDependenciesSet.ToSlice()
+	sort.Strings(directDependencies)
+	return
+}
+
+// The project packages include their optional and dev dependencies
+func getUvPackageDependencies(pkg uvPackage) []uvDependency {
+	dependencies := append([]uvDependency{}, pkg.Dependencies...)
+	for _, optional := range pkg.OptionalDependencies {
+		dependencies = append(dependencies, optional...)
+	}
+	for _, group := range pkg.DevDependencies {
+		dependencies = append(dependencies, group...)
+	}
+	return dependencies
+}
+
+func getPdmDependencies(lock *pdmLock, project *pep621Project) (dependenciesGraph map[string][]string, directDependencies []string) {
+	// Packages with extras are locked separately with the same version, so their dependencies are merged
+	versions := map[string]string{}
+	for _, pkg := range lock.Packages {
+		versions[normalizePackageName(pkg.Name)] = pkg.Version
+	}
+	dependenciesGraph = map[string][]string{}
+	for _, pkg := range lock.Packages {
+		id := toPythonDependencyId(pkg.Name, pkg.Version)
+		children := dependenciesGraph[id]
+		for _, requirement := range pkg.Dependencies {
+			name := getRequirementName(requirement)
+			if version, found := versions[name]; found && name != normalizePackageName(pkg.Name) {
+				children = appendIfMissing(children, toPythonDependencyId(name, version))
+			}
+		}
+		dependenciesGraph[id] = children
+	}
+	for name := range getPep621DirectDependenciesScopes(project) {
+		if version, found := versions[name]; found {
+			directDependencies = append(directDependencies, toPythonDependencyId(name, version))
+		}
+	}
+	sort.Strings(directDependencies)
+	return
+}
+
+// Returns the scopes of the direct dependencies of a uv or PDM project.
+// Dependency groups named 'test' or 'tests' are test dependencies, other groups are dev dependencies.
+func getLockFileDirectDependenciesScopes(tool pythonutils.PythonTool, projectDir string) (directScopes map[string][]string, err error) {
+	switch tool {
+	case Uv:
+		lock := &uvLock{}
+		var exists bool
+		if exists, err = decodeTomlIfExists(filepath.Join(projectDir, uvLockFileName), lock); err != nil || !exists {
+			return
+		}
+		directScopes = map[string][]string{}
+		for _, pkg := range lock.Packages {
+			if !pkg.isProjectPackage() {
+				continue
+			}
+			for groupName, group := range pkg.DevDependencies {
+				for _, dependency := range group {
+					directScopes[normalizePackageName(dependency.Name)] = []string{getDependencyGroupScope(groupName)}
+				}
+			}
+		}
+		for _, pkg := range lock.Packages {
+			if !pkg.isProjectPackage() {
+				continue
+			}
+			dependencies := append([]uvDependency{}, pkg.Dependencies...)
+			for _, optional := range pkg.OptionalDependencies {
+				dependencies = append(dependencies, optional...)
+			}
+			for _, dependency := range dependencies {
+				directScopes[normalizePackageName(dependency.Name)] = []string{techutils.RuntimeScope}
+			}
+		}
+	case Pdm:
+		project := &pep621Project{}
+		var exists bool
+		if exists, err = decodeTomlIfExists(filepath.Join(projectDir, pyprojectFileName), project); err != nil || !exists {
+			return
+		}
+		directScopes = getPep621DirectDependenciesScopes(project)
+	}
+	return
+}
+
+// Runtime declarations are added last, to override other scopes of the same dependency.
+func getPep621DirectDependenciesScopes(project *pep621Project) map[string][]string {
+	directScopes := map[string][]string{}
+	addRequirements := func(requirements []string, scope string) {
+		for _, requirement := range requirements {
+			if name := getRequirementName(requirement); name != "" {
+				directScopes[name] = []string{scope}
+			}
+		}
+	}
+	for groupName, group := range project.DependencyGroups {
+		var requirements []string
+		for _, entry := range group {
+			// Groups may also include other groups, which are declared separately
+			if requirement, isRequirement := entry.(string); isRequirement {
+				requirements = append(requirements, requirement)
+			}
+		}
+		addRequirements(requirements, getDependencyGroupScope(groupName))
+	}
+	for groupName, requirements := range project.Tool.Pdm.DevDependencies {
+		addRequirements(requirements, getDependencyGroupScope(groupName))
+	}
+	for _, requirements := range project.Project.OptionalDependencies {
+		addRequirements(requirements, techutils.RuntimeScope)
+	}
+	addRequirements(project.Project.Dependencies, techutils.RuntimeScope)
+	return directScopes
+}
+
+// Returns the download URLs of the locked packages, to be checked by the curation command.
+// The PyPI files URLs are converted to the matching URLs in the Artifactory PyPI remote repository.
+func getLockFileDownloadUrls(tool pythonutils.PythonTool, server *config.ServerDetails, repo string) (downloadUrls map[string]string, err error) {
+	if server == nil || repo == "" {
+		return
+	}
+	packagesUrls := map[string]string{}
+	switch tool {
+	case Uv:
+		lock := &uvLock{}
+		if err = decodeLockFile(uvLockFileName, lock); err != nil {
+			return
+		}
+		for _, pkg := range lock.Packages {
+			if pkg.Sdist != nil && pkg.Sdist.Url != "" {
+				packagesUrls[toPythonDependencyId(pkg.Name, pkg.Version)] = pkg.Sdist.Url
+			} else if len(pkg.Wheels) > 0 {
+				packagesUrls[toPythonDependencyId(pkg.Name, pkg.Version)] = pkg.Wheels[0].Url
+			}
+		}
+	case Pdm:
+		lock := &pdmLock{}
+		if err = decodeLockFile(pdmLockFileName, lock); err != nil {
+			return
+		}
+		for _, pkg := range lock.Packages {
+			// The files URLs are locked only if the project uses the 'static_urls' lock strategy
+			if len(pkg.Files) > 0 && pkg.Files[0].Url != "" {
+				packagesUrls[toPythonDependencyId(pkg.Name, pkg.Version)] = pkg.Files[0].Url
+			}
+		}
+	}
+	downloadUrls = map[string]string{}
+	for id, packageUrl := range packagesUrls {
+		downloadUrls[PythonPackageTypeIdentifier+id] = toArtifactoryPypiUrl(packageUrl, server.ArtifactoryUrl, repo)
+	}
+	return
+}
+
+func toArtifactoryPypiUrl(packageUrl, artifactoryUrl, repo string) string {
+	parsedUrl, err := url.Parse(packageUrl)
+	if err != nil || parsedUrl.Host != pypiFilesHost {
+		// The package was locked from the Artifactory repository, or from another index
+		return packageUrl
+	}
+	return fmt.Sprintf("%s/api/pypi/%s/packages%s", strings.TrimSuffix(artifactoryUrl, "/"), repo, parsedUrl.Path)
+}
+
+func getDependencyGroupScope(groupName string) string {
+	if groupName == "test" || groupName == "tests" {
+		return techutils.TestScope
+	}
+	return techutils.DevScope
+}
+
+func getRequirementName(requirement string) string {
+	match := pep508NameRegex.FindStringSubmatch(requirement)
+	if len(match) < 2 {
+		log.Debug(fmt.Sprintf("Couldn't parse the package name of the requirement '%s'", requirement))
+		return ""
+	}
+	return normalizePackageName(match[1])
+}
+
+// Normalize the package name as defined in PEP 503
+func normalizePackageName(name string) string {
+	return pep503NormalRegex.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+func toPythonDependencyId(name, version string) string {
+	return normalizePackageName(name) + ":" + version
+}
+
+func appendIfMissing(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/jfrog/gofrog/version"
 
+	"github.com/BurntSushi/toml"
 	biutils "github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/build-info-go/utils/pythonutils"
 	"github.com/jfrog/gofrog/datastructures"
@@ -32,8 +33,30 @@ const (
 	pythonReportFile            = "report.json"
 
 	CurationPipMinimumVersion = "23.0.0"
+
+	pyprojectFileName = "pyproject.toml"
+	pipfileFileName   = "Pipfile"
 )
 
+// The sections of pyproject.toml that declare the dependencies of a Poetry project
+type poetryProject struct {
+	Tool struct {
+		Poetry struct {
+			Dependencies    map[string]any `toml:"dependencies"`
+			DevDependencies map[string]any `toml:"dev-dependencies"`
+			Group           map[string]struct {
+				Dependencies map[string]any `toml:"dependencies"`
+			} `toml:"group"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// The sections of Pipfile that declare the dependencies of a Pipenv project
+type pipfile struct {
+	Packages    map[string]any `toml:"packages"`
+	DevPackages map[string]any `toml:"dev-packages"`
+}
+
 type AuditPython struct {
 	Server              *config.ServerDetails
 	Tool                pythonutils.PythonTool
@@ -69,7 +92,64 @@ func BuildDependencyTree(auditPython *AuditPython) (dependencyTree []*xrayUtils.
 	return
 }
 
+// Returns the scopes of the direct dependencies, by their declaration in the project descriptor of the given directory.
+// Poetry, uv and PDM groups named 'test' or 'tests' are test dependencies, other groups and Pipenv dev-packages are dev dependencies.
+func GetDirectDependenciesScopes(tool pythonutils.PythonTool, projectDir string) (directScopes map[string][]string, err error) {
+	directScopes = map[string][]string{}
+	switch tool {
+	case pythonutils.Poetry:
+		project := &poetryProject{}
+		var exists bool
+		if exists, err = decodeTomlIfExists(filepath.Join(projectDir, pyprojectFileName), project); err != nil || !exists {
+			return
+		}
+		for groupName, group := range project.Tool.Poetry.Group {
+			addDirectScopes(directScopes, group.Dependencies, getDependencyGroupScope(groupName))
+		}
+		addDirectScopes(directScopes, project.Tool.Poetry.DevDependencies, techutils.DevScope)
+		addDirectScopes(directScopes, project.Tool.Poetry.Dependencies, techutils.RuntimeScope)
+		// The Python interpreter constraint is declared as a dependency
+		delete(directScopes, "python")
+	case pythonutils.Pipenv:
+		project := &pipfile{}
+		var exists bool
+		if exists, err = decodeTomlIfExists(filepath.Join(projectDir, pipfileFileName), project); err != nil || !exists {
+			return
+		}
+		addDirectScopes(directScopes, project.DevPackages, techutils.DevScope)
+		addDirectScopes(directScopes, project.Packages, techutils.RuntimeScope)
+	case Uv, Pdm:
+		return getLockFileDirectDependenciesScopes(tool, projectDir)
+	}
+	return
+}
+
+func decodeTomlIfExists(path string, target any) (exists bool, err error) {
+	if exists, err = fileutils.IsFileExists(path, false); err != nil || !exists {
+		return
+	}
+	if _, err = toml.DecodeFile(path, target); err != nil {
+		err = errorutils.CheckErrorf("failed to parse '%s': %s", path, err.Error())
+	}
+	return
+}
+
+// Runtime declarations are added last, to override other scopes of the same dependency.
+func addDirectScopes(directScopes map[string][]string, dependencies map[string]any, scope string) {
+	for name := range dependencies {
+		directScopes[strings.ToLower(name)] = []string{scope}
+	}
+}
+
 func getDependencies(auditPython *AuditPython) (dependenciesGraph map[string][]string, directDependencies []string, pipUrls map[string]string, err error) {
+	if auditPython.Tool == Uv || auditPython.Tool == Pdm {
+		// The dependencies are resolved from the lock file, without installing them
+		if dependenciesGraph, directDependencies, err = getLockFileDependencies(auditPython.Tool); err != nil || !auditPython.IsCurationCmd {
+			return
+		}
+		pipUrls, err = getLockFileDownloadUrls(auditPython.Tool, auditPython.Server, auditPython.RemotePypiRepo)
+		return
+	}
 	wd, err := os.Getwd()
 	if errorutils.CheckError(err) != nil {
 		return
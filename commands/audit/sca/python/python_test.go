@@ -9,6 +9,7 @@ import (
 	"github.com/jfrog/build-info-go/utils/pythonutils"
 	"github.com/jfrog/jfrog-client-go/xray/services/utils"
 
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
@@ -216,3 +217,133 @@ func TestGetPipInstallArgs(t *testing.T) {
 	assert.Equal(t, []string{"-m", "pip", "install", ".", "--cache-dir", filepath.Join("test", "path"), "--ignore-installed", "--report", "report.json"}, getPipInstallArgs("", "", filepath.Join("test", "path"), "report.json"))
 
 }
+
+func TestGetDirectDependenciesScopes(t *testing.T) {
+	projectDir := t.TempDir()
+	pyproject := `[tool.poetry.dependencies]
+python = "^3.10"
+Requests = "^2.31"
+
+[tool.poetry.dev-dependencies]
+black = "^23.0"
+
+[tool.poetry.group.test.dependencies]
+pytest = "^7.0"
+
+[tool.poetry.group.docs.dependencies]
+mkdocs = "^1.5"
+requests = "^2.31"
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, pyprojectFileName), []byte(pyproject), 0644))
+	scopes, err := GetDirectDependenciesScopes(pythonutils.Poetry, projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"requests": {techutils.RuntimeScope},
+		"black":    {techutils.DevScope},
+		"pytest":   {techutils.TestScope},
+		"mkdocs":   {techutils.DevScope},
+	}, scopes)
+
+	pipfile := "[packages]\nflask = \"*\"\n\n[dev-packages]\npytest = \"*\"\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, pipfileFileName), []byte(pipfile), 0644))
+	scopes, err = GetDirectDependenciesScopes(pythonutils.Pipenv, projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"flask": {techutils.RuntimeScope}, "pytest": {techutils.DevScope}}, scopes)
+
+	// Pip requirements don't declare scopes
+	scopes, err = GetDirectDependenciesScopes(pythonutils.Pip, projectDir)
+	assert.NoError(t, err)
+	assert.Empty(t, scopes)
+}
+
+func TestBuildUvDependencyList(t *testing.T) {
+	_, cleanUp := sca.CreateTestWorkspace(t, filepath.Join("projects", "package-managers", "python", "uv", "uv-project"))
+	defer cleanUp()
+	rootNode, uniqueDeps, _, err := BuildDependencyTree(&AuditPython{Tool: Uv})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		PythonPackageTypeIdentifier + "black:24.1.0",
+		PythonPackageTypeIdentifier + "certifi:2024.2.2",
+		PythonPackageTypeIdentifier + "charset-normalizer:3.3.2",
+		PythonPackageTypeIdentifier + "click:8.1.7",
+		PythonPackageTypeIdentifier + "idna:3.6",
+		PythonPackageTypeIdentifier + "iniconfig:2.0.0",
+		PythonPackageTypeIdentifier + "pysocks:1.7.1",
+		PythonPackageTypeIdentifier + "requests:2.31.0",
+		PythonPackageTypeIdentifier + "urllib3:2.2.1",
+	}, uniqueDeps)
+	if assert.Len(t, rootNode, 1) {
+		assert.Len(t, rootNode[0].Nodes, 4)
+		directDepNode := tests.GetAndAssertNode(t, rootNode[0].Nodes, "requests:2.31.0")
+		tests.GetAndAssertNode(t, directDepNode.Nodes, "urllib3:2.2.1")
+		directDepNode = tests.GetAndAssertNode(t, rootNode[0].Nodes, "black:24.1.0")
+		tests.GetAndAssertNode(t, directDepNode.Nodes, "click:8.1.7")
+	}
+	scopes, err := GetDirectDependenciesScopes(Uv, ".")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"requests":  {techutils.RuntimeScope},
+		"pysocks":   {techutils.RuntimeScope},
+		"black":     {techutils.DevScope},
+		"iniconfig": {techutils.TestScope},
+	}, scopes)
+}
+
+func TestBuildPdmDependencyList(t *testing.T) {
+	_, cleanUp := sca.CreateTestWorkspace(t, filepath.Join("projects", "package-managers", "python", "pdm", "pdm-project"))
+	defer cleanUp()
+	rootNode, uniqueDeps, _, err := BuildDependencyTree(&AuditPython{Tool: Pdm})
+	assert.NoError(t, err)
+	assert.Len(t, uniqueDeps, 9)
+	if assert.Len(t, rootNode, 1) {
+		assert.Len(t, rootNode[0].Nodes, 3)
+		// The dependencies of the extras are merged into the package
+		directDepNode := tests.GetAndAssertNode(t, rootNode[0].Nodes, "requests:2.31.0")
+		assert.Len(t, directDepNode.Nodes, 5)
+		tests.GetAndAssertNode(t, directDepNode.Nodes, "pysocks:1.7.1")
+		directDepNode = tests.GetAndAssertNode(t, rootNode[0].Nodes, "black:24.1.0")
+		tests.GetAndAssertNode(t, directDepNode.Nodes, "click:8.1.7")
+	}
+	scopes, err := GetDirectDependenciesScopes(Pdm, ".")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"requests":  {techutils.RuntimeScope},
+		"black":     {techutils.DevScope},
+		"iniconfig": {techutils.TestScope},
+	}, scopes)
+}
+
+func TestGetLockFileDownloadUrls(t *testing.T) {
+	_, cleanUp := sca.CreateTestWorkspace(t, filepath.Join("projects", "package-managers", "python", "uv", "uv-project"))
+	defer cleanUp()
+	server := &config.ServerDetails{ArtifactoryUrl: "https://myjfrog.jfrog.io/artifactory/"}
+	downloadUrls, err := getLockFileDownloadUrls(Uv, server, "pypi-remote")
+	assert.NoError(t, err)
+	assert.Len(t, downloadUrls, 9)
+	// The sdist is preferred over the wheels
+	assert.Equal(t, "https://myjfrog.jfrog.io/artifactory/api/pypi/pypi-remote/packages/packages/71/da/certifi-2024.2.2.tar.gz", downloadUrls[PythonPackageTypeIdentifier+"certifi:2024.2.2"])
+	assert.Equal(t, "https://myjfrog.jfrog.io/artifactory/api/pypi/pypi-remote/packages/packages/28/76/charset_normalizer-3.3.2-py3-none-any.whl", downloadUrls[PythonPackageTypeIdentifier+"charset-normalizer:3.3.2"])
+
+	// Without a resolution repository, there's nothing to check
+	downloadUrls, err = getLockFileDownloadUrls(Uv, server, "")
+	assert.NoError(t, err)
+	assert.Empty(t, downloadUrls)
+}
+
+func TestGetRequirementName(t *testing.T) {
+	testCases := []struct {
+		requirement string
+		expected    string
+	}{
+		{requirement: "requests", expected: "requests"},
+		{requirement: "Requests[socks]>=2.31.0", expected: "requests"},
+		{requirement: "charset_normalizer<4,>=2", expected: "charset-normalizer"},
+		{requirement: "zope.interface ; python_version >= \"3.8\"", expected: "zope-interface"},
+		{requirement: "-e .", expected: ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.requirement, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, getRequirementName(testCase.requirement))
+		})
+	}
+}
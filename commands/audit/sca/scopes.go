@@ -0,0 +1,90 @@
+package sca
+
+import (
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/slices"
+)
+
+// Calculate the scopes of all the dependencies in the trees.
+// knownScopes maps a dependency ID to its scopes, for technologies that resolve the scope of each dependency (for example: Maven, Gradle and npm).
+// directScopes maps the name of a direct dependency to its scopes, for technologies that declare the scopes in the descriptor only (for example: Poetry groups).
+// A dependency without a known scope inherits the scopes of the dependencies that brought it to the project.
+func GetDependenciesScopes(dependencyTrees []*xrayUtils.GraphNode, knownScopes, directScopes map[string][]string) map[string][]string {
+	normalizedDirectScopes := map[string][]string{}
+	for name, scopes := range directScopes {
+		normalizedDirectScopes[normalizeDependencyName(name)] = scopes
+	}
+	dependenciesScopes := map[string][]string{}
+	for _, root := range dependencyTrees {
+		for _, directDependency := range root.Nodes {
+			scopes := knownScopes[directDependency.Id]
+			if len(scopes) == 0 {
+				name, _, _ := utils.SplitComponentId(directDependency.Id)
+				scopes = normalizedDirectScopes[normalizeDependencyName(name)]
+			}
+			propagateScopes(directDependency, scopes, knownScopes, dependenciesScopes)
+		}
+	}
+	return dependenciesScopes
+}
+
+// The trees are built without loops, so each path is finite.
+func propagateScopes(node *xrayUtils.GraphNode, scopes []string, knownScopes, dependenciesScopes map[string][]string) {
+	if known, ok := knownScopes[node.Id]; ok && len(known) > 0 {
+		scopes = known
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(dependenciesScopes[node.Id], scope) {
+			dependenciesScopes[node.Id] = append(dependenciesScopes[node.Id], scope)
+		}
+	}
+	for _, child := range node.Nodes {
+		propagateScopes(child, scopes, knownScopes, dependenciesScopes)
+	}
+}
+
+// Remove the dependencies that are not in the requested scopes from the trees.
+// Dependencies with unknown scopes are kept. Returns the IDs of the dependencies that remained in the trees.
+func FilterDependencyTreesByScopes(dependencyTrees []*xrayUtils.GraphNode, dependenciesScopes map[string][]string, requestedScopes []string) *datastructures.Set[string] {
+	remaining := datastructures.MakeSet[string]()
+	for _, root := range dependencyTrees {
+		remaining.Add(root.Id)
+		filterNodesByScopes(root, dependenciesScopes, requestedScopes, remaining)
+	}
+	return remaining
+}
+
+func filterNodesByScopes(node *xrayUtils.GraphNode, dependenciesScopes map[string][]string, requestedScopes []string, remaining *datastructures.Set[string]) {
+	var filteredNodes []*xrayUtils.GraphNode
+	for _, child := range node.Nodes {
+		if !IsInScopes(dependenciesScopes[child.Id], requestedScopes) {
+			continue
+		}
+		remaining.Add(child.Id)
+		filterNodesByScopes(child, dependenciesScopes, requestedScopes, remaining)
+		filteredNodes = append(filteredNodes, child)
+	}
+	node.Nodes = filteredNodes
+}
+
+// Returns true if one of the scopes is requested. Unknown scopes and empty requested scopes (all) are always included.
+func IsInScopes(scopes, requestedScopes []string) bool {
+	if len(scopes) == 0 || len(requestedScopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if slices.Contains(requestedScopes, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dependency names are compared case-insensitively, and Python treats '-', '_' and '.' the same.
+func normalizeDependencyName(name string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,53 @@
+package sca
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createScopesTestTree() []*xrayUtils.GraphNode {
+	shared := &xrayUtils.GraphNode{Id: "pypi://shared:1.0.0"}
+	return []*xrayUtils.GraphNode{{
+		Id: "root",
+		Nodes: []*xrayUtils.GraphNode{
+			{Id: "pypi://requests:2.0.0", Nodes: []*xrayUtils.GraphNode{shared, {Id: "pypi://urllib3:1.0.0"}}},
+			{Id: "pypi://py_test:7.0.0", Nodes: []*xrayUtils.GraphNode{shared, {Id: "pypi://pluggy:1.0.0"}}},
+			{Id: "pypi://unknown:1.0.0"},
+		},
+	}}
+}
+
+func TestGetDependenciesScopes(t *testing.T) {
+	directScopes := map[string][]string{"Requests": {techutils.RuntimeScope}, "py.test": {techutils.TestScope}}
+	knownScopes := map[string][]string{"pypi://urllib3:1.0.0": {techutils.DevScope}}
+	scopes := GetDependenciesScopes(createScopesTestTree(), knownScopes, directScopes)
+	assert.Equal(t, []string{techutils.RuntimeScope}, scopes["pypi://requests:2.0.0"])
+	assert.Equal(t, []string{techutils.TestScope}, scopes["pypi://py_test:7.0.0"])
+	assert.Equal(t, []string{techutils.TestScope}, scopes["pypi://pluggy:1.0.0"])
+	// Known scopes override the inherited scopes
+	assert.Equal(t, []string{techutils.DevScope}, scopes["pypi://urllib3:1.0.0"])
+	// A dependency inherits the scopes of all the dependencies that brought it
+	assert.ElementsMatch(t, []string{techutils.RuntimeScope, techutils.TestScope}, scopes["pypi://shared:1.0.0"])
+	assert.NotContains(t, scopes, "pypi://unknown:1.0.0")
+}
+
+func TestFilterDependencyTreesByScopes(t *testing.T) {
+	trees := createScopesTestTree()
+	scopes := GetDependenciesScopes(trees, nil, map[string][]string{"requests": {techutils.RuntimeScope}, "py-test": {techutils.TestScope}})
+	remaining := FilterDependencyTreesByScopes(trees, scopes, []string{techutils.RuntimeScope, techutils.DevScope})
+	assert.ElementsMatch(t, []string{"root", "pypi://requests:2.0.0", "pypi://shared:1.0.0", "pypi://urllib3:1.0.0", "pypi://unknown:1.0.0"}, remaining.ToSlice())
+	if assert.Len(t, trees[0].Nodes, 2) {
+		assert.Equal(t, "pypi://requests:2.0.0", trees[0].Nodes[0].Id)
+		assert.Equal(t, "pypi://unknown:1.0.0", trees[0].Nodes[1].Id)
+	}
+}
+
+func TestIsInScopes(t *testing.T) {
+	assert.True(t, IsInScopes(nil, []string{techutils.RuntimeScope}))
+	assert.True(t, IsInScopes([]string{techutils.TestScope}, nil))
+	assert.True(t, IsInScopes([]string{techutils.TestScope, techutils.RuntimeScope}, []string{techutils.RuntimeScope}))
+	assert.False(t, IsInScopes([]string{techutils.DevScope}, []string{techutils.RuntimeScope}))
+}
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"golang.org/x/exp/maps"
-
 	"github.com/jfrog/build-info-go/build"
 	biutils "github.com/jfrog/build-info-go/build/utils"
 	"github.com/jfrog/gofrog/version"
@@ -14,6 +12,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/ioutils"
+	"github.com/jfrog/jfrog-cli-security/commands/audit/sca/npm"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
@@ -74,9 +73,12 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils
 	if err != nil {
 		return
 	}
-	// Parse the dependencies into Xray dependency tree format
-	dependencyTree, uniqueDeps := parseYarnDependenciesMap(dependenciesMap, getXrayDependencyId(root))
-	dependencyTrees = []*xrayUtils.GraphNode{dependencyTree}
+	membersNames, err := npm.GetWorkspaceMembersNames(currentDir)
+	if err != nil {
+		return
+	}
+	// Parse the dependencies into Xray dependency tree format, with a tree for each workspace member
+	dependencyTrees, uniqueDeps = parseYarnDependenciesMap(dependenciesMap, getXrayDependencyId(root), membersNames)
 	return
 }
 
@@ -199,8 +201,9 @@ func runYarnInstallAccordingToVersion(curWd, yarnExecPath string, installCommand
 	return
 }
 
-// Parse the dependencies into a Xray dependency tree format
-func parseYarnDependenciesMap(dependencies map[string]*biutils.YarnDependency, rootXrayId string) (*xrayUtils.GraphNode, []string) {
+// Parse the dependencies into a Xray dependency tree format.
+// In workspaces, a tree is created for the root package and for each of the workspace members.
+func parseYarnDependenciesMap(dependencies map[string]*biutils.YarnDependency, rootXrayId string, workspaceMembers []string) ([]*xrayUtils.GraphNode, []string) {
 	treeMap := make(map[string]xray.DepTreeNode)
 	for _, dependency := range dependencies {
 		xrayDepId := getXrayDependencyId(dependency)
@@ -212,8 +215,7 @@ func parseYarnDependenciesMap(dependencies map[string]*biutils.YarnDependency, r
 			treeMap[xrayDepId] = xray.DepTreeNode{Children: subDeps}
 		}
 	}
-	graph, uniqDeps := xray.BuildXrayDependencyTree(treeMap, rootXrayId)
-	return graph, maps.Keys(uniqDeps)
+	return npm.BuildWorkspaceDependencyTrees(treeMap, rootXrayId, workspaceMembers)
 }
 
 func getXrayDependencyId(yarnDependency *biutils.YarnDependency) string {
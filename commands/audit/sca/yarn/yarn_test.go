@@ -48,9 +48,11 @@ func TestParseYarnDependenciesList(t *testing.T) {
 		utils.NpmPackageTypeIdentifier + "@jfrog/pack3:3.0.0",
 	}
 
-	xrayDependenciesTree, uniqueDeps := parseYarnDependenciesMap(yarnDependencies, rootXrayId)
+	xrayDependenciesTrees, uniqueDeps := parseYarnDependenciesMap(yarnDependencies, rootXrayId, nil)
 	assert.ElementsMatch(t, uniqueDeps, expectedUniqueDeps, "First is actual, Second is Expected")
-	assert.True(t, tests.CompareTree(expectedTree, xrayDependenciesTree), "expected:", expectedTree.Nodes, "got:", xrayDependenciesTree.Nodes)
+	if assert.Len(t, xrayDependenciesTrees, 1) {
+		assert.True(t, tests.CompareTree(expectedTree, xrayDependenciesTrees[0]), "expected:", expectedTree.Nodes, "got:", xrayDependenciesTrees[0].Nodes)
+	}
 }
 
 func TestIsInstallRequired(t *testing.T) {
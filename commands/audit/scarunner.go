@@ -10,10 +10,13 @@ import (
 	"golang.org/x/exp/slices"
 
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/gofrog/parallel"
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca"
@@ -24,17 +27,24 @@ import (
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca/pnpm"
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca/python"
 	"github.com/jfrog/jfrog-cli-security/commands/audit/sca/yarn"
+	"github.com/jfrog/jfrog-cli-security/formats"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/jas/applicability"
+	"github.com/jfrog/jfrog-cli-security/jas/linescan"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	xrayutils "github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/artifactory"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray"
 	"github.com/jfrog/jfrog-cli-security/utils/xray/scangraph"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	xscservices "github.com/jfrog/jfrog-client-go/xsc/services"
 )
 
 func buildDepTreeAndRunScaScan(auditParallelRunner *utils.SecurityParallelRunner, auditParams *AuditParams, results *xrayutils.Results) (err error) {
@@ -42,8 +52,8 @@ func buildDepTreeAndRunScaScan(auditParallelRunner *utils.SecurityParallelRunner
 		log.Debug("Skipping SCA scan as requested by input...")
 		return
 	}
-	if auditParams.configProfile != nil {
-		log.Debug("Skipping SCA scan as a configuration profile is being utilized and currently only Secrets and Sast scanners are supported when utilizing a configuration profile")
+	if auditParams.configProfile != nil && len(getConfigProfileScaModules(auditParams.configProfile)) == 0 {
+		log.Debug(fmt.Sprintf("Skipping SCA scan as requested by '%s' config profile...", auditParams.configProfile.ProfileName))
 		return
 	}
 
@@ -79,6 +89,11 @@ func buildDepTreeAndRunScaScan(auditParallelRunner *utils.SecurityParallelRunner
 			err = errors.Join(err, fmt.Errorf("audit command in '%s' failed:\n%s", scan.Target, bdtErr.Error()))
 			continue
 		}
+		if treeResult.FlatTree == nil || len(treeResult.FlatTree.Nodes) == 0 {
+			log.Info(fmt.Sprintf("No dependencies in the requested scopes (%s) were found in '%s'. Skipping the SCA scan...", strings.Join(auditParams.DependenciesScopes(), ", "), scan.Target))
+			continue
+		}
+		scan.DependencyTrees = treeResult.FullDepTrees
 		// Create sca scan task
 		auditParallelRunner.ScaScansWg.Add(1)
 		_, taskErr := auditParallelRunner.Runner.AddTaskWithError(executeScaScanTask(auditParallelRunner, serverDetails, auditParams, scan, treeResult), func(err error) {
@@ -97,37 +112,89 @@ func buildDepTreeAndRunScaScan(auditParallelRunner *utils.SecurityParallelRunner
 
 // Calculate the scans to preform
 func getScaScansToPreform(params *AuditParams) (scansToPreform []*xrayutils.ScaScanResult) {
+	if params.configProfile != nil {
+		// The modules of the config profile that enable SCA are scanned, each with its own roots and exclusions
+		for _, profileModule := range getConfigProfileScaModules(params.configProfile) {
+			roots, err := jas.GetConfigProfileModuleRoots(profileModule)
+			if err != nil {
+				log.Warn("Couldn't determine the roots of the", profileModule.ModuleName, "module.", err.Error())
+				continue
+			}
+			excludePattern := fspatterns.PrepareExcludePathPattern(getConfigProfileScaExclusions(profileModule, params.Exclusions()), clientutils.WildCardPattern, params.IsRecursiveScan())
+			// The config profile doesn't list the technologies of a module, so all the technologies are detected unless requested
+			for _, root := range roots {
+				scansToPreform = append(scansToPreform, detectScaScans(params, root, params.Technologies(), excludePattern)...)
+			}
+		}
+		return
+	}
 	for _, requestedDirectory := range params.workingDirs {
-		if !fileutils.IsPathExists(requestedDirectory, false) {
-			log.Warn("The working directory", requestedDirectory, "doesn't exist. Skipping SCA scan...")
+		scansToPreform = append(scansToPreform, detectScaScans(params, requestedDirectory, params.Technologies(), sca.GetExcludePattern(params.AuditBasicParams))...)
+	}
+	return
+}
+
+func detectScaScans(params *AuditParams, requestedDirectory string, technologies []string, excludePattern string) (scansToPreform []*xrayutils.ScaScanResult) {
+	if !fileutils.IsPathExists(requestedDirectory, false) {
+		log.Warn("The working directory", requestedDirectory, "doesn't exist. Skipping SCA scan...")
+		return
+	}
+	// Detect descriptors and technologies in the requested directory.
+	techToWorkingDirs, err := techutils.DetectTechnologiesDescriptors(requestedDirectory, params.IsRecursiveScan(), technologies, getRequestedDescriptors(params), excludePattern)
+	if err != nil {
+		log.Warn("Couldn't detect technologies in", requestedDirectory, "directory.", err.Error())
+		return
+	}
+	// Create scans to preform
+	for tech, workingDirs := range techToWorkingDirs {
+		if tech == techutils.Dotnet {
+			// We detect Dotnet and Nuget the same way, if one detected so does the other.
+			// We don't need to scan for both and get duplicate results.
 			continue
 		}
-		// Detect descriptors and technologies in the requested directory.
-		techToWorkingDirs, err := techutils.DetectTechnologiesDescriptors(requestedDirectory, params.IsRecursiveScan(), params.Technologies(), getRequestedDescriptors(params), sca.GetExcludePattern(params.AuditBasicParams))
-		if err != nil {
-			log.Warn("Couldn't detect technologies in", requestedDirectory, "directory.", err.Error())
-			continue
+		if len(workingDirs) == 0 {
+			// Requested technology (from params) descriptors/indicators was not found, scan only requested directory for this technology.
+			scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{Target: requestedDirectory, Technology: tech})
 		}
-		// Create scans to preform
-		for tech, workingDirs := range techToWorkingDirs {
-			if tech == techutils.Dotnet {
-				// We detect Dotnet and Nuget the same way, if one detected so does the other.
-				// We don't need to scan for both and get duplicate results.
-				continue
-			}
-			if len(workingDirs) == 0 {
-				// Requested technology (from params) descriptors/indicators was not found, scan only requested directory for this technology.
-				scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{Target: requestedDirectory, Technology: tech})
-			}
-			for workingDir, descriptors := range workingDirs {
-				// Add scan for each detected working directory.
-				scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{Target: workingDir, Technology: tech, Descriptors: descriptors})
-			}
+		for workingDir, descriptors := range workingDirs {
+			// Add scan for each detected working directory.
+			scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{Target: workingDir, Technology: tech, Descriptors: descriptors})
 		}
 	}
 	return
 }
 
+func getConfigProfileScaModules(configProfile *xscservices.ConfigProfile) (modules []xscservices.Module) {
+	for _, profileModule := range configProfile.Modules {
+		if profileModule.ScanConfig.EnableScaScan {
+			modules = append(modules, profileModule)
+		}
+	}
+	return
+}
+
+// Returns the exclusions of the SCA scan of the config profile module: the exclusions of the audit, or the default exclusions if not set, and the excluded directories of the module.
+// The directories glob patterns of the module, such as '**/node_modules/**', are converted to the wildcard patterns of the SCA scan, such as '*/node_modules/*'.
+// The files glob patterns, such as '**/*.md', don't exclude projects, so they are skipped.
+func getConfigProfileScaExclusions(profileModule xscservices.Module, auditExclusions []string) []string {
+	exclusions := append([]string{}, auditExclusions...)
+	if len(exclusions) == 0 {
+		exclusions = append(exclusions, utils.DefaultScaExcludePatterns...)
+	}
+	for _, excludePattern := range jas.GetConfigProfileModuleExcludePatterns(profileModule) {
+		if !strings.HasSuffix(excludePattern, "/**") {
+			log.Debug(fmt.Sprintf("The exclude pattern '%s' of the config profile module '%s' doesn't exclude directories, so it doesn't apply to the SCA scan", excludePattern, profileModule.ModuleName))
+			continue
+		}
+		exclusion := strings.ReplaceAll(excludePattern, "**", "*")
+		if !strings.HasPrefix(exclusion, "*") {
+			exclusion = "*/" + exclusion
+		}
+		exclusions = append(exclusions, exclusion)
+	}
+	return exclusions
+}
+
 func getRequestedDescriptors(params *AuditParams) map[techutils.Technology][]string {
 	requestedDescriptors := map[techutils.Technology][]string{}
 	if params.PipRequirementsFile() != "" {
@@ -149,15 +216,90 @@ func executeScaScanTask(auditParallelRunner *utils.SecurityParallelRunner, serve
 		if xrayErr != nil {
 			return fmt.Errorf("%s Xray dependency tree scan request on '%s' failed:\n%s", clientutils.GetLogMsgPrefix(threadId, false), scan.Technology, xrayErr.Error())
 		}
+		addLicensePolicyViolations(auditParams, scan.Technology, scanResults, treeResult)
 		scan.IsMultipleRootProject = clientutils.Pointer(len(treeResult.FullDepTrees) > 1)
 		auditParallelRunner.ResultsMu.Lock()
 		addThirdPartyDependenciesToParams(auditParams, scan.Technology, treeResult.FlatTree, treeResult.FullDepTrees)
+		scan.DependenciesScopes = treeResult.DependenciesScopes
+		scan.DependenciesDeclarations = treeResult.DependenciesDeclarations
+		scan.WorkspaceMembers = treeResult.WorkspaceMembers
 		scan.XrayResults = append(scan.XrayResults, scanResults...)
 		auditParallelRunner.ResultsMu.Unlock()
 		return
 	}
 }
 
+// Add a reachability analysis task for each Go SCA scan, which runs after the SCA scans are completed.
+// The results are added to the applicability results, the same as the Contextual Analysis results.
+// Targets that are scanned by the Contextual Analysis scanner are skipped, so their CVEs have a single applicability result.
+func addGoReachabilityTasks(auditParallelRunner *utils.SecurityParallelRunner, auditParams *AuditParams, results *xrayutils.Results, jfrogAppsConfig *jfrogappsconfig.JFrogAppsConfig) {
+	for _, scan := range results.ScaResults {
+		if scan.Technology != techutils.Go {
+			continue
+		}
+		if isScannedByContextualAnalysis(scan.Technology, scan.Target, auditParams, results, jfrogAppsConfig) {
+			log.Debug(fmt.Sprintf("The applicability of the CVEs in '%s' is determined by the Contextual Analysis scanner, skipping Go reachability analysis...", scan.Target))
+			continue
+		}
+		auditParallelRunner.JasScannersWg.Add(1)
+		if _, taskErr := auditParallelRunner.Runner.AddTaskWithError(runGoReachabilityTask(auditParallelRunner, auditParams, scan, results), auditParallelRunner.AddErrorToChan); taskErr != nil {
+			auditParallelRunner.JasScannersWg.Done()
+			auditParallelRunner.AddErrorToChan(fmt.Errorf("failed to create Go reachability task for '%s': %s", scan.Target, taskErr.Error()))
+		}
+	}
+}
+
+// Returns true if the Contextual Analysis scanner analyzes the CVEs of the technology in the target:
+// the scanner supports the technology, and the target is in a module that is scanned by it and isn't excluded from the scan.
+func isScannedByContextualAnalysis(tech techutils.Technology, target string, auditParams *AuditParams, results *xrayutils.Results, jfrogAppsConfig *jfrogappsconfig.JFrogAppsConfig) bool {
+	if !results.ExtendedScanResults.EntitledForJas || jfrogAppsConfig == nil || !applicability.IsSupportedTechnology(tech) {
+		return false
+	}
+	if scansToPerform := auditParams.ScansToPerform(); len(scansToPerform) > 0 && !slices.Contains(scansToPerform, utils.ContextualAnalysisScan) {
+		return false
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+	for _, module := range jfrogAppsConfig.Modules {
+		if slices.Contains(module.ExcludeScanners, strings.ToLower(string(jasutils.Applicability))) {
+			continue
+		}
+		root, err := filepath.Abs(module.SourceRoot)
+		if err != nil {
+			continue
+		}
+		relative, err := filepath.Rel(root, absTarget)
+		if err != nil || !filepath.IsLocal(relative) {
+			continue
+		}
+		excludeRegexes := linescan.GetExcludeRegexes(jas.GetExcludePatterns(module, nil, auditParams.jasExclusions()...))
+		if !linescan.IsExcluded(relative+string(filepath.Separator), excludeRegexes) {
+			return true
+		}
+	}
+	return false
+}
+
+func runGoReachabilityTask(auditParallelRunner *utils.SecurityParallelRunner, auditParams *AuditParams, scan *xrayutils.ScaScanResult, results *xrayutils.Results) parallel.TaskFunc {
+	return func(threadId int) (err error) {
+		defer func() {
+			auditParallelRunner.JasScannersWg.Done()
+		}()
+		// Wait for the sca scans to complete, the vulnerable symbols are taken from their results
+		auditParallelRunner.ScaScansWg.Wait()
+		runs, err := _go.RunReachabilityAnalysis(scan.Target, scan.XrayResults, auditParams.vulnerableSymbolsFile)
+		if err != nil {
+			return fmt.Errorf("%s Go reachability analysis in '%s' failed:\n%s", clientutils.GetLogMsgPrefix(threadId, false), scan.Target, err.Error())
+		}
+		auditParallelRunner.ResultsMu.Lock()
+		results.ExtendedScanResults.ApplicabilityScanResults = append(results.ExtendedScanResults.ApplicabilityScanResults, runs...)
+		auditParallelRunner.ResultsMu.Unlock()
+		return
+	}
+}
+
 func runScaWithTech(tech techutils.Technology, params *AuditParams, serverDetails *config.ServerDetails,
 	flatTree xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) (techResults []services.ScanResponse, err error) {
 	scanGraphParams := scangraph.NewScanGraphParams().
@@ -174,6 +316,14 @@ func runScaWithTech(tech techutils.Technology, params *AuditParams, serverDetail
 	return
 }
 
+// Add the violations of the local license policy, based on the licenses of the dependencies returned by Xray.
+func addLicensePolicyViolations(params *AuditParams, tech techutils.Technology, scanResults []services.ScanResponse, treeResult *DependencyTreeResult) {
+	if params.licensePolicy == nil {
+		return
+	}
+	params.licensePolicy.AddViolations(scanResults, treeResult.DependenciesScopes, tech.String())
+}
+
 func addThirdPartyDependenciesToParams(params *AuditParams, tech techutils.Technology, flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) {
 	var dependenciesForApplicabilityScan []string
 	if shouldUseAllDependencies(params.thirdPartyApplicabilityScan, tech) {
@@ -214,6 +364,12 @@ type DependencyTreeResult struct {
 	FlatTree     *xrayCmdUtils.GraphNode
 	FullDepTrees []*xrayCmdUtils.GraphNode
 	DownloadUrls map[string]string
+	// The scopes of the dependencies (runtime, dev or test), if known.
+	DependenciesScopes map[string][]string
+	// Where the versions of the direct dependencies are declared in the project descriptors, if known.
+	DependenciesDeclarations map[string]map[string]*formats.Declaration
+	// The IDs of the dependency trees roots that are members of the scanned workspace, if it's a workspace.
+	WorkspaceMembers []string
 }
 
 func GetTechDependencyTree(params xrayutils.AuditParams, artifactoryServerDetails *config.ServerDetails, tech techutils.Technology) (depTreeResult DependencyTreeResult, err error) {
@@ -231,6 +387,7 @@ func GetTechDependencyTree(params xrayutils.AuditParams, artifactoryServerDetail
 
 	var uniqueDeps []string
 	var uniqDepsWithTypes map[string]*xray.DepTreeNode
+	var knownScopes map[string][]string
 	startTime := time.Now()
 
 	switch tech {
@@ -244,14 +401,14 @@ func GetTechDependencyTree(params xrayutils.AuditParams, artifactoryServerDetail
 			CurationCacheFolder:     curationCacheFolder,
 		}, tech)
 	case techutils.Npm:
-		depTreeResult.FullDepTrees, uniqueDeps, err = npm.BuildDependencyTree(params)
+		depTreeResult.FullDepTrees, uniqueDeps, knownScopes, err = npm.BuildDependencyTree(params)
 	case techutils.Pnpm:
 		depTreeResult.FullDepTrees, uniqueDeps, err = pnpm.BuildDependencyTree(params)
 	case techutils.Yarn:
 		depTreeResult.FullDepTrees, uniqueDeps, err = yarn.BuildDependencyTree(params)
 	case techutils.Go:
 		depTreeResult.FullDepTrees, uniqueDeps, err = _go.BuildDependencyTree(params)
-	case techutils.Pipenv, techutils.Pip, techutils.Poetry:
+	case techutils.Pipenv, techutils.Pip, techutils.Poetry, techutils.Uv, techutils.Pdm:
 		depTreeResult.FullDepTrees, uniqueDeps,
 			depTreeResult.DownloadUrls, err = python.BuildDependencyTree(&python.AuditPython{
 			Server:              artifactoryServerDetails,
@@ -270,6 +427,20 @@ func GetTechDependencyTree(params xrayutils.AuditParams, artifactoryServerDetail
 		return
 	}
 	log.Debug(fmt.Sprintf("Created '%s' dependency tree with %d nodes. Elapsed time: %.1f seconds.", tech.ToFormal(), len(uniqueDeps), time.Since(startTime).Seconds()))
+	depTreeResult.DependenciesScopes = getDependenciesScopes(tech, depTreeResult.FullDepTrees, knownScopes, uniqDepsWithTypes)
+	if tech == techutils.Maven && !params.IsCurationCmd() {
+		if err = getDependenciesDeclarations(&depTreeResult); err != nil {
+			return
+		}
+	}
+	if tech == techutils.Npm || tech == techutils.Yarn || tech == techutils.Pnpm {
+		if err = getWorkspaceMembers(&depTreeResult); err != nil {
+			return
+		}
+	}
+	if requestedScopes := params.DependenciesScopes(); len(requestedScopes) > 0 {
+		uniqueDeps, uniqDepsWithTypes = filterDependenciesByScopes(&depTreeResult, uniqueDeps, uniqDepsWithTypes, requestedScopes)
+	}
 	if len(uniqDepsWithTypes) > 0 {
 		depTreeResult.FlatTree, err = createFlatTreeWithTypes(uniqDepsWithTypes)
 		return
@@ -278,6 +449,84 @@ func GetTechDependencyTree(params xrayutils.AuditParams, artifactoryServerDetail
 	return
 }
 
+// Locate the declarations of the direct dependencies versions in the poms of the project in the current directory.
+func getDependenciesDeclarations(depTreeResult *DependencyTreeResult) error {
+	currentDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return err
+	}
+	depTreeResult.DependenciesDeclarations = java.GetMavenDependenciesDeclarations(currentDir, depTreeResult.FullDepTrees)
+	return nil
+}
+
+// Find the workspace members of the JavaScript project in the current directory, which are scanned as separate roots of the dependency trees.
+func getWorkspaceMembers(depTreeResult *DependencyTreeResult) (err error) {
+	currentDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return
+	}
+	depTreeResult.WorkspaceMembers, err = npm.GetWorkspaceMembersIds(currentDir, depTreeResult.FullDepTrees)
+	return
+}
+
+// Calculate the scopes (runtime, dev or test) of the dependencies in the trees.
+// Some package managers resolve the scope of each dependency with the tree, others declare the scopes of the direct dependencies in the project descriptor only.
+// The scopes are always calculated, since they are displayed and evaluated by the license policy, and the requested scopes only filter the dependencies.
+// Failing to read the descriptors doesn't fail the audit, the scopes of the dependencies are unknown and they are not filtered.
+func getDependenciesScopes(tech techutils.Technology, dependencyTrees []*xrayCmdUtils.GraphNode, knownScopes map[string][]string, uniqDepsWithTypes map[string]*xray.DepTreeNode) map[string][]string {
+	var directScopes map[string][]string
+	var err error
+	switch tech {
+	case techutils.Maven, techutils.Gradle:
+		knownScopes = java.GetDependenciesScopes(uniqDepsWithTypes, tech)
+	case techutils.Yarn, techutils.Pnpm, techutils.Pipenv, techutils.Poetry, techutils.Uv, techutils.Pdm, techutils.Nuget:
+		directScopes, err = getDirectDependenciesScopes(tech)
+	}
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't determine the scopes of the %s dependencies, they are not filtered by scope: %s", tech.ToFormal(), err.Error()))
+		return nil
+	}
+	if len(knownScopes) == 0 && len(directScopes) == 0 {
+		return nil
+	}
+	return sca.GetDependenciesScopes(dependencyTrees, knownScopes, directScopes)
+}
+
+// Read the scopes of the direct dependencies from the descriptors of the project in the current directory.
+func getDirectDependenciesScopes(tech techutils.Technology) (map[string][]string, error) {
+	currentDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return nil, err
+	}
+	switch tech {
+	case techutils.Yarn, techutils.Pnpm:
+		return npm.GetDirectDependenciesScopes(currentDir)
+	case techutils.Nuget:
+		return nuget.GetDirectDependenciesScopes(currentDir)
+	default:
+		return python.GetDirectDependenciesScopes(pythonutils.PythonTool(tech), currentDir)
+	}
+}
+
+// Remove the dependencies that are not in the requested scopes from the dependency trees and from the unique dependencies lists.
+func filterDependenciesByScopes(depTreeResult *DependencyTreeResult, uniqueDeps []string, uniqDepsWithTypes map[string]*xray.DepTreeNode, requestedScopes []string) ([]string, map[string]*xray.DepTreeNode) {
+	remaining := sca.FilterDependencyTreesByScopes(depTreeResult.FullDepTrees, depTreeResult.DependenciesScopes, requestedScopes)
+	var filteredUniqueDeps []string
+	for _, dependency := range uniqueDeps {
+		if remaining.Exists(dependency) {
+			filteredUniqueDeps = append(filteredUniqueDeps, dependency)
+		}
+	}
+	filteredDepsWithTypes := map[string]*xray.DepTreeNode{}
+	for dependency, nodeAttr := range uniqDepsWithTypes {
+		if remaining.Exists(dependency) {
+			filteredDepsWithTypes[dependency] = nodeAttr
+		}
+	}
+	log.Debug(fmt.Sprintf("Excluded %d dependencies that are not in the requested scopes: %s", len(uniqueDeps)+len(uniqDepsWithTypes)-len(filteredUniqueDeps)-len(filteredDepsWithTypes), strings.Join(requestedScopes, ", ")))
+	return filteredUniqueDeps, filteredDepsWithTypes
+}
+
 func getCurationCacheFolderAndLogMsg(params xrayutils.AuditParams, tech techutils.Technology) (logMessage string, curationCacheFolder string, err error) {
 	if !params.IsCurationCmd() {
 		return
@@ -375,12 +624,18 @@ func buildDependencyTree(scan *utils.ScaScanResult, params *AuditParams) (*Depen
 	if err != nil {
 		return nil, err
 	}
-	treeResult, techErr := GetTechDependencyTree(params.AuditBasicParams, serverDetails, scan.Technology)
-	if techErr != nil {
-		return nil, fmt.Errorf("failed while building '%s' dependency tree:\n%s", scan.Technology, techErr.Error())
-	}
-	if treeResult.FlatTree == nil || len(treeResult.FlatTree.Nodes) == 0 {
-		return nil, errorutils.CheckErrorf("no dependencies were found. Please try to build your project and re-run the audit command")
-	}
-	return &treeResult, nil
+	return buildDependencyTreeWithCache(scan, params, func() (*DependencyTreeResult, error) {
+		treeResult, techErr := GetTechDependencyTree(params.AuditBasicParams, serverDetails, scan.Technology)
+		if techErr != nil {
+			return nil, fmt.Errorf("failed while building '%s' dependency tree:\n%s", scan.Technology, techErr.Error())
+		}
+		if treeResult.FlatTree != nil && len(treeResult.FlatTree.Nodes) == 0 && len(params.DependenciesScopes()) > 0 {
+			// All the dependencies were excluded by their scopes
+			return &treeResult, nil
+		}
+		if treeResult.FlatTree == nil || len(treeResult.FlatTree.Nodes) == 0 {
+			return nil, errorutils.CheckErrorf("no dependencies were found. Please try to build your project and re-run the audit command")
+		}
+		return &treeResult, nil
+	})
 }
@@ -6,11 +6,14 @@ import (
 	"sort"
 	"testing"
 
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
 	xrayutils "github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	clientTests "github.com/jfrog/jfrog-client-go/utils/tests"
 
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	xscservices "github.com/jfrog/jfrog-client-go/xsc/services"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -207,6 +210,42 @@ func TestGetScaScansToPreform(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Test config profile modules",
+			wd:   dir,
+			params: func() *AuditParams {
+				param := NewAuditParams().SetWorkingDirs([]string{dir}).SetConfigProfile(&xscservices.ConfigProfile{
+					ProfileName: "sca-profile",
+					Modules: []xscservices.Module{
+						{
+							ModuleName:   "sca-module",
+							PathFromRoot: filepath.Join(dir, "dir"),
+							ExcludePaths: []string{"**/*maven*/**", "**/*.md"},
+							ScanConfig:   xscservices.ScanConfig{EnableScaScan: true},
+						},
+						{
+							ModuleName:   "no-sca-module",
+							PathFromRoot: filepath.Join(dir, "yarn"),
+							ScanConfig:   xscservices.ScanConfig{SecretsScannerConfig: xscservices.SecretsScannerConfig{EnableSecretsScan: true}},
+						},
+					},
+				})
+				param.SetIsRecursiveScan(true)
+				return param
+			},
+			expected: []*xrayutils.ScaScanResult{
+				{
+					Technology:  techutils.Npm,
+					Target:      filepath.Join(dir, "dir", "npm"),
+					Descriptors: []string{filepath.Join(dir, "dir", "npm", "package.json")},
+				},
+				{
+					Technology:  techutils.Go,
+					Target:      filepath.Join(dir, "dir", "go"),
+					Descriptors: []string{filepath.Join(dir, "dir", "go", "go.mod")},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -222,3 +261,58 @@ func TestGetScaScansToPreform(t *testing.T) {
 
 	cleanUp()
 }
+
+func TestGetConfigProfileScaExclusions(t *testing.T) {
+	profileModule := xscservices.Module{
+		ModuleName:   "module",
+		ExcludePaths: []string{"**/dist/**", "generated/**", "**/*.md"},
+		ScanConfig:   xscservices.ScanConfig{ExcludePattern: "vendor/**"},
+	}
+	// The excluded directories of the module are added to the default exclusions
+	assert.Equal(t, append(append([]string{}, xrayutils.DefaultScaExcludePatterns...), "*/dist/*", "*/generated/*", "*/vendor/*"), getConfigProfileScaExclusions(profileModule, nil))
+	// The exclusions of the audit replace the default exclusions
+	assert.Equal(t, []string{"*fixtures*", "*/dist/*", "*/generated/*", "*/vendor/*"}, getConfigProfileScaExclusions(profileModule, []string{"*fixtures*"}))
+	assert.Equal(t, xrayutils.DefaultScaExcludePatterns, getConfigProfileScaExclusions(xscservices.Module{}, nil))
+}
+
+func TestGetDependenciesScopes(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	projectDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "project.csproj"), []byte("<Project><ItemGroup>"), 0644))
+	defer clientTests.ChangeDirWithCallback(t, wd, projectDir)()
+	trees := []*xrayUtils.GraphNode{{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "nuget://dep:1.0.0"}}}}
+
+	// A descriptor that can't be parsed doesn't fail the audit
+	assert.Nil(t, getDependenciesScopes(techutils.Nuget, trees, nil, nil))
+	// The scopes that are resolved with the tree are kept
+	assert.Equal(t, map[string][]string{"nuget://dep:1.0.0": {techutils.DevScope}}, getDependenciesScopes(techutils.Npm, trees, map[string][]string{"nuget://dep:1.0.0": {techutils.DevScope}}, nil))
+	// The descriptors are read even if no scopes were requested
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "project.csproj"), []byte(`<Project><ItemGroup><PackageReference Include="Dep" Version="1.0.0" PrivateAssets="all" /></ItemGroup></Project>`), 0644))
+	assert.Equal(t, map[string][]string{"nuget://dep:1.0.0": {techutils.DevScope}}, getDependenciesScopes(techutils.Nuget, trees, nil, nil))
+}
+
+func TestIsScannedByContextualAnalysis(t *testing.T) {
+	rootDir := t.TempDir()
+	target := filepath.Join(rootDir, "service")
+	appsConfig := &jfrogappsconfig.JFrogAppsConfig{Modules: []jfrogappsconfig.Module{{SourceRoot: rootDir}}}
+	results := &xrayutils.Results{ExtendedScanResults: &xrayutils.ExtendedScanResults{EntitledForJas: true}}
+	params := NewAuditParams()
+
+	assert.True(t, isScannedByContextualAnalysis(techutils.Npm, target, params, results, appsConfig))
+	assert.True(t, isScannedByContextualAnalysis(techutils.Npm, rootDir, params, results, appsConfig))
+	assert.False(t, isScannedByContextualAnalysis(techutils.Npm, t.TempDir(), params, results, appsConfig))
+	// The Contextual Analysis scanner doesn't analyze the Go CVEs
+	assert.False(t, isScannedByContextualAnalysis(techutils.Go, target, params, results, appsConfig))
+	// The module excludes the Contextual Analysis scanner
+	assert.False(t, isScannedByContextualAnalysis(techutils.Npm, target, params, results, &jfrogappsconfig.JFrogAppsConfig{Modules: []jfrogappsconfig.Module{{SourceRoot: rootDir, ExcludeScanners: []string{"applicability"}}}}))
+	// The target is excluded from the scan of the module
+	assert.False(t, isScannedByContextualAnalysis(techutils.Npm, target, params, results, &jfrogappsconfig.JFrogAppsConfig{Modules: []jfrogappsconfig.Module{{SourceRoot: rootDir, ExcludePatterns: []string{"**/service/**"}}}}))
+	// The Contextual Analysis scan wasn't requested
+	params.SetScansToPerform([]xrayutils.SubScanType{xrayutils.ScaScan})
+	assert.False(t, isScannedByContextualAnalysis(techutils.Npm, target, params, results, appsConfig))
+	// Not entitled
+	params.SetScansToPerform(nil)
+	results.ExtendedScanResults.EntitledForJas = false
+	assert.False(t, isScannedByContextualAnalysis(techutils.Npm, target, params, results, appsConfig))
+}
@@ -0,0 +1,256 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-security/formats"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+const (
+	componentIdTypeSeparator = "://"
+	directDependencyMark     = " (direct dependency)"
+)
+
+// Explains how a package enters the project: finds all the paths from the project roots (or modules) to the package in the dependency trees.
+// The dependency trees are built locally, Xray isn't required.
+type AuditWhyCommand struct {
+	packageName    string
+	packageVersion string
+	outputFormat   format.OutputFormat
+	AuditParams
+}
+
+// The paths to the package in the dependency trees of a single project.
+type DependencyPathsResult struct {
+	Target     string               `json:"target"`
+	Technology techutils.Technology `json:"technology"`
+	Paths      []DependencyPath     `json:"paths"`
+}
+
+type DependencyPath struct {
+	// The direct dependency of the project that brings the package. Upgrade it to upgrade the package.
+	DirectDependency formats.ComponentRow `json:"directDependency"`
+	// The path from the project root (or module) to the package.
+	Path []formats.ComponentRow `json:"path"`
+}
+
+func NewAuditWhyCommand() *AuditWhyCommand {
+	return &AuditWhyCommand{AuditParams: *NewAuditParams()}
+}
+
+// The package is provided as <name>[@<version>], for example: 'lodash', 'lodash@4.17.20' or '@types/node@20.1.0'.
+func (awc *AuditWhyCommand) SetPackage(pkg string) *AuditWhyCommand {
+	awc.packageName, awc.packageVersion = parsePackage(pkg)
+	return awc
+}
+
+func (awc *AuditWhyCommand) SetOutputFormat(outputFormat format.OutputFormat) *AuditWhyCommand {
+	awc.outputFormat = outputFormat
+	return awc
+}
+
+func (awc *AuditWhyCommand) CommandName() string {
+	return "audit_why"
+}
+
+func (awc *AuditWhyCommand) Run() (err error) {
+	if awc.packageName == "" {
+		return errorutils.CheckErrorf("a package name must be provided")
+	}
+	if awc.outputFormat != "" && awc.outputFormat != format.Table && awc.outputFormat != format.Json {
+		return errorutils.CheckErrorf("unsupported output format '%s'. Supported formats: %s, %s", awc.outputFormat, format.Table, format.Json)
+	}
+	// If no workingDirs were provided by the user, we search the whole repository
+	isRecursiveScan := len(awc.workingDirs) == 0
+	workingDirs, err := coreutils.GetFullPathsWorkingDirs(awc.workingDirs)
+	if err != nil {
+		return
+	}
+	awc.SetWorkingDirs(workingDirs).SetIsRecursiveScan(isRecursiveScan)
+	currentWorkingDir, err := os.Getwd()
+	if errorutils.CheckError(err) != nil {
+		return
+	}
+	defer func() {
+		// Make sure to return to the original working directory, buildDependencyTree may change it
+		err = errors.Join(err, os.Chdir(currentWorkingDir))
+	}()
+	scans := getScaScansToPreform(&awc.AuditParams)
+	if len(scans) == 0 {
+		return errorutils.CheckErrorf("couldn't determine a package manager or build tool used by this project")
+	}
+	var results []DependencyPathsResult
+	for _, scan := range scans {
+		treeResult, bdtErr := buildDependencyTree(scan, &awc.AuditParams)
+		if bdtErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to build the dependency tree of '%s':\n%s", scan.Target, bdtErr.Error()))
+			continue
+		}
+		if paths := FindDependencyPaths(treeResult.FullDepTrees, awc.packageName, awc.packageVersion); len(paths) > 0 {
+			results = append(results, DependencyPathsResult{Target: scan.Target, Technology: scan.Technology, Paths: paths})
+		}
+	}
+	if awc.Progress() != nil {
+		err = errors.Join(err, awc.Progress().Quit())
+	}
+	return errors.Join(err, awc.printResults(results))
+}
+
+func (awc *AuditWhyCommand) printResults(results []DependencyPathsResult) error {
+	if awc.outputFormat == format.Json {
+		if results == nil {
+			results = []DependencyPathsResult{}
+		}
+		return utils.PrintJson(results)
+	}
+	pkg := awc.packageName
+	if awc.packageVersion != "" {
+		pkg += "@" + awc.packageVersion
+	}
+	if len(results) == 0 {
+		log.Output(fmt.Sprintf("The package '%s' wasn't found in the dependencies of the project.", pkg))
+		return nil
+	}
+	for _, result := range results {
+		log.Output(fmt.Sprintf("Found %d paths to '%s' in '%s' (%s):", len(result.Paths), pkg, result.Target, result.Technology.ToFormal()))
+		log.Output(renderDependencyPaths(result.Paths))
+	}
+	return nil
+}
+
+func parsePackage(pkg string) (name, version string) {
+	pkg = strings.TrimSpace(pkg)
+	// Scoped npm packages start with '@'
+	if versionIndex := strings.LastIndex(pkg, "@"); versionIndex > 0 {
+		return pkg[:versionIndex], pkg[versionIndex+1:]
+	}
+	return pkg, ""
+}
+
+// Find all the paths from the roots of the dependency trees to the given package.
+// If the version is empty, all the versions of the package are matched.
+func FindDependencyPaths(dependencyTrees []*xrayCmdUtils.GraphNode, packageName, packageVersion string) (paths []DependencyPath) {
+	foundPaths := map[string][]string{}
+	for _, root := range dependencyTrees {
+		for _, child := range root.Nodes {
+			findPathsToPackage(child, []string{root.Id}, packageName, packageVersion, foundPaths)
+		}
+	}
+	keys := make([]string, 0, len(foundPaths))
+	for key := range foundPaths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := foundPaths[key]
+		dependencyPath := DependencyPath{DirectDependency: toComponentRow(path[1])}
+		for _, componentId := range path {
+			dependencyPath.Path = append(dependencyPath.Path, toComponentRow(componentId))
+		}
+		paths = append(paths, dependencyPath)
+	}
+	return
+}
+
+// The trees are built without loops, so each path is finite.
+func findPathsToPackage(node *xrayCmdUtils.GraphNode, parentPath []string, packageName, packageVersion string, foundPaths map[string][]string) {
+	path := append(append([]string{}, parentPath...), node.Id)
+	if isPackageComponent(node.Id, packageName, packageVersion) {
+		foundPaths[strings.Join(path, " > ")] = path
+		return
+	}
+	for _, child := range node.Nodes {
+		findPathsToPackage(child, path, packageName, packageVersion, foundPaths)
+	}
+}
+
+func isPackageComponent(componentId, packageName, packageVersion string) bool {
+	if !strings.Contains(componentId, componentIdTypeSeparator) {
+		return false
+	}
+	name, version, _ := utils.SplitComponentId(componentId)
+	if packageVersion != "" && strings.TrimPrefix(version, "v") != strings.TrimPrefix(packageVersion, "v") {
+		return false
+	}
+	if strings.EqualFold(name, packageName) {
+		return true
+	}
+	// Maven packages can be provided by their artifact ID only
+	if strings.HasPrefix(componentId, "gav"+componentIdTypeSeparator) && strings.HasSuffix(strings.ToLower(name), ":"+strings.ToLower(packageName)) {
+		return true
+	}
+	// Python treats '-', '_' and '.' the same
+	normalize := strings.NewReplacer("_", "-", ".", "-").Replace
+	return strings.HasPrefix(componentId, "pypi"+componentIdTypeSeparator) && strings.EqualFold(normalize(name), normalize(packageName))
+}
+
+func toComponentRow(componentId string) formats.ComponentRow {
+	name, version, _ := utils.SplitComponentId(componentId)
+	return formats.ComponentRow{Name: name, Version: version}
+}
+
+type dependencyPathsNode struct {
+	component formats.ComponentRow
+	children  []*dependencyPathsNode
+}
+
+func (dpn *dependencyPathsNode) getOrAddChild(component formats.ComponentRow) *dependencyPathsNode {
+	for _, child := range dpn.children {
+		if child.component == component {
+			return child
+		}
+	}
+	child := &dependencyPathsNode{component: component}
+	dpn.children = append(dpn.children, child)
+	return child
+}
+
+// Render the paths as a tree, merging their common prefixes. The direct dependencies are marked.
+func renderDependencyPaths(paths []DependencyPath) string {
+	roots := &dependencyPathsNode{}
+	for _, path := range paths {
+		current := roots
+		for _, component := range path.Path {
+			current = current.getOrAddChild(component)
+		}
+	}
+	builder := &strings.Builder{}
+	for _, root := range roots.children {
+		builder.WriteString(getComponentDisplayName(root.component) + "\n")
+		renderDependencyPathsChildren(builder, root, "", true)
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func renderDependencyPathsChildren(builder *strings.Builder, node *dependencyPathsNode, prefix string, isDirect bool) {
+	for i, child := range node.children {
+		connector, childPrefix := "├── ", "│   "
+		if i == len(node.children)-1 {
+			connector, childPrefix = "└── ", "    "
+		}
+		line := prefix + connector + getComponentDisplayName(child.component)
+		if isDirect {
+			line += directDependencyMark
+		}
+		builder.WriteString(line + "\n")
+		renderDependencyPathsChildren(builder, child, prefix+childPrefix, false)
+	}
+}
+
+func getComponentDisplayName(component formats.ComponentRow) string {
+	if component.Version == "" {
+		return component.Name
+	}
+	return component.Name + ":" + component.Version
+}
@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/formats"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createWhyTestTrees() []*xrayCmdUtils.GraphNode {
+	return []*xrayCmdUtils.GraphNode{{
+		Id: "npm://app:1.0.0",
+		Nodes: []*xrayCmdUtils.GraphNode{
+			{Id: "npm://express:4.18.2", Nodes: []*xrayCmdUtils.GraphNode{
+				{Id: "npm://body-parser:1.20.1", Nodes: []*xrayCmdUtils.GraphNode{{Id: "npm://qs:6.11.0"}}},
+				{Id: "npm://qs:6.11.0"},
+			}},
+			{Id: "npm://qs:6.5.3"},
+			{Id: "npm://@types/node:20.1.0"},
+		},
+	}}
+}
+
+func TestParsePackage(t *testing.T) {
+	testCases := []struct {
+		pkg             string
+		expectedName    string
+		expectedVersion string
+	}{
+		{pkg: "lodash", expectedName: "lodash"},
+		{pkg: "lodash@4.17.21", expectedName: "lodash", expectedVersion: "4.17.21"},
+		{pkg: "@types/node", expectedName: "@types/node"},
+		{pkg: "@types/node@20.1.0", expectedName: "@types/node", expectedVersion: "20.1.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pkg, func(t *testing.T) {
+			name, version := parsePackage(tc.pkg)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestFindDependencyPaths(t *testing.T) {
+	paths := FindDependencyPaths(createWhyTestTrees(), "qs", "6.11.0")
+	if assert.Len(t, paths, 2) {
+		assert.Equal(t, formats.ComponentRow{Name: "express", Version: "4.18.2"}, paths[0].DirectDependency)
+		assert.Equal(t, []formats.ComponentRow{{Name: "app", Version: "1.0.0"}, {Name: "express", Version: "4.18.2"}, {Name: "body-parser", Version: "1.20.1"}, {Name: "qs", Version: "6.11.0"}}, paths[0].Path)
+		assert.Len(t, paths[1].Path, 3)
+	}
+	// All the versions
+	assert.Len(t, FindDependencyPaths(createWhyTestTrees(), "qs", ""), 3)
+	// A direct dependency is the dependency to upgrade
+	paths = FindDependencyPaths(createWhyTestTrees(), "@types/node", "")
+	if assert.Len(t, paths, 1) {
+		assert.Equal(t, formats.ComponentRow{Name: "@types/node", Version: "20.1.0"}, paths[0].DirectDependency)
+	}
+	assert.Empty(t, FindDependencyPaths(createWhyTestTrees(), "lodash", ""))
+}
+
+func TestIsPackageComponent(t *testing.T) {
+	assert.True(t, isPackageComponent("gav://org.apache.logging.log4j:log4j-core:2.14.1", "log4j-core", "2.14.1"))
+	assert.True(t, isPackageComponent("gav://org.apache.logging.log4j:log4j-core:2.14.1", "org.apache.logging.log4j:log4j-core", ""))
+	assert.True(t, isPackageComponent("go://golang.org/x/net:v0.17.0", "golang.org/x/net", "0.17.0"))
+	assert.True(t, isPackageComponent("pypi://typing_extensions:4.8.0", "typing-extensions", ""))
+	assert.False(t, isPackageComponent("npm://typing_extensions:4.8.0", "typing-extensions", ""))
+	assert.False(t, isPackageComponent("npm://qs:6.5.3", "qs", "6.11.0"))
+	assert.False(t, isPackageComponent("root", "root", ""))
+}
+
+func TestRenderDependencyPaths(t *testing.T) {
+	expected := `app:1.0.0
+├── express:4.18.2 (direct dependency)
+│   ├── body-parser:1.20.1
+│   │   └── qs:6.11.0
+│   └── qs:6.11.0
+└── qs:6.5.3 (direct dependency)`
+	assert.Equal(t, expected, renderDependencyPaths(FindDependencyPaths(createWhyTestTrees(), "qs", "")))
+}
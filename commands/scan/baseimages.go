@@ -0,0 +1,343 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	"github.com/jfrog/jfrog-cli-security/utils/xray/scangraph"
+	"github.com/jfrog/jfrog-cli-security/utils/xsc"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	// The maximal number of newer tags of the base image to scan.
+	maxBaseImageCandidates = 5
+	// The maximal number of tags of the base image to match with the image history, when identifying the tag the image was built from.
+	maxBaseImageHistoryMatches = 10
+)
+
+// Matches versioned tags, for example: '3.19', 'v1.22.1', '20.04-slim', '1.22-alpine3.19'
+var versionedTagPattern = regexp.MustCompile(`^v?(?P<version>\d+(?:\.\d+)*)(?P<suffix>.*)$`)
+
+type BaseImageCandidate struct {
+	Image    string `json:"image"`
+	Current  bool   `json:"current,omitempty"`
+	Critical int    `json:"critical"`
+	High     int    `json:"high"`
+	Medium   int    `json:"medium"`
+	Low      int    `json:"low"`
+	Unknown  int    `json:"unknown"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (bic *BaseImageCandidate) Total() int {
+	return bic.Critical + bic.High + bic.Medium + bic.Low + bic.Unknown
+}
+
+type BaseImageCandidateTable struct {
+	Rank     string `col-name:"Rank"`
+	Image    string `col-name:"Base Image"`
+	Critical string `col-name:"Critical"`
+	High     string `col-name:"High"`
+	Medium   string `col-name:"Medium"`
+	Low      string `col-name:"Low"`
+	Unknown  string `col-name:"Unknown"`
+	Total    string `col-name:"Total"`
+}
+
+// Scan newer tags of the base image (fetched from the Artifactory Docker repository) and print them ranked by their vulnerabilities, compared to the current base image.
+func (dsc *DockerScanCommand) suggestBaseImageUpgrades(xrayVersion string) (err error) {
+	baseImage := dsc.getBaseImage()
+	if baseImage == "" {
+		log.Warn("Couldn't identify the base image of the scanned image, skipping base image suggestions. Use the --dockerfile option to provide the Dockerfile the image was built from.")
+		return
+	}
+	if dsc.baseImagesRepo == "" {
+		return errorutils.CheckErrorf("a Docker remote repository is required for suggesting base images. Use the --base-images-repo option to provide it")
+	}
+	imageName, currentTag := parseImageReference(baseImage)
+	log.Info(fmt.Sprintf("Searching for newer tags of the base image %s in the '%s' repository...", baseImage, dsc.baseImagesRepo))
+	registryClient, err := newDockerRegistryClient(dsc.serverDetails, dsc.baseImagesRepo)
+	if err != nil {
+		return
+	}
+	tags, err := registryClient.listTags(imageName)
+	if err != nil {
+		return
+	}
+	currentTag = dsc.identifyBaseImageTag(registryClient, imageName, currentTag, tags)
+	candidateTags, err := getNewerCandidateTags(currentTag, tags, maxBaseImageCandidates)
+	if err != nil {
+		return
+	}
+	if len(candidateTags) == 0 {
+		log.Info(fmt.Sprintf("No newer tags were found for the base image %s.", baseImage))
+		return
+	}
+	candidates := []*BaseImageCandidate{}
+	for _, tag := range append([]string{currentTag}, candidateTags...) {
+		if dsc.progress != nil {
+			dsc.progress.SetHeadlineMsg(fmt.Sprintf("Scanning base image candidate %s:%s 🔍", imageName, tag))
+		}
+		candidate := &BaseImageCandidate{Image: imageName + ":" + tag, Current: tag == currentTag}
+		if e := dsc.scanBaseImageCandidate(registryClient, imageName, tag, xrayVersion, candidate); e != nil {
+			log.Warn(fmt.Sprintf("Failed to scan the base image candidate %s: %s", candidate.Image, e.Error()))
+			candidate.Error = e.Error()
+		}
+		candidates = append(candidates, candidate)
+	}
+	rankBaseImageCandidates(candidates)
+	return printBaseImageCandidates(candidates, dsc.outputFormat)
+}
+
+// The base image is taken from the Dockerfile (if provided), or from the image labels
+func (dsc *DockerScanCommand) getBaseImage() string {
+	if dsc.dockerImageLayers == nil {
+		return ""
+	}
+	return dsc.dockerImageLayers.BaseImage
+}
+
+// The tag of the base image may be a moving tag (for example: 'latest' or '3.19'), that was updated since the image was built.
+// The tag that the image was built from is identified by matching the layers in the image history with the layers of the base image tags, newest first.
+func (dsc *DockerScanCommand) identifyBaseImageTag(registryClient *dockerRegistryClient, imageName, currentTag string, tags []string) string {
+	if dsc.dockerImageLayers == nil || len(dsc.dockerImageLayers.DiffIds) == 0 {
+		return currentTag
+	}
+	if _, _, versioned := parseVersionedTag(currentTag); versioned {
+		if diffIds, err := registryClient.getImageDiffIds(imageName, currentTag); err == nil && dsc.dockerImageLayers.IsBuiltOn(diffIds) {
+			return currentTag
+		}
+	}
+	for _, tag := range getHistoryMatchCandidateTags(currentTag, tags, maxBaseImageHistoryMatches) {
+		diffIds, err := registryClient.getImageDiffIds(imageName, tag)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Failed to get the layers of %s:%s: %s", imageName, tag, err.Error()))
+			continue
+		}
+		if dsc.dockerImageLayers.IsBuiltOn(diffIds) {
+			log.Info(fmt.Sprintf("According to the image history, the image was built from the base image %s:%s.", imageName, tag))
+			return tag
+		}
+	}
+	log.Debug(fmt.Sprintf("The image history doesn't match the recent tags of %s, comparing with the tag '%s'.", imageName, currentTag))
+	return currentTag
+}
+
+func (dsc *DockerScanCommand) scanBaseImageCandidate(registryClient *dockerRegistryClient, imageName, tag, xrayVersion string, candidate *BaseImageCandidate) (err error) {
+	tempDirPath, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := fileutils.RemoveTempDir(tempDirPath); err == nil {
+			err = e
+		}
+	}()
+	layoutDir := filepath.Join(tempDirPath, "layout")
+	if err = registryClient.downloadImageLayout(imageName, tag, layoutDir); err != nil {
+		return
+	}
+	imageTarPath, err := ImageSource{Type: OciLayoutImageSource, Reference: layoutDir}.CreateImageArchive(tempDirPath)
+	if err != nil {
+		return
+	}
+	graph, err := dsc.indexFile(imageTarPath)
+	if err != nil {
+		return
+	}
+	if graph.Id == "" {
+		return errorutils.CheckErrorf("the image is not supported by the Xray indexer app")
+	}
+	params := &services.XrayGraphScanParams{
+		BinaryGraph:            graph,
+		IncludeVulnerabilities: true,
+		ScanType:               services.Binary,
+	}
+	params.MultiScanId, params.XscVersion = xsc.GetXscMsiAndVersion(dsc.analyticsMetricsService)
+	scanGraphParams := scangraph.NewScanGraphParams().
+		SetServerDetails(dsc.serverDetails).
+		SetXrayGraphScanParams(params).
+		SetXrayVersion(xrayVersion).
+		SetSeverityLevel(dsc.minSeverityFilter.String())
+	xrayManager, err := xray.CreateXrayServiceManager(dsc.serverDetails)
+	if err != nil {
+		return
+	}
+	scanResults, err := scangraph.RunScanGraphAndGetResults(scanGraphParams, xrayManager)
+	if err != nil {
+		return
+	}
+	countVulnerabilitiesBySeverity(scanResults.Vulnerabilities, candidate)
+	return
+}
+
+// Count each issue once, even if it affects multiple components of the image.
+func countVulnerabilitiesBySeverity(vulnerabilities []services.Vulnerability, candidate *BaseImageCandidate) {
+	issues := map[string]bool{}
+	for _, vulnerability := range vulnerabilities {
+		if issues[vulnerability.IssueId] {
+			continue
+		}
+		issues[vulnerability.IssueId] = true
+		switch severityutils.GetSeverity(vulnerability.Severity) {
+		case severityutils.Critical:
+			candidate.Critical++
+		case severityutils.High:
+			candidate.High++
+		case severityutils.Medium:
+			candidate.Medium++
+		case severityutils.Low:
+			candidate.Low++
+		default:
+			candidate.Unknown++
+		}
+	}
+}
+
+// Get the newest tags that are newer than the current tag, with the same major version, version format and variant suffix (for example: '-alpine').
+// Major version upgrades usually require changes in the image, so they are not suggested.
+func getNewerCandidateTags(currentTag string, tags []string, limit int) ([]string, error) {
+	currentVersion, currentSuffix, ok := parseVersionedTag(currentTag)
+	if !ok {
+		return nil, errorutils.CheckErrorf("the base image tag '%s' is not versioned, can't suggest newer tags", currentTag)
+	}
+	return getNewestVersionedTags(tags, limit, func(version []int, suffix string) bool {
+		return suffix == currentSuffix && len(version) == len(currentVersion) && version[0] == currentVersion[0] && compareVersions(version, currentVersion) > 0
+	}), nil
+}
+
+// Get the newest versioned tags that the image may have been built from. If the current tag is versioned, only tags with the same variant suffix are matched.
+func getHistoryMatchCandidateTags(currentTag string, tags []string, limit int) []string {
+	_, currentSuffix, versioned := parseVersionedTag(currentTag)
+	return getNewestVersionedTags(tags, limit, func(_ []int, suffix string) bool {
+		return !versioned || suffix == currentSuffix
+	})
+}
+
+func getNewestVersionedTags(tags []string, limit int, filter func(version []int, suffix string) bool) []string {
+	type versionedTag struct {
+		tag     string
+		version []int
+	}
+	var matchingTags []versionedTag
+	for _, tag := range tags {
+		if version, suffix, ok := parseVersionedTag(tag); ok && filter(version, suffix) {
+			matchingTags = append(matchingTags, versionedTag{tag: tag, version: version})
+		}
+	}
+	sort.SliceStable(matchingTags, func(i, j int) bool {
+		return compareVersions(matchingTags[i].version, matchingTags[j].version) > 0
+	})
+	newestTags := []string{}
+	for i := 0; i < len(matchingTags) && i < limit; i++ {
+		newestTags = append(newestTags, matchingTags[i].tag)
+	}
+	return newestTags
+}
+
+func parseVersionedTag(tag string) (version []int, suffix string, ok bool) {
+	matches := versionedTagPattern.FindStringSubmatch(tag)
+	if len(matches) == 0 {
+		return
+	}
+	for _, part := range strings.Split(matches[versionedTagPattern.SubexpIndex("version")], ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, "", false
+		}
+		version = append(version, number)
+	}
+	return version, matches[versionedTagPattern.SubexpIndex("suffix")], true
+}
+
+func compareVersions(version1, version2 []int) int {
+	for i := 0; i < len(version1) && i < len(version2); i++ {
+		if version1[i] != version2[i] {
+			return version1[i] - version2[i]
+		}
+	}
+	return len(version1) - len(version2)
+}
+
+// Rank by the number of vulnerabilities of the highest severity first, candidates that failed to scan are last.
+func rankBaseImageCandidates(candidates []*BaseImageCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if (candidates[i].Error == "") != (candidates[j].Error == "") {
+			return candidates[i].Error == ""
+		}
+		for _, counts := range [][2]int{
+			{candidates[i].Critical, candidates[j].Critical},
+			{candidates[i].High, candidates[j].High},
+			{candidates[i].Medium, candidates[j].Medium},
+			{candidates[i].Low, candidates[j].Low},
+		} {
+			if counts[0] != counts[1] {
+				return counts[0] < counts[1]
+			}
+		}
+		return candidates[i].Total() < candidates[j].Total()
+	})
+}
+
+// The suggestions are printed as a table after the scan results. For the other output formats, the standard output holds the results document only, so the suggestions are logged.
+func printBaseImageCandidates(candidates []*BaseImageCandidate, outputFormat format.OutputFormat) error {
+	if outputFormat != format.Table {
+		content, err := json.MarshalIndent(candidates, "", "  ")
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Info("Base image suggestions:\n" + string(content))
+		return nil
+	}
+	var current *BaseImageCandidate
+	for _, candidate := range candidates {
+		if candidate.Current {
+			current = candidate
+		}
+	}
+	var table []BaseImageCandidateTable
+	for i, candidate := range candidates {
+		row := BaseImageCandidateTable{Rank: strconv.Itoa(i + 1), Image: candidate.Image}
+		if candidate.Current {
+			row.Image += " (current)"
+		}
+		if candidate.Error != "" {
+			row.Critical, row.High, row.Medium, row.Low, row.Unknown, row.Total = "-", "-", "-", "-", "-", "-"
+			table = append(table, row)
+			continue
+		}
+		row.Critical = formatCountWithDiff(candidate.Critical, current, func(c *BaseImageCandidate) int { return c.Critical })
+		row.High = formatCountWithDiff(candidate.High, current, func(c *BaseImageCandidate) int { return c.High })
+		row.Medium = formatCountWithDiff(candidate.Medium, current, func(c *BaseImageCandidate) int { return c.Medium })
+		row.Low = formatCountWithDiff(candidate.Low, current, func(c *BaseImageCandidate) int { return c.Low })
+		row.Unknown = formatCountWithDiff(candidate.Unknown, current, func(c *BaseImageCandidate) int { return c.Unknown })
+		row.Total = formatCountWithDiff(candidate.Total(), current, (*BaseImageCandidate).Total)
+		table = append(table, row)
+	}
+	return coreutils.PrintTable(table, "Base Image Suggestions", "No base image suggestions were found", true)
+}
+
+// Format the count with the difference from the current base image, for example: '3 (-2)'
+func formatCountWithDiff(count int, current *BaseImageCandidate, getCount func(*BaseImageCandidate) int) string {
+	if current == nil || current.Error != "" {
+		return strconv.Itoa(count)
+	}
+	diff := count - getCount(current)
+	if diff == 0 {
+		return strconv.Itoa(count)
+	}
+	return fmt.Sprintf("%d (%+d)", count, diff)
+}
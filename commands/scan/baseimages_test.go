@@ -0,0 +1,83 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageReference(t *testing.T) {
+	testCases := []struct {
+		image        string
+		expectedName string
+		expectedTag  string
+	}{
+		{image: "alpine:3.19", expectedName: "library/alpine", expectedTag: "3.19"},
+		{image: "alpine", expectedName: "library/alpine", expectedTag: "latest"},
+		{image: "docker.io/library/node:20-slim", expectedName: "library/node", expectedTag: "20-slim"},
+		{image: "bitnami/redis:7.2", expectedName: "bitnami/redis", expectedTag: "7.2"},
+		{image: "gcr.io/distroless/static:nonroot", expectedName: "distroless/static", expectedTag: "nonroot"},
+		{image: "localhost:5000/app/base:1.0@sha256:abcd", expectedName: "app/base", expectedTag: "1.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			name, tag := parseImageReference(tc.image)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedTag, tag)
+		})
+	}
+}
+
+func TestGetNewerCandidateTags(t *testing.T) {
+	tags := []string{"latest", "3.17", "3.18", "3.18.4", "3.19", "3.20", "3.9", "3.20-slim", "edge", "4.0", "3.21"}
+	// Major version upgrades are not suggested
+	candidates, err := getNewerCandidateTags("3.18", tags, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.21", "3.20", "3.19"}, candidates)
+
+	candidates, err = getNewerCandidateTags("3.19-slim", tags, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.20-slim"}, candidates)
+
+	_, err = getNewerCandidateTags("latest", tags, 3)
+	assert.Error(t, err)
+}
+
+func TestGetHistoryMatchCandidateTags(t *testing.T) {
+	tags := []string{"latest", "3.18", "3.19", "3.20-slim", "edge", "4.0"}
+	assert.Equal(t, []string{"4.0", "3.20-slim", "3.19"}, getHistoryMatchCandidateTags("latest", tags, 3))
+	assert.Equal(t, []string{"4.0", "3.19", "3.18"}, getHistoryMatchCandidateTags("3.18", tags, 5))
+	assert.Equal(t, []string{"3.20-slim"}, getHistoryMatchCandidateTags("3.19-slim", tags, 5))
+}
+
+func TestCountVulnerabilitiesBySeverity(t *testing.T) {
+	candidate := &BaseImageCandidate{}
+	countVulnerabilitiesBySeverity([]services.Vulnerability{
+		{IssueId: "XRAY-1", Severity: "Critical"},
+		{IssueId: "XRAY-1", Severity: "Critical"},
+		{IssueId: "XRAY-2", Severity: "High"},
+		{IssueId: "XRAY-3", Severity: "Low"},
+		{IssueId: "XRAY-4", Severity: "Other"},
+	}, candidate)
+	assert.Equal(t, BaseImageCandidate{Critical: 1, High: 1, Low: 1, Unknown: 1}, *candidate)
+	assert.Equal(t, 4, candidate.Total())
+}
+
+func TestRankBaseImageCandidates(t *testing.T) {
+	candidates := []*BaseImageCandidate{
+		{Image: "alpine:3.18", Current: true, Critical: 1, High: 3},
+		{Image: "alpine:3.21", Error: "failed"},
+		{Image: "alpine:3.20", High: 5},
+		{Image: "alpine:3.19", High: 2, Low: 4},
+		{Image: "alpine:3.22", High: 2, Low: 1},
+	}
+	rankBaseImageCandidates(candidates)
+	var ranked []string
+	for _, candidate := range candidates {
+		ranked = append(ranked, candidate.Image)
+	}
+	assert.Equal(t, []string{"alpine:3.22", "alpine:3.19", "alpine:3.20", "alpine:3.18", "alpine:3.21"}, ranked)
+	assert.Equal(t, "3 (+2)", formatCountWithDiff(3, &BaseImageCandidate{High: 1}, func(c *BaseImageCandidate) int { return c.High }))
+	assert.Equal(t, "1", formatCountWithDiff(1, &BaseImageCandidate{High: 1}, func(c *BaseImageCandidate) int { return c.High }))
+}
@@ -0,0 +1,280 @@
+package scan
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The transports prefixes are compatible with the image references used by skopeo, podman and buildah.
+const (
+	dockerArchiveTransport     = "docker-archive:"
+	ociArchiveTransport        = "oci-archive:"
+	ociLayoutTransport         = "oci:"
+	podmanTransport            = "podman:"
+	containersStorageTransport = "containers-storage:"
+	ociLayoutFileName          = "oci-layout"
+	ociIndexFileName           = "index.json"
+	dockerCliName              = "docker"
+	podmanCliName              = "podman"
+	// The annotation of the manifests in the index of an OCI image layout, that holds the tag of the image.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+type ImageSourceType string
+
+const (
+	// The image is saved from the local Docker daemon ('docker save').
+	DockerDaemonImageSource ImageSourceType = "docker-daemon"
+	// The image is saved from the local podman storage ('podman save').
+	PodmanImageSource ImageSourceType = "podman"
+	// The image is an existing tarball, created by 'docker save' or 'podman save --format docker-archive'.
+	DockerArchiveImageSource ImageSourceType = "docker-archive"
+	// The image is an existing OCI tarball, created by buildah, kaniko or 'podman save --format oci-archive'.
+	OciArchiveImageSource ImageSourceType = "oci-archive"
+	// The image is an OCI image layout directory.
+	OciLayoutImageSource ImageSourceType = "oci-layout"
+)
+
+type ImageSource struct {
+	Type ImageSourceType
+	// The image tag (daemon/podman) or the path (archive/layout) of the image.
+	Reference string
+	// The tag of the image in an OCI image layout directory ('oci:<path>:<tag>'). If empty, all the images in the layout are scanned.
+	Tag string
+}
+
+// Resolve the source of the image from the provided argument.
+// Explicit transport prefixes are checked first, then paths to existing archives/OCI layout directories.
+// Otherwise, the argument is treated as an image tag to be saved from the Docker daemon.
+func ResolveImageSource(image string) ImageSource {
+	for prefix, sourceType := range map[string]ImageSourceType{
+		dockerArchiveTransport:     DockerArchiveImageSource,
+		ociArchiveTransport:        OciArchiveImageSource,
+		ociLayoutTransport:         OciLayoutImageSource,
+		podmanTransport:            PodmanImageSource,
+		containersStorageTransport: PodmanImageSource,
+	} {
+		if !strings.HasPrefix(image, prefix) {
+			continue
+		}
+		reference := strings.TrimPrefix(image, prefix)
+		if sourceType == OciLayoutImageSource {
+			layoutDir, tag := splitOciLayoutReference(reference)
+			return ImageSource{Type: sourceType, Reference: layoutDir, Tag: tag}
+		}
+		return ImageSource{Type: sourceType, Reference: reference}
+	}
+	if isOciLayoutDir(image) {
+		return ImageSource{Type: OciLayoutImageSource, Reference: image}
+	}
+	if exists, err := fileutils.IsFileExists(image, false); err == nil && exists {
+		return ImageSource{Type: DockerArchiveImageSource, Reference: image}
+	}
+	return ImageSource{Type: DockerDaemonImageSource, Reference: image}
+}
+
+// Split an OCI image layout reference ('<path>[:<tag>]') into the layout directory and the tag.
+// The reference is a path without a tag if it is an existing OCI layout directory, or if the suffix after the last colon is a path (e.g. 'C:\layout').
+func splitOciLayoutReference(reference string) (layoutDir, tag string) {
+	if isOciLayoutDir(reference) {
+		return reference, ""
+	}
+	separatorIndex := strings.LastIndex(reference, ":")
+	if separatorIndex <= 0 {
+		return reference, ""
+	}
+	if suffix := reference[separatorIndex+1:]; suffix != "" && !strings.ContainsAny(suffix, `/\`) {
+		return reference[:separatorIndex], suffix
+	}
+	return reference, ""
+}
+
+func (is ImageSource) IsArchive() bool {
+	return is.Type == DockerArchiveImageSource || is.Type == OciArchiveImageSource
+}
+
+// Create (if needed) a tar file of the image, to be passed to the indexer-app.
+// Existing archives are used as-is, without being copied.
+func (is ImageSource) CreateImageArchive(tempDirPath string) (imageTarPath string, err error) {
+	imageTarPath = filepath.Join(tempDirPath, "image.tar")
+	switch is.Type {
+	case DockerArchiveImageSource, OciArchiveImageSource:
+		return getExistingImageArchive(is.Reference)
+	case OciLayoutImageSource:
+		if !isOciLayoutDir(is.Reference) {
+			return "", errorutils.CheckErrorf("'%s' is not an OCI image layout directory ('%s' file is missing)", is.Reference, ociLayoutFileName)
+		}
+		var replacedFiles map[string][]byte
+		if is.Tag != "" {
+			var index []byte
+			if index, err = getOciLayoutTagIndex(is.Reference, is.Tag); err != nil {
+				return
+			}
+			replacedFiles = map[string][]byte{ociIndexFileName: index}
+		}
+		err = archiveDirectory(is.Reference, imageTarPath, replacedFiles)
+	case PodmanImageSource:
+		err = runImageSaveCmd(podmanCliName, is.Reference, imageTarPath)
+	default:
+		err = runImageSaveCmd(getDaemonCliName(), is.Reference, imageTarPath)
+	}
+	return
+}
+
+func getExistingImageArchive(archivePath string) (string, error) {
+	absPath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	exists, err := fileutils.IsFileExists(absPath, false)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", errorutils.CheckErrorf("the image archive '%s' does not exist", archivePath)
+	}
+	return absPath, nil
+}
+
+// Docker is used by default. If the docker client is not installed (daemonless environments), podman is used if installed.
+func getDaemonCliName() string {
+	if _, err := exec.LookPath(dockerCliName); err == nil {
+		return dockerCliName
+	}
+	if _, err := exec.LookPath(podmanCliName); err == nil {
+		log.Debug(fmt.Sprintf("'%s' executable was not found, using '%s' to save the image.", dockerCliName, podmanCliName))
+		return podmanCliName
+	}
+	return dockerCliName
+}
+
+func runImageSaveCmd(cliName, imageTag, imageTarPath string) error {
+	args := []string{"save", imageTag, "-o", imageTarPath}
+	if cliName == podmanCliName {
+		// The indexer-app expects the layers to be saved in the docker archive format.
+		args = append(args, "--format", "docker-archive")
+	}
+	saveCmd := exec.Command(cliName, args...)
+	var stderr bytes.Buffer
+	saveCmd.Stderr = &stderr
+	if err := saveCmd.Run(); err != nil {
+		return fmt.Errorf("failed running command: '%s' with error: %s - %s", strings.Join(saveCmd.Args, " "), err.Error(), stderr.String())
+	}
+	return nil
+}
+
+func isOciLayoutDir(path string) bool {
+	exists, err := fileutils.IsFileExists(filepath.Join(path, ociLayoutFileName), false)
+	return err == nil && exists
+}
+
+// Returns the index of the OCI image layout, with the manifest of the tagged image only, so the other images in the layout are not scanned.
+func getOciLayoutTagIndex(layoutDir, tag string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(layoutDir, ociIndexFileName))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	// The index is kept as-is, except for its manifests list
+	var index map[string]json.RawMessage
+	if err = json.Unmarshal(content, &index); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the index of the OCI image layout '%s': %s", layoutDir, err.Error())
+	}
+	var manifests []json.RawMessage
+	if err = json.Unmarshal(index["manifests"], &manifests); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the manifests of the OCI image layout '%s': %s", layoutDir, err.Error())
+	}
+	var tagManifests []json.RawMessage
+	for _, manifest := range manifests {
+		var descriptor struct {
+			Annotations map[string]string `json:"annotations,omitempty"`
+		}
+		if err = json.Unmarshal(manifest, &descriptor); err != nil {
+			return nil, errorutils.CheckErrorf("failed to parse the manifests of the OCI image layout '%s': %s", layoutDir, err.Error())
+		}
+		if descriptor.Annotations[ociRefNameAnnotation] == tag {
+			tagManifests = append(tagManifests, manifest)
+		}
+	}
+	if len(tagManifests) == 0 {
+		return nil, errorutils.CheckErrorf("the tag '%s' was not found in the OCI image layout '%s'", tag, layoutDir)
+	}
+	if index["manifests"], err = json.Marshal(tagManifests); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	content, err = json.Marshal(index)
+	return content, errorutils.CheckError(err)
+}
+
+// Archive the content of the source directory into a tar file, with paths relative to the directory.
+// The content of the files in replacedFiles (by their relative path in the tar) is replaced with the provided content.
+func archiveDirectory(sourceDir, targetTarPath string, replacedFiles map[string][]byte) (err error) {
+	tarFile, err := os.Create(targetTarPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := tarFile.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	tarWriter := tar.NewWriter(tarFile)
+	defer func() {
+		if e := tarWriter.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	return errorutils.CheckError(filepath.Walk(sourceDir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		relativePath, err := filepath.Rel(sourceDir, path)
+		if err != nil || relativePath == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relativePath)
+		replacedContent, isReplaced := replacedFiles[header.Name]
+		if isReplaced {
+			header.Size = int64(len(replacedContent))
+		}
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if isReplaced {
+			_, err = tarWriter.Write(replacedContent)
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileToTar(path, tarWriter)
+	}))
+}
+
+func copyFileToTar(path string, tarWriter *tar.Writer) (err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = e
+		}
+	}()
+	_, err = io.Copy(tarWriter, file)
+	return
+}
@@ -0,0 +1,136 @@
+package scan
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveImageSource(t *testing.T) {
+	testsDir, cleanUp := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUp()
+	ociLayoutDir := createDummyOciLayout(t, testsDir)
+	archivePath := filepath.Join(testsDir, "image.tar")
+	assert.NoError(t, os.WriteFile(archivePath, []byte{}, 0644))
+
+	testCases := []struct {
+		name     string
+		image    string
+		expected ImageSource
+	}{
+		{name: "Docker daemon image tag", image: "alpine:3.18", expected: ImageSource{Type: DockerDaemonImageSource, Reference: "alpine:3.18"}},
+		{name: "Podman image tag", image: "podman:alpine:3.18", expected: ImageSource{Type: PodmanImageSource, Reference: "alpine:3.18"}},
+		{name: "Containers storage image tag", image: "containers-storage:alpine:3.18", expected: ImageSource{Type: PodmanImageSource, Reference: "alpine:3.18"}},
+		{name: "Docker archive prefix", image: "docker-archive:/tmp/image.tar", expected: ImageSource{Type: DockerArchiveImageSource, Reference: "/tmp/image.tar"}},
+		{name: "OCI archive prefix", image: "oci-archive:/tmp/image.tar", expected: ImageSource{Type: OciArchiveImageSource, Reference: "/tmp/image.tar"}},
+		{name: "OCI layout prefix", image: "oci:/tmp/layout", expected: ImageSource{Type: OciLayoutImageSource, Reference: "/tmp/layout"}},
+		{name: "OCI layout prefix with tag", image: "oci:/tmp/layout:1.0.0", expected: ImageSource{Type: OciLayoutImageSource, Reference: "/tmp/layout", Tag: "1.0.0"}},
+		{name: "OCI layout directory prefix with tag", image: "oci:" + ociLayoutDir + ":latest", expected: ImageSource{Type: OciLayoutImageSource, Reference: ociLayoutDir, Tag: "latest"}},
+		{name: "OCI layout prefix with a drive letter", image: `oci:C:\layout`, expected: ImageSource{Type: OciLayoutImageSource, Reference: `C:\layout`}},
+		{name: "OCI layout directory", image: ociLayoutDir, expected: ImageSource{Type: OciLayoutImageSource, Reference: ociLayoutDir}},
+		{name: "Existing archive", image: archivePath, expected: ImageSource{Type: DockerArchiveImageSource, Reference: archivePath}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ResolveImageSource(tc.image))
+		})
+	}
+}
+
+func TestCreateImageArchiveFromOciLayout(t *testing.T) {
+	testsDir, cleanUp := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUp()
+	ociLayoutDir := createDummyOciLayout(t, testsDir)
+	outputDir := filepath.Join(testsDir, "output")
+	assert.NoError(t, os.MkdirAll(outputDir, 0755))
+
+	imageTarPath, err := ImageSource{Type: OciLayoutImageSource, Reference: ociLayoutDir}.CreateImageArchive(outputDir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(outputDir, "image.tar"), imageTarPath)
+
+	tarFile, err := os.Open(imageTarPath)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tarFile.Close())
+	}()
+	entries := map[string]string{}
+	tarReader := tar.NewReader(tarFile)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		content, err := io.ReadAll(tarReader)
+		assert.NoError(t, err)
+		entries[header.Name] = string(content)
+	}
+	assert.Equal(t, `{"imageLayoutVersion": "1.0.0"}`, entries[ociLayoutFileName])
+	assert.Equal(t, `{"schemaVersion": 2}`, entries["index.json"])
+	assert.Equal(t, "layer", entries["blobs/sha256/0123abcd"])
+	assert.Contains(t, entries, "blobs/sha256")
+}
+
+func TestCreateImageArchiveFromOciLayoutTag(t *testing.T) {
+	testsDir, cleanUp := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUp()
+	ociLayoutDir := createDummyOciLayout(t, testsDir)
+	assert.NoError(t, os.WriteFile(filepath.Join(ociLayoutDir, ociIndexFileName), []byte(`{"schemaVersion": 2, "manifests": [
+		{"digest": "sha256:1111", "annotations": {"org.opencontainers.image.ref.name": "1.0.0"}},
+		{"digest": "sha256:2222", "annotations": {"org.opencontainers.image.ref.name": "2.0.0"}}
+	]}`), 0644))
+
+	imageTarPath, err := ImageSource{Type: OciLayoutImageSource, Reference: ociLayoutDir, Tag: "2.0.0"}.CreateImageArchive(testsDir)
+	assert.NoError(t, err)
+	tarFile, err := os.Open(imageTarPath)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tarFile.Close())
+	}()
+	var index []byte
+	tarReader := tar.NewReader(tarFile)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if header.Name == ociIndexFileName {
+			index, err = io.ReadAll(tarReader)
+			assert.NoError(t, err)
+		}
+	}
+	// Only the manifest of the tag is kept in the index
+	assert.JSONEq(t, `{"schemaVersion": 2, "manifests": [{"digest": "sha256:2222", "annotations": {"org.opencontainers.image.ref.name": "2.0.0"}}]}`, string(index))
+
+	_, err = ImageSource{Type: OciLayoutImageSource, Reference: ociLayoutDir, Tag: "3.0.0"}.CreateImageArchive(testsDir)
+	assert.ErrorContains(t, err, "the tag '3.0.0' was not found")
+}
+
+func TestCreateImageArchiveFromExistingArchive(t *testing.T) {
+	testsDir, cleanUp := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUp()
+	archivePath := filepath.Join(testsDir, "image.tar")
+	assert.NoError(t, os.WriteFile(archivePath, []byte{}, 0644))
+
+	imageTarPath, err := ImageSource{Type: OciArchiveImageSource, Reference: archivePath}.CreateImageArchive(testsDir)
+	assert.NoError(t, err)
+	assert.Equal(t, archivePath, imageTarPath)
+
+	_, err = ImageSource{Type: DockerArchiveImageSource, Reference: filepath.Join(testsDir, "not-exists.tar")}.CreateImageArchive(testsDir)
+	assert.Error(t, err)
+}
+
+func createDummyOciLayout(t *testing.T, baseDir string) string {
+	layoutDir := filepath.Join(baseDir, "layout")
+	assert.NoError(t, os.MkdirAll(filepath.Join(layoutDir, "blobs", "sha256"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(layoutDir, ociLayoutFileName), []byte(`{"imageLayoutVersion": "1.0.0"}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(layoutDir, "index.json"), []byte(`{"schemaVersion": 2}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(layoutDir, "blobs", "sha256", "0123abcd"), []byte("layer"), 0644))
+	return layoutDir
+}
@@ -0,0 +1,228 @@
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+)
+
+const (
+	dockerHubDefaultNamespace = "library"
+	ociIndexMediaType         = "application/vnd.oci.image.index.v1+json"
+	dockerManifestListType    = "application/vnd.docker.distribution.manifest.list.v2+json"
+	ociManifestMediaType      = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType   = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+var dockerHubRegistries = []string{"docker.io", "index.docker.io", "registry-1.docker.io"}
+
+// A client for the Docker Registry API of an Artifactory Docker repository.
+type dockerRegistryClient struct {
+	rtManager         artifactory.ArtifactoryServicesManager
+	httpClientDetails httputils.HttpClientDetails
+	// <Artifactory URL>/api/docker/<repo>/v2/
+	registryUrl string
+}
+
+type registryDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	Platform  *struct {
+		Architecture string `json:"architecture"`
+		Os           string `json:"os"`
+	} `json:"platform,omitempty"`
+}
+
+type registryManifest struct {
+	MediaType string               `json:"mediaType"`
+	Config    registryDescriptor   `json:"config"`
+	Layers    []registryDescriptor `json:"layers"`
+	Manifests []registryDescriptor `json:"manifests"`
+}
+
+func newDockerRegistryClient(serverDetails *config.ServerDetails, repo string) (*dockerRegistryClient, error) {
+	if serverDetails.ArtifactoryUrl == "" {
+		return nil, errorutils.CheckErrorf("an Artifactory URL is required to fetch the base image tags from '%s'", repo)
+	}
+	rtManager, err := rtUtils.CreateServiceManager(serverDetails, 2, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	return &dockerRegistryClient{
+		rtManager:         rtManager,
+		httpClientDetails: rtManager.GetConfig().GetServiceDetails().CreateHttpClientDetails(),
+		registryUrl:       clientutils.AddTrailingSlashIfNeeded(serverDetails.ArtifactoryUrl) + "api/docker/" + repo + "/v2/",
+	}, nil
+}
+
+// Split an image reference to the image name in the registry and its tag.
+// Docker Hub images are converted to their full name, for example: 'alpine:3.19' -> 'library/alpine', '3.19'.
+func parseImageReference(image string) (name, tag string) {
+	name, _, _ = strings.Cut(image, "@")
+	if lastSlash, lastColon := strings.LastIndex(name, "/"), strings.LastIndex(name, ":"); lastColon > lastSlash {
+		name, tag = name[:lastColon], name[lastColon+1:]
+	}
+	if registry, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		// The registry host is proxied by the Artifactory remote repository
+		name = rest
+	}
+	for _, registry := range dockerHubRegistries {
+		name = strings.TrimPrefix(name, registry+"/")
+	}
+	if !strings.Contains(name, "/") {
+		name = dockerHubDefaultNamespace + "/" + name
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	return
+}
+
+func (rc *dockerRegistryClient) listTags(imageName string) ([]string, error) {
+	body, err := rc.get(rc.registryUrl+imageName+"/tags/list", "")
+	if err != nil {
+		return nil, err
+	}
+	var tagsList struct {
+		Tags []string `json:"tags"`
+	}
+	if err = errorutils.CheckError(json.Unmarshal(body, &tagsList)); err != nil {
+		return nil, err
+	}
+	return tagsList.Tags, nil
+}
+
+// Download the image from the registry into an OCI image layout directory.
+func (rc *dockerRegistryClient) downloadImageLayout(imageName, tag, layoutDir string) (err error) {
+	blobsDir := filepath.Join(layoutDir, "blobs", "sha256")
+	if err = errorutils.CheckError(os.MkdirAll(blobsDir, 0755)); err != nil {
+		return
+	}
+	manifestContent, manifest, err := rc.getImageManifest(imageName, tag)
+	if err != nil {
+		return
+	}
+	manifestDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(manifestContent))
+	if err = writeBlob(blobsDir, manifestDigest, manifestContent); err != nil {
+		return
+	}
+	for _, blob := range append([]registryDescriptor{manifest.Config}, manifest.Layers...) {
+		if err = rc.downloadBlob(imageName, blob.Digest, blobsDir); err != nil {
+			return
+		}
+	}
+	index := registryManifest{Manifests: []registryDescriptor{{MediaType: manifest.MediaType, Digest: manifestDigest, Size: int64(len(manifestContent))}}}
+	indexContent, err := json.Marshal(index)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = errorutils.CheckError(os.WriteFile(filepath.Join(layoutDir, "index.json"), indexContent, 0644)); err != nil {
+		return
+	}
+	return errorutils.CheckError(os.WriteFile(filepath.Join(layoutDir, ociLayoutFileName), []byte(`{"imageLayoutVersion": "1.0.0"}`), 0644))
+}
+
+// Get the image manifest. For multi-platform images, the manifest of the current architecture (or the first one) is used.
+func (rc *dockerRegistryClient) getImageManifest(imageName, reference string) (content []byte, manifest registryManifest, err error) {
+	accept := strings.Join([]string{ociManifestMediaType, dockerManifestMediaType, ociIndexMediaType, dockerManifestListType}, ",")
+	if content, err = rc.get(rc.registryUrl+imageName+"/manifests/"+reference, accept); err != nil {
+		return
+	}
+	if err = errorutils.CheckError(json.Unmarshal(content, &manifest)); err != nil {
+		return
+	}
+	if len(manifest.Manifests) == 0 {
+		if manifest.MediaType == "" {
+			manifest.MediaType = ociManifestMediaType
+		}
+		return
+	}
+	selected := manifest.Manifests[0]
+	for _, platformManifest := range manifest.Manifests {
+		if platformManifest.Platform != nil && platformManifest.Platform.Os == "linux" && platformManifest.Platform.Architecture == runtime.GOARCH {
+			selected = platformManifest
+			break
+		}
+	}
+	return rc.getImageManifest(imageName, selected.Digest)
+}
+
+// Get the diff IDs of the image layers from the image config.
+func (rc *dockerRegistryClient) getImageDiffIds(imageName, tag string) ([]string, error) {
+	_, manifest, err := rc.getImageManifest(imageName, tag)
+	if err != nil {
+		return nil, err
+	}
+	content, err := rc.get(rc.registryUrl+imageName+"/blobs/"+manifest.Config.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	var imageConfig struct {
+		RootFs struct {
+			DiffIds []string `json:"diff_ids"`
+		} `json:"rootfs"`
+	}
+	if err = errorutils.CheckError(json.Unmarshal(content, &imageConfig)); err != nil {
+		return nil, err
+	}
+	return imageConfig.RootFs.DiffIds, nil
+}
+
+func (rc *dockerRegistryClient) downloadBlob(imageName, digest, blobsDir string) (err error) {
+	reader, resp, err := rc.rtManager.Client().ReadRemoteFile(rc.registryUrl+imageName+"/blobs/"+digest, &rc.httpClientDetails)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := reader.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	if err = errorutils.CheckResponseStatus(resp, http.StatusOK); err != nil {
+		return
+	}
+	blobFile, err := os.Create(filepath.Join(blobsDir, strings.TrimPrefix(digest, "sha256:")))
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := blobFile.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	_, err = io.Copy(blobFile, reader)
+	return errorutils.CheckError(err)
+}
+
+func (rc *dockerRegistryClient) get(url, accept string) ([]byte, error) {
+	httpClientDetails := rc.httpClientDetails.Clone()
+	if accept != "" {
+		httpClientDetails.Headers["Accept"] = accept
+	}
+	resp, body, _, err := rc.rtManager.Client().SendGet(url, true, httpClientDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("failed to get '%s': %s", url, err.Error())
+	}
+	return body, nil
+}
+
+func writeBlob(blobsDir, digest string, content []byte) error {
+	return errorutils.CheckError(os.WriteFile(filepath.Join(blobsDir, strings.TrimPrefix(digest, "sha256:")), content, 0644))
+}
@@ -1,16 +1,13 @@
 package scan
 
 import (
-	"bytes"
 	"fmt"
 	xscservices "github.com/jfrog/jfrog-client-go/xsc/services"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
 	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/dockerutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
@@ -27,6 +24,10 @@ type DockerScanCommand struct {
 	ScanCommand
 	imageTag       string
 	targetRepoPath string
+	dockerfilePath string
+	// Scan newer tags of the base image from the given Docker repository and suggest them as upgrades
+	suggestBaseImages bool
+	baseImagesRepo    string
 }
 
 func NewDockerScanCommand() *DockerScanCommand {
@@ -43,6 +44,21 @@ func (dsc *DockerScanCommand) SetTargetRepoPath(repoPath string) *DockerScanComm
 	return dsc
 }
 
+func (dsc *DockerScanCommand) SetDockerfilePath(dockerfilePath string) *DockerScanCommand {
+	dsc.dockerfilePath = dockerfilePath
+	return dsc
+}
+
+func (dsc *DockerScanCommand) SetSuggestBaseImages(suggestBaseImages bool) *DockerScanCommand {
+	dsc.suggestBaseImages = suggestBaseImages
+	return dsc
+}
+
+func (dsc *DockerScanCommand) SetBaseImagesRepo(baseImagesRepo string) *DockerScanCommand {
+	dsc.baseImagesRepo = baseImagesRepo
+	return dsc
+}
+
 func (dsc *DockerScanCommand) Run() (err error) {
 	// Validate Xray minimum version
 	_, xrayVersion, err := xray.CreateXrayServiceManagerAndGetVersion(dsc.ScanCommand.serverDetails)
@@ -65,19 +81,17 @@ func (dsc *DockerScanCommand) Run() (err error) {
 		}
 	}()
 
-	// Run the 'docker save' command, to create tar file from the docker image, and pass it to the indexer-app
+	// Create a tar file from the image (using 'docker save', 'podman save' or an existing archive/OCI layout), and pass it to the indexer-app
 	if dsc.progress != nil {
 		dsc.progress.SetHeadlineMsg("Creating image archive 📦")
 	}
-	log.Info("Creating image archive...")
-	imageTarPath := filepath.Join(tempDirPath, "image.tar")
-	dockerSaveCmd := exec.Command("docker", "save", dsc.imageTag, "-o", imageTarPath)
-	var stderr bytes.Buffer
-	dockerSaveCmd.Stderr = &stderr
-	err = dockerSaveCmd.Run()
+	imageSource := ResolveImageSource(dsc.imageTag)
+	log.Info(fmt.Sprintf("Creating image archive from %s...", imageSource.Type))
+	imageTarPath, err := imageSource.CreateImageArchive(tempDirPath)
 	if err != nil {
-		return fmt.Errorf("failed running command: '%s' with error: %s - %s", strings.Join(dockerSaveCmd.Args, " "), err.Error(), stderr.String())
+		return err
 	}
+	dsc.dockerImageLayers = getImageLayers(imageTarPath, dsc.getDockerfilePath())
 
 	// Perform scan on image.tar
 	dsc.analyticsMetricsService.AddGeneralEvent(dsc.analyticsMetricsService.CreateGeneralEvent(xscservices.CliProduct, xscservices.CliEventType))
@@ -109,16 +123,45 @@ func (dsc *DockerScanCommand) Run() (err error) {
 		if err = utils.RecordSarifOutput(scanResults); err != nil {
 			return
 		}
-		return utils.RecordSecurityCommandSummary(utils.NewDockerScanSummary(
+		if err = utils.RecordSecurityCommandSummary(utils.NewDockerScanSummary(
 			scanResults,
 			dsc.ScanCommand.serverDetails,
 			dsc.ScanCommand.includeVulnerabilities,
 			dsc.ScanCommand.hasViolationContext(),
 			dsc.imageTag,
-		))
+		)); err != nil || !dsc.suggestBaseImages {
+			return
+		}
+		return dsc.suggestBaseImageUpgrades(scanResults.XrayVersion)
 	})
 }
 
+// Read the image layers and attribute them to the Dockerfile instructions and base image, if a Dockerfile is available.
+// Failing to do so should not fail the scan, the findings will be reported with the layer digest only.
+func getImageLayers(imageTarPath, dockerfilePath string) *dockerutils.ImageLayers {
+	imageLayers, err := dockerutils.ReadImageLayers(imageTarPath)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to read the image layers: %s", err.Error()))
+		return nil
+	}
+	if dockerfilePath == "" {
+		return imageLayers
+	}
+	log.Debug(fmt.Sprintf("Attributing the image layers to the instructions of %s", dockerfilePath))
+	if err = imageLayers.AttributeToDockerfile(dockerfilePath); err != nil {
+		log.Warn(fmt.Sprintf("Failed to attribute the image layers to %s: %s", dockerfilePath, err.Error()))
+	}
+	return imageLayers
+}
+
+// Use the provided Dockerfile, or the default Dockerfile in the current directory (or the GitHub workspace) if exists.
+func (dsc *DockerScanCommand) getDockerfilePath() string {
+	if dsc.dockerfilePath != "" {
+		return dsc.dockerfilePath
+	}
+	return utils.GetDockerfileLocationIfExists(nil)
+}
+
 // When indexing RPM files inside the docker container, the indexer-app needs to connect to the Xray Server.
 // This is because RPM indexing is performed on the server side. This method therefore sets the Xray credentials as env vars to be read and used by the indexer-app.
 func (dsc *DockerScanCommand) setCredentialEnvsForIndexerApp() error {
@@ -15,6 +15,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-cli-security/jas"
 	"github.com/jfrog/jfrog-client-go/http/httpclient"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -28,12 +29,11 @@ const (
 )
 
 func DownloadIndexerIfNeeded(xrayManager *xray.XrayServicesManager, xrayVersionStr string) (indexerPath string, err error) {
-	dependenciesPath, err := config.GetJfrogDependenciesPath()
+	indexerDirPath, err := GetIndexerDirAbsolutePath()
 	if err != nil {
 		return
 	}
-	indexerDirPath := filepath.Join(dependenciesPath, indexerDirName)
-	indexerBinaryName := getIndexerBinaryName()
+	indexerBinaryName := GetIndexerBinaryName()
 	indexerPath = filepath.Join(indexerDirPath, xrayVersionStr, indexerBinaryName)
 
 	locksDirPath, err := coreutils.GetJfrogLocksDir()
@@ -55,6 +55,9 @@ func DownloadIndexerIfNeeded(xrayManager *xray.XrayServicesManager, xrayVersionS
 	if exists || err != nil {
 		return
 	}
+	if jas.IsOfflineMode() {
+		return getLatestCachedIndexer(indexerDirPath, indexerBinaryName, xrayVersionStr)
+	}
 
 	log.Info("JFrog Xray Indexer " + xrayVersionStr + " is not cached locally. Downloading it now...")
 	indexerPath, err = downloadIndexer(xrayManager, indexerDirPath, indexerBinaryName)
@@ -183,7 +186,60 @@ func deleteOldIndexers(indexerDirPath string) error {
 	return nil
 }
 
-func getIndexerBinaryName() string {
+// In offline mode, the indexer isn't downloaded. The newest indexer that was installed from a tools bundle is used instead.
+func getLatestCachedIndexer(indexerDirPath, indexerBinaryName, xrayVersionStr string) (string, error) {
+	versions, err := GetCachedIndexerVersions()
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", errorutils.CheckErrorf("JFrog Xray Indexer %s is not cached locally, and it can't be downloaded in offline mode (%s). Install it from a tools bundle with 'jf security tools install --from=<bundle zip>'", xrayVersionStr, jas.OfflineModeEnvVar)
+	}
+	log.Warn(fmt.Sprintf("JFrog Xray Indexer %s is not cached locally, using the cached indexer %s in offline mode.", xrayVersionStr, versions[0]))
+	return filepath.Join(indexerDirPath, versions[0], indexerBinaryName), nil
+}
+
+func GetIndexerDirAbsolutePath() (string, error) {
+	dependenciesPath, err := config.GetJfrogDependenciesPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dependenciesPath, indexerDirName), nil
+}
+
+// Returns the versions of the cached indexers, from the newest to the oldest.
+func GetCachedIndexerVersions() (versions []string, err error) {
+	indexerDirPath, err := GetIndexerDirAbsolutePath()
+	if err != nil {
+		return
+	}
+	indexerDirExists, err := fileutils.IsDirExists(indexerDirPath, false)
+	if !indexerDirExists || err != nil {
+		return
+	}
+	filesList, err := os.ReadDir(indexerDirPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	for _, file := range filesList {
+		if !file.IsDir() || file.Name() == tempIndexerDirName {
+			continue
+		}
+		var exists bool
+		if exists, err = fileutils.IsFileExists(filepath.Join(indexerDirPath, file.Name(), GetIndexerBinaryName()), false); err != nil {
+			return
+		}
+		if exists {
+			versions = append(versions, file.Name())
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return version.NewVersion(versions[i]).AtLeast(versions[j]) && versions[i] != versions[j]
+	})
+	return
+}
+
+func GetIndexerBinaryName() string {
 	switch runtime.GOOS {
 	case "windows":
 		return "indexer-app.exe"
@@ -41,7 +41,7 @@ func TestDeleteOldIndexers(t *testing.T) {
 func createDummyIndexer(t *testing.T, dirPath string) {
 	err := os.MkdirAll(dirPath, 0777)
 	assert.NoError(t, err)
-	fullPath := filepath.Join(dirPath, getIndexerBinaryName())
+	fullPath := filepath.Join(dirPath, GetIndexerBinaryName())
 	file, err := os.Create(fullPath)
 	assert.NoError(t, err)
 	defer func() {
@@ -52,7 +52,7 @@ func createDummyIndexer(t *testing.T, dirPath string) {
 }
 
 func checkIndexerExists(t *testing.T, dirPath string) bool {
-	indexerPath := filepath.Join(dirPath, getIndexerBinaryName())
+	indexerPath := filepath.Join(dirPath, GetIndexerBinaryName())
 	exists, err := fileutils.IsFileExists(indexerPath, true)
 	assert.NoError(t, err)
 	return exists
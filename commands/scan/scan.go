@@ -2,6 +2,7 @@ package scan
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,6 +19,8 @@ import (
 	"github.com/jfrog/jfrog-cli-security/jas/applicability"
 	"github.com/jfrog/jfrog-cli-security/jas/runner"
 	"github.com/jfrog/jfrog-cli-security/jas/secrets"
+	"github.com/jfrog/jfrog-cli-security/utils/dockerutils"
+	"github.com/jfrog/jfrog-cli-security/utils/licenseutils"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-cli-security/utils/xray"
@@ -76,6 +79,14 @@ type ScanCommand struct {
 	progress                ioUtils.ProgressMgr
 	commandSupportsJAS      bool
 	analyticsMetricsService *xsc.AnalyticsMetricsService
+	// The layers of the scanned image, attributed to the Dockerfile (Docker scan only)
+	dockerImageLayers *dockerutils.ImageLayers
+	noCache           bool
+	scanCache         *ScanCache
+	// A license policy that is evaluated locally on the licenses of the scanned components.
+	licensePolicy *licenseutils.LicensePolicy
+	// The context of the running command, which cancels the JAS scans when the command is interrupted
+	ctx context.Context
 }
 
 func (scanCmd *ScanCommand) SetMinSeverityFilter(minSeverityFilter severityutils.Severity) *ScanCommand {
@@ -156,13 +167,28 @@ func (scanCmd *ScanCommand) SetBypassArchiveLimits(bypassArchiveLimits bool) *Sc
 	return scanCmd
 }
 
+func (scanCmd *ScanCommand) SetNoCache(noCache bool) *ScanCommand {
+	scanCmd.noCache = noCache
+	return scanCmd
+}
+
+func (scanCmd *ScanCommand) SetLicensePolicy(licensePolicy *licenseutils.LicensePolicy) *ScanCommand {
+	scanCmd.licensePolicy = licensePolicy
+	return scanCmd
+}
+
+// The licenses are required for evaluating the license policy, even if not requested to be displayed
+func (scanCmd *ScanCommand) shouldRequestLicenses() bool {
+	return scanCmd.includeLicenses || scanCmd.licensePolicy != nil
+}
+
 func (scanCmd *ScanCommand) SetAnalyticsMetricsService(analyticsMetricsService *xsc.AnalyticsMetricsService) *ScanCommand {
 	scanCmd.analyticsMetricsService = analyticsMetricsService
 	return scanCmd
 }
 
 func (scanCmd *ScanCommand) hasViolationContext() bool {
-	return len(scanCmd.watches) > 0 || scanCmd.projectKey != ""
+	return len(scanCmd.watches) > 0 || scanCmd.projectKey != "" || scanCmd.licensePolicy != nil
 }
 
 func (scanCmd *ScanCommand) indexFile(filePath string) (*xrayUtils.BinaryGraphNode, error) {
@@ -218,6 +244,10 @@ func (scanCmd *ScanCommand) RunAndRecordResults(cmdType utils.CommandType, recor
 			}
 		}
 	}()
+	// Interrupting the command cancels the JAS scans
+	var stop context.CancelFunc
+	scanCmd.ctx, stop = jas.NewInterruptibleContext()
+	defer stop()
 	xrayManager, xrayVersion, err := xray.CreateXrayServiceManagerAndGetVersion(scanCmd.serverDetails)
 	if err != nil {
 		return err
@@ -225,6 +255,7 @@ func (scanCmd *ScanCommand) RunAndRecordResults(cmdType utils.CommandType, recor
 
 	scanResults := utils.NewAuditResults(cmdType)
 	scanResults.XrayVersion = xrayVersion
+	scanResults.DockerImageLayers = scanCmd.dockerImageLayers
 	if scanCmd.analyticsMetricsService != nil {
 		scanResults.MultiScanId = scanCmd.analyticsMetricsService.GetMsi()
 	}
@@ -268,6 +299,12 @@ func (scanCmd *ScanCommand) RunAndRecordResults(cmdType utils.CommandType, recor
 			err = e
 		}
 	}()
+	if !scanCmd.noCache {
+		if scanCmd.scanCache, err = NewScanCacheForXrayDb(xrayManager); err != nil {
+			log.Warn(fmt.Sprintf("Failed to initialize the scan cache, scanning without cache: %s", err.Error()))
+			err = nil
+		}
+	}
 	threads := 1
 	if scanCmd.threads > 1 {
 		threads = scanCmd.threads
@@ -296,7 +333,11 @@ func (scanCmd *ScanCommand) RunAndRecordResults(cmdType utils.CommandType, recor
 
 	for _, arr := range resultsArr {
 		for _, res := range arr {
-			flatResults = append(flatResults, &utils.ScaScanResult{Target: res.Target, XrayResults: []services.ScanResponse{*res.Result}})
+			xrayResults := []services.ScanResponse{*res.Result}
+			if scanCmd.licensePolicy != nil {
+				scanCmd.licensePolicy.AddViolations(xrayResults, nil, "")
+			}
+			flatResults = append(flatResults, &utils.ScaScanResult{Target: res.Target, XrayResults: xrayResults})
 			scanResults.ExtendedScanResults.ApplicabilityScanResults = append(scanResults.ExtendedScanResults.ApplicabilityScanResults, res.ExtendedScanResults.ApplicabilityScanResults...)
 			scanResults.ExtendedScanResults.SecretsScanResults = append(scanResults.ExtendedScanResults.SecretsScanResults, res.ExtendedScanResults.SecretsScanResults...)
 		}
@@ -335,8 +376,9 @@ func (scanCmd *ScanCommand) RunAndRecordResults(cmdType utils.CommandType, recor
 	}
 
 	// If includeVulnerabilities is false it means that context was provided, so we need to check for build violations.
+	// The violations of the license policy are checked as well.
 	// If user provided --fail=false, don't fail the build.
-	if scanCmd.fail && !scanCmd.includeVulnerabilities {
+	if scanCmd.fail && (!scanCmd.includeVulnerabilities || scanCmd.licensePolicy != nil) {
 		if utils.CheckIfFailBuild(scanResults.GetScaScansXrayResults()) {
 			return utils.NewFailBuildError()
 		}
@@ -379,6 +421,22 @@ func (scanCmd *ScanCommand) createIndexerHandlerFunc(file *spec.File, entitledFo
 	return func(filePath string) parallel.TaskFunc {
 		return func(threadId int) (err error) {
 			logMsgPrefix := clientutils.GetLogMsgPrefix(threadId, false)
+			// Results of JAS scans are not cached, the cache is used only if JAS scans are not performed
+			cacheKey := ""
+			if scanCmd.scanCache != nil && !(entitledForJas && scanCmd.commandSupportsJAS) {
+				if cacheKey, err = scanCmd.getScanCacheKey(filePath, getXrayRepoPathFromTarget(file.Target), scanCmd.scanCache.xrayDbVersion); err != nil {
+					log.Debug(fmt.Sprintf("%sFailed to calculate the scan cache key of %s: %s", logMsgPrefix, filePath, err.Error()))
+					cacheKey, err = "", nil
+				} else if cachedResult := scanCmd.scanCache.Get(cacheKey); cachedResult != nil {
+					log.Info(logMsgPrefix+"Using cached scan results for file:", filePath)
+					// The results are stored by the consumer of the indexed files, like the results of the scanned files
+					_, _ = indexedFileProducer.AddTask(func(threadId int) error {
+						resultsArr[threadId] = append(resultsArr[threadId], &ScanInfo{Target: filePath, Result: cachedResult, ExtendedScanResults: &utils.ExtendedScanResults{}})
+						return nil
+					})
+					return
+				}
+			}
 			log.Info(logMsgPrefix+"Indexing file:", filePath)
 			if scanCmd.progress != nil {
 				scanCmd.progress.SetHeadlineMsg("Indexing file: " + filepath.Base(filePath) + " 🗄")
@@ -401,7 +459,7 @@ func (scanCmd *ScanCommand) createIndexerHandlerFunc(file *spec.File, entitledFo
 					BinaryGraph:            graph,
 					RepoPath:               getXrayRepoPathFromTarget(file.Target),
 					Watches:                scanCmd.watches,
-					IncludeLicenses:        scanCmd.includeLicenses,
+					IncludeLicenses:        scanCmd.shouldRequestLicenses(),
 					IncludeVulnerabilities: scanCmd.includeVulnerabilities,
 					ProjectKey:             scanCmd.projectKey,
 					ScanType:               services.Binary,
@@ -427,6 +485,11 @@ func (scanCmd *ScanCommand) createIndexerHandlerFunc(file *spec.File, entitledFo
 					return
 				}
 
+				if cacheKey != "" {
+					if e := scanCmd.scanCache.Set(cacheKey, filePath, graphScanResults); e != nil {
+						log.Debug(fmt.Sprintf("Failed to store the scan results of %s in the cache: %s", filePath, e.Error()))
+					}
+				}
 				scanResults := utils.Results{
 					ScaResults:          []*utils.ScaScanResult{{XrayResults: []services.ScanResponse{*graphScanResults}}},
 					ExtendedScanResults: &utils.ExtendedScanResults{},
@@ -444,13 +507,13 @@ func (scanCmd *ScanCommand) createIndexerHandlerFunc(file *spec.File, entitledFo
 						log.Error(fmt.Sprintf("failed to create JFrogAppsConfig: %s", err.Error()))
 						indexedFileErrors[threadId] = append(indexedFileErrors[threadId], formats.SimpleJsonError{FilePath: filePath, ErrorMessage: err.Error()})
 					}
-					scanner := &jas.JasScanner{}
+					scanner := &jas.JasScanner{AnalyzerManager: jas.AnalyzerManager{Context: scanCmd.ctx}}
 					scanner, err = jas.CreateJasScanner(scanner, jfrogAppsConfig, scanCmd.serverDetails, jas.GetAnalyzerManagerXscEnvVars(scanResults.MultiScanId, techutils.Technology(graphScanResults.ScannedPackageType)))
 					if err != nil {
 						log.Error(fmt.Sprintf("failed to create jas scanner: %s", err.Error()))
 						indexedFileErrors[threadId] = append(indexedFileErrors[threadId], formats.SimpleJsonError{FilePath: filePath, ErrorMessage: err.Error()})
 					}
-					err = runner.AddJasScannersTasks(jasFileProducerConsumer, &scanResults, &depsList, scanCmd.serverDetails, false, scanner, applicability.ApplicabilityDockerScanScanType, secrets.SecretsScannerDockerScanType, jasErrHandlerFunc, utils.GetAllSupportedScans(), nil)
+					err = runner.AddJasScannersTasks(jasFileProducerConsumer, &scanResults, &depsList, scanCmd.serverDetails, false, scanner, applicability.ApplicabilityDockerScanScanType, secrets.SecretsScannerDockerScanType, jasErrHandlerFunc, utils.GetAllSupportedScans())
 					if err != nil {
 						log.Error(fmt.Sprintf("scanning '%s' failed with error: %s", graph.Id, err.Error()))
 						indexedFileErrors[threadId] = append(indexedFileErrors[threadId], formats.SimpleJsonError{FilePath: filePath, ErrorMessage: err.Error()})
@@ -0,0 +1,222 @@
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-security/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	// The time a cached scan result is valid, can be overridden with a duration (for example: '12h') in this env var.
+	ScanCacheTtlEnvVar  = "JFROG_CLI_SCAN_CACHE_TTL"
+	defaultScanCacheTtl = 24 * time.Hour
+	scanCacheFileSuffix = ".json"
+	// Returns the time of the last update of the Xray vulnerabilities database
+	xrayDbSyncTimeApi = "api/v1/dbsync/time"
+)
+
+// A local cache of binary scan results.
+// The results are stored by the checksum of the scanned file and the context of the scan (Xray DB version, watches, project, etc.).
+type ScanCache struct {
+	cacheDir string
+	ttl      time.Duration
+	// The version of the Xray vulnerabilities database. An update of the database invalidates the cached results.
+	xrayDbVersion string
+}
+
+type scanCacheEntry struct {
+	Created time.Time             `json:"created"`
+	Target  string                `json:"target,omitempty"`
+	Result  services.ScanResponse `json:"result"`
+}
+
+func NewScanCache() (*ScanCache, error) {
+	return newScanCache("")
+}
+
+// Create a cache for the results of scans with the given Xray vulnerabilities database version.
+func NewScanCacheForXrayDb(xrayManager *xray.XrayServicesManager) (*ScanCache, error) {
+	xrayDbVersion, err := getXrayDbVersion(xrayManager)
+	if err != nil {
+		return nil, err
+	}
+	return newScanCache(xrayDbVersion)
+}
+
+func newScanCache(xrayDbVersion string) (*ScanCache, error) {
+	cacheDir, err := utils.GetScanCacheFolder()
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := getScanCacheTtl()
+	if err != nil {
+		return nil, err
+	}
+	if err = fileutils.CreateDirIfNotExist(cacheDir); err != nil {
+		return nil, err
+	}
+	return &ScanCache{cacheDir: cacheDir, ttl: ttl, xrayDbVersion: xrayDbVersion}, nil
+}
+
+// The version of the Xray vulnerabilities database is the time of its last sync.
+func getXrayDbVersion(xrayManager *xray.XrayServicesManager) (string, error) {
+	xrayDetails := xrayManager.Config().GetServiceDetails()
+	httpClientDetails := xrayDetails.CreateHttpClientDetails()
+	resp, body, _, err := xrayManager.Client().SendGet(clientutils.AddTrailingSlashIfNeeded(xrayDetails.GetUrl())+xrayDbSyncTimeApi, true, &httpClientDetails)
+	if err != nil {
+		return "", err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return "", fmt.Errorf("failed to get the Xray DB version: %s", err.Error())
+	}
+	xrayDbVersion := strings.TrimSpace(string(body))
+	if xrayDbVersion == "" {
+		return "", errorutils.CheckErrorf("failed to get the Xray DB version: the response is empty")
+	}
+	return xrayDbVersion, nil
+}
+
+func getScanCacheTtl() (time.Duration, error) {
+	ttlValue := os.Getenv(ScanCacheTtlEnvVar)
+	if ttlValue == "" {
+		return defaultScanCacheTtl, nil
+	}
+	ttl, err := time.ParseDuration(ttlValue)
+	if err != nil {
+		return 0, errorutils.CheckErrorf("invalid value '%s' for %s, expected a duration (for example: '12h'): %s", ttlValue, ScanCacheTtlEnvVar, err.Error())
+	}
+	return ttl, nil
+}
+
+// Get the cache key of a file, from its checksum and the parameters that affect the scan results.
+func (scanCmd *ScanCommand) getScanCacheKey(filePath, repoPath, xrayDbVersion string) (string, error) {
+	fileChecksum, err := utils.GetFileSha256(filePath)
+	if err != nil {
+		return "", err
+	}
+	watches := append([]string{}, scanCmd.watches...)
+	sort.Strings(watches)
+	keyParts := []string{
+		fileChecksum,
+		xrayDbVersion,
+		strings.Join(watches, ","),
+		scanCmd.projectKey,
+		repoPath,
+		fmt.Sprintf("%t", scanCmd.includeVulnerabilities),
+		fmt.Sprintf("%t", scanCmd.shouldRequestLicenses()),
+		fmt.Sprintf("%t", scanCmd.fixableOnly),
+		scanCmd.minSeverityFilter.String(),
+	}
+	hash := sha256.Sum256([]byte(strings.Join(keyParts, "|")))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Get the cached scan result of the given key. Returns nil if not exists or expired.
+func (sc *ScanCache) Get(key string) *services.ScanResponse {
+	entry, err := readScanCacheEntry(sc.getEntryPath(key))
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to read the scan cache entry %s: %s", key, err.Error()))
+		return nil
+	}
+	if entry == nil || sc.isExpired(entry) {
+		return nil
+	}
+	return &entry.Result
+}
+
+func (sc *ScanCache) Set(key, target string, result *services.ScanResponse) error {
+	content, err := json.Marshal(scanCacheEntry{Created: time.Now(), Target: target, Result: *result})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	// Write to a unique temp file and rename it, so concurrent scans never read or write a partial entry
+	tempFile, err := os.CreateTemp(sc.cacheDir, key+"-*.tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = tempFile.Write(content)
+	if e := tempFile.Close(); err == nil {
+		err = e
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), sc.getEntryPath(key))
+	}
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+	}
+	return errorutils.CheckError(err)
+}
+
+// Remove the expired and corrupted entries from the cache. Returns the number of removed entries.
+func (sc *ScanCache) Prune() (removed int, err error) {
+	files, err := fileutils.ListFiles(sc.cacheDir, false)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file, scanCacheFileSuffix) {
+			continue
+		}
+		entry, e := readScanCacheEntry(file)
+		if e == nil && entry != nil && !sc.isExpired(entry) {
+			continue
+		}
+		if err = errorutils.CheckError(os.Remove(file)); err != nil {
+			return
+		}
+		removed++
+	}
+	return
+}
+
+func (sc *ScanCache) isExpired(entry *scanCacheEntry) bool {
+	return time.Since(entry.Created) > sc.ttl
+}
+
+func (sc *ScanCache) getEntryPath(key string) string {
+	return filepath.Join(sc.cacheDir, key+scanCacheFileSuffix)
+}
+
+func readScanCacheEntry(entryPath string) (*scanCacheEntry, error) {
+	content, err := os.ReadFile(entryPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	entry := &scanCacheEntry{}
+	if err = json.Unmarshal(content, entry); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return entry, nil
+}
+
+// Remove the expired entries of the local scan results cache ('jf scan cache prune').
+func PruneScanCache() error {
+	scanCache, err := NewScanCache()
+	if err != nil {
+		return err
+	}
+	removed, err := scanCache.Prune()
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Removed %d expired entries from the scan cache.", removed))
+	return nil
+}
@@ -0,0 +1,107 @@
+package scan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/xray"
+	clientTests "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanCache(t *testing.T) {
+	cacheDir, cleanUp := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUp()
+	defer clientTests.SetEnvWithCallbackAndAssert(t, utils.ScanCacheDir, cacheDir)()
+
+	scanCache, err := NewScanCache()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultScanCacheTtl, scanCache.ttl)
+	assert.Nil(t, scanCache.Get("key"))
+
+	result := &services.ScanResponse{ScanId: "scan-id", Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Severity: "High"}}}
+	assert.NoError(t, scanCache.Set("key", "file.jar", result))
+	assert.Equal(t, result, scanCache.Get("key"))
+	// The temp file of the entry is renamed
+	files, err := os.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	// Expired and corrupted entries are pruned
+	expiredContent := `{"created":"` + time.Now().Add(-2*defaultScanCacheTtl).Format(time.RFC3339) + `","result":{}}`
+	assert.NoError(t, os.WriteFile(scanCache.getEntryPath("expired"), []byte(expiredContent), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, "corrupted.json"), []byte("{"), 0600))
+	assert.Nil(t, scanCache.Get("expired"))
+	assert.Nil(t, scanCache.Get("corrupted"))
+
+	removed, err := scanCache.Prune()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	assert.NotNil(t, scanCache.Get("key"))
+}
+
+func TestGetScanCacheTtl(t *testing.T) {
+	defer clientTests.SetEnvWithCallbackAndAssert(t, ScanCacheTtlEnvVar, "2h")()
+	ttl, err := getScanCacheTtl()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, ttl)
+
+	assert.NoError(t, os.Setenv(ScanCacheTtlEnvVar, "two hours"))
+	_, err = getScanCacheTtl()
+	assert.Error(t, err)
+}
+
+func TestGetScanCacheKey(t *testing.T) {
+	testsDir, cleanUp := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanUp()
+	filePath := filepath.Join(testsDir, "file.jar")
+	assert.NoError(t, os.WriteFile(filePath, []byte("content"), 0644))
+
+	scanCmd := NewScanCommand().SetWatches([]string{"watch-b", "watch-a"})
+	key, err := scanCmd.getScanCacheKey(filePath, "", "2024-05-01T10:00:00Z")
+	assert.NoError(t, err)
+	// Same context in a different order
+	sameKey, err := NewScanCommand().SetWatches([]string{"watch-a", "watch-b"}).getScanCacheKey(filePath, "", "2024-05-01T10:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+	// Different Xray DB version
+	otherKey, err := scanCmd.getScanCacheKey(filePath, "", "2024-05-02T10:00:00Z")
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+	// Different content
+	assert.NoError(t, os.WriteFile(filePath, []byte("changed"), 0644))
+	otherKey, err = scanCmd.getScanCacheKey(filePath, "", "2024-05-01T10:00:00Z")
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+}
+
+func TestGetXrayDbVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI != "/xray/"+xrayDbSyncTimeApi {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(`{"db_sync_time":"2024-05-01T10:00:00Z"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	xrayManager, err := xray.CreateXrayServiceManager(&config.ServerDetails{XrayUrl: server.URL + "/xray/"})
+	assert.NoError(t, err)
+	xrayDbVersion, err := getXrayDbVersion(xrayManager)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"db_sync_time":"2024-05-01T10:00:00Z"}`, xrayDbVersion)
+
+	xrayManager, err = xray.CreateXrayServiceManager(&config.ServerDetails{XrayUrl: server.URL + "/other/"})
+	assert.NoError(t, err)
+	_, err = getXrayDbVersion(xrayManager)
+	assert.ErrorContains(t, err, "failed to get the Xray DB version")
+}
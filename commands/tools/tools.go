@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	biutils "github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/dependencies"
+	"github.com/jfrog/jfrog-cli-security/commands/scan"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	InstallAction = "install"
+	ExportAction  = "export"
+
+	manifestFileName         = "manifest.json"
+	analyzerManagerBundleDir = "analyzerManager"
+	indexerBundleDir         = "xray-indexer"
+	// The checksum file that is created next to an exported bundle, in the format of the sha256sum command.
+	// The file travels with the bundle, so it isn't trusted when the bundle is installed. The checksum must be provided separately.
+	ChecksumFileSuffix = ".sha256"
+)
+
+// The manifest of a tools bundle, describes the bundled tools and the SHA-256 checksums of their files.
+type bundleManifest struct {
+	OsAndArc               string            `json:"osAndArc"`
+	AnalyzerManagerVersion string            `json:"analyzerManagerVersion,omitempty"`
+	IndexerVersions        []string          `json:"indexerVersions,omitempty"`
+	Files                  map[string]string `json:"files"`
+}
+
+// Exports the locally installed analyzer manager and indexers to a bundle zip, so they can be installed on a machine without access to the JFrog releases.
+// A checksum file is created next to the bundle. Its checksum should be passed to the installation through a trusted channel, to verify the bundle.
+func Export(target string) (err error) {
+	osAndArc, err := coreutils.GetOSAndArc()
+	if err != nil {
+		return
+	}
+	manifest := bundleManifest{OsAndArc: osAndArc, Files: map[string]string{}}
+	// The paths of the bundled files in the zip, mapped to their local paths
+	bundledFiles := map[string]string{}
+	analyzerManagerDir, err := jas.GetAnalyzerManagerDirAbsolutePath()
+	if err != nil {
+		return
+	}
+	if _, err = jas.GetAnalyzerManagerExecutable(); err == nil {
+		if err = addDirToBundle(analyzerManagerDir, analyzerManagerBundleDir, bundledFiles); err != nil {
+			return
+		}
+		if manifest.AnalyzerManagerVersion, err = jas.GetInstalledAnalyzerManagerVersion(); err != nil {
+			return
+		}
+		if _, exists := bundledFiles[path.Join(analyzerManagerBundleDir, dependencies.ChecksumFileName)]; !exists || manifest.AnalyzerManagerVersion == "" {
+			return errorutils.CheckErrorf("the checksum or the version of the installed analyzer manager is missing from %s. Run an Advanced Security scan to download it again, and then export it", analyzerManagerDir)
+		}
+	} else {
+		log.Debug("The analyzer manager isn't installed, so it isn't exported:", err.Error())
+	}
+	if manifest.IndexerVersions, err = scan.GetCachedIndexerVersions(); err != nil {
+		return
+	}
+	indexerDir, err := scan.GetIndexerDirAbsolutePath()
+	if err != nil {
+		return
+	}
+	for _, indexerVersion := range manifest.IndexerVersions {
+		bundledFiles[path.Join(indexerBundleDir, indexerVersion, scan.GetIndexerBinaryName())] = filepath.Join(indexerDir, indexerVersion, scan.GetIndexerBinaryName())
+	}
+	if len(bundledFiles) == 0 {
+		return errorutils.CheckErrorf("neither the analyzer manager nor the indexer are installed locally, so there's nothing to export. Run a scan to download them first")
+	}
+	if err = writeBundle(target, bundledFiles, &manifest); err != nil {
+		return
+	}
+	checksum, err := utils.GetFileSha256(target)
+	if err != nil {
+		return
+	}
+	if err = errorutils.CheckError(os.WriteFile(target+ChecksumFileSuffix, []byte(checksum+"  "+filepath.Base(target)+"\n"), 0644)); err != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("The tools were exported to %s (SHA-256: %s)", target, checksum))
+	return
+}
+
+func addDirToBundle(dir, bundleDir string, bundledFiles map[string]string) error {
+	return errorutils.CheckError(filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		bundledFiles[path.Join(bundleDir, filepath.ToSlash(relativePath))] = filePath
+		return nil
+	}))
+}
+
+func writeBundle(target string, bundledFiles map[string]string, manifest *bundleManifest) (err error) {
+	bundle, err := os.Create(target)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(bundle.Close()))
+	}()
+	zipWriter := zip.NewWriter(bundle)
+	bundlePaths := make([]string, 0, len(bundledFiles))
+	for bundlePath := range bundledFiles {
+		bundlePaths = append(bundlePaths, bundlePath)
+	}
+	sort.Strings(bundlePaths)
+	for _, bundlePath := range bundlePaths {
+		if manifest.Files[bundlePath], err = addFileToZip(zipWriter, bundlePath, bundledFiles[bundlePath]); err != nil {
+			return
+		}
+	}
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	manifestWriter, err := zipWriter.Create(manifestFileName)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if _, err = manifestWriter.Write(manifestContent); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(zipWriter.Close())
+}
+
+// Adds the file to the zip and returns its SHA-256 checksum
+func addFileToZip(zipWriter *zip.Writer, bundlePath, filePath string) (checksum string, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(file.Close()))
+	}()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	header, err := zip.FileInfoHeader(fileInfo)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	header.Name, header.Method = bundlePath, zip.Deflate
+	entryWriter, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(entryWriter, hash), file); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Installs the analyzer manager and the indexers of a bundle that was exported by Export.
+// The bundle is verified against the expected SHA-256 checksum, which is mandatory.
+// The installed analyzer manager checksum is pinned, so it's used as is in offline mode, and isn't downloaded again if it's up to date.
+func Install(bundlePath, expectedSha256 string) (err error) {
+	if err = verifyBundleChecksum(bundlePath, expectedSha256); err != nil {
+		return
+	}
+	bundle, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return errorutils.CheckErrorf("failed to open the tools bundle %s: %s", bundlePath, err.Error())
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(bundle.Close()))
+	}()
+	manifest, err := readManifest(&bundle.Reader)
+	if err != nil {
+		return
+	}
+	osAndArc, err := coreutils.GetOSAndArc()
+	if err != nil {
+		return
+	}
+	if manifest.OsAndArc != osAndArc {
+		return errorutils.CheckErrorf("the tools bundle was exported on %s and can't be installed on %s", manifest.OsAndArc, osAndArc)
+	}
+	if err = validateManifestVersions(manifest); err != nil {
+		return
+	}
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, fileutils.RemoveTempDir(tempDir))
+	}()
+	if err = extractBundle(&bundle.Reader, manifest, tempDir); err != nil {
+		return
+	}
+	if err = coreutils.SetPermissionsRecursively(tempDir, 0755); err != nil {
+		return
+	}
+	if manifest.AnalyzerManagerVersion != "" {
+		if err = installAnalyzerManager(filepath.Join(tempDir, analyzerManagerBundleDir), manifest.AnalyzerManagerVersion); err != nil {
+			return
+		}
+	}
+	for _, indexerVersion := range manifest.IndexerVersions {
+		if err = installIndexer(filepath.Join(tempDir, indexerBundleDir, indexerVersion), indexerVersion); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// A checksum file next to the bundle could be replaced along with the bundle, so the expected checksum must be provided explicitly.
+func verifyBundleChecksum(bundlePath, expectedSha256 string) error {
+	if expectedSha256 == "" {
+		return errorutils.CheckErrorf("the SHA-256 checksum of the tools bundle is required to verify it. Provide it with --sha256. It was printed when the bundle was exported, and is in the %s file that was created with it", filepath.Base(bundlePath)+ChecksumFileSuffix)
+	}
+	actualSha256, err := utils.GetFileSha256(bundlePath)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(actualSha256, expectedSha256) {
+		return errorutils.CheckErrorf("the SHA-256 checksum of the tools bundle %s is %s, but %s was expected", bundlePath, actualSha256, expectedSha256)
+	}
+	log.Debug(fmt.Sprintf("The SHA-256 checksum of the tools bundle %s was verified", bundlePath))
+	return nil
+}
+
+func readManifest(bundle *zip.Reader) (manifest *bundleManifest, err error) {
+	manifestFile, err := bundle.Open(manifestFileName)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("the tools bundle is missing its %s: %s", manifestFileName, err.Error())
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(manifestFile.Close()))
+	}()
+	manifest = &bundleManifest{}
+	if err = json.NewDecoder(manifestFile).Decode(manifest); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the %s of the tools bundle: %s", manifestFileName, err.Error())
+	}
+	return
+}
+
+// The versions are used as directory names when the tools are installed, so they must not contain path separators or point outside the tools directories.
+func validateManifestVersions(manifest *bundleManifest) error {
+	for _, version := range append([]string{manifest.AnalyzerManagerVersion}, manifest.IndexerVersions...) {
+		if version != "" && !isValidVersionDirName(version) {
+			return errorutils.CheckErrorf("the %s of the tools bundle contains the invalid version '%s'", manifestFileName, version)
+		}
+	}
+	if slices.Contains(manifest.IndexerVersions, "") {
+		return errorutils.CheckErrorf("the %s of the tools bundle contains an empty indexer version", manifestFileName)
+	}
+	return nil
+}
+
+func isValidVersionDirName(version string) bool {
+	return filepath.IsLocal(version) && !strings.ContainsAny(version, `/\`) && version != "."
+}
+
+// Extracts the files of the bundle to the directory, and verifies that they are listed in the manifest with the same checksum.
+func extractBundle(bundle *zip.Reader, manifest *bundleManifest, dir string) error {
+	extracted := map[string]bool{}
+	for _, entry := range bundle.File {
+		if entry.Name == manifestFileName || entry.FileInfo().IsDir() {
+			continue
+		}
+		expectedSha256, exists := manifest.Files[entry.Name]
+		if !exists || !filepath.IsLocal(filepath.FromSlash(entry.Name)) {
+			return errorutils.CheckErrorf("the tools bundle contains the unexpected file '%s'", entry.Name)
+		}
+		actualSha256, err := extractFile(entry, filepath.Join(dir, filepath.FromSlash(entry.Name)))
+		if err != nil {
+			return err
+		}
+		if actualSha256 != expectedSha256 {
+			return errorutils.CheckErrorf("the SHA-256 checksum of '%s' in the tools bundle is %s, but %s was expected", entry.Name, actualSha256, expectedSha256)
+		}
+		extracted[entry.Name] = true
+	}
+	for bundledFile := range manifest.Files {
+		if !extracted[bundledFile] {
+			return errorutils.CheckErrorf("the file '%s' is missing from the tools bundle", bundledFile)
+		}
+	}
+	return nil
+}
+
+func extractFile(entry *zip.File, targetPath string) (checksum string, err error) {
+	if err = os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	reader, err := entry.Open()
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(reader.Close()))
+	}()
+	target, err := os.Create(targetPath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(target.Close()))
+	}()
+	hash := sha256.New()
+	// #nosec G110 -- the extracted files are verified against the checksums of the manifest
+	if _, err = io.Copy(io.MultiWriter(target, hash), reader); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func installAnalyzerManager(sourceDir, version string) error {
+	if exists, err := fileutils.IsFileExists(filepath.Join(sourceDir, dependencies.ChecksumFileName), false); err != nil || !exists {
+		return errors.Join(err, errorutils.CheckErrorf("the analyzer manager in the tools bundle is missing its pinned checksum"))
+	}
+	analyzerManagerDir, err := jas.GetAnalyzerManagerDirAbsolutePath()
+	if err != nil {
+		return err
+	}
+	if err = os.RemoveAll(analyzerManagerDir); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = biutils.CopyDir(sourceDir, analyzerManagerDir, true, nil); err != nil {
+		return err
+	}
+	// The files were verified against the manifest, so they are pinned to be verified in offline mode
+	if err = jas.PinAnalyzerManagerFiles(analyzerManagerDir, version); err != nil {
+		return err
+	}
+	if version != jas.GetAnalyzerManagerVersion() {
+		log.Warn(fmt.Sprintf("The installed analyzer manager version is %s, but version %s is expected by this JFrog CLI. In offline mode, the installed version is used as is.", version, jas.GetAnalyzerManagerVersion()))
+	}
+	log.Info(fmt.Sprintf("The analyzer manager %s was installed to %s", version, analyzerManagerDir))
+	return nil
+}
+
+func installIndexer(sourceDir, version string) error {
+	indexerDir, err := scan.GetIndexerDirAbsolutePath()
+	if err != nil {
+		return err
+	}
+	targetDir := filepath.Join(indexerDir, version)
+	if err = os.RemoveAll(targetDir); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = biutils.CopyDir(sourceDir, targetDir, true, nil); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("JFrog Xray Indexer %s was installed to %s", version, targetDir))
+	return nil
+}
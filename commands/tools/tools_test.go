@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/dependencies"
+	"github.com/jfrog/jfrog-cli-security/commands/scan"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	clientTestUtils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAndInstall(t *testing.T) {
+	// Export the tools of the connected machine
+	dependenciesDirCallback := clientTestUtils.SetEnvWithCallbackAndAssert(t, coreutils.DependenciesDir, t.TempDir())
+	analyzerManagerDir, err := jas.GetAnalyzerManagerDirAbsolutePath()
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(analyzerManagerDir, jas.GetAnalyzerManagerExecutableName()), "analyzer manager")
+	writeFile(t, filepath.Join(analyzerManagerDir, "jas_scanner", "jas_scanner"), "scanner")
+	writeFile(t, filepath.Join(analyzerManagerDir, dependencies.ChecksumFileName), "pinned-checksum")
+	indexerDir, err := scan.GetIndexerDirAbsolutePath()
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(indexerDir, "2.1.0", scan.GetIndexerBinaryName()), "indexer")
+	bundlePath := filepath.Join(t.TempDir(), "tools.zip")
+	// The version of the analyzer manager is pinned when it's downloaded
+	assert.ErrorContains(t, Export(bundlePath), "the checksum or the version of the installed analyzer manager is missing")
+	require.NoError(t, jas.PinAnalyzerManagerFiles(analyzerManagerDir, "1.2.3"))
+	require.NoError(t, Export(bundlePath))
+	dependenciesDirCallback()
+	checksum, err := utils.GetFileSha256(bundlePath)
+	require.NoError(t, err)
+	assert.FileExists(t, bundlePath+ChecksumFileSuffix)
+
+	// Install the tools on the air-gapped machine
+	callback := clientTestUtils.SetEnvWithCallbackAndAssert(t, coreutils.DependenciesDir, t.TempDir())
+	defer callback()
+	offlineCallback := clientTestUtils.SetEnvWithCallbackAndAssert(t, jas.OfflineModeEnvVar, "true")
+	defer offlineCallback()
+	assert.ErrorContains(t, jas.DownloadAnalyzerManagerIfNeeded(0), "can't be downloaded in offline mode")
+	assert.ErrorContains(t, Install(bundlePath, "0000"), "but 0000 was expected")
+
+	// The checksum file next to the bundle isn't trusted, so the checksum must be provided
+	assert.ErrorContains(t, Install(bundlePath, ""), "--sha256")
+	require.NoError(t, Install(bundlePath, checksum))
+	analyzerManagerDir, err = jas.GetAnalyzerManagerDirAbsolutePath()
+	require.NoError(t, err)
+	assertFileContent(t, filepath.Join(analyzerManagerDir, jas.GetAnalyzerManagerExecutableName()), "analyzer manager")
+	assertFileContent(t, filepath.Join(analyzerManagerDir, "jas_scanner", "jas_scanner"), "scanner")
+	assertFileContent(t, filepath.Join(analyzerManagerDir, dependencies.ChecksumFileName), "pinned-checksum")
+	versions, err := scan.GetCachedIndexerVersions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2.1.0"}, versions)
+	assert.NoError(t, jas.DownloadAnalyzerManagerIfNeeded(0))
+	installedVersion, err := jas.GetInstalledAnalyzerManagerVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", installedVersion)
+
+	// The installed files are verified in offline mode
+	writeFile(t, filepath.Join(analyzerManagerDir, "jas_scanner", "jas_scanner"), "modified scanner")
+	assert.ErrorContains(t, jas.DownloadAnalyzerManagerIfNeeded(0), "can't be trusted in offline mode")
+}
+
+func TestExtractBundleVerifiesFiles(t *testing.T) {
+	dependenciesDirCallback := clientTestUtils.SetEnvWithCallbackAndAssert(t, coreutils.DependenciesDir, t.TempDir())
+	defer dependenciesDirCallback()
+	indexerDir, err := scan.GetIndexerDirAbsolutePath()
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(indexerDir, "2.1.0", scan.GetIndexerBinaryName()), "indexer")
+	bundlePath := filepath.Join(t.TempDir(), "tools.zip")
+	require.NoError(t, Export(bundlePath))
+
+	bundle, err := zip.OpenReader(bundlePath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bundle.Close())
+	}()
+	manifest, err := readManifest(&bundle.Reader)
+	require.NoError(t, err)
+	assert.Empty(t, manifest.AnalyzerManagerVersion)
+	assert.Equal(t, []string{"2.1.0"}, manifest.IndexerVersions)
+
+	bundledIndexer := "xray-indexer/2.1.0/" + scan.GetIndexerBinaryName()
+	manifest.Files[bundledIndexer] = "0000"
+	assert.ErrorContains(t, extractBundle(&bundle.Reader, manifest, t.TempDir()), "but 0000 was expected")
+	delete(manifest.Files, bundledIndexer)
+	assert.ErrorContains(t, extractBundle(&bundle.Reader, manifest, t.TempDir()), "unexpected file")
+	manifest.Files["xray-indexer/2.0.0/indexer-app"] = "0000"
+	manifest.Files[bundledIndexer], err = utils.GetFileSha256(filepath.Join(indexerDir, "2.1.0", scan.GetIndexerBinaryName()))
+	require.NoError(t, err)
+	assert.ErrorContains(t, extractBundle(&bundle.Reader, manifest, t.TempDir()), "is missing from the tools bundle")
+}
+
+func TestValidateManifestVersions(t *testing.T) {
+	assert.NoError(t, validateManifestVersions(&bundleManifest{AnalyzerManagerVersion: "1.8.14", IndexerVersions: []string{"2.1.0"}}))
+	assert.NoError(t, validateManifestVersions(&bundleManifest{IndexerVersions: []string{"2.1.0"}}))
+	for _, version := range []string{"..", "../../bin", "2.1.0/../..", `2.1.0\..`, "/tmp", ".", ""} {
+		assert.ErrorContains(t, validateManifestVersions(&bundleManifest{IndexerVersions: []string{version}}), "version", version)
+	}
+	assert.ErrorContains(t, validateManifestVersions(&bundleManifest{AnalyzerManagerVersion: "../1.8.14"}), "invalid version")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0755))
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
@@ -1,11 +1,16 @@
 package formats
 
 import (
+	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
-func ConvertToVulnerabilityTableRow(rows []VulnerabilityOrViolationRow) (tableRows []vulnerabilityTableRow) {
+// The length of the abbreviated commit SHA in the tables, the same as Git's default
+const shortCommitShaLength = 7
+
+func ConvertToVulnerabilityTableRow(rows []VulnerabilityOrViolationRow, baseDir string) (tableRows []vulnerabilityTableRow) {
 	for i := range rows {
 		tableRows = append(tableRows, vulnerabilityTableRow{
 			severity:                  rows[i].Severity,
@@ -14,8 +19,9 @@ func ConvertToVulnerabilityTableRow(rows []VulnerabilityOrViolationRow) (tableRo
 			impactedDependencyName:    rows[i].ImpactedDependencyName,
 			impactedDependencyVersion: rows[i].ImpactedDependencyVersion,
 			impactedDependencyType:    rows[i].ImpactedDependencyType,
+			scope:                     strings.Join(rows[i].Scopes, ", "),
 			fixedVersions:             strings.Join(rows[i].FixedVersions, "\n"),
-			directDependencies:        convertToComponentTableRow(rows[i].Components),
+			directDependencies:        convertToComponentTableRow(rows[i].Components, baseDir),
 			cves:                      convertToCveTableRow(rows[i].Cves),
 			issueId:                   rows[i].IssueId,
 		})
@@ -41,7 +47,7 @@ func ConvertToVulnerabilityScanTableRow(rows []VulnerabilityOrViolationRow) (tab
 	return
 }
 
-func ConvertToLicenseViolationTableRow(rows []LicenseRow) (tableRows []licenseViolationTableRow) {
+func ConvertToLicenseViolationTableRow(rows []LicenseRow, baseDir string) (tableRows []licenseViolationTableRow) {
 	for i := range rows {
 		tableRows = append(tableRows, licenseViolationTableRow{
 			licenseKey:                rows[i].LicenseKey,
@@ -50,7 +56,8 @@ func ConvertToLicenseViolationTableRow(rows []LicenseRow) (tableRows []licenseVi
 			impactedDependencyName:    rows[i].ImpactedDependencyName,
 			impactedDependencyVersion: rows[i].ImpactedDependencyVersion,
 			impactedDependencyType:    rows[i].ImpactedDependencyType,
-			directDependencies:        convertToComponentTableRow(rows[i].Components),
+			scope:                     strings.Join(rows[i].Scopes, ", "),
+			directDependencies:        convertToComponentTableRow(rows[i].Components, baseDir),
 		})
 	}
 	return
@@ -71,14 +78,15 @@ func ConvertToLicenseViolationScanTableRow(rows []LicenseRow) (tableRows []licen
 	return
 }
 
-func ConvertToLicenseTableRow(rows []LicenseRow) (tableRows []licenseTableRow) {
+func ConvertToLicenseTableRow(rows []LicenseRow, baseDir string) (tableRows []licenseTableRow) {
 	for i := range rows {
 		tableRows = append(tableRows, licenseTableRow{
 			licenseKey:                rows[i].LicenseKey,
 			impactedDependencyName:    rows[i].ImpactedDependencyName,
 			impactedDependencyVersion: rows[i].ImpactedDependencyVersion,
 			impactedDependencyType:    rows[i].ImpactedDependencyType,
-			directDependencies:        convertToComponentTableRow(rows[i].Components),
+			scope:                     strings.Join(rows[i].Scopes, ", "),
+			directDependencies:        convertToComponentTableRow(rows[i].Components, baseDir),
 		})
 	}
 	return
@@ -97,7 +105,7 @@ func ConvertToLicenseScanTableRow(rows []LicenseRow) (tableRows []licenseScanTab
 	return
 }
 
-func ConvertToOperationalRiskViolationTableRow(rows []OperationalRiskViolationRow) (tableRows []operationalRiskViolationTableRow) {
+func ConvertToOperationalRiskViolationTableRow(rows []OperationalRiskViolationRow, baseDir string) (tableRows []operationalRiskViolationTableRow) {
 	for i := range rows {
 		tableRows = append(tableRows, operationalRiskViolationTableRow{
 			Severity:                  rows[i].Severity,
@@ -105,7 +113,8 @@ func ConvertToOperationalRiskViolationTableRow(rows []OperationalRiskViolationRo
 			impactedDependencyName:    rows[i].ImpactedDependencyName,
 			impactedDependencyVersion: rows[i].ImpactedDependencyVersion,
 			impactedDependencyType:    rows[i].ImpactedDependencyType,
-			directDependencies:        convertToComponentTableRow(rows[i].Components),
+			scope:                     strings.Join(rows[i].Scopes, ", "),
+			directDependencies:        convertToComponentTableRow(rows[i].Components, baseDir),
 			isEol:                     rows[i].IsEol,
 			cadence:                   rows[i].Cadence,
 			Commits:                   rows[i].Commits,
@@ -148,11 +157,29 @@ func ConvertToSecretsTableRow(rows []SourceCodeRow) (tableRows []secretsTableRow
 			file:       rows[i].File,
 			lineColumn: strconv.Itoa(rows[i].StartLine) + ":" + strconv.Itoa(rows[i].StartColumn),
 			secret:     rows[i].Snippet,
+			commit:     convertToCommitTableValue(rows[i].Commit),
 		})
 	}
 	return
 }
 
+func convertToCommitTableValue(commit *CommitDetails) string {
+	if commit == nil {
+		return ""
+	}
+	value := commit.Sha
+	if len(value) > shortCommitShaLength {
+		value = value[:shortCommitShaLength]
+	}
+	if commit.Author != "" {
+		value += "\n" + commit.Author
+	}
+	if commit.Date != "" {
+		value += "\n" + commit.Date
+	}
+	return value
+}
+
 func ConvertToIacOrSastTableRow(rows []SourceCodeRow) (tableRows []iacOrSastTableRow) {
 	for i := range rows {
 		tableRows = append(tableRows, iacOrSastTableRow{
@@ -165,16 +192,32 @@ func ConvertToIacOrSastTableRow(rows []SourceCodeRow) (tableRows []iacOrSastTabl
 	return
 }
 
-func convertToComponentTableRow(rows []ComponentRow) (tableRows []directDependenciesTableRow) {
+// The declarations files are shown relative to the base directory, if they are inside it.
+func convertToComponentTableRow(rows []ComponentRow, baseDir string) (tableRows []directDependenciesTableRow) {
 	for i := range rows {
 		tableRows = append(tableRows, directDependenciesTableRow{
-			name:    rows[i].Name,
-			version: rows[i].Version,
+			name:       rows[i].Name,
+			version:    rows[i].Version,
+			declaredIn: convertToDeclarationTableValue(rows[i].Declaration, baseDir),
 		})
 	}
 	return
 }
 
+// Returns the declaration as <file>:<line> (<type>), with the file relative to the base directory if it's inside it.
+func convertToDeclarationTableValue(declaration *Declaration, baseDir string) string {
+	if declaration == nil {
+		return ""
+	}
+	file := declaration.File
+	if baseDir != "" {
+		if relativePath, err := filepath.Rel(baseDir, file); err == nil && filepath.IsLocal(relativePath) {
+			file = relativePath
+		}
+	}
+	return fmt.Sprintf("%s:%d (%s)", file, declaration.StartLine, declaration.Type)
+}
+
 func convertToComponentScanTableRow(rows []ComponentRow) (tableRows []directPackagesTableRow) {
 	for i := range rows {
 		tableRows = append(tableRows, directPackagesTableRow{
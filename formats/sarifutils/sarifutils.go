@@ -227,6 +227,20 @@ func GetResultRuleId(result *sarif.Result) string {
 	return ""
 }
 
+func SetResultProperty(property, value string, result *sarif.Result) {
+	if result.Properties == nil {
+		result.Properties = make(sarif.Properties)
+	}
+	result.Properties[property] = value
+}
+
+func GetResultProperty(property string, result *sarif.Result) string {
+	if value, ok := result.Properties[property].(string); ok {
+		return value
+	}
+	return ""
+}
+
 func IsFingerprintsExists(result *sarif.Result) bool {
 	return len(result.Fingerprints) > 0
 }
@@ -283,6 +297,9 @@ func ConvertRunsPathsToRelative(runs ...*sarif.Run) {
 			for _, location := range result.Locations {
 				SetLocationFileName(location, GetRelativeLocationFileName(location, run.Invocations))
 			}
+			for _, location := range result.RelatedLocations {
+				SetLocationFileName(location, GetRelativeLocationFileName(location, run.Invocations))
+			}
 			for _, flows := range result.CodeFlows {
 				for _, flow := range flows.ThreadFlows {
 					for _, location := range flow.Locations {
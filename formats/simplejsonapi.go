@@ -30,6 +30,10 @@ type ImpactedDependencyDetails struct {
 	ImpactedDependencyVersion string         `json:"impactedPackageVersion"`
 	ImpactedDependencyType    string         `json:"impactedPackageType"`
 	Components                []ComponentRow `json:"components"`
+	// The scopes of the dependency in the project (runtime, dev or test), if known
+	Scopes []string `json:"scopes,omitempty"`
+	// The names of the workspace members that depend on the dependency, if the project is a workspace
+	WorkspaceMembers []string `json:"workspaceMembers,omitempty"`
 }
 
 // Used for vulnerabilities and security violations
@@ -70,6 +74,14 @@ type SourceCodeRow struct {
 	Finding            string       `json:"finding,omitempty"`
 	ScannerDescription string       `json:"scannerDescription,omitempty"`
 	CodeFlow           [][]Location `json:"codeFlow,omitempty"`
+	// The commit that added the finding, for secrets that were found in the Git history
+	Commit *CommitDetails `json:"commit,omitempty"`
+}
+
+type CommitDetails struct {
+	Sha    string `json:"sha"`
+	Author string `json:"author,omitempty"`
+	Date   string `json:"date,omitempty"`
 }
 
 type Location struct {
@@ -84,6 +96,29 @@ type Location struct {
 type ComponentRow struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
+	// Where the version of the component is declared in the project descriptors, if known
+	Declaration *Declaration `json:"declaration,omitempty"`
+}
+
+// The descriptor element that declares the version of a dependency
+type DeclarationType string
+
+const (
+	// A dependency of the module descriptor
+	ModuleDeclaration DeclarationType = "module"
+	// A dependency or managed dependency of a parent descriptor
+	ParentDeclaration DeclarationType = "parent"
+	// A managed dependency of an imported BOM. The location is the import of the BOM.
+	BomDeclaration DeclarationType = "bom"
+	// A property that the declared version refers to
+	PropertyDeclaration DeclarationType = "property"
+)
+
+type Declaration struct {
+	Location
+	Type DeclarationType `json:"type,omitempty"`
+	// The coordinates of the imported BOM, for BOM declarations
+	Bom string `json:"bom,omitempty"`
 }
 
 type CveRow struct {
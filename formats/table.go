@@ -15,6 +15,7 @@ type vulnerabilityTableRow struct {
 	impactedDependencyVersion string                       `col-name:"Impacted\nDependency\nVersion"`
 	fixedVersions             string                       `col-name:"Fixed\nVersions"`
 	impactedDependencyType    string                       `col-name:"Type"`
+	scope                     string                       `col-name:"Scope" omitempty:"true"`
 	cves                      []cveTableRow                `embed-table:"true"`
 	issueId                   string                       `col-name:"Issue ID" extended:"true"`
 }
@@ -39,6 +40,7 @@ type licenseTableRow struct {
 	impactedDependencyName    string                       `col-name:"Impacted\nDependency"`
 	impactedDependencyVersion string                       `col-name:"Impacted\nDependency\nVersion"`
 	impactedDependencyType    string                       `col-name:"Type"`
+	scope                     string                       `col-name:"Scope" omitempty:"true"`
 }
 
 type licenseScanTableRow struct {
@@ -58,6 +60,7 @@ type licenseViolationTableRow struct {
 	impactedDependencyName    string                       `col-name:"Impacted\nDependency"`
 	impactedDependencyVersion string                       `col-name:"Impacted\nDependency\nVersion"`
 	impactedDependencyType    string                       `col-name:"Type"`
+	scope                     string                       `col-name:"Scope" omitempty:"true"`
 }
 
 type licenseViolationScanTableRow struct {
@@ -79,6 +82,7 @@ type operationalRiskViolationTableRow struct {
 	impactedDependencyName    string                       `col-name:"Impacted\nDependency"`
 	impactedDependencyVersion string                       `col-name:"Impacted\nDependency\nVersion"`
 	impactedDependencyType    string                       `col-name:"Type"`
+	scope                     string                       `col-name:"Scope" omitempty:"true"`
 	riskReason                string                       `col-name:"Risk\nReason"`
 	isEol                     string                       `col-name:"Is\nEnd\nOf\nLife" extended:"true"`
 	eolMessage                string                       `col-name:"End\nOf\nLife\nMessage" extended:"true"`
@@ -108,8 +112,9 @@ type operationalRiskViolationScanTableRow struct {
 }
 
 type directDependenciesTableRow struct {
-	name    string `col-name:"Direct\nDependency"`
-	version string `col-name:"Direct\nDependency\nVersion"`
+	name       string `col-name:"Direct\nDependency"`
+	version    string `col-name:"Direct\nDependency\nVersion"`
+	declaredIn string `col-name:"Version\nDeclared In" extended:"true"`
 }
 
 type directPackagesTableRow struct {
@@ -128,6 +133,7 @@ type secretsTableRow struct {
 	file       string `col-name:"File"`
 	lineColumn string `col-name:"Line:Column"`
 	secret     string `col-name:"Secret"`
+	commit     string `col-name:"Commit" omitempty:"true"`
 }
 
 type iacOrSastTableRow struct {
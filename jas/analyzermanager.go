@@ -1,13 +1,19 @@
 package jas
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
@@ -41,8 +47,15 @@ const (
 	jfrogCliAnalyzerManagerVersionEnvVariable = "JFROG_CLI_ANALYZER_MANAGER_VERSION"
 	JfPackageManagerEnvVariable               = "AM_PACKAGE_MANAGER"
 	JfLanguageEnvVariable                     = "AM_LANGUAGE"
+	// When set to true, the analyzer manager and the indexer aren't downloaded, and the ones installed locally are used as is
+	OfflineModeEnvVar = "JFROG_CLI_SECURITY_OFFLINE"
+	// The version of the installed analyzer manager, and the checksums of its files, which are pinned when it's downloaded or installed
+	analyzerManagerVersionFileName        = "version.txt"
+	analyzerManagerFilesChecksumsFileName = "files.sha256"
 )
 
+var errScannerTimeout = errors.New("the analyzer manager didn't finish the scan within the timeout")
+
 var exitCodeErrorsMap = map[int]string{
 	notEntitledExitCode:        "got not entitled error from analyzer manager",
 	unsupportedCommandExitCode: "got unsupported scan command error from analyzer manager",
@@ -52,6 +65,23 @@ var exitCodeErrorsMap = map[int]string{
 type AnalyzerManager struct {
 	AnalyzerManagerFullPath string
 	MultiScanId             string
+	// The maximal duration of a scan, zero for no timeout
+	Timeout time.Duration
+	// The maximal memory of the analyzer manager process in megabytes, zero for no limit. Supported on Linux only.
+	MemoryLimitMB uint64
+	// The context of the command that runs the scans, which cancels them when the command is interrupted. Background if not set.
+	Context context.Context
+}
+
+// Returns a context that is canceled when the command is interrupted, to cancel the analyzer manager scans of the command with their sub processes.
+// The signals are handled as usual after the first interrupt, so interrupting again exits the CLI.
+func NewInterruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
 }
 
 func (am *AnalyzerManager) Exec(configFile, scanCommand, workingDir string, serverDetails *config.ServerDetails, envVars map[string]string) (err error) {
@@ -59,32 +89,75 @@ func (am *AnalyzerManager) Exec(configFile, scanCommand, workingDir string, serv
 }
 
 func (am *AnalyzerManager) ExecWithOutputFile(configFile, scanCommand, workingDir, outputFile string, serverDetails *config.ServerDetails, envVars map[string]string) (err error) {
-	var cmd *exec.Cmd
-	multiScanId := envVars[utils.JfMsiEnvVariable]
+	args := []string{scanCommand, configFile}
 	if len(outputFile) > 0 {
-		log.Debug("Executing", am.AnalyzerManagerFullPath, scanCommand, configFile, outputFile, multiScanId)
-		cmd = exec.Command(am.AnalyzerManagerFullPath, scanCommand, configFile, outputFile)
-	} else {
-		log.Debug("Executing", am.AnalyzerManagerFullPath, scanCommand, configFile, multiScanId)
-		cmd = exec.Command(am.AnalyzerManagerFullPath, scanCommand, configFile)
-	}
-	defer func() {
-		if cmd.ProcessState != nil && !cmd.ProcessState.Exited() {
-			if killProcessError := cmd.Process.Kill(); errorutils.CheckError(killProcessError) != nil {
-				err = errors.Join(err, killProcessError)
-			}
+		args = append(args, outputFile)
+	}
+	log.Debug("Executing", am.AnalyzerManagerFullPath, strings.Join(args, " "), envVars[utils.JfMsiEnvVariable])
+	ctx := am.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if am.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, am.Timeout)
+		defer cancel()
+	}
+	name := am.AnalyzerManagerFullPath
+	if am.MemoryLimitMB > 0 {
+		var limitErr error
+		if name, args, limitErr = getMemoryLimitedCommand(name, args, am.MemoryLimitMB); limitErr != nil {
+			log.Warn(fmt.Sprintf("Couldn't limit the memory of the analyzer manager to %dMB: %s", am.MemoryLimitMB, limitErr.Error()))
 		}
-	}()
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	// The analyzer manager runs the scanners in sub processes, which are killed with it when the scan is canceled
+	setProcessGroup(cmd)
 	cmd.Env = utils.ToCommandEnvVars(envVars)
 	cmd.Dir = workingDir
-	output, err := cmd.CombinedOutput()
-	if isCI() || err != nil {
-		if len(output) > 0 {
-			log.Debug(fmt.Sprintf("%s %q output: %s", workingDir, strings.Join(cmd.Args, " "), string(output)))
+	output := &analyzerManagerLogWriter{prefix: fmt.Sprintf("[%s] ", scanCommand)}
+	cmd.Stdout, cmd.Stderr = output, output
+	defer output.flush()
+	// A scan that completed just before the context was done is kept, so the context is checked only when the scan failed
+	if err = cmd.Run(); err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("%w of %s", errScannerTimeout, am.Timeout)
+	case ctx.Err() != nil:
+		err = errors.New("the analyzer manager scan was interrupted")
+	}
+	return errorutils.CheckError(err)
+}
+
+// Writes the output of the analyzer manager to the debug log, line by line while the scan runs.
+type analyzerManagerLogWriter struct {
+	prefix string
+	buffer []byte
+}
+
+func (w *analyzerManagerLogWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+	for {
+		lineEnd := bytes.IndexByte(w.buffer, '\n')
+		if lineEnd == -1 {
+			return len(p), nil
 		}
-		err = errorutils.CheckError(err)
+		w.logLine(w.buffer[:lineEnd])
+		w.buffer = w.buffer[lineEnd+1:]
+	}
+}
+
+func (w *analyzerManagerLogWriter) flush() {
+	w.logLine(w.buffer)
+	w.buffer = nil
+}
+
+func (w *analyzerManagerLogWriter) logLine(line []byte) {
+	if trimmed := strings.TrimRight(string(line), "\r"); trimmed != "" {
+		log.Debug(w.prefix + trimmed)
 	}
-	return
 }
 
 func GetAnalyzerManagerDownloadPath() (string, error) {
@@ -164,15 +237,32 @@ func ParseAnalyzerManagerError(scanner jasutils.JasScanType, err error) (formatE
 			return nil
 		}
 	}
+	if IsScannerTimeoutError(err) {
+		return fmt.Errorf("%s scan: %w", scanner, err)
+	}
 	if err != nil {
 		return fmt.Errorf(ErrFailedScannerRun, scanner, err.Error())
 	}
 	return
 }
 
+// Returns true if the analyzer manager was stopped since the scan exceeded its timeout.
+func IsScannerTimeoutError(err error) bool {
+	return errors.Is(err, errScannerTimeout)
+}
+
+// Returns true if the analyzer manager failed because it doesn't support the current operating system.
+func IsUnsupportedOsError(err error) bool {
+	var exitError *exec.ExitError
+	return errors.As(err, &exitError) && exitError.ExitCode() == unsupportedOsExitCode
+}
+
 // Download the latest AnalyzerManager executable if not cached locally.
 // By default, the zip is downloaded directly from jfrog releases.
 func DownloadAnalyzerManagerIfNeeded(threadId int) error {
+	if IsOfflineMode() {
+		return verifyOfflineAnalyzerManager(threadId)
+	}
 	downloadPath, err := GetAnalyzerManagerDownloadPath()
 	if err != nil {
 		return err
@@ -210,7 +300,11 @@ func DownloadAnalyzerManagerIfNeeded(threadId int) error {
 		}
 		// If the checksums are identical, there's no need to download.
 		if remoteFileDetails.Checksum.Sha256 == string(sha2) {
-			return nil
+			// The files of an analyzer manager that was downloaded by a previous version of the CLI aren't pinned yet
+			if pinned, pinnedErr := fileutils.IsFileExists(filepath.Join(analyzerManagerDir, analyzerManagerFilesChecksumsFileName), false); pinnedErr != nil || pinned {
+				return pinnedErr
+			}
+			return PinAnalyzerManagerFiles(analyzerManagerDir, GetAnalyzerManagerVersion())
 		}
 	}
 	// Download & unzip the analyzer manager files
@@ -218,7 +312,113 @@ func DownloadAnalyzerManagerIfNeeded(threadId int) error {
 	if err = dependencies.DownloadDependency(artDetails, remotePath, filepath.Join(analyzerManagerDir, AnalyzerManagerZipName), true); err != nil {
 		return err
 	}
-	return dependencies.CreateChecksumFile(checksumFilePath, remoteFileDetails.Checksum.Sha256)
+	if err = dependencies.CreateChecksumFile(checksumFilePath, remoteFileDetails.Checksum.Sha256); err != nil {
+		return err
+	}
+	return PinAnalyzerManagerFiles(analyzerManagerDir, GetAnalyzerManagerVersion())
+}
+
+// Records the version of the analyzer manager that is installed in the directory and the SHA-256 checksums of its files, so it can be verified in offline mode.
+func PinAnalyzerManagerFiles(analyzerManagerDir, version string) error {
+	var checksums strings.Builder
+	err := filepath.WalkDir(analyzerManagerDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(analyzerManagerDir, filePath)
+		if err != nil || isAnalyzerManagerMetadataFile(relativePath) {
+			return err
+		}
+		checksum, err := utils.GetFileSha256(filePath)
+		if err != nil {
+			return err
+		}
+		// The format of the sha256sum command
+		checksums.WriteString(checksum + "  " + filepath.ToSlash(relativePath) + "\n")
+		return nil
+	})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.WriteFile(filepath.Join(analyzerManagerDir, analyzerManagerFilesChecksumsFileName), []byte(checksums.String()), 0644); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(filepath.Join(analyzerManagerDir, analyzerManagerVersionFileName), []byte(version), 0644))
+}
+
+func isAnalyzerManagerMetadataFile(relativePath string) bool {
+	return relativePath == dependencies.ChecksumFileName || relativePath == analyzerManagerVersionFileName || relativePath == analyzerManagerFilesChecksumsFileName
+}
+
+// Returns the version of the locally installed analyzer manager, or an empty string if it wasn't pinned when it was installed.
+func GetInstalledAnalyzerManagerVersion() (string, error) {
+	analyzerManagerDir, err := GetAnalyzerManagerDirAbsolutePath()
+	if err != nil {
+		return "", err
+	}
+	version, err := os.ReadFile(filepath.Join(analyzerManagerDir, analyzerManagerVersionFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", errorutils.CheckError(err)
+	}
+	return strings.TrimSpace(string(version)), nil
+}
+
+func IsOfflineMode() bool {
+	return strings.ToLower(os.Getenv(OfflineModeEnvVar)) == "true"
+}
+
+// In offline mode, the remote checksum isn't queried. The installed analyzer manager is trusted if its files match the checksums that were pinned when it was downloaded or installed.
+func verifyOfflineAnalyzerManager(threadId int) error {
+	analyzerManagerDir, err := GetAnalyzerManagerDirAbsolutePath()
+	if err != nil {
+		return err
+	}
+	checksums, err := os.ReadFile(filepath.Join(analyzerManagerDir, analyzerManagerFilesChecksumsFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return errorutils.CheckErrorf("the 'Analyzer Manager' app is not cached locally, and it can't be downloaded in offline mode (%s). Install it from a tools bundle with 'jf security tools install --from=<bundle zip>'", OfflineModeEnvVar)
+		}
+		return errorutils.CheckError(err)
+	}
+	if err = verifyAnalyzerManagerFiles(analyzerManagerDir, string(checksums)); err != nil {
+		return errorutils.CheckErrorf("the locally installed 'Analyzer Manager' app can't be trusted in offline mode (%s): %s. Install it again from a tools bundle with 'jf security tools install --from=<bundle zip>'", OfflineModeEnvVar, err.Error())
+	}
+	installedVersion, err := GetInstalledAnalyzerManagerVersion()
+	if err != nil {
+		return err
+	}
+	if installedVersion != GetAnalyzerManagerVersion() {
+		log.Warn(fmt.Sprintf("The installed analyzer manager version is %s, but version %s is expected by this JFrog CLI. In offline mode, the installed version is used as is.", installedVersion, GetAnalyzerManagerVersion()))
+	}
+	log.Debug(clientutils.GetLogMsgPrefix(threadId, false) + "Offline mode is enabled, using the locally installed 'Analyzer Manager' app.")
+	return nil
+}
+
+// Verifies that the files of the analyzer manager match their pinned checksums, and that the executable is one of them.
+func verifyAnalyzerManagerFiles(analyzerManagerDir, checksums string) error {
+	executablePinned := false
+	for _, line := range strings.Split(strings.TrimSpace(checksums), "\n") {
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) != 2 || !filepath.IsLocal(filepath.FromSlash(fields[1])) {
+			return fmt.Errorf("invalid line in %s: '%s'", analyzerManagerFilesChecksumsFileName, line)
+		}
+		relativePath := filepath.FromSlash(fields[1])
+		checksum, err := utils.GetFileSha256(filepath.Join(analyzerManagerDir, relativePath))
+		if err != nil {
+			return err
+		}
+		if checksum != fields[0] {
+			return fmt.Errorf("the SHA-256 checksum of '%s' is %s, but %s was pinned", fields[1], checksum, fields[0])
+		}
+		executablePinned = executablePinned || relativePath == GetAnalyzerManagerExecutableName()
+	}
+	if !executablePinned {
+		return errors.New("the checksum of the executable isn't pinned")
+	}
+	return nil
 }
 
 func getAnalyzerManagerRemoteDetails(downloadPath string) (server *config.ServerDetails, fullRemotePath string, err error) {
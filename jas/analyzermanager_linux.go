@@ -0,0 +1,9 @@
+package jas
+
+import "strconv"
+
+// Returns a command that runs the analyzer manager with a limited virtual memory.
+// The limit is set by a shell before it executes the analyzer manager, so the analyzer manager and all its sub processes are limited from their start.
+func getMemoryLimitedCommand(name string, args []string, limitMB uint64) (string, []string, error) {
+	return "/bin/sh", append([]string{"-c", `ulimit -v "$1" && shift && exec "$@"`, "sh", strconv.FormatUint(limitMB*1024, 10), name}, args...), nil
+}
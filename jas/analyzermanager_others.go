@@ -0,0 +1,9 @@
+//go:build !linux
+
+package jas
+
+import "errors"
+
+func getMemoryLimitedCommand(name string, args []string, _ uint64) (string, []string, error) {
+	return name, args, errors.New("limiting the memory of the analyzer manager is supported on Linux only")
+}
@@ -0,0 +1,163 @@
+package jas
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
+	clientTestUtils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake analyzer manager is a shell script")
+	}
+	// The fake analyzer manager runs a sub process that doesn't finish, so the scan is stopped only if its process group is killed
+	analyzerManagerPath := filepath.Join(t.TempDir(), "analyzerManager")
+	require.NoError(t, os.WriteFile(analyzerManagerPath, []byte("#!/bin/sh\necho scanning\nsleep 60\n"), 0755))
+	analyzerManager := AnalyzerManager{AnalyzerManagerFullPath: analyzerManagerPath, Timeout: 500 * time.Millisecond}
+
+	start := time.Now()
+	err := analyzerManager.Exec("config.yaml", "sec", t.TempDir(), nil, map[string]string{})
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.True(t, IsScannerTimeoutError(err))
+	assert.True(t, IsScannerTimeoutError(ParseAnalyzerManagerError("Secrets", err)))
+
+	analyzerManager.Timeout = 0
+	require.NoError(t, os.WriteFile(analyzerManagerPath, []byte("#!/bin/sh\nexit 3\n"), 0755))
+	err = analyzerManager.Exec("config.yaml", "sec", t.TempDir(), nil, map[string]string{})
+	assert.Error(t, err)
+	assert.False(t, IsScannerTimeoutError(err))
+}
+
+func TestExecCanceledAndMemoryLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake analyzer manager is a shell script")
+	}
+	analyzerManagerPath := filepath.Join(t.TempDir(), "analyzerManager")
+	require.NoError(t, os.WriteFile(analyzerManagerPath, []byte("#!/bin/sh\nsleep 60\n"), 0755))
+	// The scans of a command are canceled with its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	analyzerManager := AnalyzerManager{AnalyzerManagerFullPath: analyzerManagerPath, Context: ctx}
+	err := analyzerManager.Exec("config.yaml", "sec", t.TempDir(), nil, map[string]string{})
+	assert.ErrorContains(t, err, "interrupted")
+	assert.False(t, IsScannerTimeoutError(err))
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	// The limit is set before the analyzer manager starts
+	workingDir := t.TempDir()
+	require.NoError(t, os.WriteFile(analyzerManagerPath, []byte("#!/bin/sh\nulimit -v > limit.txt\n"), 0755))
+	analyzerManager = AnalyzerManager{AnalyzerManagerFullPath: analyzerManagerPath, MemoryLimitMB: 2048}
+	require.NoError(t, analyzerManager.Exec("config.yaml", "sec", workingDir, nil, map[string]string{}))
+	limit, err := os.ReadFile(filepath.Join(workingDir, "limit.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "2097152", strings.TrimSpace(string(limit)))
+}
+
+func TestAnalyzerManagerLogWriter(t *testing.T) {
+	writer := &analyzerManagerLogWriter{prefix: "[sec] "}
+	_, err := writer.Write([]byte("first line\nsecond "))
+	assert.NoError(t, err)
+	assert.Equal(t, "second ", string(writer.buffer))
+	_, err = writer.Write([]byte("line\r\nthird"))
+	assert.NoError(t, err)
+	assert.Equal(t, "third", string(writer.buffer))
+	writer.flush()
+	assert.Empty(t, writer.buffer)
+}
+
+func TestGetModulesTimeouts(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	tempDir := t.TempDir()
+	chdirCallback := clientTestUtils.ChangeDirWithCallback(t, wd, tempDir)
+	defer chdirCallback()
+
+	// No JFrog apps config
+	modulesSettings, err := getModulesJasSettings()
+	assert.NoError(t, err)
+	assert.Empty(t, modulesSettings)
+
+	require.NoError(t, os.MkdirAll(".jfrog", 0755))
+	require.NoError(t, os.WriteFile(JFrogAppsConfigPath, []byte(`version: "1.0"
+modules:
+  - source_root: "api"
+    jas_timeout: "45m"
+  - source_root: "web"
+    iac_helm_values: ["charts/web/values-prod.yaml"]
+`), 0644))
+	modulesSettings, err = getModulesJasSettings()
+	require.NoError(t, err)
+	timeouts, err := getModulesTimeouts(modulesSettings)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{GetModuleKey("api"): 45 * time.Minute}, timeouts)
+
+	scanner := &JasScanner{AnalyzerManager: AnalyzerManager{Timeout: time.Hour}, ModulesTimeouts: timeouts}
+	assert.Equal(t, 45*time.Minute, scanner.GetAnalyzerManager(jfrogappsconfig.Module{SourceRoot: "api"}).Timeout)
+	// The module roots can be absolute
+	assert.Equal(t, 45*time.Minute, scanner.GetAnalyzerManager(jfrogappsconfig.Module{SourceRoot: filepath.Join(tempDir, "api")}).Timeout)
+	assert.Equal(t, time.Hour, scanner.GetAnalyzerManager(jfrogappsconfig.Module{SourceRoot: "web"}).Timeout)
+	assert.Equal(t, time.Hour, scanner.AnalyzerManager.Timeout)
+	assert.Equal(t, map[string][]string{GetModuleKey("web"): {"charts/web/values-prod.yaml"}}, getModulesHelmValues(modulesSettings))
+
+	require.NoError(t, os.WriteFile(JFrogAppsConfigPath, []byte("modules:\n  - source_root: \"api\"\n    jas_timeout: \"soon\"\n"), 0644))
+	modulesSettings, err = getModulesJasSettings()
+	require.NoError(t, err)
+	_, err = getModulesTimeouts(modulesSettings)
+	assert.ErrorContains(t, err, "invalid jas_timeout 'soon'")
+}
+
+func TestLoadCustomRules(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	tempDir := t.TempDir()
+	chdirCallback := clientTestUtils.ChangeDirWithCallback(t, wd, tempDir)
+	defer chdirCallback()
+
+	writeRules := func(dir, id, ruleType string) {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, id+".yaml"), []byte("rules:\n  - id: "+id+"\n    type: "+ruleType+"\n    description: test\n    regex: "+id+"\n"), 0644))
+	}
+	writeRules("cli-rules", "cli-secret", "secrets")
+	writeRules(filepath.Join("api", "rules"), "api-sast", "sast")
+	writeRules(customrules.DefaultCustomRulesDir, "repo-secret", "secrets")
+	require.NoError(t, os.WriteFile(JFrogAppsConfigPath, []byte("version: \"1.0\"\nmodules:\n  - source_root: \"api\"\n    custom_rules: [\"rules\"]\n"), 0644))
+	appsConfig := &jfrogappsconfig.JFrogAppsConfig{Modules: []jfrogappsconfig.Module{{SourceRoot: "api"}, {SourceRoot: "web"}}}
+
+	customRules, err := LoadCustomRules(appsConfig, []string{"cli-rules"})
+	require.NoError(t, err)
+	getIds := func(rules []customrules.Rule) (ids []string) {
+		for _, rule := range rules {
+			ids = append(ids, rule.Id)
+		}
+		return
+	}
+	// The modules are matched by their keys, so an absolute source root gets the same rules
+	assert.Equal(t, []string{"cli-secret", "repo-secret"}, getIds(customRules.GetModuleRules(jfrogappsconfig.Module{SourceRoot: filepath.Join(tempDir, "api")}, customrules.SecretsRule)))
+	assert.Equal(t, []string{"api-sast"}, getIds(customRules.GetModuleRules(jfrogappsconfig.Module{SourceRoot: "api"}, customrules.SastRule)))
+	assert.Empty(t, customRules.GetModuleRules(jfrogappsconfig.Module{SourceRoot: "web"}, customrules.SastRule))
+	assert.Empty(t, (*CustomRules)(nil).GetModuleRules(jfrogappsconfig.Module{SourceRoot: "web"}, customrules.SecretsRule))
+
+	// The custom rules directory of the repository is optional, while the configured directories must exist
+	require.NoError(t, os.RemoveAll(customrules.DefaultCustomRulesDir))
+	_, err = LoadCustomRules(appsConfig, []string{"cli-rules"})
+	assert.NoError(t, err)
+	_, err = LoadCustomRules(appsConfig, []string{"missing-rules"})
+	assert.ErrorContains(t, err, "doesn't exist")
+
+	// An invalid rule fails the loading
+	require.NoError(t, os.WriteFile(filepath.Join("cli-rules", "invalid.yaml"), []byte("rules:\n  - id: invalid\n    type: secrets\n"), 0644))
+	_, err = LoadCustomRules(appsConfig, []string{"cli-rules"})
+	assert.ErrorContains(t, err, "'description' is required")
+}
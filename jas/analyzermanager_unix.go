@@ -0,0 +1,16 @@
+//go:build !windows
+
+package jas
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Runs the command in a new process group, so canceling the command kills all the processes in the group.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
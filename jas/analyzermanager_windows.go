@@ -0,0 +1,6 @@
+package jas
+
+import "os/exec"
+
+// Process groups can't be killed on Windows, canceling the command kills its process only.
+func setProcessGroup(_ *exec.Cmd) {}
@@ -9,6 +9,7 @@ import (
 	"github.com/jfrog/jfrog-cli-security/jas"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
@@ -27,6 +28,14 @@ const (
 
 type ApplicabilityScanType string
 
+// The technologies whose CVEs are analyzed by the Contextual Analysis scanner in the source code.
+// The CVEs of other technologies, such as Go, are reported as not covered.
+var supportedTechnologies = []techutils.Technology{techutils.Npm, techutils.Yarn, techutils.Pnpm, techutils.Pip, techutils.Pipenv, techutils.Poetry, techutils.Uv, techutils.Pdm, techutils.Maven, techutils.Gradle}
+
+func IsSupportedTechnology(tech techutils.Technology) bool {
+	return slices.Contains(supportedTechnologies, tech)
+}
+
 type ApplicabilityScanManager struct {
 	applicabilityScanResults []*sarif.Run
 	directDependenciesCves   []string
@@ -132,7 +141,7 @@ func (asm *ApplicabilityScanManager) Run(module jfrogappsconfig.Module) (err err
 	if err = asm.createConfigFile(module, asm.scanner.Exclusions...); err != nil {
 		return
 	}
-	if err = asm.runAnalyzerManager(); err != nil {
+	if err = asm.runAnalyzerManager(module); err != nil {
 		return
 	}
 	workingDirResults, err := jas.ReadJasScanRunsFromFile(asm.resultsFileName, module.SourceRoot, applicabilityDocsUrlSuffix)
@@ -190,8 +199,8 @@ func (asm *ApplicabilityScanManager) createConfigFile(module jfrogappsconfig.Mod
 
 // Runs the analyzerManager app and returns a boolean to indicate whether the user is entitled for
 // advance security feature
-func (asm *ApplicabilityScanManager) runAnalyzerManager() error {
-	return asm.scanner.AnalyzerManager.Exec(asm.configFileName, applicabilityScanCommand, filepath.Dir(asm.scanner.AnalyzerManager.AnalyzerManagerFullPath), asm.scanner.ServerDetails, asm.scanner.EnvVars)
+func (asm *ApplicabilityScanManager) runAnalyzerManager(module jfrogappsconfig.Module) error {
+	return asm.scanner.GetAnalyzerManager(module).Exec(asm.configFileName, applicabilityScanCommand, filepath.Dir(asm.scanner.AnalyzerManager.AnalyzerManagerFullPath), asm.scanner.ServerDetails, asm.scanner.EnvVars)
 }
 
 func removeElementFromSlice(skipDirs []string, element string) []string {
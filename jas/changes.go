@@ -0,0 +1,176 @@
+package jas
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+)
+
+type lineRange struct {
+	start, end int
+}
+
+// The lines of the source code of a module that were changed since a Git reference.
+// Used to limit the source code scanners to the changes of a pull request.
+type ChangedLines struct {
+	// The changed lines ranges, by the absolute paths of the changed files
+	files map[string][]lineRange
+}
+
+// Returns the lines of the working tree of the directory that were changed since its merge base with the Git reference, including the untracked files.
+// Returns nil if the reference is empty, meaning that the scan isn't limited to the changes.
+func GetChangedLines(dir, ref string) (changedLines *ChangedLines, err error) {
+	if ref == "" {
+		return
+	}
+	mergeBase, err := RunGitCommand(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return
+	}
+	diff, err := RunGitCommand(dir, append(GetGitDiffCommandArgs("diff"), strings.TrimSpace(mergeBase), "--")...)
+	if err != nil {
+		return
+	}
+	if changedLines, err = parseGitDiff(dir, diff); err != nil {
+		return
+	}
+	// Untracked files aren't part of the diff, all their lines are new
+	untrackedFiles, err := RunGitCommand(dir, "-c", "core.quotePath=false", "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return
+	}
+	for _, file := range strings.Split(strings.TrimSpace(untrackedFiles), "\n") {
+		if file != "" {
+			changedLines.files[filepath.Join(dir, filepath.FromSlash(file))] = []lineRange{{start: 1, end: math.MaxInt}}
+		}
+	}
+	log.Debug(fmt.Sprintf("Found %d files in %s that were changed since '%s'", len(changedLines.files), dir, ref))
+	return
+}
+
+// Parses the output of 'git diff' into the added lines ranges of each file.
+func parseGitDiff(dir, diff string) (*ChangedLines, error) {
+	changedLines := &ChangedLines{files: map[string][]lineRange{}}
+	parser := &GitDiffParser{}
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(diff)+1)
+	for scanner.Scan() {
+		diffLine, err := parser.ParseLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		// Hunks that only remove lines don't add lines to scan
+		if diffLine.Type == HunkHeaderDiffLine && diffLine.Path != "" && diffLine.LinesCount > 0 {
+			path := filepath.Join(dir, diffLine.Path)
+			changedLines.files[path] = append(changedLines.files[path], lineRange{start: diffLine.LineNumber, end: diffLine.LineNumber + diffLine.LinesCount - 1})
+		}
+	}
+	return changedLines, errorutils.CheckError(scanner.Err())
+}
+
+// Returns true if the scan is limited to the changes, and no lines were added.
+func (c *ChangedLines) NothingChanged() bool {
+	return c != nil && len(c.files) == 0
+}
+
+// Returns the directories of the changed files under the roots, so only those directories are scanned.
+// Returns the roots as is if the scan isn't limited to the changes.
+func (c *ChangedLines) NarrowRoots(roots []string) []string {
+	if c == nil {
+		return roots
+	}
+	dirs := map[string]bool{}
+	for file := range c.files {
+		for _, root := range roots {
+			if IsSubPath(root, file) {
+				dirs[filepath.Dir(file)] = true
+				break
+			}
+		}
+	}
+	narrowed := []string{}
+	for dir := range dirs {
+		nested := false
+		for other := range dirs {
+			if other != dir && IsSubPath(other, dir) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			narrowed = append(narrowed, dir)
+		}
+	}
+	sort.Strings(narrowed)
+	return narrowed
+}
+
+// Returns true if the path is the directory or is nested in it
+func IsSubPath(dir, path string) bool {
+	relative, err := filepath.Rel(dir, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// Removes the results that aren't located in the changed lines. Does nothing if the scan isn't limited to the changes.
+// If includeCodeFlows is true, the results with a code flow that passes through the changed lines are kept as well.
+func (c *ChangedLines) FilterRuns(includeCodeFlows bool, runs ...*sarif.Run) {
+	if c == nil {
+		return
+	}
+	for _, run := range runs {
+		results := []*sarif.Result{}
+		for _, result := range run.Results {
+			if c.isChangedResult(result, run.Invocations, includeCodeFlows) {
+				results = append(results, result)
+			}
+		}
+		if removed := len(run.Results) - len(results); removed > 0 {
+			log.Debug(fmt.Sprintf("Excluding %d results of %s that aren't in the changed lines", removed, sarifutils.GetRunToolName(run)))
+		}
+		run.Results = results
+	}
+}
+
+func (c *ChangedLines) isChangedResult(result *sarif.Result, invocations []*sarif.Invocation, includeCodeFlows bool) bool {
+	for _, location := range result.Locations {
+		if c.isChangedLocation(location, invocations) {
+			return true
+		}
+	}
+	if !includeCodeFlows {
+		return false
+	}
+	for _, codeFlow := range result.CodeFlows {
+		for _, threadFlow := range codeFlow.ThreadFlows {
+			for _, threadFlowLocation := range threadFlow.Locations {
+				if c.isChangedLocation(threadFlowLocation.Location, invocations) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (c *ChangedLines) isChangedLocation(location *sarif.Location, invocations []*sarif.Invocation) bool {
+	ranges, exists := c.files[sarifutils.GetFullLocationFileName(sarifutils.GetRelativeLocationFileName(location, invocations), invocations)]
+	if !exists {
+		return false
+	}
+	startLine := sarifutils.GetLocationStartLine(location)
+	endLine := max(sarifutils.GetLocationEndLine(location), startLine)
+	for _, changedRange := range ranges {
+		if startLine <= changedRange.end && endLine >= changedRange.start {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package jas
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitDiff(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/src/main.go b/src/main.go",
+		"index 1111111..2222222 100644",
+		"--- a/src/main.go",
+		"+++ b/src/main.go",
+		"@@ -3,0 +4,2 @@ import (",
+		"+\t\"os\"",
+		"+\t\"fmt\"",
+		"@@ -10 +12 @@ func main() {",
+		"-\treturn",
+		"+\tos.Exit(0)",
+		"@@ -20,3 +21,0 @@ func other() {",
+		"diff --git a/old.go b/old.go",
+		"deleted file mode 100644",
+		"--- a/old.go",
+		"+++ /dev/null",
+		"@@ -1,2 +0,0 @@",
+	}, "\n")
+	changedLines, err := parseGitDiff("root", diff)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]lineRange{
+		filepath.Join("root", "src", "main.go"): {{start: 4, end: 5}, {start: 12, end: 12}},
+	}, changedLines.files)
+}
+
+func TestGetChangedLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = root
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0644))
+	}
+	git("init", "-q", "-b", "main")
+	writeFile(filepath.Join("api", "server.py"), "import os\n\nprint(1)\n")
+	writeFile(filepath.Join("web", "app.js"), "console.log(1)\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Initial commit")
+	git("checkout", "-q", "-b", "feature")
+	writeFile(filepath.Join("api", "server.py"), "import os\n\nprint(2)\nprint(3)\n")
+	git("commit", "-q", "-a", "-m", "Change the server")
+	// Uncommitted and untracked changes are included
+	writeFile(filepath.Join("api", "handlers", "auth.py"), "token = 1\n")
+
+	changedLines, err := GetChangedLines(root, "main")
+	require.NoError(t, err)
+	assert.False(t, changedLines.NothingChanged())
+	assert.Equal(t, []lineRange{{start: 3, end: 4}}, changedLines.files[filepath.Join(root, "api", "server.py")])
+	assert.Contains(t, changedLines.files, filepath.Join(root, "api", "handlers", "auth.py"))
+	assert.NotContains(t, changedLines.files, filepath.Join(root, "web", "app.js"))
+	assert.Equal(t, []string{filepath.Join(root, "api")}, changedLines.NarrowRoots([]string{root}))
+	assert.Empty(t, changedLines.NarrowRoots([]string{filepath.Join(root, "web")}))
+
+	changedLines, err = GetChangedLines(root, "HEAD")
+	require.NoError(t, err)
+	assert.Len(t, changedLines.files, 1)
+
+	_, err = GetChangedLines(root, "missing-branch")
+	assert.Error(t, err)
+
+	// The scan isn't limited without a reference
+	changedLines, err = GetChangedLines(root, "")
+	require.NoError(t, err)
+	assert.Nil(t, changedLines)
+	assert.False(t, changedLines.NothingChanged())
+	assert.Equal(t, []string{root}, changedLines.NarrowRoots([]string{root}))
+}
+
+func TestFilterRuns(t *testing.T) {
+	root := filepath.Join("/", "root")
+	changedLines := &ChangedLines{files: map[string][]lineRange{filepath.Join(root, "main.go"): {{start: 10, end: 12}}}}
+	newResult := func(ruleId, file string, line int, codeFlowLines ...int) *sarif.Result {
+		result := sarif.NewRuleResult(ruleId).WithLocations([]*sarif.Location{
+			sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+filepath.Join(root, file), line, line, 1, 2)),
+		})
+		if len(codeFlowLines) > 0 {
+			var locations []*sarif.ThreadFlowLocation
+			for _, codeFlowLine := range codeFlowLines {
+				locations = append(locations, sarif.NewThreadFlowLocation().WithLocation(
+					sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+filepath.Join(root, "main.go"), codeFlowLine, codeFlowLine, 1, 2))))
+			}
+			result.WithCodeFlows([]*sarif.CodeFlow{sarif.NewCodeFlow().WithThreadFlows([]*sarif.ThreadFlow{sarif.NewThreadFlow().WithLocations(locations)})})
+		}
+		return result
+	}
+	newRun := func() *sarif.Run {
+		run := sarif.NewRunWithInformationURI("scanner", "")
+		run.Invocations = []*sarif.Invocation{sarif.NewInvocation().WithWorkingDirectory(sarif.NewSimpleArtifactLocation(root))}
+		run.Results = []*sarif.Result{
+			newResult("changed", "main.go", 11),
+			newResult("unchanged-line", "main.go", 20),
+			newResult("unchanged-file", "other.go", 11),
+			newResult("changed-code-flow", "other.go", 5, 30, 12),
+		}
+		return run
+	}
+	getRulesIds := func(run *sarif.Run) (ids []string) {
+		for _, result := range run.Results {
+			ids = append(ids, sarifutils.GetResultRuleId(result))
+		}
+		return
+	}
+
+	run := newRun()
+	changedLines.FilterRuns(false, run)
+	assert.Equal(t, []string{"changed"}, getRulesIds(run))
+
+	run = newRun()
+	changedLines.FilterRuns(true, run)
+	assert.Equal(t, []string{"changed", "changed-code-flow"}, getRulesIds(run))
+
+	run = newRun()
+	(*ChangedLines)(nil).FilterRuns(false, run)
+	assert.Len(t, run.Results, 4)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
@@ -31,6 +32,9 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// The JFrog apps config file, relative to the current directory
+var JFrogAppsConfigPath = filepath.Join(".jfrog", "jfrog-apps-config.yml")
+
 type JasScanner struct {
 	TempDir               string
 	AnalyzerManager       AnalyzerManager
@@ -39,6 +43,16 @@ type JasScanner struct {
 	ScannerDirCleanupFunc func() error
 	EnvVars               map[string]string
 	Exclusions            []string
+	// A Git reference. If set, the source code scanners only scan the files that were changed since it, and report the results in the changed lines.
+	ChangedSince string
+	// If set with ChangedSince, SAST scans the whole modules and also reports the results whose code flow passes through the changed lines
+	SastCodeFlows bool
+	// The timeouts of the scans of the modules that override the analyzer manager timeout, by the modules keys
+	ModulesTimeouts map[string]time.Duration
+	// The values files to render the Helm charts of the modules with before the IaC scan, by the modules keys
+	ModulesHelmValues map[string][]string
+	// The custom SAST and Secrets rules to scan the modules with, loaded before the scans
+	CustomRules *CustomRules
 }
 
 func CreateJasScanner(scanner *JasScanner, jfrogAppsConfig *jfrogappsconfig.JFrogAppsConfig, serverDetails *config.ServerDetails, envVars map[string]string, exclusions ...string) (*JasScanner, error) {
@@ -60,7 +74,155 @@ func CreateJasScanner(scanner *JasScanner, jfrogAppsConfig *jfrogappsconfig.JFro
 	scanner.ServerDetails = serverDetails
 	scanner.JFrogAppsConfig = jfrogAppsConfig
 	scanner.Exclusions = exclusions
-	return scanner, err
+	modulesSettings, err := getModulesJasSettings()
+	if err != nil {
+		return scanner, err
+	}
+	if scanner.ModulesTimeouts, err = getModulesTimeouts(modulesSettings); err != nil {
+		return scanner, err
+	}
+	scanner.ModulesHelmValues = getModulesHelmValues(modulesSettings)
+	return scanner, nil
+}
+
+// Returns the analyzer manager to scan the module with, limited by the timeout of the module if it's set.
+func (a *JasScanner) GetAnalyzerManager(module jfrogappsconfig.Module) *AnalyzerManager {
+	analyzerManager := a.AnalyzerManager
+	if timeout, exists := a.ModulesTimeouts[GetModuleKey(module.SourceRoot)]; exists {
+		analyzerManager.Timeout = timeout
+	}
+	return &analyzerManager
+}
+
+// The custom rules of the modules, by the modules keys.
+// The rules are loaded and validated once before the scans, so invalid rules fail the command before any scan runs.
+type CustomRules struct {
+	modulesRules map[string][]customrules.Rule
+}
+
+// Loads the custom rules of the modules. The rules of a module are loaded from the directories that are set in the CLI,
+// the directories of the module in jfrog-apps-config.yml, and the custom rules directory of the repository.
+// The configured directories must exist, while the custom rules directory of the repository is optional.
+func LoadCustomRules(jfrogAppsConfig *jfrogappsconfig.JFrogAppsConfig, customRulesDirs []string) (*CustomRules, error) {
+	modulesSettings, err := getModulesJasSettings()
+	if err != nil {
+		return nil, err
+	}
+	modulesDirs := getModulesCustomRules(modulesSettings)
+	defaultDirExists, err := fileutils.IsDirExists(customrules.DefaultCustomRulesDir, false)
+	if err != nil {
+		return nil, err
+	}
+	customRules := &CustomRules{modulesRules: map[string][]customrules.Rule{}}
+	for _, module := range jfrogAppsConfig.Modules {
+		moduleKey := GetModuleKey(module.SourceRoot)
+		dirs := append([]string{}, customRulesDirs...)
+		for _, dir := range modulesDirs[moduleKey] {
+			dirs = append(dirs, filepath.Join(module.SourceRoot, filepath.FromSlash(dir)))
+		}
+		if defaultDirExists {
+			dirs = append(dirs, customrules.DefaultCustomRulesDir)
+		}
+		rules, err := customrules.LoadRules(dirs...)
+		if err != nil {
+			return nil, err
+		}
+		customRules.modulesRules[moduleKey] = rules
+	}
+	return customRules, nil
+}
+
+// Returns the custom rules of the type to scan the module with.
+func (cr *CustomRules) GetModuleRules(module jfrogappsconfig.Module, ruleType customrules.RuleType) []customrules.Rule {
+	if cr == nil {
+		return nil
+	}
+	return customrules.GetRulesOfType(cr.modulesRules[GetModuleKey(module.SourceRoot)], ruleType)
+}
+
+// The settings of a module in jfrog-apps-config.yml that aren't part of the JFrog apps config schema.
+type moduleJasSettings struct {
+	SourceRoot string `yaml:"source_root,omitempty"`
+	// The timeout of each scan of the module, for example: 30m
+	JasTimeout string `yaml:"jas_timeout,omitempty"`
+	// Values files to render the Helm charts of the module with, relative to the source root.
+	// A values file in the directory of a chart is used for that chart only, the rest are used for all the charts of the module.
+	IacHelmValues []string `yaml:"iac_helm_values,omitempty"`
+	// Directories of custom SAST and Secrets rules to scan the module with, relative to the source root
+	CustomRules []string `yaml:"custom_rules,omitempty"`
+}
+
+// Returns the settings of the modules in jfrog-apps-config.yml that aren't part of the JFrog apps config schema.
+func getModulesJasSettings() (modules []moduleJasSettings, err error) {
+	content, err := os.ReadFile(JFrogAppsConfigPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	var config struct {
+		Modules []moduleJasSettings `yaml:"modules,omitempty"`
+	}
+	if err = yaml.Unmarshal(content, &config); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return config.Modules, nil
+}
+
+// Returns the timeouts of the scans of the modules, by the modules keys.
+func getModulesTimeouts(modules []moduleJasSettings) (timeouts map[string]time.Duration, err error) {
+	for _, module := range modules {
+		if module.JasTimeout == "" {
+			continue
+		}
+		timeout, parseErr := time.ParseDuration(module.JasTimeout)
+		if parseErr != nil || timeout <= 0 {
+			return nil, errorutils.CheckErrorf("invalid jas_timeout '%s' of the module '%s' in %s, expected a positive duration such as '30m'", module.JasTimeout, module.SourceRoot, JFrogAppsConfigPath)
+		}
+		if timeouts == nil {
+			timeouts = map[string]time.Duration{}
+		}
+		timeouts[GetModuleKey(module.SourceRoot)] = timeout
+	}
+	return
+}
+
+// Returns the key of the module in the settings of the scanner, which is the absolute path of its source root.
+// The source roots in jfrog-apps-config.yml are relative to the current directory, and the modules of a config profile have absolute roots.
+func GetModuleKey(sourceRoot string) string {
+	if absRoot, err := filepath.Abs(filepath.FromSlash(sourceRoot)); err == nil {
+		return absRoot
+	}
+	return sourceRoot
+}
+
+// Returns the values files to render the Helm charts of the modules with, by the modules keys.
+func getModulesHelmValues(modules []moduleJasSettings) (helmValues map[string][]string) {
+	for _, module := range modules {
+		if len(module.IacHelmValues) == 0 {
+			continue
+		}
+		if helmValues == nil {
+			helmValues = map[string][]string{}
+		}
+		helmValues[GetModuleKey(module.SourceRoot)] = module.IacHelmValues
+	}
+	return
+}
+
+// Returns the directories of the custom rules of the modules, by the modules keys.
+func getModulesCustomRules(modules []moduleJasSettings) (customRules map[string][]string) {
+	for _, module := range modules {
+		if len(module.CustomRules) == 0 {
+			continue
+		}
+		if customRules == nil {
+			customRules = map[string][]string{}
+		}
+		customRules[GetModuleKey(module.SourceRoot)] = module.CustomRules
+	}
+	return
 }
 
 func getJasEnvVars(serverDetails *config.ServerDetails, vars map[string]string) (map[string]string, error) {
@@ -73,7 +235,7 @@ func getJasEnvVars(serverDetails *config.ServerDetails, vars map[string]string)
 
 func CreateJFrogAppsConfig(workingDirs []string) (*jfrogappsconfig.JFrogAppsConfig, error) {
 	if jfrogAppsConfig, err := jfrogappsconfig.LoadConfigIfExist(); err != nil {
-		return nil, errorutils.CheckError(err)
+		return nil, errorutils.CheckErrorf("failed to load %s: %s\nRun 'jf apps-config validate' to find the errors in the file", JFrogAppsConfigPath, err.Error())
 	} else if jfrogAppsConfig != nil {
 		// jfrog-apps-config.yml exist in the workspace
 		for _, module := range jfrogAppsConfig.Modules {
@@ -87,6 +249,7 @@ func CreateJFrogAppsConfig(workingDirs []string) (*jfrogappsconfig.JFrogAppsConf
 	}
 
 	// jfrog-apps-config.yml does not exist in the workspace
+	log.Debug(fmt.Sprintf("%s wasn't found, each working directory is scanned as a module. Run 'jf apps-config init' to create it.", JFrogAppsConfigPath))
 	fullPathsWorkingDirs, err := coreutils.GetFullPathsWorkingDirs(workingDirs)
 	if err != nil {
 		return nil, err
@@ -115,6 +278,12 @@ func ReadJasScanRunsFromFile(fileName, wd, informationUrlSuffix string) (sarifRu
 	if sarifRuns, err = sarifutils.ReadScanRunsFromFile(fileName); err != nil {
 		return
 	}
+	ProcessJasScanRuns(wd, informationUrlSuffix, sarifRuns...)
+	return
+}
+
+// Prepares the runs of a JAS scanner for the results: sets the working directory, fills the missing driver information, excludes the suppressed results and adds scores to the rules.
+func ProcessJasScanRuns(wd, informationUrlSuffix string, sarifRuns ...*sarif.Run) {
 	for _, sarifRun := range sarifRuns {
 		// Jas reports has only one invocation
 		// Set the actual working directory to the invocation, not the analyzerManager directory
@@ -128,7 +297,6 @@ func ReadJasScanRunsFromFile(fileName, wd, informationUrlSuffix string) (sarifRu
 		sarifRun.Results = excludeSuppressResults(sarifRun.Results)
 		addScoreToRunRules(sarifRun)
 	}
-	return
 }
 
 func fillMissingRequiredDriverInformation(defaultJasInformationUri, defaultVersion string, run *sarif.Run) {
@@ -0,0 +1,124 @@
+package jas
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/xsc/services"
+)
+
+// Maps the modules of the config profile onto JFrog apps config modules, so the profile determines the source roots, exclude patterns and enabled scanners of every module.
+// The exclusions, such as the exclusions of the audit, are added to the exclude patterns of every module.
+func CreateJFrogAppsConfigFromProfile(configProfile *services.ConfigProfile, exclusions ...string) (*jfrogappsconfig.JFrogAppsConfig, error) {
+	if len(configProfile.Modules) == 0 {
+		return nil, errorutils.CheckErrorf("the config profile '%s' doesn't contain any module", configProfile.ProfileName)
+	}
+	jfrogAppsConfig := new(jfrogappsconfig.JFrogAppsConfig)
+	for _, profileModule := range configProfile.Modules {
+		module, err := convertConfigProfileModule(profileModule)
+		if err != nil {
+			return nil, err
+		}
+		module.ExcludePatterns = append(module.ExcludePatterns, convertToFilesExcludePatterns(exclusions)...)
+		jfrogAppsConfig.Modules = append(jfrogAppsConfig.Modules, module)
+	}
+	return jfrogAppsConfig, nil
+}
+
+func convertConfigProfileModule(profileModule services.Module) (module jfrogappsconfig.Module, err error) {
+	roots, err := GetConfigProfileModuleRoots(profileModule)
+	if err != nil {
+		return
+	}
+	scanConfig := profileModule.ScanConfig
+	module = jfrogappsconfig.Module{
+		Name:            profileModule.ModuleName,
+		SourceRoot:      roots[0],
+		ExcludePatterns: GetConfigProfileModuleExcludePatterns(profileModule),
+	}
+	// The additional paths of the module are scanned in addition to its root
+	var workingDirs []string
+	if len(roots) > 1 {
+		for _, root := range roots {
+			var workingDir string
+			if workingDir, err = filepath.Rel(roots[0], root); err != nil {
+				return module, errorutils.CheckError(err)
+			}
+			workingDirs = append(workingDirs, workingDir)
+		}
+	}
+	module.Scanners.Secrets = &jfrogappsconfig.Scanner{WorkingDirs: workingDirs, ExcludePatterns: scanConfig.SecretsScannerConfig.ExcludePatterns}
+	module.Scanners.Iac = &jfrogappsconfig.Scanner{WorkingDirs: workingDirs, ExcludePatterns: scanConfig.IacScannerConfig.ExcludePatterns}
+	module.Scanners.Sast = &jfrogappsconfig.SastScanner{
+		Scanner:       jfrogappsconfig.Scanner{WorkingDirs: workingDirs, ExcludePatterns: scanConfig.SastScannerConfig.ExcludePatterns},
+		Language:      scanConfig.SastScannerConfig.Language,
+		ExcludedRules: scanConfig.SastScannerConfig.ExcludeRules,
+	}
+	disabledScanners := map[jasutils.JasScanType]bool{
+		jasutils.Applicability: !scanConfig.EnableScaScan || !scanConfig.EnableContextualAnalysisScan,
+		jasutils.Secrets:       !scanConfig.SecretsScannerConfig.EnableSecretsScan,
+		jasutils.IaC:           !scanConfig.IacScannerConfig.EnableIacScan,
+		jasutils.Sast:          !scanConfig.SastScannerConfig.EnableSastScan,
+	}
+	for _, scanType := range []jasutils.JasScanType{jasutils.Applicability, jasutils.Secrets, jasutils.IaC, jasutils.Sast} {
+		if disabledScanners[scanType] {
+			module.ExcludeScanners = append(module.ExcludeScanners, strings.ToLower(scanType.String()))
+		}
+	}
+	return
+}
+
+// Returns the absolute paths of the module root and its additional paths, relative to the current directory.
+func GetConfigProfileModuleRoots(profileModule services.Module) (roots []string, err error) {
+	for _, modulePath := range append([]string{profileModule.PathFromRoot}, profileModule.AdditionalPathsForModule...) {
+		var root string
+		if root, err = filepath.Abs(filepath.FromSlash(modulePath)); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		roots = append(roots, root)
+	}
+	return
+}
+
+// Returns the exclude patterns of all the scanners of the module, as glob patterns.
+func GetConfigProfileModuleExcludePatterns(profileModule services.Module) []string {
+	excludePatterns := append([]string{}, profileModule.ExcludePaths...)
+	if excludePattern := profileModule.ScanConfig.ExcludePattern; excludePattern != "" {
+		if !strings.HasPrefix(excludePattern, "**/") {
+			excludePattern = "**/" + excludePattern
+		}
+		excludePatterns = append(excludePatterns, excludePattern)
+	}
+	return excludePatterns
+}
+
+// Returns the scan timeouts of the config profile modules, by the modules keys.
+func GetConfigProfileModulesTimeouts(configProfile *services.ConfigProfile) (timeouts map[string]time.Duration, err error) {
+	for _, profileModule := range configProfile.Modules {
+		if profileModule.ScanConfig.ScanTimeout <= 0 {
+			continue
+		}
+		var roots []string
+		if roots, err = GetConfigProfileModuleRoots(profileModule); err != nil {
+			return
+		}
+		if timeouts == nil {
+			timeouts = map[string]time.Duration{}
+		}
+		timeouts[GetModuleKey(roots[0])] = time.Duration(profileModule.ScanConfig.ScanTimeout) * time.Second
+	}
+	return
+}
+
+// Returns a description of the scanners that the config profile enables in the module, for debug logs.
+func DescribeConfigProfileModule(profileModule services.Module) string {
+	scanConfig := profileModule.ScanConfig
+	return fmt.Sprintf("module '%s' (%s): SCA=%t, Contextual Analysis=%t, Secrets=%t, IaC=%t, SAST=%t", profileModule.ModuleName, profileModule.PathFromRoot,
+		scanConfig.EnableScaScan, scanConfig.EnableScaScan && scanConfig.EnableContextualAnalysisScan, scanConfig.SecretsScannerConfig.EnableSecretsScan,
+		scanConfig.IacScannerConfig.EnableIacScan, scanConfig.SastScannerConfig.EnableSastScan)
+}
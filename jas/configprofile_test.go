@@ -0,0 +1,84 @@
+package jas
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-client-go/xsc/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateJFrogAppsConfigFromProfile(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	configProfile := &services.ConfigProfile{
+		ProfileName: "test-profile",
+		Modules: []services.Module{
+			{
+				ModuleName:               "backend",
+				PathFromRoot:             "backend",
+				AdditionalPathsForModule: []string{"libs/common"},
+				ExcludePaths:             []string{"**/.git/**"},
+				ScanConfig: services.ScanConfig{
+					ScanTimeout:                  600,
+					ExcludePattern:               "*.md",
+					EnableScaScan:                true,
+					EnableContextualAnalysisScan: true,
+					SastScannerConfig:            services.SastScannerConfig{EnableSastScan: true, Language: "java", ExcludeRules: []string{"rule-1"}, ExcludePatterns: []string{"**/generated/**"}},
+					IacScannerConfig:             services.IacScannerConfig{EnableIacScan: true},
+				},
+			},
+			{
+				ModuleName:   "frontend",
+				PathFromRoot: "frontend",
+				ScanConfig: services.ScanConfig{
+					EnableContextualAnalysisScan: true,
+					SecretsScannerConfig:         services.SecretsScannerConfig{EnableSecretsScan: true, ExcludePatterns: []string{"**/fixtures/**"}},
+				},
+			},
+		},
+	}
+
+	jfrogAppsConfig, err := CreateJFrogAppsConfigFromProfile(configProfile)
+	require.NoError(t, err)
+	require.Len(t, jfrogAppsConfig.Modules, 2)
+
+	backend := jfrogAppsConfig.Modules[0]
+	assert.Equal(t, "backend", backend.Name)
+	assert.Equal(t, filepath.Join(wd, "backend"), backend.SourceRoot)
+	assert.Equal(t, []string{"**/.git/**", "**/*.md"}, backend.ExcludePatterns)
+	assert.Equal(t, []string{"secrets"}, backend.ExcludeScanners)
+	assert.Equal(t, []string{".", filepath.Join("..", "libs", "common")}, backend.Scanners.Sast.WorkingDirs)
+	assert.Equal(t, []string{"**/generated/**"}, backend.Scanners.Sast.ExcludePatterns)
+	assert.Equal(t, "java", backend.Scanners.Sast.Language)
+	assert.Equal(t, []string{"rule-1"}, backend.Scanners.Sast.ExcludedRules)
+	roots, err := GetSourceRoots(backend, backend.Scanners.Iac)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(wd, "backend"), filepath.Join(wd, "libs", "common")}, roots)
+
+	frontend := jfrogAppsConfig.Modules[1]
+	// Contextual Analysis requires the SCA scan
+	assert.Equal(t, []string{"applicability", "iac", "sast"}, frontend.ExcludeScanners)
+	assert.Empty(t, frontend.Scanners.Secrets.WorkingDirs)
+	assert.Equal(t, []string{"**/fixtures/**"}, frontend.Scanners.Secrets.ExcludePatterns)
+
+	// The exclusions of the audit are added to the exclude patterns of the modules
+	jfrogAppsConfig, err = CreateJFrogAppsConfigFromProfile(configProfile, "*dist*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"**/.git/**", "**/*.md", "**/*dist*/**"}, jfrogAppsConfig.Modules[0].ExcludePatterns)
+	assert.Equal(t, []string{"**/*dist*/**"}, jfrogAppsConfig.Modules[1].ExcludePatterns)
+
+	timeouts, err := GetConfigProfileModulesTimeouts(configProfile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{filepath.Join(wd, "backend"): 10 * time.Minute}, timeouts)
+	scanner := &JasScanner{ModulesTimeouts: timeouts}
+	assert.Equal(t, 10*time.Minute, scanner.GetAnalyzerManager(backend).Timeout)
+	assert.Equal(t, time.Duration(0), scanner.GetAnalyzerManager(jfrogappsconfig.Module{SourceRoot: frontend.SourceRoot}).Timeout)
+
+	_, err = CreateJFrogAppsConfigFromProfile(&services.ConfigProfile{ProfileName: "empty-profile"})
+	assert.ErrorContains(t, err, "doesn't contain any module")
+}
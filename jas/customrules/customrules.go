@@ -0,0 +1,293 @@
+package customrules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/jas/linescan"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// The directory of the custom rules of the repository, relative to the current directory.
+// Its rules are used in addition to the rules in the directories that are set in the CLI and in jfrog-apps-config.yml.
+var DefaultCustomRulesDir = filepath.Join(".jfrog", "custom-rules")
+
+type RuleType string
+
+const (
+	SecretsRule RuleType = "secrets"
+	SastRule    RuleType = "sast"
+)
+
+// The file extensions of the languages that SAST rules can be limited to
+var languagesExtensions = map[string][]string{
+	"java":       {".java"},
+	"javascript": {".js", ".jsx", ".mjs", ".cjs"},
+	"typescript": {".ts", ".tsx"},
+	"python":     {".py"},
+	"go":         {".go"},
+	"csharp":     {".cs"},
+	"kotlin":     {".kt", ".kts"},
+	"php":        {".php"},
+	"ruby":       {".rb"},
+	"c":          {".c", ".h"},
+	"cpp":        {".cpp", ".cc", ".cxx", ".hpp", ".hh"},
+}
+
+// A custom rule, that is defined in a YAML file of a custom rules directory:
+//
+//	rules:
+//	  - id: internal-service-token
+//	    type: secrets
+//	    name: Internal service token
+//	    description: Hardcoded token of an internal service
+//	    severity: high
+//	    cwe: [CWE-798]
+//	    help: Load the token from the vault instead.
+//	    regex: '\b(ist_[0-9a-f]{32})\b'
+//
+// The finding of a secrets rule is the first submatch of its regular expression, or the whole match if the expression has no submatches.
+// A SAST rule reports the lines that its regular expression matches, in the files of its languages.
+type Rule struct {
+	Id          string   `yaml:"id"`
+	Type        RuleType `yaml:"type"`
+	Name        string   `yaml:"name,omitempty"`
+	Description string   `yaml:"description"`
+	// critical, high, medium or low. Default: medium
+	Severity string   `yaml:"severity,omitempty"`
+	Cwe      []string `yaml:"cwe,omitempty"`
+	// Markdown that describes how to fix the findings of the rule
+	Help  string `yaml:"help,omitempty"`
+	Regex string `yaml:"regex"`
+	// Secrets rules only: the minimal Shannon entropy (bits per character) of the secret. Zero to skip the check.
+	MinEntropy float64 `yaml:"min_entropy,omitempty"`
+	// SAST rules only: the languages of the files to scan. Empty to scan all the files.
+	Languages []string `yaml:"languages,omitempty"`
+	// SAST rules only: the message of the findings. Default: the description
+	Message string `yaml:"message,omitempty"`
+
+	regex    *regexp.Regexp
+	severity severityutils.Severity
+}
+
+type rulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Loads the rules in the YAML files of the directories.
+// Returns an error if a directory doesn't exist, if a rule is invalid, or if the same rule ID is defined more than once.
+func LoadRules(dirs ...string) (rules []Rule, err error) {
+	loadedDirs := map[string]bool{}
+	ids := map[string]string{}
+	for _, dir := range dirs {
+		var absDir string
+		if absDir, err = filepath.Abs(dir); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		if loadedDirs[absDir] {
+			continue
+		}
+		loadedDirs[absDir] = true
+		var files []string
+		if files, err = getRulesFiles(absDir); err != nil {
+			return
+		}
+		for _, file := range files {
+			var fileRules []Rule
+			if fileRules, err = loadRulesFile(file); err != nil {
+				return
+			}
+			for _, rule := range fileRules {
+				if otherFile, exists := ids[rule.Id]; exists {
+					return nil, errorutils.CheckErrorf("the custom rule '%s' is defined in both %s and %s", rule.Id, otherFile, file)
+				}
+				ids[rule.Id] = file
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return
+}
+
+func getRulesFiles(dir string) (files []string, err error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errorutils.CheckErrorf("the custom rules directory %s doesn't exist", dir)
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	if !info.IsDir() {
+		return nil, errorutils.CheckErrorf("the custom rules directory %s isn't a directory", dir)
+	}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if extension := strings.ToLower(filepath.Ext(path)); !entry.IsDir() && (extension == ".yaml" || extension == ".yml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, errorutils.CheckError(err)
+}
+
+func loadRulesFile(file string) (rules []Rule, err error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var parsed rulesFile
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&parsed); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the custom rules file %s: %s", file, err.Error())
+	}
+	for i := range parsed.Rules {
+		if err = parsed.Rules[i].init(); err != nil {
+			return nil, errorutils.CheckErrorf("invalid custom rule #%d in %s: %s", i+1, file, err.Error())
+		}
+	}
+	return parsed.Rules, nil
+}
+
+func (r *Rule) init() (err error) {
+	if r.Id == "" {
+		return fmt.Errorf("'id' is required")
+	}
+	if r.Type != SecretsRule && r.Type != SastRule {
+		return fmt.Errorf("rule '%s': unsupported type '%s', the supported types are: %s, %s", r.Id, r.Type, SecretsRule, SastRule)
+	}
+	if r.Description == "" {
+		return fmt.Errorf("rule '%s': 'description' is required", r.Id)
+	}
+	if r.Regex == "" {
+		return fmt.Errorf("rule '%s': 'regex' is required", r.Id)
+	}
+	if r.regex, err = regexp.Compile(r.Regex); err != nil {
+		return fmt.Errorf("rule '%s': invalid regex: %s", r.Id, err.Error())
+	}
+	r.severity = severityutils.Medium
+	if r.Severity != "" {
+		if r.severity, err = severityutils.ParseToSeverity(r.Severity); err != nil || r.severity == severityutils.Unknown {
+			return fmt.Errorf("rule '%s': unsupported severity '%s', the supported severities are: critical, high, medium, low", r.Id, r.Severity)
+		}
+	}
+	for _, language := range r.Languages {
+		if _, exists := languagesExtensions[strings.ToLower(language)]; !exists {
+			return fmt.Errorf("rule '%s': unsupported language '%s'", r.Id, language)
+		}
+	}
+	if r.Type == SecretsRule && len(r.Languages) > 0 {
+		return fmt.Errorf("rule '%s': 'languages' is supported in SAST rules only", r.Id)
+	}
+	return nil
+}
+
+// Returns the rules of the type.
+func GetRulesOfType(rules []Rule, ruleType RuleType) (filtered []Rule) {
+	for _, rule := range rules {
+		if rule.Type == ruleType {
+			filtered = append(filtered, rule)
+		}
+	}
+	return
+}
+
+func (r *Rule) GetRegexp() *regexp.Regexp {
+	return r.regex
+}
+
+func (r *Rule) GetName() string {
+	if r.Name == "" {
+		return r.Id
+	}
+	return r.Name
+}
+
+// Returns the SARIF level of the findings of the rule
+func (r *Rule) GetLevel() string {
+	return severityutils.SeverityToSarifSeverityLevel(r.severity).String()
+}
+
+func (r *Rule) getMessage() string {
+	if r.Message == "" {
+		return r.Description
+	}
+	return r.Message
+}
+
+// Returns true if the SAST rule should scan the file, by the file extension.
+func (r *Rule) isScannedFile(path string) bool {
+	if len(r.Languages) == 0 {
+		return true
+	}
+	extension := strings.ToLower(filepath.Ext(path))
+	for _, language := range r.Languages {
+		if slices.Contains(languagesExtensions[strings.ToLower(language)], extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds the rule to the run, with its metadata.
+// The severity and the CWEs are kept in the rule properties, and are set in the SARIF rule when the results are written.
+func AddRule(run *sarif.Run, rule Rule) *sarif.ReportingDescriptor {
+	sarifRule := run.AddRule(rule.Id).WithName(rule.GetName()).WithShortDescription(sarif.NewMultiformatMessageString(rule.Description)).
+		WithFullDescription(sarif.NewMultiformatMessageString(rule.Description))
+	if rule.Help != "" {
+		sarifRule.WithHelp(sarif.NewMultiformatMessageString(rule.Help).WithMarkdown(rule.Help))
+	}
+	properties := sarif.Properties{jasutils.CustomRuleSeverityProperty: rule.severity.String()}
+	if len(rule.Cwe) > 0 {
+		properties[jasutils.CustomRuleCweProperty] = rule.Cwe
+	}
+	sarifRule.WithProperties(properties)
+	return sarifRule
+}
+
+// Runs the SAST rules on the files under the roots, and returns their findings as a SARIF run of the tool.
+// The exclude patterns are ant patterns, matched against the paths relative to the roots.
+func RunSastRules(toolName, informationUri string, roots, excludePatterns []string, rules []Rule) (run *sarif.Run, err error) {
+	run = sarif.NewRunWithInformationURI(toolName, informationUri)
+	for _, rule := range rules {
+		AddRule(run, rule)
+	}
+	isScannedFile := func(path string) bool {
+		for _, rule := range rules {
+			if rule.isScannedFile(path) {
+				return true
+			}
+		}
+		return false
+	}
+	err = linescan.ScanRoots(roots, linescan.GetExcludeRegexes(excludePatterns), isScannedFile, func(path string, lineNumber int, line string) {
+		for _, rule := range rules {
+			if !rule.isScannedFile(path) {
+				continue
+			}
+			for _, match := range rule.regex.FindAllStringIndex(line, -1) {
+				physicalLocation := sarifutils.NewPhysicalLocationWithRegion("file://"+path, lineNumber, lineNumber, match[0]+1, match[1]+1)
+				physicalLocation.Region.Snippet = sarif.NewArtifactContent().WithText(line[match[0]:match[1]])
+				run.CreateResultForRule(rule.Id).WithLevel(rule.GetLevel()).WithMessage(sarif.NewTextMessage(rule.getMessage())).
+					AddLocation(sarif.NewLocationWithPhysicalLocation(physicalLocation))
+			}
+		}
+	})
+	return
+}
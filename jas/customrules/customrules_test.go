@@ -0,0 +1,145 @@
+package customrules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRules = `rules:
+  - id: internal-token
+    type: secrets
+    name: Internal service token
+    description: Hardcoded token of an internal service
+    severity: critical
+    cwe: [CWE-798]
+    help: Load the token from the **vault**.
+    regex: '\b(ist_[0-9a-f]{32})\b'
+  - id: banned-md5
+    type: sast
+    description: Usage of the banned MD5 hash
+    languages: [java, go]
+    message: MD5 is banned, use SHA-256
+    regex: 'md5\.New\(\)|MessageDigest\.getInstance\("MD5"\)'
+`
+
+func writeRulesFile(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	writeRulesFile(t, dir, "rules.yaml", testRules)
+	writeRulesFile(t, dir, "README.md", "not a rules file")
+
+	// A directory is loaded once
+	rules, err := LoadRules(dir, dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "internal-token", rules[0].Id)
+	assert.Equal(t, "Internal service token", rules[0].GetName())
+	assert.Equal(t, severityutils.Critical, rules[0].severity)
+	assert.Equal(t, "error", rules[0].GetLevel())
+	assert.Equal(t, "banned-md5", rules[1].GetName())
+	assert.Equal(t, severityutils.Medium, rules[1].severity)
+	assert.Equal(t, "warning", rules[1].GetLevel())
+	assert.Equal(t, []Rule{rules[0]}, GetRulesOfType(rules, SecretsRule))
+	assert.Equal(t, []Rule{rules[1]}, GetRulesOfType(rules, SastRule))
+
+	otherDir := t.TempDir()
+	writeRulesFile(t, otherDir, "more.yml", "rules:\n  - id: internal-token\n    type: secrets\n    description: Duplicate\n    regex: ist_\n")
+	_, err = LoadRules(dir, otherDir)
+	assert.ErrorContains(t, err, "the custom rule 'internal-token' is defined in both")
+
+	// A directory that doesn't exist fails the load
+	_, err = LoadRules(dir, filepath.Join(dir, "missing"))
+	assert.ErrorContains(t, err, "doesn't exist")
+
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{name: "unknown field", content: "rules:\n  - id: a\n    typ: sast\n", expectedError: "field typ not found"},
+		{name: "missing id", content: "rules:\n  - type: sast\n    description: d\n    regex: a\n", expectedError: "'id' is required"},
+		{name: "unsupported type", content: "rules:\n  - id: a\n    type: iac\n    description: d\n    regex: a\n", expectedError: "unsupported type 'iac'"},
+		{name: "invalid regex", content: "rules:\n  - id: a\n    type: sast\n    description: d\n    regex: '('\n", expectedError: "rule 'a': invalid regex"},
+		{name: "unsupported severity", content: "rules:\n  - id: a\n    type: sast\n    description: d\n    regex: a\n    severity: urgent\n", expectedError: "unsupported severity 'urgent'"},
+		{name: "unsupported language", content: "rules:\n  - id: a\n    type: sast\n    description: d\n    regex: a\n    languages: [cobol]\n", expectedError: "unsupported language 'cobol'"},
+		{name: "secrets languages", content: "rules:\n  - id: a\n    type: secrets\n    description: d\n    regex: a\n    languages: [go]\n", expectedError: "'languages' is supported in SAST rules only"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			invalidDir := t.TempDir()
+			writeRulesFile(t, invalidDir, "rules.yaml", testCase.content)
+			_, err := LoadRules(invalidDir)
+			assert.ErrorContains(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func TestRunSastRules(t *testing.T) {
+	rulesDir := t.TempDir()
+	writeRulesFile(t, rulesDir, "rules.yaml", testRules)
+	rules, err := LoadRules(rulesDir)
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	writeRulesFile(t, filepath.Join(root, "src"), "hash.go", "package src\n\nfunc hash() {\n\th := md5.New()\n\t// jfrog-ignore: legacy checksum\n\tlegacy := md5.New()\n}\n")
+	writeRulesFile(t, filepath.Join(root, "src"), "Hash.java", "class Hash {\n  MessageDigest d = MessageDigest.getInstance(\"MD5\");\n}\n")
+	// Not a file of the rule languages, and an excluded directory
+	writeRulesFile(t, filepath.Join(root, "docs"), "hash.md", "Don't use md5.New()\n")
+	writeRulesFile(t, filepath.Join(root, "vendor", "lib"), "lib.go", "var h = md5.New()\n")
+
+	run, err := RunSastRules("Custom SAST", "", []string{root}, []string{"**/vendor/**"}, GetRulesOfType(rules, SastRule))
+	require.NoError(t, err)
+	assert.Equal(t, "Custom SAST", sarifutils.GetRunToolName(run))
+	require.Len(t, run.Tool.Driver.Rules, 1)
+	assert.Equal(t, "Usage of the banned MD5 hash", sarifutils.GetRuleShortDescriptionText(run.Tool.Driver.Rules[0]))
+	assert.Equal(t, "Medium", run.Tool.Driver.Rules[0].Properties[jasutils.CustomRuleSeverityProperty])
+
+	type finding struct {
+		file, snippet string
+		line, column  int
+	}
+	var findings []finding
+	for _, result := range run.Results {
+		assert.Equal(t, "banned-md5", sarifutils.GetResultRuleId(result))
+		assert.Equal(t, "MD5 is banned, use SHA-256", sarifutils.GetResultMsgText(result))
+		assert.Equal(t, "warning", sarifutils.GetResultLevel(result))
+		for _, location := range result.Locations {
+			findings = append(findings, finding{
+				file:    sarifutils.GetLocationFileName(location),
+				snippet: sarifutils.GetLocationSnippet(location),
+				line:    sarifutils.GetLocationStartLine(location),
+				column:  sarifutils.GetLocationStartColumn(location),
+			})
+		}
+	}
+	assert.ElementsMatch(t, []finding{
+		{file: "file://" + filepath.Join(root, "src", "hash.go"), snippet: "md5.New()", line: 4, column: 7},
+		{file: "file://" + filepath.Join(root, "src", "Hash.java"), snippet: "MessageDigest.getInstance(\"MD5\")", line: 2, column: 21},
+	}, findings)
+}
+
+func TestAddRule(t *testing.T) {
+	rulesDir := t.TempDir()
+	writeRulesFile(t, rulesDir, "rules.yaml", testRules)
+	rules, err := LoadRules(rulesDir)
+	require.NoError(t, err)
+
+	rule := AddRule(sarif.NewRunWithInformationURI("Custom Secrets", ""), rules[0])
+	assert.Equal(t, "Internal service token", *rule.Name)
+	assert.Equal(t, "Hardcoded token of an internal service", sarifutils.GetRuleFullDescriptionText(rule))
+	assert.Equal(t, "Load the token from the **vault**.", *rule.Help.Markdown)
+	assert.Equal(t, "Critical", rule.Properties[jasutils.CustomRuleSeverityProperty])
+	assert.Equal(t, []string{"CWE-798"}, rule.Properties[jasutils.CustomRuleCweProperty])
+}
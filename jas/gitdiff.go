@@ -0,0 +1,137 @@
+package jas
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	gitDiffFileHeaderStart = "diff --git "
+	gitDiffNewFilePrefix   = "+++ "
+)
+
+// A diff hunk header, with the start and count of the lines of the new file, for example: @@ -10,2 +12,3 @@
+var gitDiffHunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+type GitDiffLineType int
+
+const (
+	OtherDiffLine GitDiffLineType = iota
+	// The 'diff --git' line that starts the patch of a file
+	FileHeaderDiffLine
+	// The '+++' line with the path of the new file
+	NewFileDiffLine
+	// The '@@' line that starts a hunk
+	HunkHeaderDiffLine
+	// A line that was added to the new file
+	AddedDiffLine
+)
+
+type GitDiffLine struct {
+	Type GitDiffLineType
+	// The path of the new file, relative to the directory of the diff. Empty if the file was deleted.
+	Path string
+	// The number of the added line, or of the first line of the hunk in the new file
+	LineNumber int
+	// The number of lines of the hunk in the new file
+	LinesCount int
+	// The content of the added line
+	Content string
+}
+
+// Parses the patches printed by 'git diff' and 'git log -p', line by line, and tracks the files and the line numbers of the added lines.
+type GitDiffParser struct {
+	path         string
+	inFileHeader bool
+	inHunk       bool
+	lineNumber   int
+}
+
+// Resets the parser before the patches of the next commit of a 'git log -p' output.
+func (p *GitDiffParser) Reset() {
+	*p = GitDiffParser{}
+}
+
+func (p *GitDiffParser) ParseLine(line string) (diffLine GitDiffLine, err error) {
+	switch {
+	case strings.HasPrefix(line, gitDiffFileHeaderStart):
+		p.path, p.inFileHeader, p.inHunk = "", true, false
+		return GitDiffLine{Type: FileHeaderDiffLine}, nil
+	case p.inFileHeader && strings.HasPrefix(line, gitDiffNewFilePrefix):
+		// Deleted files are compared to /dev/null
+		if newPath := strings.Trim(strings.TrimPrefix(line, gitDiffNewFilePrefix), "\""); newPath != "/dev/null" {
+			p.path = filepath.FromSlash(strings.TrimPrefix(newPath, "b/"))
+		}
+		return GitDiffLine{Type: NewFileDiffLine, Path: p.path}, nil
+	case gitDiffHunkHeaderRegex.MatchString(line):
+		groups := gitDiffHunkHeaderRegex.FindStringSubmatch(line)
+		diffLine = GitDiffLine{Type: HunkHeaderDiffLine, Path: p.path, LinesCount: 1}
+		if diffLine.LineNumber, err = strconv.Atoi(groups[1]); err != nil {
+			return diffLine, errorutils.CheckError(err)
+		}
+		if groups[2] != "" {
+			if diffLine.LinesCount, err = strconv.Atoi(groups[2]); err != nil {
+				return diffLine, errorutils.CheckError(err)
+			}
+		}
+		p.inFileHeader, p.inHunk, p.lineNumber = false, true, diffLine.LineNumber
+		return
+	case p.inHunk && strings.HasPrefix(line, "+"):
+		diffLine = GitDiffLine{Type: AddedDiffLine, Path: p.path, LineNumber: p.lineNumber, Content: strings.TrimSuffix(strings.TrimPrefix(line, "+"), "\r")}
+		p.lineNumber++
+		return
+	case p.inHunk && strings.HasPrefix(line, " "):
+		// Context lines are part of the new file
+		p.lineNumber++
+	}
+	return GitDiffLine{Type: OtherDiffLine, Path: p.path}, nil
+}
+
+// Returns the arguments of a Git command that prints patches, such as 'diff' or 'log -p', in the format expected by GitDiffParser.
+// The paths are relative to the working directory, and the renamed files are compared to their origin so only the changed lines are added.
+func GetGitDiffCommandArgs(command ...string) []string {
+	return append(append([]string{"-c", "core.quotePath=false"}, command...), "--relative", "--unified=0", "--no-color", "--no-ext-diff", "--find-renames")
+}
+
+func RunGitCommand(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errorutils.CheckErrorf("failed to run 'git %s' in %s: %s %s", strings.Join(args, " "), dir, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// Runs the Git command in the directory and passes its output to handleOutput while it runs, for outputs that are too large to be kept in memory, such as the 'git log -p' output.
+func StreamGitCommand(dir string, handleOutput func(output io.Reader) error, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err = cmd.Start(); err != nil {
+		return errorutils.CheckErrorf("failed to run 'git %s' in %s: %s", strings.Join(args, " "), dir, err.Error())
+	}
+	handleErr := handleOutput(stdout)
+	if handleErr != nil {
+		// Read the rest of the output, so the command can exit
+		_, _ = io.Copy(io.Discard, stdout)
+	}
+	if err = errors.Join(handleErr, cmd.Wait()); err != nil {
+		return errorutils.CheckErrorf("failed to run 'git %s' in %s: %s %s", strings.Join(args, " "), dir, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package jas
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitDiffParser(t *testing.T) {
+	parser := &GitDiffParser{}
+	parse := func(line string) GitDiffLine {
+		diffLine, err := parser.ParseLine(line)
+		require.NoError(t, err)
+		return diffLine
+	}
+	assert.Equal(t, GitDiffLine{Type: FileHeaderDiffLine}, parse("diff --git a/src/main.go b/src/main.go"))
+	assert.Equal(t, OtherDiffLine, parse("--- a/src/main.go").Type)
+	assert.Equal(t, GitDiffLine{Type: NewFileDiffLine, Path: filepath.Join("src", "main.go")}, parse("+++ b/src/main.go"))
+	assert.Equal(t, GitDiffLine{Type: HunkHeaderDiffLine, Path: filepath.Join("src", "main.go"), LineNumber: 4, LinesCount: 3}, parse("@@ -3,2 +4,3 @@ import ("))
+	// Context lines are counted, removed lines aren't
+	assert.Equal(t, OtherDiffLine, parse(" \"os\"").Type)
+	assert.Equal(t, OtherDiffLine, parse("-\t\"fmt\"").Type)
+	assert.Equal(t, GitDiffLine{Type: AddedDiffLine, Path: filepath.Join("src", "main.go"), LineNumber: 5, Content: "++ added"}, parse("+++ added\r"))
+	assert.Equal(t, GitDiffLine{Type: HunkHeaderDiffLine, Path: filepath.Join("src", "main.go"), LineNumber: 12, LinesCount: 1}, parse("@@ -10 +12 @@ func main() {"))
+	assert.Equal(t, 12, parse("+\tos.Exit(0)").LineNumber)
+
+	// Deleted files don't have a path
+	parse("diff --git a/old.go b/old.go")
+	assert.Equal(t, GitDiffLine{Type: NewFileDiffLine}, parse("+++ /dev/null"))
+
+	// Added lines are only counted in hunks
+	parser.Reset()
+	assert.Equal(t, OtherDiffLine, parse("+not a diff line").Type)
+}
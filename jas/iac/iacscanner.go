@@ -22,8 +22,12 @@ const (
 type IacScanManager struct {
 	iacScannerResults []*sarif.Run
 	scanner           *jas.JasScanner
+	changedLines      *jas.ChangedLines
 	configFileName    string
 	resultsFileName   string
+	// The directory that the Helm charts and Kustomize overlays of the module are rendered into
+	renderedDir     string
+	renderedSources []*renderedSource
 }
 
 // The getIacScanResults function runs the iac scan flow, which includes the following steps:
@@ -57,20 +61,29 @@ func newIacScanManager(scanner *jas.JasScanner, scannerTempDir string) (manager
 		iacScannerResults: []*sarif.Run{},
 		scanner:           scanner,
 		configFileName:    filepath.Join(scannerTempDir, "config.yaml"),
-		resultsFileName:   filepath.Join(scannerTempDir, "results.sarif")}
+		resultsFileName:   filepath.Join(scannerTempDir, "results.sarif"),
+		renderedDir:       filepath.Join(scannerTempDir, renderedDirName)}
 }
 
 func (iac *IacScanManager) Run(module jfrogappsconfig.Module) (err error) {
+	if iac.changedLines, err = jas.GetChangedLines(module.SourceRoot, iac.scanner.ChangedSince); err != nil || iac.changedLines.NothingChanged() {
+		return
+	}
+	if err = iac.renderManifests(module); err != nil {
+		return
+	}
 	if err = iac.createConfigFile(module, iac.scanner.Exclusions...); err != nil {
 		return
 	}
-	if err = iac.runAnalyzerManager(); err != nil {
+	if err = iac.runAnalyzerManager(module); err != nil {
 		return
 	}
 	workingDirResults, err := jas.ReadJasScanRunsFromFile(iac.resultsFileName, module.SourceRoot, iacDocsUrlSuffix)
 	if err != nil {
 		return
 	}
+	mapRenderedLocations(iac.renderedSources, workingDirResults...)
+	iac.changedLines.FilterRuns(false, workingDirResults...)
 	iac.iacScannerResults = append(iac.iacScannerResults, workingDirResults...)
 	return
 }
@@ -86,11 +99,26 @@ type iacScanConfiguration struct {
 	SkippedDirs []string `yaml:"skipped-folders"`
 }
 
+// Renders the Helm charts and Kustomize overlays of the module, so the resources they create are scanned in addition to their raw files.
+func (iac *IacScanManager) renderManifests(module jfrogappsconfig.Module) (err error) {
+	roots, err := jas.GetSourceRoots(module, module.Scanners.Iac)
+	if err != nil {
+		return
+	}
+	excludePatterns := jas.GetExcludePatterns(module, module.Scanners.Iac, iac.scanner.Exclusions...)
+	iac.renderedSources, err = renderManifests(module.SourceRoot, roots, excludePatterns, iac.scanner.ModulesHelmValues[jas.GetModuleKey(module.SourceRoot)], iac.renderedDir)
+	return
+}
+
 func (iac *IacScanManager) createConfigFile(module jfrogappsconfig.Module, exclusions ...string) error {
 	roots, err := jas.GetSourceRoots(module, module.Scanners.Iac)
 	if err != nil {
 		return err
 	}
+	roots = iac.changedLines.NarrowRoots(roots)
+	for _, source := range iac.renderedSources {
+		roots = append(roots, source.renderedDir)
+	}
 	configFileContent := iacScanConfig{
 		Scans: []iacScanConfiguration{
 			{
@@ -104,6 +132,6 @@ func (iac *IacScanManager) createConfigFile(module jfrogappsconfig.Module, exclu
 	return jas.CreateScannersConfigFile(iac.configFileName, configFileContent, jasutils.IaC)
 }
 
-func (iac *IacScanManager) runAnalyzerManager() error {
-	return iac.scanner.AnalyzerManager.Exec(iac.configFileName, iacScanCommand, filepath.Dir(iac.scanner.AnalyzerManager.AnalyzerManagerFullPath), iac.scanner.ServerDetails, iac.scanner.EnvVars)
+func (iac *IacScanManager) runAnalyzerManager(module jfrogappsconfig.Module) error {
+	return iac.scanner.GetAnalyzerManager(module).Exec(iac.configFileName, iacScanCommand, filepath.Dir(iac.scanner.AnalyzerManager.AnalyzerManagerFullPath), iac.scanner.ServerDetails, iac.scanner.EnvVars)
 }
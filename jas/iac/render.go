@@ -0,0 +1,617 @@
+package iac
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	helmChartFileName  = "Chart.yaml"
+	helmValuesFileName = "values.yaml"
+	// The release name that the Helm charts are rendered with
+	helmReleaseName = "iac-scan"
+	renderedDirName = "rendered"
+	// The comment that Helm adds before each rendered resource, with the template it was rendered from
+	helmSourceCommentPrefix = "# Source:"
+	yamlDocumentSeparator   = "---"
+	// A property of the regions that contain the result, but can't point to its exact line
+	approximateRegionProperty = "approximate"
+)
+
+var (
+	kustomizationFileNames  = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+	templateExpressionRegex = regexp.MustCompile(`\{\{.*?\}\}`)
+)
+
+type renderedSourceType string
+
+const (
+	helmChart     renderedSourceType = "Helm chart"
+	kustomization renderedSourceType = "Kustomize overlay"
+)
+
+// A Helm chart or a Kustomize overlay, that is rendered into Kubernetes manifests so the IaC scanner can scan the resources it creates.
+type renderedSource struct {
+	sourceType renderedSourceType
+	// The directory of the chart or the overlay
+	dir string
+	// The file that defines the source: Chart.yaml or kustomization.yaml
+	descriptor string
+	// The values files that the chart is rendered with, including its default values file
+	valuesFiles []string
+	// The directory that the manifests are rendered into
+	renderedDir string
+}
+
+// Detects the Helm charts and the Kustomize overlays in the roots, and renders them into sub directories of the rendered directory.
+// The charts are rendered with their default values, and with the values files of the module (relative to the source root).
+// The sources that can't be rendered, for example because helm isn't installed, are skipped with a warning and only their raw files are scanned.
+func renderManifests(sourceRoot string, roots, excludePatterns, helmValues []string, renderedDir string) (rendered []*renderedSource, err error) {
+	sources, err := detectRenderedSources(roots, excludePatterns)
+	if err != nil || len(sources) == 0 {
+		return
+	}
+	if err = setHelmValuesFiles(sourceRoot, helmValues, sources); err != nil {
+		return
+	}
+	missingTools := map[renderedSourceType]bool{}
+	for i, source := range sources {
+		if missingTools[source.sourceType] {
+			continue
+		}
+		source.renderedDir = filepath.Join(renderedDir, fmt.Sprintf("%d", i))
+		if err = os.MkdirAll(source.renderedDir, 0755); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		var cmd *exec.Cmd
+		if cmd = source.getRenderCommand(); cmd == nil {
+			log.Warn(fmt.Sprintf("A %s was found in %s, but the tool to render it isn't installed. Only its raw files are scanned.", source.sourceType, source.dir))
+			missingTools[source.sourceType] = true
+			continue
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if renderErr := cmd.Run(); renderErr != nil {
+			log.Warn(fmt.Sprintf("Failed to render the %s in %s, only its raw files are scanned: %s\n%s", source.sourceType, source.dir, renderErr.Error(), strings.TrimSpace(stderr.String())))
+			continue
+		}
+		log.Debug(fmt.Sprintf("Rendered the %s in %s for the IaC scan", source.sourceType, source.dir))
+		rendered = append(rendered, source)
+	}
+	return
+}
+
+func detectRenderedSources(roots, excludePatterns []string) (sources []*renderedSource, err error) {
+	var excludeRegexes []*regexp.Regexp
+	for _, excludePattern := range excludePatterns {
+		var excludeRegex *regexp.Regexp
+		if excludeRegex, err = regexp.Compile(clientutils.AntToRegex(filepath.FromSlash(excludePattern))); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		excludeRegexes = append(excludeRegexes, excludeRegex)
+	}
+	for _, root := range roots {
+		err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			if relativePath, err := filepath.Rel(root, path); err == nil && relativePath != "." && isExcludedDir(relativePath, excludeRegexes) {
+				return filepath.SkipDir
+			}
+			if exists, err := isFileExists(filepath.Join(path, helmChartFileName)); err != nil || exists {
+				if exists {
+					// The sub charts of a chart are rendered with it
+					sources = append(sources, &renderedSource{sourceType: helmChart, dir: path, descriptor: filepath.Join(path, helmChartFileName)})
+					return filepath.SkipDir
+				}
+				return err
+			}
+			for _, kustomizationFileName := range kustomizationFileNames {
+				if exists, err := isFileExists(filepath.Join(path, kustomizationFileName)); err != nil || exists {
+					if exists {
+						sources = append(sources, &renderedSource{sourceType: kustomization, dir: path, descriptor: filepath.Join(path, kustomizationFileName)})
+					}
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	return skipReferencedKustomizations(sources), nil
+}
+
+// Removes the kustomizations that other detected kustomizations refer to, such as the bases of overlays.
+// Their resources are rendered with the overlays that refer to them, so rendering them separately would report their results twice.
+func skipReferencedKustomizations(sources []*renderedSource) (filtered []*renderedSource) {
+	referencedDirs := map[string]bool{}
+	for _, source := range sources {
+		if source.sourceType != kustomization {
+			continue
+		}
+		_, dirs := getKustomizationReferences(source.dir)
+		for _, dir := range dirs {
+			referencedDirs[filepath.Clean(dir)] = true
+		}
+	}
+	for _, source := range sources {
+		if source.sourceType == kustomization && referencedDirs[filepath.Clean(source.dir)] {
+			log.Debug(fmt.Sprintf("The %s in %s is rendered with the overlays that refer to it", source.sourceType, source.dir))
+			continue
+		}
+		filtered = append(filtered, source)
+	}
+	return
+}
+
+func isExcludedDir(relativePath string, excludeRegexes []*regexp.Regexp) bool {
+	for _, excludeRegex := range excludeRegexes {
+		if excludeRegex.MatchString(relativePath + string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isFileExists(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// Sets the values files of the charts. A values file in the directory of a chart is used for that chart only, the rest are used for all the charts.
+func setHelmValuesFiles(sourceRoot string, helmValues []string, sources []*renderedSource) error {
+	var commonValuesFiles []string
+	chartsValuesFiles := map[*renderedSource][]string{}
+	for _, valuesFile := range helmValues {
+		valuesFilePath, err := filepath.Abs(filepath.Join(sourceRoot, filepath.FromSlash(valuesFile)))
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		if exists, err := isFileExists(valuesFilePath); err != nil || !exists {
+			return errorutils.CheckErrorf("the Helm values file '%s' of the module '%s' doesn't exist", valuesFile, sourceRoot)
+		}
+		chart := getValuesFileChart(valuesFilePath, sources)
+		if chart == nil {
+			commonValuesFiles = append(commonValuesFiles, valuesFilePath)
+			continue
+		}
+		chartsValuesFiles[chart] = append(chartsValuesFiles[chart], valuesFilePath)
+	}
+	for _, source := range sources {
+		if source.sourceType != helmChart {
+			continue
+		}
+		if exists, _ := isFileExists(filepath.Join(source.dir, helmValuesFileName)); exists {
+			source.valuesFiles = append(source.valuesFiles, filepath.Join(source.dir, helmValuesFileName))
+		}
+		source.valuesFiles = append(source.valuesFiles, commonValuesFiles...)
+		source.valuesFiles = append(source.valuesFiles, chartsValuesFiles[source]...)
+	}
+	return nil
+}
+
+func getValuesFileChart(valuesFilePath string, sources []*renderedSource) *renderedSource {
+	for _, source := range sources {
+		if source.sourceType == helmChart && jas.IsSubPath(source.dir, valuesFilePath) {
+			return source
+		}
+	}
+	return nil
+}
+
+// Returns the command that renders the source into its rendered directory, or nil if the tool to render it isn't installed.
+func (rs *renderedSource) getRenderCommand() *exec.Cmd {
+	switch rs.sourceType {
+	case helmChart:
+		helmPath, err := exec.LookPath("helm")
+		if err != nil {
+			return nil
+		}
+		args := []string{"template", helmReleaseName, rs.dir, "--output-dir", rs.renderedDir}
+		for _, valuesFile := range rs.valuesFiles {
+			// The default values file is used by helm anyway
+			if filepath.Dir(valuesFile) != rs.dir || filepath.Base(valuesFile) != helmValuesFileName {
+				args = append(args, "--values", valuesFile)
+			}
+		}
+		return exec.Command(helmPath, args...)
+	default:
+		output := filepath.Join(rs.renderedDir, "manifests.yaml")
+		if kustomizePath, err := exec.LookPath("kustomize"); err == nil {
+			return exec.Command(kustomizePath, "build", rs.dir, "--output", output)
+		}
+		if kubectlPath, err := exec.LookPath("kubectl"); err == nil {
+			return exec.Command(kubectlPath, "kustomize", rs.dir, "--output", output)
+		}
+		return nil
+	}
+}
+
+// Maps the locations of the results in the rendered manifests back to the sources they were rendered from.
+// A result in a rendered Helm template is located in the template, and a result in a rendered overlay is located in the resource file of the overlay that defines the resource, or in its kustomization file.
+// The resource is matched by its kind and name. If the line of the result isn't found in the resource, the region is the whole resource (or file) and is marked as approximate.
+// The values files that the chart was rendered with are added to the result as related locations.
+// The raw files of the sources are scanned as well, so the mapped results that duplicate the results in the raw files are removed.
+func mapRenderedLocations(sources []*renderedSource, runs ...*sarif.Run) {
+	if len(sources) == 0 {
+		return
+	}
+	for _, run := range runs {
+		mappedResults := map[*sarif.Result]bool{}
+		for _, result := range run.Results {
+			relatedFiles := map[string]bool{}
+			for _, location := range result.Locations {
+				source, renderedFile := getRenderedSource(sources, sarifutils.GetLocationFileName(location))
+				if source == nil {
+					continue
+				}
+				source.mapLocation(location, renderedFile)
+				mappedResults[result] = true
+				for _, valuesFile := range source.valuesFiles {
+					if relatedFiles[valuesFile] {
+						continue
+					}
+					relatedFiles[valuesFile] = true
+					result.AddRelatedLocation(sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocation("file://" + valuesFile)).
+						WithDescriptionText(fmt.Sprintf("A values file that the %s was rendered with", source.sourceType)))
+				}
+			}
+		}
+		removeDuplicateResults(run, mappedResults)
+	}
+}
+
+// Removes the mapped results that are located where a result of the same rule is already located.
+// For example, a resource of a Kustomize base is scanned in its raw file, and in the rendered overlay that refers to the base.
+// A mapped result with an approximate region duplicates the results of its rule in the region.
+func removeDuplicateResults(run *sarif.Run, mappedResults map[*sarif.Result]bool) {
+	if len(mappedResults) == 0 {
+		return
+	}
+	// The locations of the results that are kept, by their rules. The results in the raw files are always kept.
+	keptLocations := map[string][]*sarif.Location{}
+	for _, result := range run.Results {
+		if !mappedResults[result] {
+			keptLocations[sarifutils.GetResultRuleId(result)] = append(keptLocations[sarifutils.GetResultRuleId(result)], result.Locations...)
+		}
+	}
+	var results []*sarif.Result
+	for _, result := range run.Results {
+		ruleId := sarifutils.GetResultRuleId(result)
+		if mappedResults[result] {
+			if isDuplicateLocation(result.Locations, keptLocations[ruleId]) {
+				continue
+			}
+			keptLocations[ruleId] = append(keptLocations[ruleId], result.Locations...)
+		}
+		results = append(results, result)
+	}
+	run.Results = results
+}
+
+// Returns true if one of the locations starts at the same line as one of the other locations, or contains it if its region is approximate.
+func isDuplicateLocation(locations, otherLocations []*sarif.Location) bool {
+	for _, location := range locations {
+		startLine, endLine := sarifutils.GetLocationStartLine(location), sarifutils.GetLocationEndLine(location)
+		approximate := location.PhysicalLocation != nil && location.PhysicalLocation.Region != nil && location.PhysicalLocation.Region.Properties[approximateRegionProperty] == true
+		for _, other := range otherLocations {
+			if getFilePath(sarifutils.GetLocationFileName(location)) != getFilePath(sarifutils.GetLocationFileName(other)) {
+				continue
+			}
+			otherStartLine := sarifutils.GetLocationStartLine(other)
+			if otherStartLine == startLine || (approximate && otherStartLine >= startLine && otherStartLine <= endLine) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the path of the file of a location URI.
+func getFilePath(fileUri string) string {
+	filePath := fileUri
+	for _, prefix := range []string{"file:///private", "file://"} {
+		filePath = strings.TrimPrefix(filePath, prefix)
+	}
+	return filePath
+}
+
+// Returns the source that the file was rendered from, and the path of the file in the rendered directory of the source.
+func getRenderedSource(sources []*renderedSource, fileUri string) (*renderedSource, string) {
+	filePath := getFilePath(fileUri)
+	for _, source := range sources {
+		if jas.IsSubPath(source.renderedDir, filePath) {
+			if relativePath, err := filepath.Rel(source.renderedDir, filePath); err == nil {
+				return source, relativePath
+			}
+		}
+	}
+	return nil, ""
+}
+
+// Returns the file in the source that the rendered file was created from.
+// For a Helm chart, the rendered file is a path in the format of the rendered directory and of Helm's '# Source:' comments, for example: my-chart/templates/deployment.yaml or my-chart/charts/sub-chart/templates/service.yaml
+func (rs *renderedSource) getSourceFile(renderedFile string) string {
+	if rs.sourceType == helmChart {
+		if parts := strings.SplitN(filepath.ToSlash(renderedFile), "/", 2); len(parts) == 2 {
+			template := filepath.Join(rs.dir, filepath.FromSlash(parts[1]))
+			if exists, _ := isFileExists(template); exists {
+				return template
+			}
+		}
+	}
+	return rs.descriptor
+}
+
+// Returns the files that can define the resources of the source: the template of a Helm chart, or the resource files of a Kustomize overlay and of its bases.
+func (rs *renderedSource) getResourcesFiles(renderedFile string, resource renderedResource) []string {
+	if rs.sourceType == helmChart {
+		// Helm comments the template of each resource, which is more accurate than the rendered file for the sub charts
+		if resource.source != "" {
+			return []string{rs.getSourceFile(resource.source)}
+		}
+		return []string{rs.getSourceFile(renderedFile)}
+	}
+	return getKustomizationResourcesFiles(rs.dir, map[string]bool{})
+}
+
+func (rs *renderedSource) mapLocation(location *sarif.Location, renderedFile string) {
+	snippet := sarifutils.GetLocationSnippet(location)
+	resource := getRenderedResource(filepath.Join(rs.renderedDir, renderedFile), sarifutils.GetLocationStartLine(location))
+	// Kustomize adds the name prefixes and suffixes of the overlays to the names of the resources
+	sourceFile, document := findResourceDocument(rs.getResourcesFiles(renderedFile, resource), resource, rs.sourceType == kustomization)
+	if sourceFile == "" {
+		// The resource isn't found, the result is located in the whole file it was rendered from
+		sourceFile = rs.getSourceFile(renderedFile)
+		document = getFileDocument(sourceFile)
+	}
+	sarifutils.SetLocationFileName(location, "file://"+sourceFile)
+	var region *sarif.Region
+	if line, column := document.findSnippet(snippet); line > 0 {
+		region = sarif.NewRegion().WithStartLine(line).WithEndLine(line).WithStartColumn(column).WithEndColumn(column + len(getSnippetFirstLine(snippet)))
+	} else {
+		region = sarif.NewRegion().WithStartLine(document.startLine).WithEndLine(max(document.endLine, document.startLine)).
+			WithTextMessage(fmt.Sprintf("Approximate location: the issue was found in the rendered %s, in the resource that starts at this line", rs.sourceType))
+		region.Properties = sarif.Properties{approximateRegionProperty: true}
+	}
+	if snippet != "" {
+		region = region.WithSnippet(sarif.NewArtifactContent().WithText(snippet))
+	}
+	location.PhysicalLocation.Region = region
+}
+
+// A resource in the rendered manifests
+type renderedResource struct {
+	kind string
+	name string
+	// The template that the resource was rendered from, from the '# Source:' comment that Helm adds to each resource
+	source string
+}
+
+// A YAML document in a file, with the numbers of its first and last lines.
+type yamlDocument struct {
+	startLine int
+	endLine   int
+	lines     []string
+}
+
+// Splits the content of a YAML file, or of a template of one, into its documents.
+func splitYamlDocuments(content string) (documents []yamlDocument) {
+	document := yamlDocument{startLine: 1}
+	for i, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		if strings.HasPrefix(line, yamlDocumentSeparator) {
+			if len(document.lines) > 0 {
+				documents = append(documents, document)
+			}
+			document = yamlDocument{startLine: i + 2}
+			continue
+		}
+		document.lines = append(document.lines, strings.TrimSuffix(line, "\r"))
+		document.endLine = i + 1
+	}
+	if len(document.lines) > 0 {
+		documents = append(documents, document)
+	}
+	return
+}
+
+// Returns the whole file as a single document.
+func getFileDocument(file string) yamlDocument {
+	document := yamlDocument{startLine: 1, endLine: 1}
+	if content, err := os.ReadFile(file); err == nil {
+		document.lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+		document.endLine = len(document.lines)
+	}
+	return document
+}
+
+// Returns the kind, the name and the template of the rendered resource that contains the line.
+func getRenderedResource(renderedFile string, line int) (resource renderedResource) {
+	content, err := os.ReadFile(renderedFile)
+	if err != nil {
+		return
+	}
+	for _, document := range splitYamlDocuments(string(content)) {
+		if line < document.startLine || line > document.endLine {
+			continue
+		}
+		for _, documentLine := range document.lines {
+			if source, isSourceComment := strings.CutPrefix(documentLine, helmSourceCommentPrefix); isSourceComment {
+				resource.source = strings.TrimSpace(source)
+				break
+			}
+		}
+		manifest := struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}{}
+		if err = yaml.Unmarshal([]byte(strings.Join(document.lines, "\n")), &manifest); err == nil {
+			resource.kind, resource.name = manifest.Kind, manifest.Metadata.Name
+		}
+		return
+	}
+	return
+}
+
+// Returns the file and the document that define the resource. The resource is matched by its kind, and by its name if several resources of its kind are defined.
+// The names in the templates can contain template expressions, so only their literal parts are compared. If nameAffixes is true, the name of the resource can have a prefix and a suffix.
+func findResourceDocument(files []string, resource renderedResource, nameAffixes bool) (string, yamlDocument) {
+	if resource.kind == "" {
+		return "", yamlDocument{}
+	}
+	var kindFiles []string
+	var kindDocuments []yamlDocument
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, document := range splitYamlDocuments(string(content)) {
+			if !document.hasField("kind", resource.kind, false) {
+				continue
+			}
+			if document.hasField("name", resource.name, nameAffixes) {
+				return file, document
+			}
+			kindFiles = append(kindFiles, file)
+			kindDocuments = append(kindDocuments, document)
+		}
+	}
+	if len(kindDocuments) == 1 {
+		return kindFiles[0], kindDocuments[0]
+	}
+	return "", yamlDocument{}
+}
+
+// Returns true if the document has a line with the field and the value, or with a part of the value if affixes is true.
+// The template expressions in the document match any text, so a value with template expressions matches if it contains all its literal parts.
+func (yd yamlDocument) hasField(field, value string, affixes bool) bool {
+	if value == "" {
+		return false
+	}
+	for _, line := range yd.lines {
+		documentValue, found := strings.CutPrefix(strings.TrimSpace(line), field+":")
+		if !found {
+			continue
+		}
+		documentValue = strings.Trim(strings.TrimSpace(documentValue), "\"'")
+		if documentValue == value || (affixes && documentValue != "" && strings.Contains(value, documentValue)) {
+			return true
+		}
+		if !templateExpressionRegex.MatchString(documentValue) {
+			continue
+		}
+		matches := false
+		for _, literalPart := range templateExpressionRegex.Split(documentValue, -1) {
+			if literalPart == "" {
+				continue
+			}
+			if matches = strings.Contains(value, literalPart); !matches {
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the line and the column of the first line of the snippet in the document, if it appears exactly once in it.
+func (yd yamlDocument) findSnippet(snippet string) (line, column int) {
+	snippetLine := getSnippetFirstLine(snippet)
+	if snippetLine == "" {
+		return
+	}
+	for i, documentLine := range yd.lines {
+		if index := strings.Index(documentLine, snippetLine); index >= 0 {
+			if line > 0 {
+				// The snippet is ambiguous
+				return 0, 0
+			}
+			line, column = yd.startLine+i, index+1
+		}
+	}
+	return
+}
+
+func getSnippetFirstLine(snippet string) string {
+	return strings.TrimSpace(strings.Split(snippet, "\n")[0])
+}
+
+// The files and the kustomization directories that a kustomization refers to, as its resources, bases and components.
+type kustomizationReferences struct {
+	Resources  []string `yaml:"resources"`
+	Bases      []string `yaml:"bases"`
+	Components []string `yaml:"components"`
+}
+
+// Returns the local files and directories that the kustomization in the directory refers to. Remote references are skipped.
+func getKustomizationReferences(dir string) (files, dirs []string) {
+	for _, kustomizationFileName := range kustomizationFileNames {
+		content, err := os.ReadFile(filepath.Join(dir, kustomizationFileName))
+		if err != nil {
+			continue
+		}
+		references := kustomizationReferences{}
+		if err = yaml.Unmarshal(content, &references); err != nil {
+			log.Debug(fmt.Sprintf("Failed to read the references of the kustomization in %s: %s", dir, err.Error()))
+			return
+		}
+		for _, reference := range append(append(references.Resources, references.Bases...), references.Components...) {
+			if strings.Contains(reference, "://") || strings.HasPrefix(reference, "github.com/") {
+				continue
+			}
+			path := filepath.Join(dir, filepath.FromSlash(reference))
+			if info, err := os.Stat(path); err == nil {
+				if info.IsDir() {
+					dirs = append(dirs, path)
+				} else {
+					files = append(files, path)
+				}
+			}
+		}
+		return
+	}
+	return
+}
+
+// Returns the resource files of the kustomization in the directory and of the kustomizations it refers to.
+func getKustomizationResourcesFiles(dir string, visited map[string]bool) (files []string) {
+	if visited[dir] {
+		return
+	}
+	visited[dir] = true
+	files, dirs := getKustomizationReferences(dir)
+	for _, referencedDir := range dirs {
+		files = append(files, getKustomizationResourcesFiles(referencedDir, visited)...)
+	}
+	return
+}
@@ -0,0 +1,201 @@
+package iac
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestDetectRenderedSources(t *testing.T) {
+	root := t.TempDir()
+	createTestFile(t, filepath.Join(root, "charts", "app", helmChartFileName), "name: app\n")
+	createTestFile(t, filepath.Join(root, "charts", "app", "charts", "db", helmChartFileName), "name: db\n")
+	createTestFile(t, filepath.Join(root, "k8s", "base", "kustomization.yaml"), "resources: []\n")
+	createTestFile(t, filepath.Join(root, "k8s", "overlays", "prod", "kustomization.yml"), "resources: [../../base]\n")
+	createTestFile(t, filepath.Join(root, "k8s", "standalone", "kustomization.yaml"), "resources: [https://github.com/org/repo/base]\n")
+	createTestFile(t, filepath.Join(root, "node_modules", "lib", helmChartFileName), "name: lib\n")
+
+	sources, err := detectRenderedSources([]string{root}, []string{"**/*node_modules*/**"})
+	require.NoError(t, err)
+	// The sub chart is rendered with its chart, the base is rendered with the overlay that refers to it, and the excluded directories aren't searched
+	require.Len(t, sources, 3)
+	assert.Equal(t, &renderedSource{sourceType: helmChart, dir: filepath.Join(root, "charts", "app"), descriptor: filepath.Join(root, "charts", "app", helmChartFileName)}, sources[0])
+	assert.Equal(t, &renderedSource{sourceType: kustomization, dir: filepath.Join(root, "k8s", "overlays", "prod"), descriptor: filepath.Join(root, "k8s", "overlays", "prod", "kustomization.yml")}, sources[1])
+	assert.Equal(t, &renderedSource{sourceType: kustomization, dir: filepath.Join(root, "k8s", "standalone"), descriptor: filepath.Join(root, "k8s", "standalone", "kustomization.yaml")}, sources[2])
+}
+
+func TestSetHelmValuesFiles(t *testing.T) {
+	root := t.TempDir()
+	createTestFile(t, filepath.Join(root, "api", helmChartFileName), "name: api\n")
+	createTestFile(t, filepath.Join(root, "api", helmValuesFileName), "replicas: 1\n")
+	createTestFile(t, filepath.Join(root, "api", "values-prod.yaml"), "replicas: 3\n")
+	createTestFile(t, filepath.Join(root, "web", helmChartFileName), "name: web\n")
+	createTestFile(t, filepath.Join(root, "values-common.yaml"), "region: eu\n")
+	sources, err := detectRenderedSources([]string{root}, nil)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+
+	require.NoError(t, setHelmValuesFiles(root, []string{"api/values-prod.yaml", "values-common.yaml"}, sources))
+	assert.Equal(t, []string{filepath.Join(root, "api", helmValuesFileName), filepath.Join(root, "values-common.yaml"), filepath.Join(root, "api", "values-prod.yaml")}, sources[0].valuesFiles)
+	assert.Equal(t, []string{filepath.Join(root, "values-common.yaml")}, sources[1].valuesFiles)
+
+	assert.ErrorContains(t, setHelmValuesFiles(root, []string{"values-missing.yaml"}, sources), "the Helm values file 'values-missing.yaml'")
+}
+
+func TestRenderManifests(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake helm is a shell script")
+	}
+	root := t.TempDir()
+	createTestFile(t, filepath.Join(root, "app", helmChartFileName), "name: app\n")
+	createTestFile(t, filepath.Join(root, "app", "templates", "deployment.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}-svc\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: {{ .Release.Name }}-app\nspec:\n  hostNetwork: true\n  privileged: {{ .Values.privileged }}\n")
+	createTestFile(t, filepath.Join(root, "app", "values-prod.yaml"), "privileged: true\n")
+	createTestFile(t, filepath.Join(root, "overlay", "kustomization.yaml"), "resources: []\n")
+	// The fake helm renders the template with the '# Source:' comments of helm, and saves the arguments it was called with
+	binDir := t.TempDir()
+	renderedContent := "---\n# Source: app/templates/deployment.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: iac-scan-svc\n---\n# Source: app/templates/deployment.yaml\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: iac-scan-app\nspec:\n  hostNetwork: true\n  privileged: true\n"
+	createTestFile(t, filepath.Join(binDir, "helm"), "#!/bin/sh\n/bin/mkdir -p \"$5/app/templates\"\necho \"$@\" > \"$5/args\"\n/bin/cat > \"$5/app/templates/deployment.yaml\" <<EOF\n"+renderedContent+"EOF\n")
+	require.NoError(t, os.Chmod(filepath.Join(binDir, "helm"), 0755))
+	t.Setenv("PATH", binDir)
+
+	renderedDir := filepath.Join(t.TempDir(), renderedDirName)
+	rendered, err := renderManifests(root, []string{root}, nil, []string{"app/values-prod.yaml"}, renderedDir)
+	require.NoError(t, err)
+	// Kustomize isn't installed, so only the chart is rendered
+	require.Len(t, rendered, 1)
+	renderedTemplate := filepath.Join(rendered[0].renderedDir, "app", "templates", "deployment.yaml")
+	content, err := os.ReadFile(filepath.Join(rendered[0].renderedDir, "args"))
+	require.NoError(t, err)
+	assert.Equal(t, "template iac-scan "+filepath.Join(root, "app")+" --output-dir "+rendered[0].renderedDir+" --values "+filepath.Join(root, "app", "values-prod.yaml")+"\n", string(content))
+
+	// The results in the rendered manifests are located in the templates they were rendered from
+	run := sarif.NewRunWithInformationURI("JFrog Terraform scanner", "")
+	run.AddResult(sarif.NewRuleResult("host-network").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+renderedTemplate, 14, 14, 3, 20).WithRegion(
+			sarif.NewRegion().WithStartLine(14).WithEndLine(14).WithStartColumn(3).WithEndColumn(20).WithSnippet(sarif.NewArtifactContent().WithText("hostNetwork: true")))),
+	}))
+	run.AddResult(sarif.NewRuleResult("privileged").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+renderedTemplate, 15, 15, 3, 19)),
+	}))
+	run.AddResult(sarif.NewRuleResult("raw").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+filepath.Join(root, "main.tf"), 2, 2, 1, 10)),
+	}))
+	mapRenderedLocations(rendered, run)
+
+	template := "file://" + filepath.Join(root, "app", "templates", "deployment.yaml")
+	hostNetwork := run.Results[0].Locations[0]
+	assert.Equal(t, template, sarifutils.GetLocationFileName(hostNetwork))
+	assert.Equal(t, 11, sarifutils.GetLocationStartLine(hostNetwork))
+	assert.Equal(t, 3, sarifutils.GetLocationStartColumn(hostNetwork))
+	assert.Equal(t, "hostNetwork: true", sarifutils.GetLocationSnippet(hostNetwork))
+	require.Len(t, run.Results[0].RelatedLocations, 1)
+	assert.Equal(t, "file://"+filepath.Join(root, "app", "values-prod.yaml"), sarifutils.GetLocationFileName(run.Results[0].RelatedLocations[0]))
+
+	privileged := run.Results[1].Locations[0]
+	// The line of the rendered value isn't in the template, so the result is located in the resource it was found in
+	assert.Equal(t, template, sarifutils.GetLocationFileName(privileged))
+	assert.Equal(t, 6, sarifutils.GetLocationStartLine(privileged))
+	assert.Equal(t, 12, sarifutils.GetLocationEndLine(privileged))
+	assert.Equal(t, 0, sarifutils.GetLocationStartColumn(privileged))
+	assert.Equal(t, true, privileged.PhysicalLocation.Region.Properties[approximateRegionProperty])
+	assert.Nil(t, hostNetwork.PhysicalLocation.Region.Properties)
+
+	assert.Equal(t, "file://"+filepath.Join(root, "main.tf"), sarifutils.GetLocationFileName(run.Results[2].Locations[0]))
+	assert.Equal(t, 2, sarifutils.GetLocationStartLine(run.Results[2].Locations[0]))
+	assert.Empty(t, run.Results[2].RelatedLocations)
+}
+
+func TestMapKustomizationLocations(t *testing.T) {
+	root := t.TempDir()
+	createTestFile(t, filepath.Join(root, "base", "kustomization.yaml"), "resources: [deployment.yaml, service.yaml]\n")
+	createTestFile(t, filepath.Join(root, "base", "deployment.yaml"), "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\nspec:\n  hostNetwork: true\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: worker\nspec:\n  hostNetwork: true\n")
+	createTestFile(t, filepath.Join(root, "base", "service.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\n")
+	createTestFile(t, filepath.Join(root, "prod", "kustomization.yaml"), "namePrefix: prod-\nresources: [../base]\n")
+	renderedDir := filepath.Join(t.TempDir(), "0")
+	renderedManifests := filepath.Join(renderedDir, "manifests.yaml")
+	createTestFile(t, renderedManifests, "apiVersion: v1\nkind: Service\nmetadata:\n  name: prod-api\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: prod-api\nspec:\n  hostNetwork: true\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: prod-worker\nspec:\n  hostNetwork: true\n  replicas: 2\n")
+	overlay := &renderedSource{sourceType: kustomization, dir: filepath.Join(root, "prod"), descriptor: filepath.Join(root, "prod", "kustomization.yaml"), renderedDir: renderedDir}
+
+	run := sarif.NewRunWithInformationURI("JFrog Terraform scanner", "")
+	run.AddResult(sarif.NewRuleResult("host-network").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocation("file://" + renderedManifests).WithRegion(
+			sarif.NewRegion().WithStartLine(17).WithEndLine(17).WithStartColumn(3).WithEndColumn(20).WithSnippet(sarif.NewArtifactContent().WithText("hostNetwork: true")))),
+	}))
+	run.AddResult(sarif.NewRuleResult("replicas").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+renderedManifests, 18, 18, 3, 14)),
+	}))
+	run.AddResult(sarif.NewRuleResult("unknown").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+filepath.Join(renderedDir, "other.yaml"), 1, 1, 1, 5)),
+	}))
+	mapRenderedLocations([]*renderedSource{overlay}, run)
+
+	// The resources are matched by their kind and name, in the files of the base
+	hostNetwork := run.Results[0].Locations[0]
+	assert.Equal(t, "file://"+filepath.Join(root, "base", "deployment.yaml"), sarifutils.GetLocationFileName(hostNetwork))
+	assert.Equal(t, 13, sarifutils.GetLocationStartLine(hostNetwork))
+	assert.Equal(t, 3, sarifutils.GetLocationStartColumn(hostNetwork))
+
+	replicas := run.Results[1].Locations[0]
+	assert.Equal(t, "file://"+filepath.Join(root, "base", "deployment.yaml"), sarifutils.GetLocationFileName(replicas))
+	assert.Equal(t, 8, sarifutils.GetLocationStartLine(replicas))
+	assert.Equal(t, 13, sarifutils.GetLocationEndLine(replicas))
+	assert.Equal(t, true, replicas.PhysicalLocation.Region.Properties[approximateRegionProperty])
+
+	// A resource that isn't found is located in the whole kustomization file
+	unknown := run.Results[2].Locations[0]
+	assert.Equal(t, "file://"+overlay.descriptor, sarifutils.GetLocationFileName(unknown))
+	assert.Equal(t, 1, sarifutils.GetLocationStartLine(unknown))
+	assert.Equal(t, 2, sarifutils.GetLocationEndLine(unknown))
+	assert.Equal(t, true, unknown.PhysicalLocation.Region.Properties[approximateRegionProperty])
+}
+
+func TestMapRenderedLocationsRemovesDuplicates(t *testing.T) {
+	root := t.TempDir()
+	baseDeployment := filepath.Join(root, "base", "deployment.yaml")
+	createTestFile(t, filepath.Join(root, "base", "kustomization.yaml"), "resources: [deployment.yaml]\n")
+	createTestFile(t, baseDeployment, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\nspec:\n  hostNetwork: true\n  replicas: 2\n")
+	createTestFile(t, filepath.Join(root, "prod", "kustomization.yaml"), "resources: [../base]\n")
+	renderedDir := filepath.Join(t.TempDir(), "0")
+	renderedManifests := filepath.Join(renderedDir, "manifests.yaml")
+	createTestFile(t, renderedManifests, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\nspec:\n  hostNetwork: true\n  replicas: 2\n")
+	overlay := &renderedSource{sourceType: kustomization, dir: filepath.Join(root, "prod"), descriptor: filepath.Join(root, "prod", "kustomization.yaml"), renderedDir: renderedDir}
+
+	run := sarif.NewRunWithInformationURI("JFrog Terraform scanner", "")
+	// The findings in the raw file of the base
+	run.AddResult(sarif.NewRuleResult("host-network").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+baseDeployment, 6, 6, 3, 20)),
+	}))
+	run.AddResult(sarif.NewRuleResult("replicas").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+baseDeployment, 7, 7, 3, 14)),
+	}))
+	// The same findings in the rendered overlay
+	run.AddResult(sarif.NewRuleResult("host-network").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocation("file://" + renderedManifests).WithRegion(
+			sarif.NewRegion().WithStartLine(6).WithEndLine(6).WithStartColumn(3).WithEndColumn(20).WithSnippet(sarif.NewArtifactContent().WithText("hostNetwork: true")))),
+	}))
+	run.AddResult(sarif.NewRuleResult("replicas").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+renderedManifests, 7, 7, 3, 14)),
+	}))
+	// A finding of another rule in the rendered overlay is kept
+	run.AddResult(sarif.NewRuleResult("privileged").WithLocations([]*sarif.Location{
+		sarif.NewLocationWithPhysicalLocation(sarifutils.NewPhysicalLocationWithRegion("file://"+renderedManifests, 6, 6, 3, 20)),
+	}))
+	mapRenderedLocations([]*renderedSource{overlay}, run)
+
+	require.Len(t, run.Results, 3)
+	assert.Equal(t, "host-network", sarifutils.GetResultRuleId(run.Results[0]))
+	assert.Equal(t, "replicas", sarifutils.GetResultRuleId(run.Results[1]))
+	assert.Equal(t, "privileged", sarifutils.GetResultRuleId(run.Results[2]))
+	assert.Equal(t, "file://"+baseDeployment, sarifutils.GetLocationFileName(run.Results[2].Locations[0]))
+}
@@ -0,0 +1,100 @@
+package linescan
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// A comment that suppresses the findings in its line and in the line that follows it
+	IgnoreComment      = "jfrog-ignore"
+	maxScannedFileSize = 5 * 1024 * 1024
+	// The prefix of a file that is checked for null bytes, to skip binary files
+	binaryCheckPrefixSize = 8000
+)
+
+// Compiles the exclude patterns, which are ant patterns matched against the paths relative to the scanned roots.
+// The invalid patterns are skipped with a warning.
+func GetExcludeRegexes(excludePatterns []string) (regexes []*regexp.Regexp) {
+	for _, pattern := range excludePatterns {
+		if regex, err := regexp.Compile(clientutils.AntToRegex(filepath.FromSlash(pattern))); err == nil {
+			regexes = append(regexes, regex)
+		} else {
+			log.Warn(fmt.Sprintf("Skipping the invalid exclude pattern '%s': %s", pattern, err.Error()))
+		}
+	}
+	return
+}
+
+func IsExcluded(relativePath string, excludeRegexes []*regexp.Regexp) bool {
+	for _, regex := range excludeRegexes {
+		if regex.MatchString(relativePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the findings in the line are suppressed by an ignore comment in it or in the line before it.
+func IsIgnoredLine(line, previousLine string) bool {
+	return strings.Contains(line, IgnoreComment) || strings.Contains(previousLine, IgnoreComment)
+}
+
+// Walks the roots and passes each line of their text files to scanLine, with its number.
+// The excluded paths, the files that isScannedFile rejects (if it's set), the large and binary files, and the ignored lines are skipped.
+func ScanRoots(roots []string, excludeRegexes []*regexp.Regexp, isScannedFile func(path string) bool, scanLine func(path string, lineNumber int, line string)) error {
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			relativePath, err := filepath.Rel(root, path)
+			if err != nil || relativePath == "." {
+				return err
+			}
+			if IsExcluded(relativePath, excludeRegexes) {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() || (isScannedFile != nil && !isScannedFile(path)) {
+				return nil
+			}
+			return ScanFile(path, scanLine)
+		})
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	return nil
+}
+
+// Passes each line of the file to scanLine, unless the file is large or binary. The ignored lines are skipped.
+func ScanFile(path string, scanLine func(path string, lineNumber int, line string)) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxScannedFileSize {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(content[:min(len(content), binaryCheckPrefixSize)], 0) != -1 {
+		return err
+	}
+	previousLine := ""
+	for i, line := range strings.Split(string(content), "\n") {
+		if !IsIgnoredLine(line, previousLine) {
+			scanLine(path, i+1, strings.TrimSuffix(line, "\r"))
+		}
+		previousLine = line
+	}
+	return nil
+}
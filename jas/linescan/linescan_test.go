@@ -0,0 +1,46 @@
+package linescan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanRoots(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":                               "first\r\n// jfrog-ignore: reviewed\nignored\nthird\n",
+		filepath.Join("docs", "README.md"):      "skipped by the filter\n",
+		filepath.Join("vendor", "lib", "a.go"):  "excluded\n",
+		filepath.Join("assets", "image.bin"):    "\x00binary\n",
+		filepath.Join("assets", "generated.go"): strings.Repeat("a", maxScannedFileSize+1),
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0644))
+	}
+
+	var scanned []string
+	isScannedFile := func(path string) bool {
+		return filepath.Ext(path) != ".md"
+	}
+	err := ScanRoots([]string{root}, GetExcludeRegexes([]string{"**/vendor/**", "[invalid"}), isScannedFile, func(path string, lineNumber int, line string) {
+		relativePath, err := filepath.Rel(root, path)
+		require.NoError(t, err)
+		scanned = append(scanned, fmt.Sprintf("%s:%d:%s", relativePath, lineNumber, line))
+	})
+	require.NoError(t, err)
+	// The ignore comment suppresses its line and the line that follows it
+	assert.Equal(t, []string{"main.go:1:first", "main.go:4:third", "main.go:5:"}, scanned)
+}
+
+func TestIsIgnoredLine(t *testing.T) {
+	assert.True(t, IsIgnoredLine("token = 1 // jfrog-ignore", ""))
+	assert.True(t, IsIgnoredLine("token = 1", "# jfrog-ignore: test data"))
+	assert.False(t, IsIgnoredLine("token = 1", "token = 2"))
+}
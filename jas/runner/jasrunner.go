@@ -5,8 +5,10 @@ import (
 	"github.com/jfrog/gofrog/parallel"
 	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/formats"
 	"github.com/jfrog/jfrog-cli-security/jas"
 	"github.com/jfrog/jfrog-cli-security/jas/applicability"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
 	"github.com/jfrog/jfrog-cli-security/jas/iac"
 	"github.com/jfrog/jfrog-cli-security/jas/sast"
 	"github.com/jfrog/jfrog-cli-security/jas/secrets"
@@ -14,13 +16,12 @@ import (
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
-	"github.com/jfrog/jfrog-client-go/xsc/services"
 	"golang.org/x/exp/slices"
 )
 
 func AddJasScannersTasks(securityParallelRunner *utils.SecurityParallelRunner, scanResults *utils.Results, directDependencies *[]string,
 	serverDetails *config.ServerDetails, thirdPartyApplicabilityScan bool, scanner *jas.JasScanner, scanType applicability.ApplicabilityScanType,
-	secretsScanType secrets.SecretsScanType, errHandlerFunc func(error), scansToPreform []utils.SubScanType, configProfile *services.ConfigProfile) (err error) {
+	secretsScanType secrets.SecretsScanType, errHandlerFunc func(error), scansToPreform []utils.SubScanType) (err error) {
 	if serverDetails == nil || len(serverDetails.Url) == 0 {
 		log.Warn("To include 'Advanced Security' scan as part of the audit output, please run the 'jf c add' command before running this command.")
 		return
@@ -32,39 +33,21 @@ func AddJasScannersTasks(securityParallelRunner *utils.SecurityParallelRunner, s
 	}
 	// Set environments variables for analytics in analyzers manager.
 	// Don't execute other scanners when scanning third party dependencies.
-	// Currently, if config profile exists, the only possible scanners to run are: Secrets, Sast
 	if !thirdPartyApplicabilityScan {
 		for _, module := range scanner.JFrogAppsConfig.Modules {
 			if len(scansToPreform) > 0 && !slices.Contains(scansToPreform, utils.SecretsScan) {
 				log.Debug("Skipping secrets scan as requested by input...")
-			} else if configProfile != nil {
-				// This code section is related to CentralizedConfig integration in CI Next.
-				log.Debug(fmt.Sprintf("Using config profile '%s' to determine whether to run secrets scan...", configProfile.ProfileName))
-				if configProfile.Modules[0].ScanConfig.SecretsScannerConfig.EnableSecretsScan {
-					err = addModuleJasScanTask(jfrogappsconfig.Module{}, jasutils.Secrets, securityParallelRunner, runSecretsScan(securityParallelRunner, scanner, scanResults.ExtendedScanResults, module, secretsScanType), errHandlerFunc)
-				} else {
-					log.Debug(fmt.Sprintf("Skipping secrets scan as requested by '%s' config profile...", configProfile.ProfileName))
-				}
 			} else if err = addModuleJasScanTask(module, jasutils.Secrets, securityParallelRunner, runSecretsScan(securityParallelRunner, scanner, scanResults.ExtendedScanResults, module, secretsScanType), errHandlerFunc); err != nil {
 				return
 			}
 			if runAllScanners {
-				if configProfile == nil {
-					if len(scansToPreform) > 0 && !slices.Contains(scansToPreform, utils.IacScan) {
-						log.Debug("Skipping Iac scan as requested by input...")
-					} else if err = addModuleJasScanTask(module, jasutils.IaC, securityParallelRunner, runIacScan(securityParallelRunner, scanner, scanResults.ExtendedScanResults, module), errHandlerFunc); err != nil {
-						return
-					}
+				if len(scansToPreform) > 0 && !slices.Contains(scansToPreform, utils.IacScan) {
+					log.Debug("Skipping Iac scan as requested by input...")
+				} else if err = addModuleJasScanTask(module, jasutils.IaC, securityParallelRunner, runIacScan(securityParallelRunner, scanner, scanResults.ExtendedScanResults, module), errHandlerFunc); err != nil {
+					return
 				}
 				if len(scansToPreform) > 0 && !slices.Contains(scansToPreform, utils.SastScan) {
 					log.Debug("Skipping Sast scan as requested by input...")
-				} else if configProfile != nil {
-					log.Debug(fmt.Sprintf("Using config profile '%s' to determine whether to run Sast scan...", configProfile.ProfileName))
-					if configProfile.Modules[0].ScanConfig.SastScannerConfig.EnableSastScan {
-						err = addModuleJasScanTask(jfrogappsconfig.Module{}, jasutils.Sast, securityParallelRunner, runSastScan(securityParallelRunner, scanner, scanResults.ExtendedScanResults, module), errHandlerFunc)
-					} else {
-						log.Debug(fmt.Sprintf("Skipping Sast scan as requested by '%s' config profile...", configProfile.ProfileName))
-					}
 				} else if err = addModuleJasScanTask(module, jasutils.Sast, securityParallelRunner, runSastScan(securityParallelRunner, scanner, scanResults.ExtendedScanResults, module), errHandlerFunc); err != nil {
 					return
 				}
@@ -72,11 +55,6 @@ func AddJasScannersTasks(securityParallelRunner *utils.SecurityParallelRunner, s
 		}
 	}
 
-	if configProfile != nil {
-		log.Debug("Config profile is in use. Skipping Contextual Analysis scan as it is not currently supported with a config profile...")
-		return
-	}
-
 	if len(scansToPreform) > 0 && !slices.Contains(scansToPreform, utils.ContextualAnalysisScan) {
 		log.Debug("Skipping contextual analysis scan as requested by input...")
 		return err
@@ -89,6 +67,29 @@ func AddJasScannersTasks(securityParallelRunner *utils.SecurityParallelRunner, s
 	return err
 }
 
+// Adds a task that runs the built-in secrets detector for each module, with the custom secrets rules of the module. Used when the analyzer manager can't run the Secrets scanner.
+func AddNativeSecretsScanTasks(securityParallelRunner *utils.SecurityParallelRunner, extendedScanResults *utils.ExtendedScanResults, jfrogAppsConfig *jfrogappsconfig.JFrogAppsConfig,
+	changedSince string, customRules *jas.CustomRules, errHandlerFunc func(error), exclusions ...string) (err error) {
+	for _, module := range jfrogAppsConfig.Modules {
+		moduleCustomRules := customRules.GetModuleRules(module, customrules.SecretsRule)
+		if err = addModuleJasScanTask(module, jasutils.Secrets, securityParallelRunner, runNativeSecretsScan(securityParallelRunner, extendedScanResults, module, changedSince, exclusions, moduleCustomRules), errHandlerFunc); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Adds a task that scans the lines that were added in the revision range of the Git history of each module for secrets.
+func AddGitHistorySecretsScanTasks(securityParallelRunner *utils.SecurityParallelRunner, extendedScanResults *utils.ExtendedScanResults, jfrogAppsConfig *jfrogappsconfig.JFrogAppsConfig,
+	revRange string, errHandlerFunc func(error), exclusions ...string) (err error) {
+	for _, module := range jfrogAppsConfig.Modules {
+		if err = addModuleJasScanTask(module, jasutils.Secrets, securityParallelRunner, runGitHistorySecretsScan(securityParallelRunner, extendedScanResults, module, revRange, exclusions), errHandlerFunc); err != nil {
+			return
+		}
+	}
+	return
+}
+
 func addModuleJasScanTask(module jfrogappsconfig.Module, scanType jasutils.JasScanType, securityParallelRunner *utils.SecurityParallelRunner, task parallel.TaskFunc, errHandlerFunc func(error)) (err error) {
 	if jas.ShouldSkipScanner(module, scanType) {
 		return
@@ -107,6 +108,40 @@ func runSecretsScan(securityParallelRunner *utils.SecurityParallelRunner, scanne
 			securityParallelRunner.JasScannersWg.Done()
 		}()
 		results, err := secrets.RunSecretsScan(scanner, secretsScanType, module, threadId)
+		if err != nil {
+			return handleJasScanError(securityParallelRunner, extendedScanResults, module, err, threadId)
+		}
+		securityParallelRunner.ResultsMu.Lock()
+		extendedScanResults.SecretsScanResults = append(extendedScanResults.SecretsScanResults, results...)
+		securityParallelRunner.ResultsMu.Unlock()
+		return
+	}
+}
+
+func runNativeSecretsScan(securityParallelRunner *utils.SecurityParallelRunner, extendedScanResults *utils.ExtendedScanResults,
+	module jfrogappsconfig.Module, changedSince string, exclusions []string, customRules []customrules.Rule) parallel.TaskFunc {
+	return func(threadId int) (err error) {
+		defer func() {
+			securityParallelRunner.JasScannersWg.Done()
+		}()
+		results, err := secrets.RunNativeSecretsScan(module, changedSince, exclusions, customRules, threadId)
+		if err != nil {
+			return fmt.Errorf("%s%s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
+		}
+		securityParallelRunner.ResultsMu.Lock()
+		extendedScanResults.SecretsScanResults = append(extendedScanResults.SecretsScanResults, results...)
+		securityParallelRunner.ResultsMu.Unlock()
+		return
+	}
+}
+
+func runGitHistorySecretsScan(securityParallelRunner *utils.SecurityParallelRunner, extendedScanResults *utils.ExtendedScanResults,
+	module jfrogappsconfig.Module, revRange string, exclusions []string) parallel.TaskFunc {
+	return func(threadId int) (err error) {
+		defer func() {
+			securityParallelRunner.JasScannersWg.Done()
+		}()
+		results, err := secrets.RunGitHistorySecretsScan(module, revRange, exclusions, threadId)
 		if err != nil {
 			return fmt.Errorf("%s%s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
 		}
@@ -125,7 +160,7 @@ func runIacScan(securityParallelRunner *utils.SecurityParallelRunner, scanner *j
 		}()
 		results, err := iac.RunIacScan(scanner, module, threadId)
 		if err != nil {
-			return fmt.Errorf("%s %s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
+			return handleJasScanError(securityParallelRunner, extendedScanResults, module, err, threadId)
 		}
 		securityParallelRunner.ResultsMu.Lock()
 		extendedScanResults.IacScanResults = append(extendedScanResults.IacScanResults, results...)
@@ -142,7 +177,7 @@ func runSastScan(securityParallelRunner *utils.SecurityParallelRunner, scanner *
 		}()
 		results, err := sast.RunSastScan(scanner, module, threadId)
 		if err != nil {
-			return fmt.Errorf("%s %s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
+			return handleJasScanError(securityParallelRunner, extendedScanResults, module, err, threadId)
 		}
 		securityParallelRunner.ResultsMu.Lock()
 		extendedScanResults.SastScanResults = append(extendedScanResults.SastScanResults, results...)
@@ -161,7 +196,7 @@ func runContextualScan(securityParallelRunner *utils.SecurityParallelRunner, sca
 		securityParallelRunner.ScaScansWg.Wait()
 		results, err := applicability.RunApplicabilityScan(scanResults.GetScaScansXrayResults(), *directDependencies, scanner, thirdPartyApplicabilityScan, scanType, module, threadId)
 		if err != nil {
-			return fmt.Errorf("%s %s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
+			return handleJasScanError(securityParallelRunner, scanResults.ExtendedScanResults, module, err, threadId)
 		}
 		securityParallelRunner.ResultsMu.Lock()
 		scanResults.ExtendedScanResults.ApplicabilityScanResults = append(scanResults.ExtendedScanResults.ApplicabilityScanResults, results...)
@@ -169,3 +204,15 @@ func runContextualScan(securityParallelRunner *utils.SecurityParallelRunner, sca
 		return
 	}
 }
+
+// A scan that exceeded its timeout doesn't fail the command. The results of the other scans are kept, and the timeout is reported with them.
+func handleJasScanError(securityParallelRunner *utils.SecurityParallelRunner, extendedScanResults *utils.ExtendedScanResults, module jfrogappsconfig.Module, err error, threadId int) error {
+	if !jas.IsScannerTimeoutError(err) {
+		return fmt.Errorf("%s %s", clientutils.GetLogMsgPrefix(threadId, false), err.Error())
+	}
+	log.Warn(clientutils.GetLogMsgPrefix(threadId, false) + err.Error())
+	securityParallelRunner.ResultsMu.Lock()
+	extendedScanResults.ScanErrors = append(extendedScanResults.ScanErrors, formats.SimpleJsonError{FilePath: module.SourceRoot, ErrorMessage: err.Error()})
+	securityParallelRunner.ResultsMu.Unlock()
+	return nil
+}
@@ -7,22 +7,29 @@ import (
 	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
 	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
 	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
+	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/owenrumney/go-sarif/v2/sarif"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 const (
 	sastScannerType   = "sast"
 	sastScanCommand   = "zd"
 	sastDocsUrlSuffix = "sast"
+
+	CustomSastRulesToolName    = "JFrog Custom SAST Rules"
+	customSastRulesToolVersion = "1.0.0"
 )
 
 type SastScanManager struct {
 	sastScannerResults []*sarif.Run
 	scanner            *jas.JasScanner
+	changedLines       *jas.ChangedLines
 	configFileName     string
 	resultsFileName    string
 }
@@ -54,17 +61,28 @@ func newSastScanManager(scanner *jas.JasScanner, scannerTempDir string) (manager
 }
 
 func (ssm *SastScanManager) Run(module jfrogappsconfig.Module) (err error) {
+	if ssm.changedLines, err = jas.GetChangedLines(module.SourceRoot, ssm.scanner.ChangedSince); err != nil || ssm.changedLines.NothingChanged() {
+		return
+	}
 	if err = ssm.createConfigFile(module, ssm.scanner.Exclusions...); err != nil {
 		return
 	}
-	if err = ssm.runAnalyzerManager(filepath.Dir(ssm.scanner.AnalyzerManager.AnalyzerManagerFullPath)); err != nil {
+	if err = ssm.runAnalyzerManager(module, filepath.Dir(ssm.scanner.AnalyzerManager.AnalyzerManagerFullPath)); err != nil {
 		return
 	}
 	workingDirRuns, err := jas.ReadJasScanRunsFromFile(ssm.resultsFileName, module.SourceRoot, sastDocsUrlSuffix)
 	if err != nil {
 		return
 	}
+	// The custom rules were validated before the scans, so failing to run them doesn't fail the scan and drop the results of the SAST scanner
+	if customRulesRuns, customRulesErr := ssm.runCustomRules(module); customRulesErr != nil {
+		log.Warn("Failed to run the custom SAST rules: " + customRulesErr.Error())
+	} else {
+		workingDirRuns = append(workingDirRuns, customRulesRuns...)
+	}
 	groupResultsByLocation(workingDirRuns)
+	// A vulnerability whose code flow passes through the changes is affected by them as well, if the whole module was scanned
+	ssm.changedLines.FilterRuns(ssm.scanner.SastCodeFlows, workingDirRuns...)
 	ssm.sastScannerResults = append(ssm.sastScannerResults, workingDirRuns...)
 	return
 }
@@ -90,6 +108,9 @@ func (ssm *SastScanManager) createConfigFile(module jfrogappsconfig.Module, excl
 	if err != nil {
 		return err
 	}
+	if !ssm.scanner.SastCodeFlows {
+		roots = ssm.changedLines.NarrowRoots(roots)
+	}
 	configFileContent := sastScanConfig{
 		Scans: []scanConfiguration{
 			{
@@ -104,8 +125,41 @@ func (ssm *SastScanManager) createConfigFile(module jfrogappsconfig.Module, excl
 	return jas.CreateScannersConfigFile(ssm.configFileName, configFileContent, jasutils.Sast)
 }
 
-func (ssm *SastScanManager) runAnalyzerManager(wd string) error {
-	return ssm.scanner.AnalyzerManager.ExecWithOutputFile(ssm.configFileName, sastScanCommand, wd, ssm.resultsFileName, ssm.scanner.ServerDetails, ssm.scanner.EnvVars)
+// Runs the custom SAST rules of the module, that match patterns in the source code.
+func (ssm *SastScanManager) runCustomRules(module jfrogappsconfig.Module) (runs []*sarif.Run, err error) {
+	rules := ssm.scanner.CustomRules.GetModuleRules(module, customrules.SastRule)
+	if len(rules) == 0 {
+		return
+	}
+	sastScanner := module.Scanners.Sast
+	if sastScanner == nil {
+		sastScanner = &jfrogappsconfig.SastScanner{}
+	}
+	roots, err := jas.GetSourceRoots(module, &sastScanner.Scanner)
+	if err != nil {
+		return
+	}
+	// The excluded rules of the module apply to the custom rules as well
+	var includedRules []customrules.Rule
+	for _, rule := range rules {
+		if !slices.Contains(sastScanner.ExcludedRules, rule.Id) {
+			includedRules = append(includedRules, rule)
+		}
+	}
+	if len(includedRules) == 0 {
+		return
+	}
+	run, err := customrules.RunSastRules(CustomSastRulesToolName, utils.BaseDocumentationURL+sastDocsUrlSuffix, roots, jas.GetExcludePatterns(module, &sastScanner.Scanner, ssm.scanner.Exclusions...), includedRules)
+	if err != nil {
+		return
+	}
+	run.Tool.Driver.Version = clientutils.Pointer(customSastRulesToolVersion)
+	jas.ProcessJasScanRuns(module.SourceRoot, sastDocsUrlSuffix, run)
+	return []*sarif.Run{run}, nil
+}
+
+func (ssm *SastScanManager) runAnalyzerManager(module jfrogappsconfig.Module, wd string) error {
+	return ssm.scanner.GetAnalyzerManager(module).ExecWithOutputFile(ssm.configFileName, sastScanCommand, wd, ssm.resultsFileName, ssm.scanner.ServerDetails, ssm.scanner.EnvVars)
 }
 
 // In the Sast scanner, there can be multiple results with the same location.
@@ -0,0 +1,137 @@
+package secrets
+
+import (
+	"path/filepath"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
+	"github.com/jfrog/jfrog-cli-security/jas/linescan"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	NativeSecretsDetectorName    = "JFrog Secrets Detector"
+	CustomSecretsRulesToolName   = "JFrog Custom Secrets Rules"
+	nativeSecretsDetectorVersion = "1.0.0"
+)
+
+// Files that contain checksums and generated values that look like secrets
+var skippedFilesNames = []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "go.sum", "poetry.lock", "Pipfile.lock", "uv.lock", "pdm.lock", "packages.lock.json", "gradle.lockfile"}
+
+// Runs the built-in secrets detector, and the custom secrets rules of the module, on the source roots of the module.
+// The detector doesn't require the analyzer manager or JAS entitlement, and its results are reported the same as the results of the Secrets scanner.
+// If changedSince is set, only the secrets in the lines that were changed since the Git reference are reported.
+func RunNativeSecretsScan(module jfrogappsconfig.Module, changedSince string, exclusions []string, customRules []customrules.Rule, threadId int) (results []*sarif.Run, err error) {
+	changedLines, err := jas.GetChangedLines(module.SourceRoot, changedSince)
+	if err != nil || changedLines.NothingChanged() {
+		return
+	}
+	log.Info(clientutils.GetLogMsgPrefix(threadId, false) + "Running secrets scan with the built-in secrets detector...")
+	run, err := detectModuleSecrets(module, changedLines, exclusions, secretRules, newNativeSecretsRun())
+	if err != nil {
+		return
+	}
+	results = []*sarif.Run{run}
+	if len(customRules) > 0 {
+		if run, err = runCustomSecretsRules(module, changedLines, exclusions, customRules); err != nil {
+			return
+		}
+		results = append(results, run)
+	}
+	if count := sarifutils.GetResultsLocationCount(results...); count > 0 {
+		log.Info(clientutils.GetLogMsgPrefix(threadId, false)+"Found", count, "secrets vulnerabilities")
+	}
+	return
+}
+
+// Creates a run of the built-in secrets detector, with all its rules.
+func newNativeSecretsRun() *sarif.Run {
+	run := sarif.NewRunWithInformationURI(NativeSecretsDetectorName, utils.BaseDocumentationURL+secretsDocsUrlSuffix)
+	run.Tool.Driver.Version = clientutils.Pointer(nativeSecretsDetectorVersion)
+	for _, rule := range secretRules {
+		run.AddRule(rule.id).WithName(rule.name).WithShortDescription(sarif.NewMultiformatMessageString(rule.description)).
+			WithFullDescription(sarif.NewMultiformatMessageString(rule.description))
+	}
+	return run
+}
+
+// Runs the custom secrets rules on the source roots of the module with the built-in secrets detector.
+func runCustomSecretsRules(module jfrogappsconfig.Module, changedLines *jas.ChangedLines, exclusions []string, rules []customrules.Rule) (*sarif.Run, error) {
+	run := sarif.NewRunWithInformationURI(CustomSecretsRulesToolName, utils.BaseDocumentationURL+secretsDocsUrlSuffix)
+	run.Tool.Driver.Version = clientutils.Pointer(nativeSecretsDetectorVersion)
+	var detectorRules []secretRule
+	for _, rule := range rules {
+		customrules.AddRule(run, rule)
+		detectorRules = append(detectorRules, secretRule{id: rule.Id, name: rule.GetName(), description: rule.Description, level: rule.GetLevel(), regex: rule.GetRegexp(), minEntropy: rule.MinEntropy})
+	}
+	return detectModuleSecrets(module, changedLines, exclusions, detectorRules, run)
+}
+
+// Detects the secrets of the rules in the source roots of the module, and adds them to the run with masked snippets.
+func detectModuleSecrets(module jfrogappsconfig.Module, changedLines *jas.ChangedLines, exclusions []string, rules []secretRule, run *sarif.Run) (*sarif.Run, error) {
+	roots, err := jas.GetSourceRoots(module, module.Scanners.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	excludeRegexes := linescan.GetExcludeRegexes(jas.GetExcludePatterns(module, module.Scanners.Secrets, exclusions...))
+	isScannedFile := func(path string) bool {
+		return !slices.Contains(skippedFilesNames, filepath.Base(path))
+	}
+	if err = linescan.ScanRoots(changedLines.NarrowRoots(roots), excludeRegexes, isScannedFile, func(path string, lineNumber int, line string) {
+		detectSecretsInLine(path, lineNumber, line, rules, run)
+	}); err != nil {
+		return nil, err
+	}
+	jas.ProcessJasScanRuns(module.SourceRoot, secretsDocsUrlSuffix, run)
+	changedLines.FilterRuns(false, run)
+	processSecretScanRuns([]*sarif.Run{run})
+	return run, nil
+}
+
+// Adds a result for each secret in the line.
+func detectSecretsInLine(path string, lineNumber int, line string, rules []secretRule, run *sarif.Run) {
+	for _, secret := range findSecrets(rules, line) {
+		addSecretResult(run, secret, path, lineNumber)
+	}
+}
+
+type detectedSecret struct {
+	rule       *secretRule
+	value      string
+	start, end int
+}
+
+// Returns the secrets in the line. A location is detected once, by the first rule that matches it.
+func findSecrets(rules []secretRule, line string) (secrets []detectedSecret) {
+	var detectedColumns []int
+	for i := range rules {
+		rule := &rules[i]
+		for _, match := range rule.regex.FindAllStringSubmatchIndex(line, -1) {
+			start, end := match[0], match[1]
+			if len(match) > 2 && match[2] != -1 {
+				start, end = match[2], match[3]
+			}
+			value := line[start:end]
+			if slices.Contains(detectedColumns, start) || isAllowedSecret(value) || (rule.minEntropy > 0 && shannonEntropy(value) < rule.minEntropy) {
+				continue
+			}
+			detectedColumns = append(detectedColumns, start)
+			secrets = append(secrets, detectedSecret{rule: rule, value: value, start: start, end: end})
+		}
+	}
+	return
+}
+
+func addSecretResult(run *sarif.Run, secret detectedSecret, path string, lineNumber int) *sarif.Result {
+	physicalLocation := sarifutils.NewPhysicalLocationWithRegion("file://"+path, lineNumber, lineNumber, secret.start+1, secret.end+1)
+	physicalLocation.Region.Snippet = sarif.NewArtifactContent().WithText(secret.value)
+	result := run.CreateResultForRule(secret.rule.id).WithLevel(secret.rule.level).WithMessage(sarif.NewTextMessage(secret.rule.description))
+	result.AddLocation(sarif.NewLocationWithPhysicalLocation(physicalLocation))
+	return result
+}
@@ -0,0 +1,123 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunNativeSecretsScan(t *testing.T) {
+	// The fake secrets are concatenated, so they won't be detected in this file
+	awsAccessKeyId := "AKIA" + "Z7QW3RT5YU8IOP2A"
+	githubToken := "ghp_" + "aB3dE5fG7hJ9kL1mN3pQ5rS7tU9vW1xY3z5A"
+	genericPassword := "Xk9#" + "mP2$vL7q"
+	files := map[string]string{
+		"config.py":                     "import os\n\nAWS_ACCESS_KEY_ID = \"" + awsAccessKeyId + "\"\ndb_password = \"" + genericPassword + "\"\n",
+		filepath.Join("src", "ci.sh"):   "#!/bin/bash\n# jfrog-ignore: used by the tests\nexport TOKEN=" + githubToken + "\ncurl -H \"Authorization: " + githubToken + "\" https://api.github.com\n",
+		filepath.Join("src", "key.pem"): "-----BEGIN RSA " + "PRIVATE KEY-----\nMIIEpAIBAAKCAQEA\n-----END RSA PRIVATE KEY-----\n",
+		// Placeholders and references to variables aren't secrets
+		filepath.Join("src", "settings.yaml"): "password: \"${DB_PASSWORD}\"\napi_key: \"<your-api-key>\"\nsecret: \"********\"\naws_key: \"AKIA" + "IOSFODNN7EXAMPLE\"\n",
+		// Excluded by the default exclude patterns, or skipped since they are binary or lock files
+		filepath.Join("node_modules", "lib", "index.js"): "const token = \"" + githubToken + "\"\n",
+		filepath.Join("src", "image.bin"):                "\x00\x01" + githubToken,
+		"package-lock.json":                              "{\"token\": \"" + githubToken + "\"}",
+	}
+	root := t.TempDir()
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0644))
+	}
+
+	runs, err := RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "", nil, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, NativeSecretsDetectorName, sarifutils.GetRunToolName(runs[0]))
+	assert.Equal(t, root, sarifutils.GetInvocationWorkingDirectory(runs[0].Invocations[0]))
+
+	type detectedSecret struct {
+		ruleId, file, snippet string
+		line, column          int
+	}
+	var detected []detectedSecret
+	for _, result := range runs[0].Results {
+		for _, location := range result.Locations {
+			detected = append(detected, detectedSecret{
+				ruleId:  sarifutils.GetResultRuleId(result),
+				file:    sarifutils.GetRelativeLocationFileName(location, runs[0].Invocations),
+				snippet: sarifutils.GetLocationSnippet(location),
+				line:    sarifutils.GetLocationStartLine(location),
+				column:  sarifutils.GetLocationStartColumn(location),
+			})
+		}
+	}
+	assert.ElementsMatch(t, []detectedSecret{
+		{ruleId: "aws-access-key-id", file: "config.py", snippet: "AKI************", line: 3, column: 22},
+		{ruleId: "generic-secret", file: "config.py", snippet: "Xk9************", line: 4, column: 16},
+		{ruleId: "github-token", file: filepath.Join("src", "ci.sh"), snippet: "ghp************", line: 4, column: 25},
+		{ruleId: "private-key", file: filepath.Join("src", "key.pem"), snippet: "---************", line: 1, column: 1},
+	}, detected)
+}
+
+func TestRunNativeSecretsScanExclusions(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "generated"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "generated", "client.go"), []byte("var token = \"ghp_"+"aB3dE5fG7hJ9kL1mN3pQ5rS7tU9vW1xY3z5A\"\n"), 0644))
+
+	runs, err := RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "", nil, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sarifutils.GetResultsLocationCount(runs...))
+
+	runs, err = RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "", []string{"generated"}, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sarifutils.GetResultsLocationCount(runs...))
+}
+
+func TestRunCustomSecretsRules(t *testing.T) {
+	rulesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "rules.yaml"), []byte(`rules:
+  - id: internal-token
+    type: secrets
+    description: Hardcoded token of an internal service
+    severity: high
+    regex: '\b(ist_[0-9a-f]{32})\b'
+  - id: banned-md5
+    type: sast
+    description: Usage of the banned MD5 hash
+    regex: 'md5\.New\(\)'
+`), 0644))
+	rules, err := customrules.LoadRules(rulesDir)
+	require.NoError(t, err)
+	root := t.TempDir()
+	// The fake token is concatenated, so it won't be detected in this file
+	require.NoError(t, os.WriteFile(filepath.Join(root, "client.go"), []byte("var token = \"ist_"+"0123456789abcdef0123456789abcdef\"\nvar h = md5.New()\n"), 0644))
+
+	// The custom rules run with the built-in secrets detector
+	runs, err := RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "", nil, customrules.GetRulesOfType(rules, customrules.SecretsRule), 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, NativeSecretsDetectorName, sarifutils.GetRunToolName(runs[0]))
+	run := runs[1]
+	assert.Equal(t, CustomSecretsRulesToolName, sarifutils.GetRunToolName(run))
+	require.Len(t, run.Tool.Driver.Rules, 1)
+	assert.Equal(t, "Hardcoded token of an internal service", sarifutils.GetRuleShortDescriptionText(run.Tool.Driver.Rules[0]))
+	require.Len(t, run.Results, 1)
+	assert.Equal(t, "internal-token", sarifutils.GetResultRuleId(run.Results[0]))
+	assert.Equal(t, "error", sarifutils.GetResultLevel(run.Results[0]))
+	assert.Equal(t, []string{"ist************"}, sarifutils.GetResultLocationSnippets(run.Results[0]))
+	assert.Equal(t, 1, sarifutils.GetLocationStartLine(run.Results[0].Locations[0]))
+	assert.Equal(t, 14, sarifutils.GetLocationStartColumn(run.Results[0].Locations[0]))
+}
+
+func TestShannonEntropy(t *testing.T) {
+	assert.Zero(t, shannonEntropy(""))
+	assert.Zero(t, shannonEntropy("aaaa"))
+	assert.Equal(t, float64(1), shannonEntropy("abab"))
+	assert.Equal(t, float64(4), shannonEntropy(strings.Repeat("0123456789abcdef", 2)))
+}
@@ -0,0 +1,150 @@
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/jas/linescan"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// Separates the commits in the 'git log' output, followed by the commit SHA, author and date separated by unitSeparator
+	commitSeparator       = "\x1e"
+	unitSeparator         = "\x1f"
+	gitLogFormat          = "--format=" + commitSeparator + "%H" + unitSeparator + "%an" + unitSeparator + "%aI"
+	gitHistoryFingerprint = "jfrogSecretFingerprint"
+	maxGitLogLineSize     = 10 * 1024 * 1024
+)
+
+type gitCommit struct {
+	sha    string
+	author string
+	date   string
+}
+
+// A secret that was added to a file in the Git history
+type historySecret struct {
+	detectedSecret
+	commit     gitCommit
+	path       string
+	lineNumber int
+}
+
+// Runs the built-in secrets detector on the lines that were added by the commits of the Git history of the module.
+// revRange is a revision range of the commits to scan, as accepted by 'git log', for example 'origin/main..HEAD'. Use 'HEAD' to scan all the commits.
+// A secret that was added by several commits is reported once, with the earliest commit that added it.
+func RunGitHistorySecretsScan(module jfrogappsconfig.Module, revRange string, exclusions []string, threadId int) (results []*sarif.Run, err error) {
+	roots, err := jas.GetSourceRoots(module, module.Scanners.Secrets)
+	if err != nil {
+		return
+	}
+	excludePatterns := linescan.GetExcludeRegexes(jas.GetExcludePatterns(module, module.Scanners.Secrets, exclusions...))
+	log.Info(clientutils.GetLogMsgPrefix(threadId, false) + fmt.Sprintf("Running secrets scan on the Git history '%s'...", revRange))
+	run := newNativeSecretsRun()
+	for _, root := range roots {
+		var secrets []historySecret
+		if secrets, err = detectSecretsInGitHistory(root, revRange, excludePatterns); err != nil {
+			return
+		}
+		for _, secret := range secrets {
+			result := addSecretResult(run, secret.detectedSecret, filepath.Join(root, secret.path), secret.lineNumber)
+			sarifutils.SetResultProperty(jasutils.CommitShaProperty, secret.commit.sha, result)
+			sarifutils.SetResultProperty(jasutils.CommitAuthorProperty, secret.commit.author, result)
+			sarifutils.SetResultProperty(jasutils.CommitDateProperty, secret.commit.date, result)
+			sarifutils.SetResultFingerprint(gitHistoryFingerprint, getSecretFingerprint(secret), result)
+		}
+	}
+	jas.ProcessJasScanRuns(module.SourceRoot, secretsDocsUrlSuffix, run)
+	results = processSecretScanRuns([]*sarif.Run{run})
+	if len(run.Results) > 0 {
+		log.Info(clientutils.GetLogMsgPrefix(threadId, false)+"Found", sarifutils.GetResultsLocationCount(results...), "secrets vulnerabilities in the Git history")
+	}
+	return
+}
+
+// Secrets are identified by the rule that detected them, their value and the file that contains them
+func getSecretFingerprint(secret historySecret) string {
+	fingerprint, err := utils.Md5Hash(secret.rule.id, filepath.ToSlash(secret.path), secret.value)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to calculate the fingerprint of a secret in %s: %s", secret.path, err.Error()))
+	}
+	return fingerprint
+}
+
+// Runs 'git log' in the directory and detects the secrets in the added lines of the commits, for the files in the directory.
+func detectSecretsInGitHistory(dir, revRange string, excludePatterns []*regexp.Regexp) (secrets []historySecret, err error) {
+	err = jas.StreamGitCommand(dir, func(gitLog io.Reader) (parseErr error) {
+		secrets, parseErr = parseGitLog(gitLog, excludePatterns)
+		return
+	}, append(jas.GetGitDiffCommandArgs("log", "-p"), gitLogFormat, revRange, "--")...)
+	return
+}
+
+// Parses the patches of the 'git log' output and returns the secrets in the added lines.
+// The commits are ordered from the newest to the oldest, so a secret that appears again is replaced by the older occurrence.
+func parseGitLog(gitLog io.Reader, excludePatterns []*regexp.Regexp) (secrets []historySecret, err error) {
+	fingerprintsIndexes := map[string]int{}
+	var commit gitCommit
+	var previousAddedLine string
+	skipFile := true
+	parser := &jas.GitDiffParser{}
+	scanner := bufio.NewScanner(gitLog)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxGitLogLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, commitSeparator) {
+			fields := strings.SplitN(strings.TrimPrefix(line, commitSeparator), unitSeparator, 3)
+			commit = gitCommit{sha: fields[0]}
+			if len(fields) == 3 {
+				commit.author, commit.date = fields[1], fields[2]
+			}
+			parser.Reset()
+			skipFile = true
+			continue
+		}
+		var diffLine jas.GitDiffLine
+		if diffLine, err = parser.ParseLine(line); err != nil {
+			return nil, err
+		}
+		switch diffLine.Type {
+		case jas.FileHeaderDiffLine:
+			skipFile = true
+		case jas.NewFileDiffLine:
+			skipFile = diffLine.Path == "" || linescan.IsExcluded(diffLine.Path, excludePatterns) || slices.Contains(skippedFilesNames, filepath.Base(diffLine.Path))
+		case jas.HunkHeaderDiffLine:
+			previousAddedLine = ""
+		case jas.AddedDiffLine:
+			if skipFile {
+				continue
+			}
+			if !linescan.IsIgnoredLine(diffLine.Content, previousAddedLine) {
+				for _, secret := range findSecrets(secretRules, diffLine.Content) {
+					historySecret := historySecret{detectedSecret: secret, commit: commit, path: diffLine.Path, lineNumber: diffLine.LineNumber}
+					fingerprint := getSecretFingerprint(historySecret)
+					if index, exists := fingerprintsIndexes[fingerprint]; exists {
+						secrets[index] = historySecret
+						continue
+					}
+					fingerprintsIndexes[fingerprint] = len(secrets)
+					secrets = append(secrets, historySecret)
+				}
+			}
+			previousAddedLine = diffLine.Content
+		}
+	}
+	return secrets, errorutils.CheckError(scanner.Err())
+}
@@ -0,0 +1,149 @@
+package secrets
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
+	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitLog(t *testing.T) {
+	// The fake secrets are concatenated, so they won't be detected in this file
+	githubToken := "ghp_" + "aB3dE5fG7hJ9kL1mN3pQ5rS7tU9vW1xY3z5A"
+	awsAccessKeyId := "AKIA" + "Z7QW3RT5YU8IOP2A"
+	gitLog := strings.Join([]string{
+		commitSeparator + "2222222222222222222222222222222222222222" + unitSeparator + "Jane Doe" + unitSeparator + "2024-05-02T10:00:00+00:00",
+		"",
+		"diff --git a/src/ci.sh b/src/ci.sh",
+		"index 1111111..2222222 100644",
+		"--- a/src/ci.sh",
+		"+++ b/src/ci.sh",
+		"@@ -3,0 +4,2 @@ export A=1",
+		"+export TOKEN=" + githubToken,
+		"+export KEY=" + awsAccessKeyId,
+		"diff --git a/old.env b/old.env",
+		"deleted file mode 100644",
+		"--- a/old.env",
+		"+++ /dev/null",
+		"@@ -1 +0,0 @@",
+		"-KEY=" + awsAccessKeyId,
+		commitSeparator + "1111111111111111111111111111111111111111" + unitSeparator + "John Doe" + unitSeparator + "2024-05-01T10:00:00+00:00",
+		"",
+		"diff --git a/src/ci.sh b/src/ci.sh",
+		"new file mode 100644",
+		"--- /dev/null",
+		"+++ b/src/ci.sh",
+		"@@ -0,0 +1,3 @@",
+		"+#!/bin/bash",
+		"+# jfrog-ignore",
+		"+export IGNORED=" + awsAccessKeyId,
+		"+export TOKEN=" + githubToken,
+		"diff --git a/package-lock.json b/package-lock.json",
+		"--- /dev/null",
+		"+++ b/package-lock.json",
+		"@@ -0,0 +1 @@",
+		"+{\"token\": \"" + githubToken + "\"}",
+	}, "\n")
+
+	secrets, err := parseGitLog(strings.NewReader(gitLog), nil)
+	require.NoError(t, err)
+	require.Len(t, secrets, 2)
+	// The token was added again by the newer commit, so it's reported with the older commit that added it first
+	assert.Equal(t, "github-token", secrets[0].rule.id)
+	assert.Equal(t, "1111111111111111111111111111111111111111", secrets[0].commit.sha)
+	assert.Equal(t, "John Doe", secrets[0].commit.author)
+	assert.Equal(t, filepath.Join("src", "ci.sh"), secrets[0].path)
+	assert.Equal(t, 4, secrets[0].lineNumber)
+	assert.Equal(t, "aws-access-key-id", secrets[1].rule.id)
+	assert.Equal(t, "2222222222222222222222222222222222222222", secrets[1].commit.sha)
+	assert.Equal(t, "2024-05-02T10:00:00+00:00", secrets[1].commit.date)
+	assert.Equal(t, 5, secrets[1].lineNumber)
+}
+
+func TestRunGitHistorySecretsScan(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	root := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = root
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	githubToken := "ghp_" + "aB3dE5fG7hJ9kL1mN3pQ5rS7tU9vW1xY3z5A"
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "deploy.sh"), []byte("#!/bin/bash\nexport TOKEN="+githubToken+"\n"), 0644))
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add the deploy script")
+	firstCommit := git("rev-parse", "HEAD")
+	// The secret is removed from the working tree, but remains in the history
+	require.NoError(t, os.WriteFile(filepath.Join(root, "deploy.sh"), []byte("#!/bin/bash\nexport TOKEN=$TOKEN\n"), 0644))
+	git("commit", "-q", "-a", "-m", "Remove the token")
+
+	runs, err := RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "", nil, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sarifutils.GetResultsLocationCount(runs...))
+
+	runs, err = RunGitHistorySecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "HEAD", nil, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Len(t, runs[0].Results, 1)
+	result := runs[0].Results[0]
+	assert.Equal(t, "github-token", sarifutils.GetResultRuleId(result))
+	assert.Equal(t, firstCommit, sarifutils.GetResultProperty(jasutils.CommitShaProperty, result))
+	assert.Equal(t, "Jane Doe", sarifutils.GetResultProperty(jasutils.CommitAuthorProperty, result))
+	assert.NotEmpty(t, sarifutils.GetResultProperty(jasutils.CommitDateProperty, result))
+	assert.NotEmpty(t, result.Fingerprints[gitHistoryFingerprint])
+	require.Len(t, result.Locations, 1)
+	assert.Equal(t, "deploy.sh", sarifutils.GetRelativeLocationFileName(result.Locations[0], runs[0].Invocations))
+	assert.Equal(t, 2, sarifutils.GetLocationStartLine(result.Locations[0]))
+
+	// The last commit only removed the token
+	runs, err = RunGitHistorySecretsScan(jfrogappsconfig.Module{SourceRoot: root}, firstCommit+"..HEAD", nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sarifutils.GetResultsLocationCount(runs...))
+}
+
+func TestRunNativeSecretsScanChangedSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = root
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	githubToken := "ghp_" + "aB3dE5fG7hJ9kL1mN3pQ5rS7tU9vW1xY3z5A"
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "deploy.sh"), []byte("#!/bin/bash\nexport TOKEN="+githubToken+"\n"), 0644))
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add the deploy script")
+
+	runs, err := RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "HEAD", nil, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sarifutils.GetResultsLocationCount(runs...))
+
+	// Only the secrets in the changed lines are reported
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "ci"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "ci", "release.sh"), []byte("#!/bin/bash\nexport TOKEN="+githubToken+"\n"), 0644))
+	runs, err = RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "HEAD", nil, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Len(t, runs[0].Results, 1)
+	assert.Equal(t, filepath.Join("ci", "release.sh"), sarifutils.GetRelativeLocationFileName(runs[0].Results[0].Locations[0], runs[0].Invocations))
+
+	runs, err = RunNativeSecretsScan(jfrogappsconfig.Module{SourceRoot: root}, "", nil, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sarifutils.GetResultsLocationCount(runs...))
+}
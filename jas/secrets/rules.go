@@ -0,0 +1,169 @@
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Levels of the detected secrets, parsed to the results severities
+	highSecretLevel   = "error"
+	mediumSecretLevel = "warning"
+)
+
+// A rule of the built-in secrets detector.
+// The secret is the first submatch of the rule regular expression, or the whole match if the expression has no submatches.
+type secretRule struct {
+	id          string
+	name        string
+	description string
+	level       string
+	regex       *regexp.Regexp
+	// The minimal Shannon entropy (bits per character) of the secret, to filter out values that aren't random enough. Zero to skip the check.
+	minEntropy float64
+}
+
+// The rules of the built-in secrets detector, ordered from the most specific to the most generic.
+// A location that is matched by a rule isn't matched again by the rules that follow it.
+var secretRules = []secretRule{
+	{
+		id:          "private-key",
+		name:        "Private key",
+		description: "Hardcoded private key",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`-----BEGIN[ A-Z0-9_-]{0,100}PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		id:          "aws-access-key-id",
+		name:        "AWS access key ID",
+		description: "Hardcoded AWS access key ID",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b((?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16})\b`),
+	},
+	{
+		id:          "aws-secret-access-key",
+		name:        "AWS secret access key",
+		description: "Hardcoded AWS secret access key",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`(?i)aws[\w.-]{0,20}(?:secret|key)[\w.-]{0,20}["']?\s*(?::=|=>|[:=])\s*["']?([0-9a-zA-Z/+]{40})\b`),
+		minEntropy:  3.5,
+	},
+	{
+		id:          "github-token",
+		name:        "GitHub token",
+		description: "Hardcoded GitHub token",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b((?:gh[pousr]_[0-9A-Za-z]{36,255})|(?:github_pat_[0-9A-Za-z_]{82}))\b`),
+	},
+	{
+		id:          "gitlab-token",
+		name:        "GitLab token",
+		description: "Hardcoded GitLab personal access token",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b(glpat-[0-9A-Za-z_-]{20})\b`),
+	},
+	{
+		id:          "slack-token",
+		name:        "Slack token",
+		description: "Hardcoded Slack token",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b(xox[abposr]-[0-9A-Za-z-]{10,250})\b`),
+	},
+	{
+		id:          "slack-webhook",
+		name:        "Slack webhook",
+		description: "Hardcoded Slack webhook URL",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`https://hooks\.slack\.com/(?:services|workflows)/[A-Za-z0-9+/]{40,60}`),
+	},
+	{
+		id:          "google-api-key",
+		name:        "Google API key",
+		description: "Hardcoded Google API key",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})\b`),
+	},
+	{
+		id:          "stripe-key",
+		name:        "Stripe key",
+		description: "Hardcoded Stripe secret key",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b((?:sk|rk)_live_[0-9A-Za-z]{24,99})\b`),
+	},
+	{
+		id:          "sendgrid-api-key",
+		name:        "SendGrid API key",
+		description: "Hardcoded SendGrid API key",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b(SG\.[0-9A-Za-z_-]{22}\.[0-9A-Za-z_-]{43})\b`),
+	},
+	{
+		id:          "npm-token",
+		name:        "npm token",
+		description: "Hardcoded npm access token",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b(npm_[0-9A-Za-z]{36})\b`),
+	},
+	{
+		id:          "jfrog-token",
+		name:        "JFrog token",
+		description: "Hardcoded JFrog API key or reference token",
+		level:       highSecretLevel,
+		regex:       regexp.MustCompile(`\b((?:AKCp[0-9A-Za-z]{69})|(?:cmVmdGtu[0-9A-Za-z]{56,}))\b`),
+	},
+	{
+		id:          "jwt",
+		name:        "JSON web token",
+		description: "Hardcoded JSON web token",
+		level:       mediumSecretLevel,
+		regex:       regexp.MustCompile(`\b(eyJ[0-9A-Za-z_-]{10,}\.eyJ[0-9A-Za-z_-]{10,}\.[0-9A-Za-z_-]{10,})`),
+	},
+	{
+		id:          "generic-secret",
+		name:        "Generic secret",
+		description: "Hardcoded password or secret assigned to a sensitive variable",
+		level:       mediumSecretLevel,
+		regex:       regexp.MustCompile(`(?i)[\w.-]*(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|auth[_-]?key|credentials?)[\w.-]*["']?\s*(?::=|=>|[:=])\s*["']([^"'\s]{8,})["']`),
+		minEntropy:  3,
+	},
+}
+
+// Values that look like secrets but are placeholders, references to variables or templates
+var allowedSecretsRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`^\$\{?[\w.:-]+\}?$`),
+	regexp.MustCompile(`^\{\{.*\}\}$`),
+	regexp.MustCompile(`^<.*>$`),
+	regexp.MustCompile(`^%[\w(]`),
+	regexp.MustCompile(`(?i)example|placeholder|changeme|dummy|redacted|sample|your[_-]?|process\.env|os\.environ|getenv`),
+}
+
+func isAllowedSecret(secret string) bool {
+	// Masked values, such as '********'
+	if len(secret) == 0 || strings.Trim(secret, secret[:1]) == "" {
+		return true
+	}
+	for _, allowedRegex := range allowedSecretsRegexes {
+		if allowedRegex.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the Shannon entropy of the value, in bits per character.
+func shannonEntropy(value string) (entropy float64) {
+	if len(value) == 0 {
+		return
+	}
+	frequencies := map[rune]float64{}
+	for _, char := range value {
+		frequencies[char]++
+	}
+	length := float64(len([]rune(value)))
+	for _, count := range frequencies {
+		probability := count / length
+		entropy -= probability * math.Log2(probability)
+	}
+	return
+}
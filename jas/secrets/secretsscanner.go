@@ -9,6 +9,7 @@ import (
 	jfrogappsconfig "github.com/jfrog/jfrog-apps-config/go"
 	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
 	"github.com/jfrog/jfrog-cli-security/jas"
+	"github.com/jfrog/jfrog-cli-security/jas/customrules"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/owenrumney/go-sarif/v2/sarif"
@@ -27,6 +28,7 @@ type SecretsScanType string
 type SecretScanManager struct {
 	secretsScannerResults []*sarif.Run
 	scanner               *jas.JasScanner
+	changedLines          *jas.ChangedLines
 	scanType              SecretsScanType
 	configFileName        string
 	resultsFileName       string
@@ -47,9 +49,17 @@ func RunSecretsScan(scanner *jas.JasScanner, scanType SecretsScanType, module jf
 	secretScanManager := newSecretsScanManager(scanner, scanType, scannerTempDir)
 	log.Info(clientutils.GetLogMsgPrefix(threadId, false) + "Running secrets scan...")
 	if err = secretScanManager.scanner.Run(secretScanManager, module); err != nil {
+		if jas.IsUnsupportedOsError(err) {
+			log.Warn(clientutils.GetLogMsgPrefix(threadId, false) + "The analyzer manager doesn't support the current operating system. Falling back to the built-in secrets detector...")
+			return RunNativeSecretsScan(module, scanner.ChangedSince, scanner.Exclusions, secretScanManager.getCustomRules(module), threadId)
+		}
 		err = jas.ParseAnalyzerManagerError(jasutils.Secrets, err)
 		return
 	}
+	// The custom rules were validated before the scans, so failing to run them doesn't fail the scan and drop the results of the Secrets scanner
+	if customRulesErr := secretScanManager.runCustomRules(module); customRulesErr != nil {
+		log.Warn(clientutils.GetLogMsgPrefix(threadId, false) + "Failed to run the custom secrets rules: " + customRulesErr.Error())
+	}
 	results = secretScanManager.secretsScannerResults
 	if len(results) > 0 {
 		log.Info(clientutils.GetLogMsgPrefix(threadId, false)+"Found", sarifutils.GetResultsLocationCount(results...), "secrets vulnerabilities")
@@ -68,20 +78,46 @@ func newSecretsScanManager(scanner *jas.JasScanner, scanType SecretsScanType, sc
 }
 
 func (ssm *SecretScanManager) Run(module jfrogappsconfig.Module) (err error) {
+	if ssm.changedLines, err = jas.GetChangedLines(module.SourceRoot, ssm.scanner.ChangedSince); err != nil || ssm.changedLines.NothingChanged() {
+		return
+	}
 	if err = ssm.createConfigFile(module, ssm.scanner.Exclusions...); err != nil {
 		return
 	}
-	if err = ssm.runAnalyzerManager(); err != nil {
+	if err = ssm.runAnalyzerManager(module); err != nil {
 		return
 	}
 	workingDirRuns, err := jas.ReadJasScanRunsFromFile(ssm.resultsFileName, module.SourceRoot, secretsDocsUrlSuffix)
 	if err != nil {
 		return
 	}
+	ssm.changedLines.FilterRuns(false, workingDirRuns...)
 	ssm.secretsScannerResults = append(ssm.secretsScannerResults, processSecretScanRuns(workingDirRuns)...)
 	return
 }
 
+// Runs the custom secrets rules of the module with the built-in secrets detector, and adds their results to the results of the scan.
+func (ssm *SecretScanManager) runCustomRules(module jfrogappsconfig.Module) error {
+	rules := ssm.getCustomRules(module)
+	if len(rules) == 0 || ssm.changedLines.NothingChanged() {
+		return nil
+	}
+	run, err := runCustomSecretsRules(module, ssm.changedLines, ssm.scanner.Exclusions, rules)
+	if err != nil {
+		return err
+	}
+	ssm.secretsScannerResults = append(ssm.secretsScannerResults, run)
+	return nil
+}
+
+// Returns the custom secrets rules of the module. The custom rules apply to the source code only, and not to the scanned Docker images.
+func (ssm *SecretScanManager) getCustomRules(module jfrogappsconfig.Module) []customrules.Rule {
+	if ssm.scanType != SecretsScannerType {
+		return nil
+	}
+	return ssm.scanner.CustomRules.GetModuleRules(module, customrules.SecretsRule)
+}
+
 type secretsScanConfig struct {
 	Scans []secretsScanConfiguration `yaml:"scans"`
 }
@@ -101,7 +137,7 @@ func (s *SecretScanManager) createConfigFile(module jfrogappsconfig.Module, excl
 	configFileContent := secretsScanConfig{
 		Scans: []secretsScanConfiguration{
 			{
-				Roots:       roots,
+				Roots:       s.changedLines.NarrowRoots(roots),
 				Output:      s.resultsFileName,
 				Type:        string(s.scanType),
 				SkippedDirs: jas.GetExcludePatterns(module, module.Scanners.Secrets, exclusions...),
@@ -111,8 +147,8 @@ func (s *SecretScanManager) createConfigFile(module jfrogappsconfig.Module, excl
 	return jas.CreateScannersConfigFile(s.configFileName, configFileContent, jasutils.Secrets)
 }
 
-func (s *SecretScanManager) runAnalyzerManager() error {
-	return s.scanner.AnalyzerManager.Exec(s.configFileName, secretsScanCommand, filepath.Dir(s.scanner.AnalyzerManager.AnalyzerManagerFullPath), s.scanner.ServerDetails, s.scanner.EnvVars)
+func (s *SecretScanManager) runAnalyzerManager(module jfrogappsconfig.Module) error {
+	return s.scanner.GetAnalyzerManager(module).Exec(s.configFileName, secretsScanCommand, filepath.Dir(s.scanner.AnalyzerManager.AnalyzerManagerFullPath), s.scanner.ServerDetails, s.scanner.EnvVars)
 }
 
 func maskSecret(secret string) string {
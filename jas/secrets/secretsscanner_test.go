@@ -60,7 +60,7 @@ func TestRunAnalyzerManager_ReturnsGeneralError(t *testing.T) {
 	defer cleanUp()
 
 	secretScanManager := newSecretsScanManager(scanner, SecretsScannerType, "temoDirPath")
-	assert.Error(t, secretScanManager.runAnalyzerManager())
+	assert.Error(t, secretScanManager.runAnalyzerManager(jfrogappsconfig.Module{}))
 }
 
 func TestParseResults_EmptyResults(t *testing.T) {
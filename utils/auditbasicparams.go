@@ -3,6 +3,7 @@ package utils
 import (
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	ioUtils "github.com/jfrog/jfrog-client-go/utils/io"
 )
 
@@ -40,6 +41,10 @@ type AuditParams interface {
 	Exclusions() []string
 	SetIsRecursiveScan(isRecursiveScan bool) *AuditBasicParams
 	IsRecursiveScan() bool
+	SetUseDepTreeCache(useDepTreeCache bool) *AuditBasicParams
+	UseDepTreeCache() bool
+	SetDependenciesScopes(dependenciesScopes []string) *AuditBasicParams
+	DependenciesScopes() []string
 }
 
 type AuditBasicParams struct {
@@ -63,6 +68,8 @@ type AuditBasicParams struct {
 	dependenciesForApplicabilityScan []string
 	exclusions                       []string
 	isRecursiveScan                  bool
+	useDepTreeCache                  bool
+	dependenciesScopes               []string
 }
 
 func (abp *AuditBasicParams) DirectDependencies() *[]string {
@@ -253,3 +260,25 @@ func (abp *AuditBasicParams) SetIsRecursiveScan(isRecursiveScan bool) *AuditBasi
 func (abp *AuditBasicParams) IsRecursiveScan() bool {
 	return abp.isRecursiveScan
 }
+
+func (abp *AuditBasicParams) SetUseDepTreeCache(useDepTreeCache bool) *AuditBasicParams {
+	abp.useDepTreeCache = useDepTreeCache
+	return abp
+}
+
+func (abp *AuditBasicParams) UseDepTreeCache() bool {
+	return abp.useDepTreeCache
+}
+
+func (abp *AuditBasicParams) SetDependenciesScopes(dependenciesScopes []string) *AuditBasicParams {
+	abp.dependenciesScopes = dependenciesScopes
+	return abp
+}
+
+// Returns the dependency scopes to audit, or an empty list to audit all the dependencies.
+func (abp *AuditBasicParams) DependenciesScopes() []string {
+	if len(abp.dependenciesScopes) == 0 && abp.excludeTestDependencies {
+		return []string{techutils.RuntimeScope, techutils.DevScope}
+	}
+	return abp.dependenciesScopes
+}
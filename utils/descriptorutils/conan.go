@@ -0,0 +1,45 @@
+package descriptorutils
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// A package reference in a conanfile.py, for example: self.requires("zlib/1.2.13") or requires = "zlib/1.2.13", "fmt/10.1.1"
+	conanfilePyReferenceRegex = regexp.MustCompile(`["']([A-Za-z0-9_][\w.+-]*)/[^"'\s]+["']`)
+	// Sections of a conanfile.txt that declare packages references
+	conanfileTxtRequiresSections = []string{"requires", "tool_requires", "build_requires", "test_requires"}
+)
+
+// Locates the references in the requires sections of a conanfile.txt by the packages names.
+func parseConanfileTxt(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	linesOffsets := newLinesIndex(content)
+	lines, offsets := splitLines(content)
+	section := ""
+	for i, line := range lines {
+		if match := tomlSectionRegex.FindStringSubmatch(line); match != nil {
+			section = match[1]
+			continue
+		}
+		trimmed := strings.TrimSpace(strings.Split(line, "#")[0])
+		if !slices.Contains(conanfileTxtRequiresSections, section) || !strings.Contains(trimmed, "/") {
+			continue
+		}
+		start := offsets[i] + strings.Index(line, trimmed)
+		locations.add(strings.Split(trimmed, "/")[0], linesOffsets.getLocation(content, descriptorPath, start, start+len(trimmed)))
+	}
+	return locations, nil
+}
+
+// Locates the quoted references in a conanfile.py by the packages names.
+func parseConanfilePy(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	lines := newLinesIndex(content)
+	for _, match := range conanfilePyReferenceRegex.FindAllSubmatchIndex(content, -1) {
+		locations.add(string(content[match[2]:match[3]]), lines.getLocation(content, descriptorPath, match[0], match[1]))
+	}
+	return locations, nil
+}
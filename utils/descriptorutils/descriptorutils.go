@@ -0,0 +1,155 @@
+package descriptorutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/formats"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Parses a descriptor and returns the locations of the dependencies declared in it, by their normalized names
+type descriptorParser func(descriptorPath string, content []byte) (dependenciesLocations, error)
+
+// The locations of the dependencies declarations in a descriptor, by the normalized names of the dependencies
+type dependenciesLocations map[string]*formats.Location
+
+// Adds the location of the dependency, unless it was already declared in an earlier location
+func (dl dependenciesLocations) add(name string, location formats.Location) {
+	if _, exists := dl[name]; !exists {
+		dl[name] = &location
+	}
+}
+
+// Locates the declarations of the direct dependencies in the project descriptors.
+// Each descriptor is read and parsed once, on the first lookup.
+type DependenciesLocator struct {
+	descriptors map[string]dependenciesLocations
+}
+
+func NewDependenciesLocator() *DependenciesLocator {
+	return &DependenciesLocator{descriptors: map[string]dependenciesLocations{}}
+}
+
+// Returns the location of the dependency declaration in the first descriptor that declares it, or nil if it's not declared in any of them.
+func (dl *DependenciesLocator) GetDependencyLocation(dependencyName string, descriptorsPaths ...string) *formats.Location {
+	for _, descriptorPath := range descriptorsPaths {
+		parser, normalize := getDescriptorParser(descriptorPath)
+		if parser == nil {
+			continue
+		}
+		locations, parsed := dl.descriptors[descriptorPath]
+		if !parsed {
+			locations = parseDescriptor(descriptorPath, parser)
+			dl.descriptors[descriptorPath] = locations
+		}
+		if location, exists := locations[normalize(dependencyName)]; exists {
+			return location
+		}
+	}
+	return nil
+}
+
+func parseDescriptor(descriptorPath string, parser descriptorParser) dependenciesLocations {
+	content, err := os.ReadFile(descriptorPath)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't read the descriptor '%s' to locate its dependencies: %s", descriptorPath, err.Error()))
+		return nil
+	}
+	locations, err := parser(descriptorPath, content)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't parse the descriptor '%s' to locate its dependencies: %s", descriptorPath, err.Error()))
+	}
+	return locations
+}
+
+// Returns the parser of the descriptor by its file name, and the function to normalize the dependencies names in it.
+func getDescriptorParser(descriptorPath string) (parser descriptorParser, normalize func(string) string) {
+	normalize = func(name string) string { return name }
+	fileName := filepath.Base(descriptorPath)
+	switch {
+	case fileName == "package.json":
+		parser = parsePackageJson
+	case fileName == "pom.xml":
+		parser = parsePomXml
+	case fileName == "build.gradle" || fileName == "build.gradle.kts":
+		parser = parseBuildGradle
+	case fileName == "go.mod":
+		parser = parseGoMod
+	case strings.HasPrefix(fileName, "requirements") && strings.HasSuffix(fileName, ".txt"):
+		parser, normalize = parseRequirementsTxt, normalizePythonName
+	case fileName == "Pipfile":
+		parser, normalize = parsePipfile, normalizePythonName
+	case fileName == "pyproject.toml":
+		parser, normalize = parsePyprojectToml, normalizePythonName
+	case strings.HasSuffix(fileName, ".csproj"):
+		// NuGet packages IDs are case-insensitive
+		parser, normalize = parseCsproj, strings.ToLower
+	case fileName == "conanfile.txt":
+		parser = parseConanfileTxt
+	case fileName == "conanfile.py":
+		parser = parseConanfilePy
+	}
+	return
+}
+
+// Returns the paths of the files with the given extension in the directory and its direct subdirectories, sorted.
+// Used for descriptors that are identified by their extension, such as .csproj files.
+func FindDescriptorsByExtension(dir, extension string) (descriptorsPaths []string, err error) {
+	for _, pattern := range []string{filepath.Join(dir, "*"+extension), filepath.Join(dir, "*", "*"+extension)} {
+		matches, globErr := filepath.Glob(pattern)
+		if globErr != nil {
+			return nil, errorutils.CheckError(globErr)
+		}
+		descriptorsPaths = append(descriptorsPaths, matches...)
+	}
+	sort.Strings(descriptorsPaths)
+	return
+}
+
+// The offsets of the lines starts in a file, used to convert offsets to lines and columns
+type linesIndex []int
+
+func newLinesIndex(content []byte) linesIndex {
+	lines := linesIndex{0}
+	for i, char := range content {
+		if char == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// Returns the location of the content between the given offsets, with 1-based lines and columns
+func (lines linesIndex) getLocation(content []byte, filePath string, start, end int) formats.Location {
+	startLine, startColumn := lines.getPosition(start)
+	endLine, endColumn := lines.getPosition(end)
+	return formats.Location{
+		File:        filePath,
+		StartLine:   startLine,
+		StartColumn: startColumn,
+		EndLine:     endLine,
+		EndColumn:   endColumn,
+		Snippet:     string(content[start:end]),
+	}
+}
+
+func (lines linesIndex) getPosition(offset int) (line, column int) {
+	line = sort.Search(len(lines), func(i int) bool { return lines[i] > offset })
+	return line, offset - lines[line-1] + 1
+}
+
+// Returns the lines of the content with the offset of each line start
+func splitLines(content []byte) (lines []string, offsets []int) {
+	offset := 0
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		lines = append(lines, strings.TrimRight(line, "\r\n"))
+		offsets = append(offsets, offset)
+		offset += len(line)
+	}
+	return
+}
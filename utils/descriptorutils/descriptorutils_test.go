@@ -0,0 +1,157 @@
+package descriptorutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDependencyLocation(t *testing.T) {
+	testCases := []struct {
+		descriptor      string
+		content         string
+		dependency      string
+		expectedLine    int
+		expectedColumn  int
+		expectedSnippet string
+	}{
+		{
+			descriptor:      "package.json",
+			content:         "{\n  \"name\": \"app\",\n  \"dependencies\": {\n    \"lodash\": \"^4.17.0\"\n  },\n  \"devDependencies\": {\"@types/node\": \"20.0.0\"}\n}\n",
+			dependency:      "@types/node",
+			expectedLine:    6,
+			expectedColumn:  23,
+			expectedSnippet: `"@types/node": "20.0.0"`,
+		},
+		{
+			descriptor:      "package.json",
+			content:         "{\n  \"name\": \"lodash\",\n  \"scripts\": {\"lodash\": \"echo\"},\n  \"dependencies\": {\n    \"lodash\": \"^4.17.0\"\n  }\n}\n",
+			dependency:      "lodash",
+			expectedLine:    5,
+			expectedColumn:  5,
+			expectedSnippet: `"lodash": "^4.17.0"`,
+		},
+		{
+			descriptor:      "pom.xml",
+			content:         "<project>\n  <dependencies>\n    <dependency>\n      <groupId>org.slf4j</groupId>\n      <artifactId>slf4j-api</artifactId>\n    </dependency>\n  </dependencies>\n</project>\n",
+			dependency:      "org.slf4j:slf4j-api",
+			expectedLine:    3,
+			expectedColumn:  5,
+			expectedSnippet: "<dependency>\n      <groupId>org.slf4j</groupId>\n      <artifactId>slf4j-api</artifactId>\n    </dependency>",
+		},
+		{
+			descriptor:      "build.gradle",
+			content:         "dependencies {\n    implementation 'org.slf4j:slf4j-api:2.0.9'\n    testImplementation group: 'junit', name: 'junit', version: '4.13'\n}\n",
+			dependency:      "junit:junit",
+			expectedLine:    3,
+			expectedColumn:  24,
+			expectedSnippet: "group: 'junit', name: 'junit', version: '4.13'",
+		},
+		{
+			descriptor:      "build.gradle.kts",
+			content:         "dependencies {\n    implementation(\"org.slf4j:slf4j-api:2.0.9\")\n}\n",
+			dependency:      "org.slf4j:slf4j-api",
+			expectedLine:    2,
+			expectedColumn:  20,
+			expectedSnippet: `"org.slf4j:slf4j-api:2.0.9"`,
+		},
+		{
+			descriptor:      "go.mod",
+			content:         "module example.com/app\n\ngo 1.21\n\nrequire (\n\tgolang.org/x/net v0.17.0\n\tgolang.org/x/text v0.13.0 // indirect\n)\n",
+			dependency:      "golang.org/x/text",
+			expectedLine:    7,
+			expectedColumn:  2,
+			expectedSnippet: "golang.org/x/text v0.13.0",
+		},
+		{
+			descriptor:      "requirements.txt",
+			content:         "# Requirements\n--index-url https://pypi.org/simple\nrequests==2.31.0\n  PyYAML >= 6.0  # For the configuration\n",
+			dependency:      "pyyaml",
+			expectedLine:    4,
+			expectedColumn:  3,
+			expectedSnippet: "PyYAML >= 6.0",
+		},
+		{
+			descriptor:      "Pipfile",
+			content:         "[[source]]\nname = \"pypi\"\n\n[packages]\nrequests = \"*\"\n\n[dev-packages]\npytest = {version = \"*\"}\n",
+			dependency:      "pytest",
+			expectedLine:    8,
+			expectedColumn:  1,
+			expectedSnippet: `pytest = {version = "*"}`,
+		},
+		{
+			descriptor:      "pyproject.toml",
+			content:         "[tool.poetry]\nname = \"app\"\n\n[tool.poetry.dependencies]\npython = \"^3.9\"\nFlask = \"^3.0\"\n",
+			dependency:      "flask",
+			expectedLine:    6,
+			expectedColumn:  1,
+			expectedSnippet: `Flask = "^3.0"`,
+		},
+		{
+			descriptor:      "pyproject.toml",
+			content:         "[project]\nname = \"app\"\ndependencies = [\n  \"requests>=2.31\",\n  \"Typing_Extensions; python_version < '3.11'\",\n]\n\n[project.optional-dependencies]\ntest = [\"pytest\"]\n",
+			dependency:      "typing-extensions",
+			expectedLine:    5,
+			expectedColumn:  3,
+			expectedSnippet: `"Typing_Extensions; python_version < '3.11'"`,
+		},
+		{
+			descriptor:      "pyproject.toml",
+			content:         "[project]\nname = \"app\"\ndependencies = [\n  \"requests>=2.31\",\n]\n\n[project.optional-dependencies]\ntest = [\"pytest\"]\n",
+			dependency:      "pytest",
+			expectedLine:    8,
+			expectedColumn:  9,
+			expectedSnippet: `"pytest"`,
+		},
+		{
+			descriptor:      "app.csproj",
+			content:         "<Project Sdk=\"Microsoft.NET.Sdk\">\n  <ItemGroup>\n    <PackageReference Include=\"Newtonsoft.Json\" Version=\"13.0.1\" />\n  </ItemGroup>\n</Project>\n",
+			dependency:      "newtonsoft.json",
+			expectedLine:    3,
+			expectedColumn:  5,
+			expectedSnippet: `<PackageReference Include="Newtonsoft.Json" Version="13.0.1" />`,
+		},
+		{
+			descriptor:      "conanfile.txt",
+			content:         "[requires]\nzlib/1.2.13\nfmt/10.1.1 # Formatting\n\n[generators]\nCMakeDeps\n",
+			dependency:      "fmt",
+			expectedLine:    3,
+			expectedColumn:  1,
+			expectedSnippet: "fmt/10.1.1",
+		},
+		{
+			descriptor:      "conanfile.py",
+			content:         "class App(ConanFile):\n    def requirements(self):\n        self.requires(\"zlib/1.2.13\")\n",
+			dependency:      "zlib",
+			expectedLine:    3,
+			expectedColumn:  23,
+			expectedSnippet: `"zlib/1.2.13"`,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.descriptor+"/"+test.dependency, func(t *testing.T) {
+			descriptorPath := filepath.Join(t.TempDir(), test.descriptor)
+			require.NoError(t, os.WriteFile(descriptorPath, []byte(test.content), 0644))
+			location := NewDependenciesLocator().GetDependencyLocation(test.dependency, descriptorPath)
+			require.NotNil(t, location)
+			assert.Equal(t, descriptorPath, location.File)
+			assert.Equal(t, test.expectedLine, location.StartLine)
+			assert.Equal(t, test.expectedColumn, location.StartColumn)
+			assert.Equal(t, test.expectedSnippet, location.Snippet)
+		})
+	}
+}
+
+func TestGetDependencyLocationNotDeclared(t *testing.T) {
+	tempDir := t.TempDir()
+	packageJson := filepath.Join(tempDir, "package.json")
+	require.NoError(t, os.WriteFile(packageJson, []byte(`{"dependencies": {"lodash": "^4.17.0"}}`), 0644))
+	locator := NewDependenciesLocator()
+	assert.Nil(t, locator.GetDependencyLocation("express", packageJson))
+	// Unsupported and missing descriptors are skipped
+	assert.Nil(t, locator.GetDependencyLocation("lodash", filepath.Join(tempDir, "setup.py"), filepath.Join(tempDir, "missing", "package.json")))
+	assert.NotNil(t, locator.GetDependencyLocation("lodash", filepath.Join(tempDir, "setup.py"), packageJson))
+}
@@ -0,0 +1,24 @@
+package descriptorutils
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/modfile"
+)
+
+// Locates the required modules in a go.mod file by their paths.
+func parseGoMod(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	goMod, err := modfile.Parse(descriptorPath, content, nil)
+	if err != nil {
+		return locations, errorutils.CheckError(err)
+	}
+	lines := newLinesIndex(content)
+	for _, require := range goMod.Require {
+		if require.Syntax == nil {
+			continue
+		}
+		start, end := require.Syntax.Start.Byte, require.Syntax.End.Byte
+		locations.add(require.Mod.Path, lines.getLocation(content, descriptorPath, start, end))
+	}
+	return locations, nil
+}
@@ -0,0 +1,29 @@
+package descriptorutils
+
+import "regexp"
+
+var (
+	// A dependency in string notation, for example: 'org.slf4j:slf4j-api:2.0.9' or "org.slf4j:slf4j-api"
+	gradleStringNotationRegex = regexp.MustCompile(`(['"])([\w.\-]+):([\w.\-]+)(?::[^'"\s]*)?(['"])`)
+	// A dependency in map notation, for example: group: 'org.slf4j', name: 'slf4j-api', version: '2.0.9' or group = "org.slf4j", name = "slf4j-api"
+	gradleMapNotationRegex = regexp.MustCompile(`group\s*[:=]\s*['"]([\w.\-]+)['"]\s*,\s*name\s*[:=]\s*['"]([\w.\-]+)['"](?:\s*,\s*version\s*[:=]\s*['"][^'"]*['"])?`)
+)
+
+// Locates the dependencies declared in a build.gradle or build.gradle.kts file by groupId:artifactId.
+func parseBuildGradle(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	lines := newLinesIndex(content)
+	for _, match := range gradleStringNotationRegex.FindAllSubmatchIndex(content, -1) {
+		// The opening and closing quotes must match
+		if content[match[2]] != content[match[8]] {
+			continue
+		}
+		name := string(content[match[4]:match[5]]) + ":" + string(content[match[6]:match[7]])
+		locations.add(name, lines.getLocation(content, descriptorPath, match[0], match[1]))
+	}
+	for _, match := range gradleMapNotationRegex.FindAllSubmatchIndex(content, -1) {
+		name := string(content[match[2]:match[3]]) + ":" + string(content[match[4]:match[5]])
+		locations.add(name, lines.getLocation(content, descriptorPath, match[0], match[1]))
+	}
+	return locations, nil
+}
@@ -0,0 +1,76 @@
+package descriptorutils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/formats"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Parses the elements of a pom.xml file. Each element is passed to handleElement when it ends (after its child elements), with the names of the elements that lead to it, its trimmed text and its location.
+func ParsePomElements(pomPath string, content []byte, handleElement func(elementsPath []string, text string, location formats.Location)) error {
+	lines := newLinesIndex(content)
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	// Some poms are encoded in ISO-8859-1. The elements that are used here are ASCII anyway.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	var elementsPath []string
+	var startOffsets []int
+	var text strings.Builder
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errorutils.CheckErrorf("failed to parse %s: %s", pomPath, err.Error())
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			elementsPath = append(elementsPath, element.Name.Local)
+			startOffsets = append(startOffsets, offset)
+			text.Reset()
+		case xml.CharData:
+			text.Write(element)
+		case xml.EndElement:
+			if len(elementsPath) == 0 {
+				continue
+			}
+			handleElement(elementsPath, strings.TrimSpace(text.String()), lines.getLocation(content, pomPath, startOffsets[len(startOffsets)-1], int(decoder.InputOffset())))
+			elementsPath, startOffsets = elementsPath[:len(elementsPath)-1], startOffsets[:len(startOffsets)-1]
+			text.Reset()
+		}
+	}
+}
+
+// Locates the dependency elements of a pom.xml file by groupId:artifactId.
+// The dependencies of the project take precedence over its managed dependencies. Plugins dependencies are ignored.
+func parsePomXml(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	dependencies, managedDependencies := dependenciesLocations{}, dependenciesLocations{}
+	var groupId, artifactId string
+	err := ParsePomElements(descriptorPath, content, func(elementsPath []string, text string, location formats.Location) {
+		path := strings.Join(elementsPath, "/")
+		switch {
+		case strings.HasSuffix(path, "dependency/groupId"):
+			groupId = text
+		case strings.HasSuffix(path, "dependency/artifactId"):
+			artifactId = text
+		case strings.HasSuffix(path, "dependencies/dependency"):
+			switch {
+			case strings.Contains(path, "plugin"):
+			case strings.Contains(path, "dependencyManagement"):
+				managedDependencies.add(groupId+":"+artifactId, location)
+			default:
+				dependencies.add(groupId+":"+artifactId, location)
+			}
+			groupId, artifactId = "", ""
+		}
+	})
+	for name, location := range managedDependencies {
+		dependencies.add(name, *location)
+	}
+	return dependencies, err
+}
@@ -0,0 +1,78 @@
+package descriptorutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"slices"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+var packageJsonDependenciesSections = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// An object or an array while walking the JSON tokens
+type jsonScope struct {
+	isObject  bool
+	expectKey bool
+	key       string
+	keyStart  int
+}
+
+// Locates the dependencies in the dependencies sections of a package.json file. The location includes the name and the version range.
+func parsePackageJson(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	lines := newLinesIndex(content)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	var scopes []*jsonScope
+	for {
+		start := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return locations, errorutils.CheckError(err)
+		}
+		end := int(decoder.InputOffset())
+		var current *jsonScope
+		if len(scopes) > 0 {
+			current = scopes[len(scopes)-1]
+		}
+		if current != nil && current.isObject && current.expectKey {
+			if delim, isDelim := token.(json.Delim); !isDelim || delim != '}' {
+				current.key, _ = token.(string)
+				current.keyStart = skipJsonSeparators(content, start)
+				current.expectKey = false
+				continue
+			}
+		}
+		switch token {
+		case json.Delim('{'):
+			scopes = append(scopes, &jsonScope{isObject: true, expectKey: true})
+		case json.Delim('['):
+			scopes = append(scopes, &jsonScope{})
+		case json.Delim('}'), json.Delim(']'):
+			scopes = scopes[:len(scopes)-1]
+			if len(scopes) > 0 && scopes[len(scopes)-1].isObject {
+				scopes[len(scopes)-1].expectKey = true
+			}
+		default:
+			if _, isString := token.(string); isString && len(scopes) == 2 && slices.Contains(packageJsonDependenciesSections, scopes[0].key) {
+				locations.add(current.key, lines.getLocation(content, descriptorPath, current.keyStart, end))
+			}
+			if current != nil && current.isObject {
+				current.expectKey = true
+			}
+		}
+	}
+	return locations, nil
+}
+
+// The decoder offset before a token may point to the whitespaces and separators that precede it
+func skipJsonSeparators(content []byte, offset int) int {
+	for offset < len(content) && bytes.ContainsRune([]byte(" \t\r\n,:"), rune(content[offset])) {
+		offset++
+	}
+	return offset
+}
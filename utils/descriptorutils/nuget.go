@@ -0,0 +1,19 @@
+package descriptorutils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A package reference of a .csproj file, for example: <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+var csprojPackageReferenceRegex = regexp.MustCompile(`(?is)<PackageReference\s[^>]*?\bInclude\s*=\s*"([^"]+)"[^>]*?(?:/>|>.*?</PackageReference\s*>)`)
+
+// Locates the package references of a .csproj file by their lower-cased IDs.
+func parseCsproj(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	lines := newLinesIndex(content)
+	for _, match := range csprojPackageReferenceRegex.FindAllSubmatchIndex(content, -1) {
+		locations.add(strings.ToLower(string(content[match[2]:match[3]])), lines.getLocation(content, descriptorPath, match[0], match[1]))
+	}
+	return locations, nil
+}
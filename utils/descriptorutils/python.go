@@ -0,0 +1,135 @@
+package descriptorutils
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/formats"
+)
+
+var (
+	pythonNameSeparatorsRegex = regexp.MustCompile(`[-_.]+`)
+	// The name at the start of a requirement specifier (PEP 508)
+	pythonRequirementNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+	tomlSectionRegex           = regexp.MustCompile(`^\s*\[+\s*([^\]]+?)\s*\]+`)
+	tomlKeyRegex               = regexp.MustCompile(`^\s*(?:"([^"]+)"|'([^']+)'|([A-Za-z0-9._-]+))\s*=`)
+	tomlStringRegex            = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+	poetryDependenciesRegex    = regexp.MustCompile(`^tool\.poetry\.(?:dependencies|dev-dependencies|group\.[^.]+\.dependencies)$`)
+	// Sections of a Pipfile that don't declare packages
+	pipfileNonPackagesSections = []string{"source", "requires", "scripts", "pipenv"}
+	// Sections of a pyproject.toml file in which each array is a list of requirements
+	pyprojectRequirementsSections = []string{"project.optional-dependencies", "dependency-groups", "tool.pdm.dev-dependencies"}
+)
+
+// Python packages names are case-insensitive and treat runs of '-', '_' and '.' as equal (PEP 503)
+func normalizePythonName(name string) string {
+	return pythonNameSeparatorsRegex.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// Locates the requirements of a requirements.txt file. The location includes the whole requirement, without comments.
+func parseRequirementsTxt(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	linesOffsets := newLinesIndex(content)
+	lines, offsets := splitLines(content)
+	for i, line := range lines {
+		requirement := strings.TrimRight(strings.TrimSuffix(strings.Split(line, " #")[0], "\\"), " \t")
+		trimmed := strings.TrimLeft(requirement, " \t")
+		name := pythonRequirementNameRegex.FindString(trimmed)
+		if name == "" {
+			// Empty lines, comments and options
+			continue
+		}
+		start := offsets[i] + len(requirement) - len(trimmed)
+		locations.add(normalizePythonName(name), linesOffsets.getLocation(content, descriptorPath, start, offsets[i]+len(requirement)))
+	}
+	return locations, nil
+}
+
+// Locates the packages in the packages sections of a Pipfile. The location is the line of the package.
+func parsePipfile(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	linesOffsets := newLinesIndex(content)
+	lines, offsets := splitLines(content)
+	section := ""
+	for i, line := range lines {
+		if match := tomlSectionRegex.FindStringSubmatch(line); match != nil {
+			section = match[1]
+			continue
+		}
+		if section == "" || slices.Contains(pipfileNonPackagesSections, section) {
+			continue
+		}
+		if name := getTomlKey(line); name != "" {
+			locations.add(normalizePythonName(name), getTrimmedLineLocation(content, linesOffsets, descriptorPath, line, offsets[i]))
+		}
+	}
+	return locations, nil
+}
+
+// Locates the dependencies of a pyproject.toml file, declared in the Poetry dependencies tables or as PEP 508 requirements in the PEP 621, PEP 735, uv and PDM arrays.
+func parsePyprojectToml(descriptorPath string, content []byte) (dependenciesLocations, error) {
+	locations := dependenciesLocations{}
+	linesOffsets := newLinesIndex(content)
+	lines, offsets := splitLines(content)
+	section := ""
+	inRequirementsArray := false
+	for i, line := range lines {
+		if !inRequirementsArray {
+			if match := tomlSectionRegex.FindStringSubmatch(line); match != nil && !strings.Contains(line, "=") {
+				section = match[1]
+				continue
+			}
+			key := getTomlKey(line)
+			if key == "" {
+				continue
+			}
+			if poetryDependenciesRegex.MatchString(section) {
+				if key != "python" {
+					locations.add(normalizePythonName(key), getTrimmedLineLocation(content, linesOffsets, descriptorPath, line, offsets[i]))
+				}
+				continue
+			}
+			if !isPyprojectRequirementsArray(section, key) {
+				continue
+			}
+			// The array starts after the key
+			arrayStart := strings.Index(line, "=") + 1
+			inRequirementsArray = addTomlArrayRequirements(locations, content, linesOffsets, descriptorPath, line[arrayStart:], offsets[i]+arrayStart)
+			continue
+		}
+		inRequirementsArray = addTomlArrayRequirements(locations, content, linesOffsets, descriptorPath, line, offsets[i])
+	}
+	return locations, nil
+}
+
+func isPyprojectRequirementsArray(section, key string) bool {
+	return (section == "project" && key == "dependencies") ||
+		(section == "tool.uv" && key == "dev-dependencies") ||
+		slices.Contains(pyprojectRequirementsSections, section)
+}
+
+// Adds the requirements quoted in the line of a TOML array. Returns false if the array ends in this line.
+func addTomlArrayRequirements(locations dependenciesLocations, content []byte, linesOffsets linesIndex, descriptorPath, line string, lineOffset int) (inArray bool) {
+	for _, match := range tomlStringRegex.FindAllStringSubmatchIndex(line, -1) {
+		requirement := line[match[0]+1 : match[1]-1]
+		if name := pythonRequirementNameRegex.FindString(strings.TrimSpace(requirement)); name != "" {
+			locations.add(normalizePythonName(name), linesOffsets.getLocation(content, descriptorPath, lineOffset+match[0], lineOffset+match[1]))
+		}
+	}
+	return !strings.Contains(tomlStringRegex.ReplaceAllString(line, ""), "]")
+}
+
+func getTomlKey(line string) string {
+	match := tomlKeyRegex.FindStringSubmatch(line)
+	if match == nil {
+		return ""
+	}
+	return match[1] + match[2] + match[3]
+}
+
+func getTrimmedLineLocation(content []byte, linesOffsets linesIndex, descriptorPath, line string, lineOffset int) formats.Location {
+	trimmed := strings.TrimSpace(line)
+	start := lineOffset + strings.Index(line, trimmed)
+	return linesOffsets.getLocation(content, descriptorPath, start, start+len(trimmed))
+}
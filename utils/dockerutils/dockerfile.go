@@ -0,0 +1,143 @@
+package dockerutils
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	fromInstruction = "FROM"
+	argInstruction  = "ARG"
+	scratchImage    = "scratch"
+)
+
+// A single instruction in a Dockerfile, including its location (1-based lines) in the file.
+type DockerfileInstruction struct {
+	Command   string `json:"command"`
+	Value     string `json:"value"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+}
+
+func (di DockerfileInstruction) String() string {
+	return strings.TrimSpace(di.Command + " " + di.Value)
+}
+
+// A build stage in a Dockerfile, starting with a FROM instruction.
+type DockerfileStage struct {
+	From         DockerfileInstruction
+	BaseImage    string
+	Name         string
+	Instructions []DockerfileInstruction
+}
+
+// Parse the Dockerfile at the given path into its build stages.
+func ParseDockerfile(dockerfilePath string) (stages []DockerfileStage, err error) {
+	file, err := os.Open(dockerfilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	instructions, err := readDockerfileInstructions(bufio.NewScanner(file))
+	if err != nil {
+		return
+	}
+	for _, instruction := range instructions {
+		if instruction.Command == fromInstruction {
+			baseImage, name := parseFromValue(instruction.Value)
+			stages = append(stages, DockerfileStage{From: instruction, BaseImage: baseImage, Name: name})
+			continue
+		}
+		if len(stages) == 0 {
+			// Global ARG instructions before the first FROM
+			continue
+		}
+		stages[len(stages)-1].Instructions = append(stages[len(stages)-1].Instructions, instruction)
+	}
+	return
+}
+
+func readDockerfileInstructions(scanner *bufio.Scanner) (instructions []DockerfileInstruction, err error) {
+	var current *DockerfileInstruction
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if current == nil && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		continued := strings.HasSuffix(line, "\\")
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\\"))
+		if current == nil {
+			command, value, _ := strings.Cut(line, " ")
+			current = &DockerfileInstruction{Command: strings.ToUpper(command), Value: strings.TrimSpace(value), StartLine: lineNumber}
+		} else if line != "" && !strings.HasPrefix(line, "#") {
+			current.Value = strings.TrimSpace(current.Value + " " + line)
+		}
+		current.EndLine = lineNumber
+		if !continued {
+			instructions = append(instructions, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		instructions = append(instructions, *current)
+	}
+	err = errorutils.CheckError(scanner.Err())
+	return
+}
+
+// FROM [--platform=<platform>] <image> [AS <name>]
+func parseFromValue(value string) (baseImage, name string) {
+	var fields []string
+	for _, field := range strings.Fields(value) {
+		if !strings.HasPrefix(field, "--") {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	baseImage = fields[0]
+	if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+		name = fields[2]
+	}
+	return
+}
+
+// Get the instructions that were used to build the final image (after the base image), with the external base image it was built on.
+// If the final stage is based on a previous stage, the instructions of the previous stage are included as well.
+func GetFinalImageInstructions(stages []DockerfileStage) (baseImage string, from *DockerfileInstruction, instructions []DockerfileInstruction) {
+	if len(stages) == 0 {
+		return
+	}
+	current := len(stages) - 1
+	for current >= 0 {
+		stage := stages[current]
+		instructions = append(append([]DockerfileInstruction{}, stage.Instructions...), instructions...)
+		previous := getStageIndexByName(stages[:current], stage.BaseImage)
+		if previous < 0 {
+			baseImage = stage.BaseImage
+			from = &stages[current].From
+			return
+		}
+		current = previous
+	}
+	return
+}
+
+func getStageIndexByName(stages []DockerfileStage, name string) int {
+	for i := len(stages) - 1; i >= 0; i-- {
+		if stages[i].Name != "" && strings.EqualFold(stages[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,73 @@
+package dockerutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const multiStageDockerfile = `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.22
+
+FROM golang:${GO_VERSION} AS builder
+WORKDIR /src
+COPY . .
+RUN go build \
+    -o /app .
+
+FROM --platform=linux/amd64 alpine:3.19 AS base
+RUN apk add --no-cache ca-certificates
+
+FROM base
+ARG USER=app
+COPY --from=builder /app /app
+ENTRYPOINT ["/app"]
+`
+
+func createDockerfile(t *testing.T, content string) string {
+	dockerfilePath := filepath.Join(t.TempDir(), "Dockerfile")
+	assert.NoError(t, os.WriteFile(dockerfilePath, []byte(content), 0644))
+	return dockerfilePath
+}
+
+func TestParseDockerfile(t *testing.T) {
+	stages, err := ParseDockerfile(createDockerfile(t, multiStageDockerfile))
+	assert.NoError(t, err)
+	if !assert.Len(t, stages, 3) {
+		return
+	}
+	assert.Equal(t, "golang:${GO_VERSION}", stages[0].BaseImage)
+	assert.Equal(t, "builder", stages[0].Name)
+	assert.Equal(t, []DockerfileInstruction{
+		{Command: "WORKDIR", Value: "/src", StartLine: 5, EndLine: 5},
+		{Command: "COPY", Value: ". .", StartLine: 6, EndLine: 6},
+		{Command: "RUN", Value: "go build -o /app .", StartLine: 7, EndLine: 8},
+	}, stages[0].Instructions)
+	assert.Equal(t, "alpine:3.19", stages[1].BaseImage)
+	assert.Equal(t, "base", stages[1].Name)
+	assert.Equal(t, 10, stages[1].From.StartLine)
+	assert.Equal(t, "base", stages[2].BaseImage)
+	assert.Empty(t, stages[2].Name)
+}
+
+func TestGetFinalImageInstructions(t *testing.T) {
+	stages, err := ParseDockerfile(createDockerfile(t, multiStageDockerfile))
+	assert.NoError(t, err)
+	baseImage, from, instructions := GetFinalImageInstructions(stages)
+	assert.Equal(t, "alpine:3.19", baseImage)
+	if assert.NotNil(t, from) {
+		assert.Equal(t, 10, from.StartLine)
+	}
+	var commands []string
+	for _, instruction := range instructions {
+		commands = append(commands, instruction.Command)
+	}
+	assert.Equal(t, []string{"RUN", "ARG", "COPY", "ENTRYPOINT"}, commands)
+
+	baseImage, from, instructions = GetFinalImageInstructions(nil)
+	assert.Empty(t, baseImage)
+	assert.Nil(t, from)
+	assert.Empty(t, instructions)
+}
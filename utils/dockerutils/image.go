@@ -0,0 +1,356 @@
+package dockerutils
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	dockerManifestFileName = "manifest.json"
+	ociIndexFileName       = "index.json"
+	ociBlobsDir            = "blobs"
+	// Metadata files (manifests, configs) are small, layers bigger than this size are not read into memory.
+	maxMetadataFileSize = 4 * 1024 * 1024
+	buildkitSuffix      = "# buildkit"
+	nopPrefix           = "#(nop)"
+	// Set by BuildKit and other builders to the reference of the base image
+	baseImageNameLabel = "org.opencontainers.image.base.name"
+)
+
+var dockerfileCommands = []string{"ADD", "ARG", "CMD", "COPY", "ENTRYPOINT", "ENV", "EXPOSE", "HEALTHCHECK", "LABEL", "MAINTAINER", "ONBUILD", "RUN", "SHELL", "STOPSIGNAL", "USER", "VOLUME", "WORKDIR"}
+
+type LayerOrigin string
+
+const (
+	// The layer was inherited from the base image of the Dockerfile.
+	BaseImageLayer LayerOrigin = "base_image"
+	// The layer was created by an instruction in the Dockerfile.
+	InstructionLayer LayerOrigin = "dockerfile_instruction"
+)
+
+type LayerInfo struct {
+	// All the known digests of the layer (diff ID and archive blob digest), without the algorithm prefix.
+	Digests   []string `json:"digests"`
+	Algorithm string   `json:"algorithm,omitempty"`
+	// The command that created the layer, taken from the image history.
+	CreatedBy string      `json:"createdBy,omitempty"`
+	Origin    LayerOrigin `json:"origin,omitempty"`
+	BaseImage string      `json:"baseImage,omitempty"`
+	// The Dockerfile instruction that created the layer. For layers inherited from the base image, this is the FROM instruction.
+	Instruction *DockerfileInstruction `json:"instruction,omitempty"`
+}
+
+type ImageLayers struct {
+	DockerfilePath string    `json:"dockerfilePath,omitempty"`
+	BaseImage      string    `json:"baseImage,omitempty"`
+	History        []History `json:"-"`
+	// The diff IDs of the image layers, from the lowest layer to the top one.
+	DiffIds []string     `json:"-"`
+	Layers  []*LayerInfo `json:"layers,omitempty"`
+}
+
+type History struct {
+	CreatedBy  string `json:"created_by,omitempty"`
+	EmptyLayer bool   `json:"empty_layer,omitempty"`
+}
+
+type imageConfig struct {
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+	History []History `json:"history"`
+	RootFs  struct {
+		DiffIds []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+type dockerManifest struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	Config ociDescriptor   `json:"config"`
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// Get the layer with the given digest (without the algorithm prefix), or nil if not exists.
+func (il *ImageLayers) GetLayer(digest string) *LayerInfo {
+	if il == nil || digest == "" {
+		return nil
+	}
+	for _, layer := range il.Layers {
+		for _, layerDigest := range layer.Digests {
+			if layerDigest == digest {
+				return layer
+			}
+		}
+	}
+	return nil
+}
+
+// Read the layers and their history from an image tarball (docker-archive or oci-archive).
+func ReadImageLayers(imageTarPath string) (imageLayers *ImageLayers, err error) {
+	files, err := readMetadataFiles(imageTarPath)
+	if err != nil {
+		return
+	}
+	var config imageConfig
+	var layersPaths []string
+	if manifestContent, exists := files[dockerManifestFileName]; exists {
+		config, layersPaths, err = readDockerArchiveConfig(manifestContent, files)
+	} else if indexContent, exists := files[ociIndexFileName]; exists {
+		config, layersPaths, err = readOciArchiveConfig(indexContent, files)
+	} else {
+		err = errorutils.CheckErrorf("couldn't find '%s' or '%s' in the image archive %s", dockerManifestFileName, ociIndexFileName, imageTarPath)
+	}
+	if err != nil {
+		return
+	}
+	imageLayers = &ImageLayers{History: config.History, BaseImage: config.Config.Labels[baseImageNameLabel], DiffIds: config.RootFs.DiffIds}
+	layerIndex := 0
+	for _, history := range config.History {
+		if history.EmptyLayer {
+			continue
+		}
+		layer := &LayerInfo{CreatedBy: history.CreatedBy}
+		if layerIndex < len(config.RootFs.DiffIds) {
+			layer.Algorithm, layer.Digests = appendDigest(layer.Digests, config.RootFs.DiffIds[layerIndex])
+		}
+		if layerIndex < len(layersPaths) {
+			_, layer.Digests = appendDigest(layer.Digests, layersPaths[layerIndex])
+		}
+		imageLayers.Layers = append(imageLayers.Layers, layer)
+		layerIndex++
+	}
+	return
+}
+
+// Returns true if the image was built on top of the image with the given layers diff IDs, i.e. the layers of the image start with them.
+func (il *ImageLayers) IsBuiltOn(baseDiffIds []string) bool {
+	if il == nil || len(baseDiffIds) == 0 || len(baseDiffIds) > len(il.DiffIds) {
+		return false
+	}
+	for i, diffId := range baseDiffIds {
+		if il.DiffIds[i] != diffId {
+			return false
+		}
+	}
+	return true
+}
+
+// Accepts 'sha256:<hash>', 'blobs/sha256/<hash>' or '<hash>/layer.tar'
+func appendDigest(digests []string, digest string) (algorithm string, updated []string) {
+	updated = digests
+	if algorithm, hash, found := strings.Cut(digest, ":"); found {
+		return algorithm, append(updated, hash)
+	}
+	parts := strings.Split(strings.TrimSuffix(digest, "/layer.tar"), "/")
+	if len(parts) >= 3 && parts[len(parts)-3] == ociBlobsDir {
+		algorithm = parts[len(parts)-2]
+	}
+	return algorithm, append(updated, parts[len(parts)-1])
+}
+
+func readMetadataFiles(imageTarPath string) (files map[string][]byte, err error) {
+	imageTar, err := os.Open(imageTarPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := imageTar.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	files = map[string][]byte{}
+	tarReader := tar.NewReader(imageTar)
+	for {
+		header, e := tarReader.Next()
+		if e == io.EOF {
+			return
+		}
+		if e != nil {
+			return nil, errorutils.CheckError(e)
+		}
+		if header.Typeflag != tar.TypeReg || header.Size > maxMetadataFileSize {
+			continue
+		}
+		content, e := io.ReadAll(tarReader)
+		if e != nil {
+			return nil, errorutils.CheckError(e)
+		}
+		files[path.Clean(header.Name)] = content
+	}
+}
+
+func readDockerArchiveConfig(manifestContent []byte, files map[string][]byte) (config imageConfig, layersPaths []string, err error) {
+	var manifests []dockerManifest
+	if err = errorutils.CheckError(json.Unmarshal(manifestContent, &manifests)); err != nil {
+		return
+	}
+	if len(manifests) == 0 {
+		err = errorutils.CheckErrorf("the image archive manifest is empty")
+		return
+	}
+	if err = unmarshalArchiveFile(files, manifests[0].Config, &config); err != nil {
+		return
+	}
+	return config, manifests[0].Layers, nil
+}
+
+func readOciArchiveConfig(indexContent []byte, files map[string][]byte) (config imageConfig, layersPaths []string, err error) {
+	var index ociIndex
+	if err = errorutils.CheckError(json.Unmarshal(indexContent, &index)); err != nil {
+		return
+	}
+	// The index may point to a nested index (multi-platform images), use the first manifest.
+	for len(index.Manifests) > 0 && strings.HasSuffix(index.Manifests[0].MediaType, "image.index.v1+json") {
+		nested := ociIndex{}
+		if err = unmarshalArchiveFile(files, digestToBlobPath(index.Manifests[0].Digest), &nested); err != nil {
+			return
+		}
+		index = nested
+	}
+	if len(index.Manifests) == 0 {
+		err = errorutils.CheckErrorf("the image archive index is empty")
+		return
+	}
+	var manifest ociManifest
+	if err = unmarshalArchiveFile(files, digestToBlobPath(index.Manifests[0].Digest), &manifest); err != nil {
+		return
+	}
+	if err = unmarshalArchiveFile(files, digestToBlobPath(manifest.Config.Digest), &config); err != nil {
+		return
+	}
+	for _, layer := range manifest.Layers {
+		layersPaths = append(layersPaths, layer.Digest)
+	}
+	return
+}
+
+func digestToBlobPath(digest string) string {
+	return path.Join(ociBlobsDir, strings.Replace(digest, ":", "/", 1))
+}
+
+func unmarshalArchiveFile(files map[string][]byte, filePath string, target any) error {
+	content, exists := files[path.Clean(filePath)]
+	if !exists {
+		return errorutils.CheckErrorf("couldn't find '%s' in the image archive", filePath)
+	}
+	return errorutils.CheckError(json.Unmarshal(content, target))
+}
+
+// Match the image history with the instructions of the given Dockerfile, from the last instruction backwards.
+// Layers created before the first instruction of the final stage are attributed to the base image.
+func (il *ImageLayers) AttributeToDockerfile(dockerfilePath string) error {
+	stages, err := ParseDockerfile(dockerfilePath)
+	if err != nil {
+		return err
+	}
+	baseImage, from, instructions := GetFinalImageInstructions(stages)
+	if from == nil {
+		return errorutils.CheckErrorf("couldn't find a FROM instruction in %s", dockerfilePath)
+	}
+	historyToInstruction, baseHistoryCount, matched := matchHistoryToInstructions(il.History, instructions)
+	if !matched {
+		log.Debug(fmt.Sprintf("The image history doesn't match the instructions of %s, skipping layers attribution.", dockerfilePath))
+		return nil
+	}
+	il.DockerfilePath = dockerfilePath
+	if !strings.EqualFold(baseImage, scratchImage) {
+		il.BaseImage = baseImage
+	}
+	layerIndex := 0
+	for historyIndex, history := range il.History {
+		if history.EmptyLayer {
+			continue
+		}
+		if layerIndex >= len(il.Layers) {
+			break
+		}
+		layer := il.Layers[layerIndex]
+		layerIndex++
+		if historyIndex < baseHistoryCount {
+			layer.Origin = BaseImageLayer
+			layer.BaseImage = il.BaseImage
+			layer.Instruction = from
+			continue
+		}
+		if instructionIndex, exists := historyToInstruction[historyIndex]; exists {
+			layer.Origin = InstructionLayer
+			layer.Instruction = &instructions[instructionIndex]
+		}
+	}
+	return nil
+}
+
+// Returns a map between history entries indexes to the matching instructions indexes, and the number of history entries that belong to the base image.
+func matchHistoryToInstructions(history []History, instructions []DockerfileInstruction) (historyToInstruction map[int]int, baseHistoryCount int, matched bool) {
+	historyToInstruction = map[int]int{}
+	historyIndex, instructionIndex := len(history)-1, len(instructions)-1
+	for historyIndex >= 0 && instructionIndex >= 0 {
+		if GetHistoryCommand(history[historyIndex].CreatedBy) == instructions[instructionIndex].Command {
+			historyToInstruction[historyIndex] = instructionIndex
+			historyIndex--
+			instructionIndex--
+			continue
+		}
+		if instructions[instructionIndex].Command != argInstruction {
+			// ARG instructions are not recorded in the history by BuildKit, any other mismatch means the image wasn't built from this Dockerfile.
+			return nil, 0, false
+		}
+		instructionIndex--
+	}
+	for instructionIndex >= 0 && instructions[instructionIndex].Command == argInstruction {
+		instructionIndex--
+	}
+	if instructionIndex >= 0 {
+		return nil, 0, false
+	}
+	return historyToInstruction, historyIndex + 1, true
+}
+
+// Get the Dockerfile command from the 'created_by' field of an image history entry.
+// Supports both the legacy builder ('/bin/sh -c #(nop)  CMD ["sh"]') and BuildKit ('CMD ["sh"]', 'RUN /bin/sh -c ... # buildkit') formats.
+func GetHistoryCommand(createdBy string) string {
+	createdBy = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(createdBy), buildkitSuffix))
+	if strings.HasPrefix(createdBy, "|") {
+		// Legacy builder RUN with build arguments: '|1 ARG=value /bin/sh -c ...'
+		return "RUN"
+	}
+	for _, shellPrefix := range []string{"/bin/sh -c ", "cmd /S /C "} {
+		if strings.HasPrefix(createdBy, shellPrefix) {
+			createdBy = strings.TrimSpace(strings.TrimPrefix(createdBy, shellPrefix))
+			if !strings.HasPrefix(createdBy, nopPrefix) {
+				return "RUN"
+			}
+			createdBy = strings.TrimSpace(strings.TrimPrefix(createdBy, nopPrefix))
+			break
+		}
+	}
+	command, _, _ := strings.Cut(createdBy, " ")
+	command = strings.ToUpper(command)
+	for _, dockerfileCommand := range dockerfileCommands {
+		if command == dockerfileCommand {
+			return command
+		}
+	}
+	return "RUN"
+}
@@ -0,0 +1,137 @@
+package dockerutils
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testImageConfig = `{
+  "history": [
+    {"created_by": "/bin/sh -c #(nop) ADD file:1234 in / "},
+    {"created_by": "/bin/sh -c #(nop)  CMD [\"/bin/sh\"]", "empty_layer": true},
+    {"created_by": "RUN /bin/sh -c apk add --no-cache ca-certificates # buildkit"},
+    {"created_by": "COPY /app /app # buildkit"},
+    {"created_by": "ENTRYPOINT [\"/app\"]", "empty_layer": true}
+  ],
+  "rootfs": {"type": "layers", "diff_ids": ["sha256:aaaa", "sha256:bbbb", "sha256:cccc"]}
+}`
+	testDockerManifest = `[{"Config": "blobs/sha256/config", "Layers": ["blobs/sha256/1111", "blobs/sha256/2222", "blobs/sha256/3333"]}]`
+)
+
+func createImageArchive(t *testing.T, files map[string]string) string {
+	archivePath := filepath.Join(t.TempDir(), "image.tar")
+	archive, err := os.Create(archivePath)
+	assert.NoError(t, err)
+	tarWriter := tar.NewWriter(archive)
+	for name, content := range files {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err = tarWriter.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, archive.Close())
+	return archivePath
+}
+
+func TestReadImageLayers(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name:  "Docker archive",
+			files: map[string]string{"manifest.json": testDockerManifest, "blobs/sha256/config": testImageConfig},
+		},
+		{
+			name: "OCI archive",
+			files: map[string]string{
+				"index.json":            `{"manifests": [{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:manifest"}]}`,
+				"blobs/sha256/manifest": `{"config": {"digest": "sha256:config"}, "layers": [{"digest": "sha256:1111"}, {"digest": "sha256:2222"}, {"digest": "sha256:3333"}]}`,
+				"blobs/sha256/config":   testImageConfig,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			imageLayers, err := ReadImageLayers(createImageArchive(t, tc.files))
+			assert.NoError(t, err)
+			if !assert.Len(t, imageLayers.Layers, 3) {
+				return
+			}
+			assert.Equal(t, []string{"aaaa", "1111"}, imageLayers.Layers[0].Digests)
+			assert.Equal(t, "sha256", imageLayers.Layers[0].Algorithm)
+			assert.Equal(t, imageLayers.Layers[1], imageLayers.GetLayer("2222"))
+			assert.Equal(t, imageLayers.Layers[2], imageLayers.GetLayer("cccc"))
+			assert.Nil(t, imageLayers.GetLayer("dddd"))
+		})
+	}
+
+	_, err := ReadImageLayers(createImageArchive(t, map[string]string{"layer.tar": ""}))
+	assert.Error(t, err)
+}
+
+func TestAttributeToDockerfile(t *testing.T) {
+	imageLayers, err := ReadImageLayers(createImageArchive(t, map[string]string{"manifest.json": testDockerManifest, "blobs/sha256/config": testImageConfig}))
+	assert.NoError(t, err)
+	dockerfilePath := createDockerfile(t, multiStageDockerfile)
+	assert.NoError(t, imageLayers.AttributeToDockerfile(dockerfilePath))
+
+	assert.Equal(t, dockerfilePath, imageLayers.DockerfilePath)
+	assert.Equal(t, "alpine:3.19", imageLayers.BaseImage)
+	baseLayer := imageLayers.GetLayer("aaaa")
+	assert.Equal(t, BaseImageLayer, baseLayer.Origin)
+	assert.Equal(t, "alpine:3.19", baseLayer.BaseImage)
+	assert.Equal(t, 10, baseLayer.Instruction.StartLine)
+	runLayer := imageLayers.GetLayer("bbbb")
+	assert.Equal(t, InstructionLayer, runLayer.Origin)
+	assert.Equal(t, "RUN apk add --no-cache ca-certificates", runLayer.Instruction.String())
+	assert.Equal(t, 11, runLayer.Instruction.StartLine)
+	copyLayer := imageLayers.GetLayer("cccc")
+	assert.Equal(t, InstructionLayer, copyLayer.Origin)
+	assert.Equal(t, 15, copyLayer.Instruction.StartLine)
+}
+
+func TestAttributeToDockerfileMismatch(t *testing.T) {
+	imageLayers, err := ReadImageLayers(createImageArchive(t, map[string]string{"manifest.json": testDockerManifest, "blobs/sha256/config": testImageConfig}))
+	assert.NoError(t, err)
+	assert.NoError(t, imageLayers.AttributeToDockerfile(createDockerfile(t, "FROM alpine:3.19\nUSER app\nENTRYPOINT [\"/app\"]\n")))
+	assert.Empty(t, imageLayers.DockerfilePath)
+	for _, layer := range imageLayers.Layers {
+		assert.Empty(t, layer.Origin)
+		assert.Nil(t, layer.Instruction)
+	}
+}
+
+func TestGetHistoryCommand(t *testing.T) {
+	testCases := []struct {
+		createdBy string
+		expected  string
+	}{
+		{createdBy: "/bin/sh -c #(nop)  CMD [\"sh\"]", expected: "CMD"},
+		{createdBy: "/bin/sh -c #(nop) COPY file:abc in /app ", expected: "COPY"},
+		{createdBy: "/bin/sh -c apk add curl", expected: "RUN"},
+		{createdBy: "|1 VERSION=1.0 /bin/sh -c make", expected: "RUN"},
+		{createdBy: "RUN /bin/sh -c make # buildkit", expected: "RUN"},
+		{createdBy: "WORKDIR /app", expected: "WORKDIR"},
+		{createdBy: "ENV PATH=/usr/bin", expected: "ENV"},
+		{createdBy: "make install", expected: "RUN"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.createdBy, func(t *testing.T) {
+			assert.Equal(t, tc.expected, GetHistoryCommand(tc.createdBy))
+		})
+	}
+}
+
+func TestIsBuiltOn(t *testing.T) {
+	imageLayers := &ImageLayers{DiffIds: []string{"sha256:base1", "sha256:base2", "sha256:app"}}
+	assert.True(t, imageLayers.IsBuiltOn([]string{"sha256:base1", "sha256:base2"}))
+	assert.False(t, imageLayers.IsBuiltOn([]string{"sha256:base1", "sha256:other"}))
+	assert.False(t, imageLayers.IsBuiltOn([]string{"sha256:base1", "sha256:base2", "sha256:app", "sha256:more"}))
+	assert.False(t, imageLayers.IsBuiltOn(nil))
+}
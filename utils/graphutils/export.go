@@ -0,0 +1,198 @@
+package graphutils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type GraphFormat string
+
+const (
+	Json    GraphFormat = "json"
+	Dot     GraphFormat = "dot"
+	GraphML GraphFormat = "graphml"
+)
+
+var GraphFormats = []string{string(Json), string(Dot), string(GraphML)}
+
+// Write the dependency graphs to the given file path in the requested format.
+func ExportDependencyGraphs(graphs []*DependencyGraph, format GraphFormat, path string) (err error) {
+	var content []byte
+	switch format {
+	case Json, "":
+		content, err = toJson(graphs)
+	case Dot:
+		content = []byte(toDot(graphs))
+	case GraphML:
+		content, err = toGraphML(graphs)
+	default:
+		return errorutils.CheckErrorf("unsupported graph format '%s'. Supported formats: %s", format, strings.Join(GraphFormats, ", "))
+	}
+	if err != nil {
+		return
+	}
+	if err = errorutils.CheckError(os.WriteFile(path, content, 0644)); err != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("The dependency graph of %d modules was exported to: %s", len(graphs), path))
+	return
+}
+
+func toJson(graphs []*DependencyGraph) ([]byte, error) {
+	if graphs == nil {
+		graphs = []*DependencyGraph{}
+	}
+	content, err := json.MarshalIndent(graphs, "", "  ")
+	return content, errorutils.CheckError(err)
+}
+
+// All the modules are written into a single digraph, each module in its own cluster.
+// The node IDs are prefixed with the cluster index since the same dependency may appear in several modules.
+func toDot(graphs []*DependencyGraph) string {
+	builder := &strings.Builder{}
+	builder.WriteString("digraph dependencies {\n")
+	builder.WriteString("  node [shape=box];\n")
+	for i, graph := range graphs {
+		builder.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", i))
+		builder.WriteString(fmt.Sprintf("    label=%s;\n", dotQuote(fmt.Sprintf("%s (%s)", graph.Module, graph.Technology.ToFormal()))))
+		for _, node := range graph.Nodes {
+			attributes := fmt.Sprintf("label=%s", dotQuote(getDotLabel(node)))
+			if node.Vulnerabilities > 0 {
+				attributes += ", color=red"
+			}
+			builder.WriteString(fmt.Sprintf("    %s [%s];\n", dotQuote(fmt.Sprintf("%d:%s", i, node.Id)), attributes))
+		}
+		for _, edge := range graph.Edges {
+			builder.WriteString(fmt.Sprintf("    %s -> %s;\n", dotQuote(fmt.Sprintf("%d:%s", i, edge.From)), dotQuote(fmt.Sprintf("%d:%s", i, edge.To))))
+		}
+		builder.WriteString("  }\n")
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func getDotLabel(node DependencyGraphNode) string {
+	label := node.Name
+	if node.Version != "" {
+		label += ":" + node.Version
+	}
+	if node.Classifier != "" {
+		label += " (" + node.Classifier + ")"
+	}
+	if len(node.Types) > 0 {
+		label += "\n[" + strings.Join(node.Types, ", ") + "]"
+	}
+	if node.Vulnerabilities > 0 {
+		label += fmt.Sprintf("\n%d vulnerabilities (%s)", node.Vulnerabilities, node.Severity)
+	}
+	return label
+}
+
+func dotQuote(value string) string {
+	return "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value) + "\""
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graphs  []graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	Id       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	Id          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphMLData `xml:"data"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	Id   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+var graphMLKeys = []graphMLKey{
+	{Id: "technology", For: "graph", AttrName: "technology", AttrType: "string"},
+	{Id: "target", For: "graph", AttrName: "target", AttrType: "string"},
+	{Id: "name", For: "node", AttrName: "name", AttrType: "string"},
+	{Id: "version", For: "node", AttrName: "version", AttrType: "string"},
+	{Id: "types", For: "node", AttrName: "types", AttrType: "string"},
+	{Id: "classifier", For: "node", AttrName: "classifier", AttrType: "string"},
+	{Id: "scopes", For: "node", AttrName: "scopes", AttrType: "string"},
+	{Id: "vulnerabilities", For: "node", AttrName: "vulnerabilities", AttrType: "int"},
+	{Id: "severity", For: "node", AttrName: "severity", AttrType: "string"},
+}
+
+// Each module is written as a separate graph. GraphML requires the IDs to be unique in the document, so the graphs IDs are prefixed with their targets
+// (modules with the same coordinates may be found in several targets) and the node IDs are prefixed with the ID of their graph.
+func toGraphML(graphs []*DependencyGraph) ([]byte, error) {
+	document := graphMLDocument{Xmlns: "http://graphml.graphdrawing.org/xmlns", Keys: graphMLKeys}
+	graphsIds := datastructures.MakeSet[string]()
+	for i, graph := range graphs {
+		graphId := graph.Target + ":" + graph.Module
+		if graphsIds.Exists(graphId) {
+			graphId = fmt.Sprintf("%s#%d", graphId, i)
+		}
+		graphsIds.Add(graphId)
+		graphElement := graphMLGraph{
+			Id:          graphId,
+			EdgeDefault: "directed",
+			Data:        []graphMLData{{Key: "technology", Value: graph.Technology.String()}, {Key: "target", Value: graph.Target}},
+		}
+		for _, node := range graph.Nodes {
+			graphElement.Nodes = append(graphElement.Nodes, graphMLNode{Id: graphId + "/" + node.Id, Data: getGraphMLNodeData(node)})
+		}
+		for _, edge := range graph.Edges {
+			graphElement.Edges = append(graphElement.Edges, graphMLEdge{Source: graphId + "/" + edge.From, Target: graphId + "/" + edge.To})
+		}
+		document.Graphs = append(document.Graphs, graphElement)
+	}
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func getGraphMLNodeData(node DependencyGraphNode) (data []graphMLData) {
+	addData := func(key, value string) {
+		if value != "" {
+			data = append(data, graphMLData{Key: key, Value: value})
+		}
+	}
+	addData("name", node.Name)
+	addData("version", node.Version)
+	addData("types", strings.Join(node.Types, ","))
+	addData("classifier", node.Classifier)
+	addData("scopes", strings.Join(node.Scopes, ","))
+	if node.Vulnerabilities > 0 {
+		addData("vulnerabilities", fmt.Sprintf("%d", node.Vulnerabilities))
+		addData("severity", node.Severity)
+	}
+	return
+}
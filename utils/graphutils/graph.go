@@ -0,0 +1,113 @@
+package graphutils
+
+import (
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// The resolved dependency graph of a single module (root) of an audited project.
+type DependencyGraph struct {
+	Target     string                `json:"target"`
+	Technology techutils.Technology  `json:"technology"`
+	Module     string                `json:"module"`
+	Nodes      []DependencyGraphNode `json:"nodes"`
+	Edges      []DependencyGraphEdge `json:"edges"`
+}
+
+type DependencyGraphNode struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Classifier string   `json:"classifier,omitempty"`
+	Scopes     []string `json:"scopes,omitempty"`
+	// The number of vulnerabilities (and security violations) of the dependency and their highest severity, if scanned.
+	Vulnerabilities int    `json:"vulnerabilities,omitempty"`
+	Severity        string `json:"severity,omitempty"`
+}
+
+type DependencyGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type componentIssues struct {
+	issues   *datastructures.Set[string]
+	severity severityutils.Severity
+}
+
+// Create the dependency graphs of all the modules of the audited projects, annotated with the vulnerabilities found by the scan.
+func NewDependencyGraphs(results *utils.Results) (graphs []*DependencyGraph) {
+	for _, scaResult := range results.ScaResults {
+		issues := getComponentsIssues(scaResult.XrayResults)
+		for _, tree := range scaResult.DependencyTrees {
+			graph := &DependencyGraph{Target: scaResult.Target, Technology: scaResult.Technology, Module: tree.Id}
+			graph.addNode(tree, datastructures.MakeSet[string](), datastructures.MakeSet[string](), issues, scaResult.DependenciesScopes)
+			graphs = append(graphs, graph)
+		}
+	}
+	return
+}
+
+// Add the node and its descendants to the graph. The same dependency may appear in several paths of the tree, but it's added once.
+func (dg *DependencyGraph) addNode(node *xrayUtils.GraphNode, addedNodes, addedEdges *datastructures.Set[string], issues map[string]*componentIssues, dependenciesScopes map[string][]string) {
+	if addedNodes.Exists(node.Id) {
+		return
+	}
+	addedNodes.Add(node.Id)
+	dg.Nodes = append(dg.Nodes, newDependencyGraphNode(node, issues[node.Id], dependenciesScopes[node.Id]))
+	for _, child := range node.Nodes {
+		if edge := node.Id + "->" + child.Id; !addedEdges.Exists(edge) {
+			addedEdges.Add(edge)
+			dg.Edges = append(dg.Edges, DependencyGraphEdge{From: node.Id, To: child.Id})
+		}
+		dg.addNode(child, addedNodes, addedEdges, issues, dependenciesScopes)
+	}
+}
+
+func newDependencyGraphNode(node *xrayUtils.GraphNode, issues *componentIssues, scopes []string) DependencyGraphNode {
+	name, version, _ := utils.SplitComponentId(node.Id)
+	graphNode := DependencyGraphNode{Id: node.Id, Name: name, Version: version, Scopes: scopes}
+	if node.Types != nil {
+		graphNode.Types = *node.Types
+	}
+	if node.Classifier != nil {
+		graphNode.Classifier = *node.Classifier
+	}
+	if issues != nil {
+		graphNode.Vulnerabilities = issues.issues.Size()
+		graphNode.Severity = issues.severity.String()
+	}
+	return graphNode
+}
+
+// Map each vulnerable component to its unique issues (vulnerabilities and security violations) and their highest severity.
+func getComponentsIssues(scanResults []services.ScanResponse) map[string]*componentIssues {
+	issues := map[string]*componentIssues{}
+	addIssue := func(issueId, severity string, components map[string]services.Component) {
+		for componentId := range components {
+			if _, exists := issues[componentId]; !exists {
+				issues[componentId] = &componentIssues{issues: datastructures.MakeSet[string](), severity: severityutils.Unknown}
+			}
+			issues[componentId].issues.Add(issueId)
+			if parsed := severityutils.GetSeverity(severity); severityutils.CompareSeverity(parsed, issues[componentId].severity) > 0 {
+				issues[componentId].severity = parsed
+			}
+		}
+	}
+	for _, scanResult := range scanResults {
+		for _, vulnerability := range scanResult.Vulnerabilities {
+			addIssue(vulnerability.IssueId, vulnerability.Severity, vulnerability.Components)
+		}
+		for _, violation := range scanResult.Violations {
+			if violation.ViolationType == utils.ViolationTypeSecurity.String() {
+				addIssue(violation.IssueId, violation.Severity, violation.Components)
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,110 @@
+package graphutils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestResults() *utils.Results {
+	jarType := []string{"jar"}
+	classifier := "sources"
+	shared := &xrayUtils.GraphNode{Id: "gav://org:shared:1.0.0"}
+	return &utils.Results{ScaResults: []*utils.ScaScanResult{{
+		Target:     "project",
+		Technology: techutils.Maven,
+		DependencyTrees: []*xrayUtils.GraphNode{
+			{Id: "gav://org:module-a:1.0.0", Nodes: []*xrayUtils.GraphNode{
+				{Id: "gav://org:direct:2.0.0", Types: &jarType, Classifier: &classifier, Nodes: []*xrayUtils.GraphNode{shared}},
+				shared,
+			}},
+			{Id: "gav://org:module-b:1.0.0", Nodes: []*xrayUtils.GraphNode{shared}},
+		},
+		DependenciesScopes: map[string][]string{"gav://org:direct:2.0.0": {"runtime"}},
+		XrayResults: []services.ScanResponse{{
+			Vulnerabilities: []services.Vulnerability{
+				{IssueId: "XRAY-1", Severity: "Medium", Components: map[string]services.Component{"gav://org:shared:1.0.0": {}}},
+				{IssueId: "XRAY-2", Severity: "Critical", Components: map[string]services.Component{"gav://org:shared:1.0.0": {}}},
+			},
+			Violations: []services.Violation{
+				{IssueId: "XRAY-1", Severity: "Medium", ViolationType: "security", Components: map[string]services.Component{"gav://org:shared:1.0.0": {}}},
+				{IssueId: "XRAY-3", Severity: "High", ViolationType: "license", Components: map[string]services.Component{"gav://org:direct:2.0.0": {}}},
+			},
+		}},
+	}}}
+}
+
+func TestNewDependencyGraphs(t *testing.T) {
+	graphs := NewDependencyGraphs(getTestResults())
+	assert.Len(t, graphs, 2)
+
+	moduleA := graphs[0]
+	assert.Equal(t, "gav://org:module-a:1.0.0", moduleA.Module)
+	assert.Equal(t, techutils.Maven, moduleA.Technology)
+	assert.Len(t, moduleA.Nodes, 3)
+	assert.ElementsMatch(t, []DependencyGraphEdge{
+		{From: "gav://org:module-a:1.0.0", To: "gav://org:direct:2.0.0"},
+		{From: "gav://org:direct:2.0.0", To: "gav://org:shared:1.0.0"},
+		{From: "gav://org:module-a:1.0.0", To: "gav://org:shared:1.0.0"},
+	}, moduleA.Edges)
+	assert.Equal(t, DependencyGraphNode{Id: "gav://org:direct:2.0.0", Name: "org:direct", Version: "2.0.0", Types: []string{"jar"}, Classifier: "sources", Scopes: []string{"runtime"}}, moduleA.Nodes[1])
+	// License violations aren't counted, the same issue is counted once
+	assert.Equal(t, DependencyGraphNode{Id: "gav://org:shared:1.0.0", Name: "org:shared", Version: "1.0.0", Vulnerabilities: 2, Severity: "Critical"}, moduleA.Nodes[2])
+
+	moduleB := graphs[1]
+	assert.Len(t, moduleB.Nodes, 2)
+	assert.Len(t, moduleB.Edges, 1)
+}
+
+func TestExportDependencyGraphs(t *testing.T) {
+	graphs := NewDependencyGraphs(getTestResults())
+	tempDir := t.TempDir()
+
+	jsonPath := filepath.Join(tempDir, "graph.json")
+	assert.NoError(t, ExportDependencyGraphs(graphs, Json, jsonPath))
+	content, err := os.ReadFile(jsonPath)
+	assert.NoError(t, err)
+	var exported []*DependencyGraph
+	assert.NoError(t, json.Unmarshal(content, &exported))
+	assert.Equal(t, graphs, exported)
+
+	dotPath := filepath.Join(tempDir, "graph.dot")
+	assert.NoError(t, ExportDependencyGraphs(graphs, Dot, dotPath))
+	content, err = os.ReadFile(dotPath)
+	assert.NoError(t, err)
+	dot := string(content)
+	assert.True(t, strings.HasPrefix(dot, "digraph dependencies {"))
+	assert.Contains(t, dot, "subgraph cluster_1 {")
+	assert.Contains(t, dot, `"0:gav://org:shared:1.0.0" [label="org:shared:1.0.0\n2 vulnerabilities (Critical)", color=red];`)
+	assert.Contains(t, dot, `"1:gav://org:module-b:1.0.0" -> "1:gav://org:shared:1.0.0";`)
+
+	graphMLPath := filepath.Join(tempDir, "graph.graphml")
+	assert.NoError(t, ExportDependencyGraphs(graphs, GraphML, graphMLPath))
+	content, err = os.ReadFile(graphMLPath)
+	assert.NoError(t, err)
+	graphML := string(content)
+	assert.Contains(t, graphML, `<graph id="project:gav://org:module-a:1.0.0" edgedefault="directed">`)
+	assert.Contains(t, graphML, `<data key="classifier">sources</data>`)
+	assert.Contains(t, graphML, `<edge source="project:gav://org:module-a:1.0.0/gav://org:direct:2.0.0" target="project:gav://org:module-a:1.0.0/gav://org:shared:1.0.0"></edge>`)
+
+	// The same module in two targets
+	otherTarget := NewDependencyGraphs(getTestResults())
+	otherTarget[0].Target = "other-project"
+	assert.NoError(t, ExportDependencyGraphs(append(graphs, otherTarget[0], graphs[0]), GraphML, graphMLPath))
+	content, err = os.ReadFile(graphMLPath)
+	assert.NoError(t, err)
+	graphML = string(content)
+	assert.Contains(t, graphML, `<node id="project:gav://org:module-a:1.0.0/gav://org:shared:1.0.0">`)
+	assert.Contains(t, graphML, `<node id="other-project:gav://org:module-a:1.0.0/gav://org:shared:1.0.0">`)
+	assert.Contains(t, graphML, `<node id="project:gav://org:module-a:1.0.0#3/gav://org:shared:1.0.0">`)
+
+	assert.Error(t, ExportDependencyGraphs(graphs, "svg", filepath.Join(tempDir, "graph.svg")))
+}
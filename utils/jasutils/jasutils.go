@@ -8,6 +8,21 @@ import (
 
 const (
 	ApplicabilityRuleIdPrefix = "applic_"
+	// The tool of the applicability runs of the Go reachability analysis, which covers only the Go packages
+	GoReachabilityToolName = "Go Reachability"
+)
+
+// The properties of the results of secrets that were found in the Git history, describing the commit that added them
+const (
+	CommitShaProperty    = "commitSha"
+	CommitAuthorProperty = "commitAuthor"
+	CommitDateProperty   = "commitDate"
+)
+
+// The properties of the SARIF rules of the custom rules, with the severity and the CWEs that are defined in the rules
+const (
+	CustomRuleSeverityProperty = "custom-rule-severity"
+	CustomRuleCweProperty      = "custom-rule-cwe"
 )
 
 const (
@@ -0,0 +1,190 @@
+package licenseutils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// The watch name of the violations of the local license policy, to distinguish them from the violations of Xray watches.
+	LocalLicensePolicyWatchName = "local-license-policy"
+	// Same as utils.ViolationTypeLicense
+	licenseViolationType = "license"
+	wildcard             = "*"
+)
+
+// A license policy that is evaluated locally, without Xray watches.
+// The policy file can be a YAML or a JSON file, for example:
+//
+//	allowed-licenses: [MIT, Apache-2.0, BSD-*]
+//	denied-licenses: [GPL-3.0-only, AGPL-*]
+//	scopes: [runtime]
+//	severity: High
+type LicensePolicy struct {
+	// If not empty, only these licenses are allowed. A trailing '*' matches any suffix.
+	Allowed []string `yaml:"allowed-licenses,omitempty"`
+	// These licenses are not allowed, even if allowed by the 'Allowed' list. A trailing '*' matches any suffix.
+	Denied []string `yaml:"denied-licenses,omitempty"`
+	// The dependency scopes the policy applies to (runtime, dev or test). If empty, applies to all the dependencies.
+	Scopes []string `yaml:"scopes,omitempty"`
+	// The severity of the policy violations, 'High' if not provided.
+	Severity string `yaml:"severity,omitempty"`
+}
+
+func NewLicensePolicy(allowed, denied []string) *LicensePolicy {
+	return &LicensePolicy{Allowed: allowed, Denied: denied}
+}
+
+func LoadLicensePolicy(policyFilePath string) (*LicensePolicy, error) {
+	content, err := os.ReadFile(policyFilePath)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to read the license policy file '%s': %s", policyFilePath, err.Error())
+	}
+	policy := &LicensePolicy{}
+	if err = yaml.Unmarshal(content, policy); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the license policy file '%s': %s", policyFilePath, err.Error())
+	}
+	return policy, nil
+}
+
+func (lp *LicensePolicy) Validate() error {
+	if len(lp.Allowed) == 0 && len(lp.Denied) == 0 {
+		return errorutils.CheckErrorf("the license policy must contain allowed or denied licenses")
+	}
+	for _, scope := range lp.Scopes {
+		if !slices.Contains(techutils.DependencyScopes, scope) {
+			return errorutils.CheckErrorf("unsupported dependency scope '%s' in the license policy. Supported scopes: %s", scope, strings.Join(techutils.DependencyScopes, ", "))
+		}
+	}
+	if lp.Severity != "" {
+		if _, err := severityutils.ParseSeverity(lp.Severity, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (lp *LicensePolicy) GetSeverity() string {
+	if lp.Severity == "" {
+		return severityutils.High.String()
+	}
+	return lp.Severity
+}
+
+// Returns true if the license key (an SPDX expression) is compliant with the policy.
+func (lp *LicensePolicy) IsCompliant(licenseKey string) bool {
+	return parseLicenseKey(licenseKey).IsCompliant(lp)
+}
+
+// Returns true if the policy applies to a dependency with the given scopes.
+// Dependencies with unknown scopes are always evaluated.
+func (lp *LicensePolicy) AppliesToScopes(scopes []string) bool {
+	if len(lp.Scopes) == 0 || len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if slices.Contains(lp.Scopes, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the violations of the policy from the licenses of the scan results.
+// dependencyScopes maps a component ID to its scopes, if known.
+func (lp *LicensePolicy) GetViolations(licenses []services.License, dependencyScopes map[string][]string) (violations []services.Violation) {
+	for _, license := range licenses {
+		expression := parseLicenseKey(license.Key)
+		if expression.IsCompliant(lp) {
+			continue
+		}
+		components := map[string]services.Component{}
+		for componentId, component := range license.Components {
+			if lp.AppliesToScopes(dependencyScopes[componentId]) {
+				components[componentId] = component
+			}
+		}
+		if len(components) == 0 {
+			continue
+		}
+		violations = append(violations, services.Violation{
+			Summary:       fmt.Sprintf("The license '%s' is not compliant with the license policy (non-compliant licenses: %s)", license.Key, strings.Join(expression.GetNonCompliantLicenses(lp), ", ")),
+			Severity:      lp.GetSeverity(),
+			ViolationType: licenseViolationType,
+			Components:    components,
+			WatchName:     LocalLicensePolicyWatchName,
+			IssueId:       license.Key,
+			LicenseKey:    license.Key,
+			LicenseName:   license.Name,
+			References:    license.References,
+			FailBuild:     true,
+		})
+	}
+	return
+}
+
+// Add the violations of the policy to the scan results, based on their licenses.
+func (lp *LicensePolicy) AddViolations(scanResults []services.ScanResponse, dependencyScopes map[string][]string, technology string) {
+	for i := range scanResults {
+		violations := lp.GetViolations(scanResults[i].Licenses, dependencyScopes)
+		for j := range violations {
+			violations[j].Technology = technology
+		}
+		scanResults[i].Violations = append(scanResults[i].Violations, violations...)
+	}
+}
+
+func parseLicenseKey(licenseKey string) Expression {
+	expression, err := ParseExpression(licenseKey)
+	if err != nil {
+		// Not an SPDX expression, evaluate it as a single license
+		log.Debug(fmt.Sprintf("Evaluating the license '%s' as a single license: %s", licenseKey, err.Error()))
+		return simpleExpression{license: strings.TrimSpace(licenseKey)}
+	}
+	return expression
+}
+
+// A license with an exception can be explicitly allowed or denied ('GPL-2.0-only WITH Classpath-exception-2.0'), otherwise the license itself is evaluated.
+func (lp *LicensePolicy) isLicenseAllowed(license, exception string) bool {
+	if exception != "" {
+		if matchLicense(lp.Denied, license, exception) {
+			return false
+		}
+		if matchLicense(lp.Allowed, license, exception) {
+			return true
+		}
+	}
+	if matchLicense(lp.Denied, license, "") {
+		return false
+	}
+	return len(lp.Allowed) == 0 || matchLicense(lp.Allowed, license, "")
+}
+
+func matchLicense(patterns []string, license, exception string) bool {
+	for _, pattern := range patterns {
+		patternLicense, patternException := pattern, ""
+		if parts := strings.Fields(pattern); len(parts) == 3 && strings.EqualFold(parts[1], withOperator) {
+			patternLicense, patternException = parts[0], parts[2]
+		}
+		if matchLicenseId(patternLicense, license) && strings.EqualFold(patternException, exception) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchLicenseId(pattern, license string) bool {
+	if strings.HasSuffix(pattern, wildcard) {
+		return strings.HasPrefix(strings.ToLower(license), strings.ToLower(strings.TrimSuffix(pattern, wildcard)))
+	}
+	return normalizeLicenseId(pattern) == normalizeLicenseId(license)
+}
@@ -0,0 +1,74 @@
+package licenseutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/utils/techutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadLicensePolicy(t *testing.T) {
+	policyFile := filepath.Join(t.TempDir(), "license-policy.yaml")
+	assert.NoError(t, os.WriteFile(policyFile, []byte("allowed-licenses: [MIT, Apache-2.0]\ndenied-licenses:\n  - GPL-*\nscopes: [runtime]\nseverity: Medium\n"), 0644))
+	policy, err := LoadLicensePolicy(policyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, &LicensePolicy{Allowed: []string{"MIT", "Apache-2.0"}, Denied: []string{"GPL-*"}, Scopes: []string{techutils.RuntimeScope}, Severity: "Medium"}, policy)
+	assert.NoError(t, policy.Validate())
+
+	// JSON policy file
+	assert.NoError(t, os.WriteFile(policyFile, []byte(`{"denied-licenses": ["AGPL-3.0-only"]}`), 0644))
+	policy, err = LoadLicensePolicy(policyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AGPL-3.0-only"}, policy.Denied)
+	assert.Equal(t, "High", policy.GetSeverity())
+
+	_, err = LoadLicensePolicy(filepath.Join(t.TempDir(), "not-exists.yaml"))
+	assert.Error(t, err)
+}
+
+func TestValidateLicensePolicy(t *testing.T) {
+	assert.Error(t, (&LicensePolicy{}).Validate())
+	assert.Error(t, (&LicensePolicy{Allowed: []string{"MIT"}, Scopes: []string{"compile"}}).Validate())
+	assert.Error(t, (&LicensePolicy{Allowed: []string{"MIT"}, Severity: "Severe"}).Validate())
+	assert.NoError(t, NewLicensePolicy(nil, []string{"GPL-3.0-only"}).Validate())
+}
+
+func TestGetViolations(t *testing.T) {
+	policy := &LicensePolicy{Denied: []string{"GPL-3.0-only"}, Scopes: []string{techutils.RuntimeScope}}
+	licenses := []services.License{
+		{Key: "MIT", Components: map[string]services.Component{"npm://a:1.0.0": {}}},
+		{Key: "MIT OR GPL-3.0-only", Components: map[string]services.Component{"npm://b:1.0.0": {}}},
+		{Key: "GPL-3.0-only", Name: "GNU General Public License v3.0 only", Components: map[string]services.Component{
+			"npm://c:1.0.0": {ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "npm://root:1.0.0"}, {ComponentId: "npm://c:1.0.0"}}}},
+			"npm://d:1.0.0": {},
+			"npm://e:1.0.0": {},
+		}},
+	}
+	dependencyScopes := map[string][]string{
+		"npm://c:1.0.0": {techutils.RuntimeScope},
+		"npm://d:1.0.0": {techutils.DevScope},
+	}
+	violations := policy.GetViolations(licenses, dependencyScopes)
+	if assert.Len(t, violations, 1) {
+		violation := violations[0]
+		assert.Equal(t, "license", violation.ViolationType)
+		assert.Equal(t, "GPL-3.0-only", violation.LicenseKey)
+		assert.Equal(t, LocalLicensePolicyWatchName, violation.WatchName)
+		assert.Equal(t, "High", violation.Severity)
+		assert.True(t, violation.FailBuild)
+		// Dev dependencies are out of the policy scopes, dependencies with unknown scopes are evaluated
+		assert.Len(t, violation.Components, 2)
+		assert.Contains(t, violation.Components, "npm://c:1.0.0")
+		assert.Contains(t, violation.Components, "npm://e:1.0.0")
+		assert.Len(t, violation.Components["npm://c:1.0.0"].ImpactPaths, 1)
+	}
+
+	scanResults := []services.ScanResponse{{Licenses: licenses}}
+	policy.AddViolations(scanResults, dependencyScopes, "npm")
+	if assert.Len(t, scanResults[0].Violations, 1) {
+		assert.Equal(t, "npm", scanResults[0].Violations[0].Technology)
+	}
+}
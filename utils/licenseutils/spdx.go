@@ -0,0 +1,229 @@
+package licenseutils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	andOperator  = "AND"
+	orOperator   = "OR"
+	withOperator = "WITH"
+)
+
+// An SPDX license expression, for example: 'MIT OR (GPL-2.0-only WITH Classpath-exception-2.0 AND BSD-3-Clause)'
+// https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+type Expression interface {
+	// Returns true if the expression is compliant with the given policy.
+	// 'OR' requires one of the options to be compliant, 'AND' requires all of them.
+	IsCompliant(policy *LicensePolicy) bool
+	// Returns the licenses of the expression that are not compliant with the given policy.
+	GetNonCompliantLicenses(policy *LicensePolicy) []string
+	String() string
+}
+
+type simpleExpression struct {
+	license   string
+	exception string
+}
+
+func (se simpleExpression) IsCompliant(policy *LicensePolicy) bool {
+	return policy.isLicenseAllowed(se.license, se.exception)
+}
+
+func (se simpleExpression) GetNonCompliantLicenses(policy *LicensePolicy) []string {
+	if se.IsCompliant(policy) {
+		return []string{}
+	}
+	return []string{se.String()}
+}
+
+func (se simpleExpression) String() string {
+	if se.exception == "" {
+		return se.license
+	}
+	return se.license + " " + withOperator + " " + se.exception
+}
+
+type compoundExpression struct {
+	operator string
+	operands []Expression
+}
+
+func (ce compoundExpression) IsCompliant(policy *LicensePolicy) bool {
+	for _, operand := range ce.operands {
+		compliant := operand.IsCompliant(policy)
+		if ce.operator == orOperator && compliant {
+			return true
+		}
+		if ce.operator == andOperator && !compliant {
+			return false
+		}
+	}
+	return ce.operator == andOperator
+}
+
+func (ce compoundExpression) GetNonCompliantLicenses(policy *LicensePolicy) (licenses []string) {
+	licenses = []string{}
+	if ce.IsCompliant(policy) {
+		return
+	}
+	for _, operand := range ce.operands {
+		licenses = append(licenses, operand.GetNonCompliantLicenses(policy)...)
+	}
+	return
+}
+
+func (ce compoundExpression) String() string {
+	var operands []string
+	for _, operand := range ce.operands {
+		if compound, ok := operand.(compoundExpression); ok && compound.operator != ce.operator {
+			operands = append(operands, "("+operand.String()+")")
+			continue
+		}
+		operands = append(operands, operand.String())
+	}
+	return strings.Join(operands, " "+ce.operator+" ")
+}
+
+// Parse an SPDX license expression.
+// Operators are case-insensitive, 'WITH' binds tighter than 'AND', which binds tighter than 'OR'.
+func ParseExpression(expression string) (Expression, error) {
+	parser := &expressionParser{tokens: tokenizeExpression(expression)}
+	if len(parser.tokens) == 0 {
+		return nil, errorutils.CheckErrorf("empty license expression")
+	}
+	parsed, err := parser.parseOr()
+	if err != nil {
+		return nil, errorutils.CheckErrorf("invalid license expression '%s': %s", expression, err.Error())
+	}
+	if !parser.done() {
+		return nil, errorutils.CheckErrorf("invalid license expression '%s': unexpected '%s'", expression, parser.peek())
+	}
+	return parsed, nil
+}
+
+func tokenizeExpression(expression string) (tokens []string) {
+	current := strings.Builder{}
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, char := range expression {
+		switch {
+		case char == '(' || char == ')':
+			flush()
+			tokens = append(tokens, string(char))
+		case unicode.IsSpace(char):
+			flush()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	flush()
+	return
+}
+
+type expressionParser struct {
+	tokens   []string
+	position int
+}
+
+func (ep *expressionParser) done() bool {
+	return ep.position >= len(ep.tokens)
+}
+
+func (ep *expressionParser) peek() string {
+	if ep.done() {
+		return ""
+	}
+	return ep.tokens[ep.position]
+}
+
+func (ep *expressionParser) next() string {
+	token := ep.peek()
+	ep.position++
+	return token
+}
+
+func (ep *expressionParser) isOperator(operator string) bool {
+	return strings.EqualFold(ep.peek(), operator)
+}
+
+func (ep *expressionParser) parseOr() (Expression, error) {
+	return ep.parseCompound(orOperator, ep.parseAnd)
+}
+
+func (ep *expressionParser) parseAnd() (Expression, error) {
+	return ep.parseCompound(andOperator, ep.parseWith)
+}
+
+func (ep *expressionParser) parseCompound(operator string, parseOperand func() (Expression, error)) (Expression, error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []Expression{operand}
+	for ep.isOperator(operator) {
+		ep.next()
+		if operand, err = parseOperand(); err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return compoundExpression{operator: operator, operands: operands}, nil
+}
+
+func (ep *expressionParser) parseWith() (Expression, error) {
+	if ep.peek() == "(" {
+		ep.next()
+		inner, err := ep.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if ep.next() != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		return inner, nil
+	}
+	license, err := ep.parseLicenseId()
+	if err != nil {
+		return nil, err
+	}
+	expression := simpleExpression{license: license}
+	if ep.isOperator(withOperator) {
+		ep.next()
+		if expression.exception, err = ep.parseLicenseId(); err != nil {
+			return nil, err
+		}
+	}
+	return expression, nil
+}
+
+func (ep *expressionParser) parseLicenseId() (string, error) {
+	token := ep.next()
+	if token == "" {
+		return "", fmt.Errorf("unexpected end of expression")
+	}
+	if token == "(" || token == ")" || strings.EqualFold(token, andOperator) || strings.EqualFold(token, orOperator) || strings.EqualFold(token, withOperator) {
+		return "", fmt.Errorf("expected a license identifier, got '%s'", token)
+	}
+	return token, nil
+}
+
+// Normalize a license identifier for comparison: case-insensitive, 'X+' is 'X-or-later' and 'X-only' is 'X' (the deprecated SPDX identifiers of GPL family licenses).
+func normalizeLicenseId(license string) string {
+	license = strings.ToLower(strings.TrimSpace(license))
+	if strings.HasSuffix(license, "+") {
+		return strings.TrimSuffix(license, "+") + "-or-later"
+	}
+	return strings.TrimSuffix(license, "-only")
+}
@@ -0,0 +1,74 @@
+package licenseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpression(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expected   string
+		expectErr  bool
+	}{
+		{expression: "MIT", expected: "MIT"},
+		{expression: "MIT OR Apache-2.0", expected: "MIT OR Apache-2.0"},
+		{expression: "(MIT or Apache-2.0)", expected: "MIT OR Apache-2.0"},
+		{expression: "MIT AND BSD-3-Clause OR GPL-2.0+", expected: "(MIT AND BSD-3-Clause) OR GPL-2.0+"},
+		{expression: "MIT AND (BSD-3-Clause OR GPL-2.0-only WITH Classpath-exception-2.0)", expected: "MIT AND (BSD-3-Clause OR GPL-2.0-only WITH Classpath-exception-2.0)"},
+		{expression: "", expectErr: true},
+		{expression: "MIT OR", expectErr: true},
+		{expression: "(MIT AND ISC", expectErr: true},
+		{expression: "MIT Apache-2.0", expectErr: true},
+		{expression: "MIT WITH", expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expression, err := ParseExpression(tc.expression)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, expression.String())
+		})
+	}
+}
+
+func TestExpressionCompliance(t *testing.T) {
+	policy := &LicensePolicy{
+		Allowed: []string{"MIT", "Apache-2.0", "BSD-*", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		Denied:  []string{"BSD-4-Clause"},
+	}
+	testCases := []struct {
+		expression   string
+		compliant    bool
+		nonCompliant []string
+	}{
+		{expression: "mit", compliant: true},
+		{expression: "MIT OR GPL-3.0-only", compliant: true},
+		{expression: "MIT AND GPL-3.0-only", nonCompliant: []string{"GPL-3.0-only"}},
+		{expression: "BSD-3-Clause", compliant: true},
+		{expression: "BSD-4-Clause", nonCompliant: []string{"BSD-4-Clause"}},
+		{expression: "GPL-2.0 WITH Classpath-exception-2.0", compliant: true},
+		{expression: "GPL-2.0-only", nonCompliant: []string{"GPL-2.0-only"}},
+		{expression: "(GPL-3.0-only OR LGPL-2.1+) AND MIT", nonCompliant: []string{"GPL-3.0-only", "LGPL-2.1+"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expression, err := ParseExpression(tc.expression)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.compliant, expression.IsCompliant(policy))
+			if !tc.compliant {
+				assert.ElementsMatch(t, tc.nonCompliant, expression.GetNonCompliantLicenses(policy))
+			}
+		})
+	}
+}
+
+func TestNormalizeLicenseId(t *testing.T) {
+	assert.Equal(t, "gpl-2.0-or-later", normalizeLicenseId("GPL-2.0+"))
+	assert.Equal(t, "gpl-2.0", normalizeLicenseId("GPL-2.0-only"))
+	assert.Equal(t, normalizeLicenseId("gpl-2.0"), normalizeLicenseId("GPL-2.0-only"))
+}
@@ -8,9 +8,13 @@ import (
 )
 
 const (
-	JfrogCurationDirName = "curation"
+	JfrogCurationDirName     = "curation"
+	JfrogScanCacheDirName    = "scan-cache"
+	JfrogDepTreeCacheDirName = "dep-tree-cache"
 
-	CurationsDir = "JFROG_CLI_CURATION_DIR"
+	CurationsDir    = "JFROG_CLI_CURATION_DIR"
+	ScanCacheDir    = "JFROG_CLI_SCAN_CACHE_DIR"
+	DepTreeCacheDir = "JFROG_CLI_DEP_TREE_CACHE_DIR"
 
 	// #nosec G101 -- Not credentials.
 	CurationSupportFlag = "JFROG_CLI_CURATION"
@@ -28,6 +32,28 @@ func getJfrogCurationFolder() (string, error) {
 	return filepath.Join(jfrogHome, JfrogCurationDirName), nil
 }
 
+func GetScanCacheFolder() (string, error) {
+	if scanCacheDir := os.Getenv(ScanCacheDir); scanCacheDir != "" {
+		return scanCacheDir, nil
+	}
+	jfrogHome, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(jfrogHome, JfrogScanCacheDirName), nil
+}
+
+func GetDepTreeCacheFolder() (string, error) {
+	if depTreeCacheDir := os.Getenv(DepTreeCacheDir); depTreeCacheDir != "" {
+		return depTreeCacheDir, nil
+	}
+	jfrogHome, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(jfrogHome, JfrogDepTreeCacheDirName), nil
+}
+
 func GetCurationCacheFolder() (string, error) {
 	curationFolder, err := getJfrogCurationFolder()
 	if err != nil {
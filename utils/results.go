@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"strings"
+
 	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-security/formats"
 	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/dockerutils"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"github.com/owenrumney/go-sarif/v2/sarif"
+	"golang.org/x/exp/slices"
 )
 
 type Results struct {
@@ -16,6 +22,8 @@ type Results struct {
 	ScansErr    error
 
 	ExtendedScanResults *ExtendedScanResults
+	// The layers of the scanned image and their origin in the Dockerfile (Docker scan only)
+	DockerImageLayers *dockerutils.ImageLayers
 
 	MultiScanId string
 }
@@ -39,6 +47,61 @@ func (r *Results) GetScaScannedTechnologies() []techutils.Technology {
 	return technologies.ToSlice()
 }
 
+// Returns the scopes of the dependencies in all the scanned projects, by their component IDs.
+func (r *Results) GetDependenciesScopes() map[string][]string {
+	dependenciesScopes := map[string][]string{}
+	if r == nil {
+		return dependenciesScopes
+	}
+	for _, scaResult := range r.ScaResults {
+		for componentId, scopes := range scaResult.DependenciesScopes {
+			for _, scope := range scopes {
+				if !slices.Contains(dependenciesScopes[componentId], scope) {
+					dependenciesScopes[componentId] = append(dependenciesScopes[componentId], scope)
+				}
+			}
+		}
+	}
+	return dependenciesScopes
+}
+
+// Where the versions of the direct dependencies are declared in the scanned projects.
+// The same dependency may be declared differently in each project module, so the declarations are kept by the module.
+type DependenciesDeclarations struct {
+	// The declarations of each module, by the IDs of the dependency trees roots and the component IDs
+	rootsDeclarations map[string]map[string]*formats.Declaration
+}
+
+// Returns where the direct dependency is declared in the module of the dependency tree root, or nil if unknown.
+func (dd DependenciesDeclarations) Get(rootId, componentId string) *formats.Declaration {
+	return dd.rootsDeclarations[rootId][componentId]
+}
+
+// Returns where the versions of the direct dependencies are declared in all the scanned projects.
+func (r *Results) GetDependenciesDeclarations() (dependenciesDeclarations DependenciesDeclarations) {
+	dependenciesDeclarations = DependenciesDeclarations{rootsDeclarations: map[string]map[string]*formats.Declaration{}}
+	if r == nil {
+		return
+	}
+	for _, scaResult := range r.ScaResults {
+		for rootId, declarations := range scaResult.DependenciesDeclarations {
+			dependenciesDeclarations.rootsDeclarations[rootId] = declarations
+		}
+	}
+	return
+}
+
+// Returns the IDs of the dependency trees roots that are workspace members in all the scanned projects.
+func (r *Results) GetWorkspaceMembers() (workspaceMembers []string) {
+	if r == nil {
+		return
+	}
+	for _, scaResult := range r.ScaResults {
+		workspaceMembers = append(workspaceMembers, scaResult.WorkspaceMembers...)
+	}
+	return
+}
+
 func (r *Results) IsMultipleProject() bool {
 	if len(r.ScaResults) == 0 {
 		return false
@@ -98,6 +161,14 @@ type ScaScanResult struct {
 	XrayResults           []services.ScanResponse `json:"XrayResults,omitempty"`
 	Descriptors           []string                `json:"Descriptors,omitempty"`
 	IsMultipleRootProject *bool                   `json:"IsMultipleRootProject,omitempty"`
+	// The scopes (runtime, dev or test) of the dependencies, if known.
+	DependenciesScopes map[string][]string `json:"DependenciesScopes,omitempty"`
+	// Where the versions of the direct dependencies are declared in the project descriptors, if known, by the IDs of the dependency trees roots and the component IDs.
+	DependenciesDeclarations map[string]map[string]*formats.Declaration `json:"DependenciesDeclarations,omitempty"`
+	// The IDs of the dependency trees roots that are members of the scanned workspace, if it's a workspace.
+	WorkspaceMembers []string `json:"WorkspaceMembers,omitempty"`
+	// The full dependency trees of the project modules, used to export the dependency graph.
+	DependencyTrees []*xrayUtils.GraphNode `json:"-"`
 }
 
 func (s ScaScanResult) HasInformation() bool {
@@ -115,6 +186,8 @@ type ExtendedScanResults struct {
 	IacScanResults           []*sarif.Run
 	SastScanResults          []*sarif.Run
 	EntitledForJas           bool
+	// Errors of scans that didn't fail the command, such as scans that exceeded their timeout
+	ScanErrors []formats.SimpleJsonError
 }
 
 func (e *ExtendedScanResults) IsIssuesFound() bool {
@@ -124,6 +197,37 @@ func (e *ExtendedScanResults) IsIssuesFound() bool {
 		sarifutils.GetResultsLocationCount(e.SastScanResults...) > 0
 }
 
+// The applicability of the vulnerabilities is determined by the Contextual Analysis scanner, or by the reachability analysis that doesn't require JAS entitlement.
+// The reachability analysis covers only the Go packages, so the applicability of other components is scanned only by the Contextual Analysis scanner.
+func (e *ExtendedScanResults) IsApplicabilityScanned(components map[string]services.Component) bool {
+	return e.EntitledForJas || len(e.GetApplicabilityScanResults(components)) > 0
+}
+
+// Returns the applicability runs that cover the components: the Contextual Analysis runs, and the Go reachability runs if the components are Go packages.
+func (e *ExtendedScanResults) GetApplicabilityScanResults(components map[string]services.Component) (runs []*sarif.Run) {
+	goComponents := isGoComponents(components)
+	for _, run := range e.ApplicabilityScanResults {
+		if goComponents || sarifutils.GetRunToolName(run) != jasutils.GoReachabilityToolName {
+			runs = append(runs, run)
+		}
+	}
+	return
+}
+
+func isGoComponents(components map[string]services.Component) bool {
+	for componentId := range components {
+		if strings.HasPrefix(componentId, techutils.Go.GetPackageType()+"://") {
+			return true
+		}
+	}
+	return false
+}
+
+// The secrets are detected by the Secrets scanner, or by the built-in secrets detector that doesn't require JAS entitlement.
+func (e *ExtendedScanResults) IsSecretsScanned() bool {
+	return e.EntitledForJas || len(e.SecretsScanResults) > 0
+}
+
 func (e *ExtendedScanResults) GetResultsForTarget(target string) (result *ExtendedScanResults) {
 	return &ExtendedScanResults{
 		ApplicabilityScanResults: sarifutils.GetRunsByWorkingDirectory(target, e.ApplicabilityScanResults...),
@@ -1,8 +1,13 @@
 package utils
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/jfrog/jfrog-cli-security/formats"
+	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetScaScanResultByTarget(t *testing.T) {
@@ -44,3 +49,44 @@ func TestGetScaScanResultByTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDependenciesDeclarations(t *testing.T) {
+	serviceDeclaration := &formats.Declaration{Location: formats.Location{File: "service/pom.xml", StartLine: 10}, Type: formats.ModuleDeclaration}
+	libDeclaration := &formats.Declaration{Location: formats.Location{File: "lib/pom.xml", StartLine: 20}, Type: formats.PropertyDeclaration}
+	results := &Results{ScaResults: []*ScaScanResult{
+		{
+			Target:                   "service",
+			DependenciesDeclarations: map[string]map[string]*formats.Declaration{"gav://org:service:1.0": {"gav://org:dep:1.0": serviceDeclaration}},
+		},
+		{
+			Target:                   "lib",
+			DependenciesDeclarations: map[string]map[string]*formats.Declaration{"gav://org:lib:1.0": {"gav://org:dep:1.0": libDeclaration}},
+		},
+	}}
+	declarations := results.GetDependenciesDeclarations()
+	// The same dependency is declared differently in each project
+	assert.Equal(t, serviceDeclaration, declarations.Get("gav://org:service:1.0", "gav://org:dep:1.0"))
+	assert.Equal(t, libDeclaration, declarations.Get("gav://org:lib:1.0", "gav://org:dep:1.0"))
+	assert.Nil(t, declarations.Get("gav://org:other:1.0", "gav://org:dep:1.0"))
+	assert.Nil(t, declarations.Get("gav://org:lib:1.0", "gav://org:other-dep:1.0"))
+}
+
+func TestGetApplicabilityScanResults(t *testing.T) {
+	contextualAnalysisRun := sarif.NewRunWithInformationURI("JFrog Applicability Scanner", "")
+	reachabilityRun := sarif.NewRunWithInformationURI(jasutils.GoReachabilityToolName, "")
+	goComponents := map[string]services.Component{"go://github.com/org/dep:1.0.0": {}}
+	npmComponents := map[string]services.Component{"npm://dep:1.0.0": {}}
+
+	// The Go reachability analysis covers only the Go packages
+	results := &ExtendedScanResults{ApplicabilityScanResults: []*sarif.Run{reachabilityRun}}
+	assert.True(t, results.IsApplicabilityScanned(goComponents))
+	assert.Equal(t, []*sarif.Run{reachabilityRun}, results.GetApplicabilityScanResults(goComponents))
+	assert.False(t, results.IsApplicabilityScanned(npmComponents))
+	assert.Empty(t, results.GetApplicabilityScanResults(npmComponents))
+
+	// The Contextual Analysis runs cover all the components
+	results = &ExtendedScanResults{ApplicabilityScanResults: []*sarif.Run{contextualAnalysisRun, reachabilityRun}, EntitledForJas: true}
+	assert.Equal(t, []*sarif.Run{contextualAnalysisRun, reachabilityRun}, results.GetApplicabilityScanResults(goComponents))
+	assert.True(t, results.IsApplicabilityScanned(npmComponents))
+	assert.Equal(t, []*sarif.Run{contextualAnalysisRun}, results.GetApplicabilityScanResults(npmComponents))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -43,6 +44,11 @@ func PrintViolationsTable(violations []services.Violation, results *Results, mul
 	if err != nil {
 		return err
 	}
+	// The declarations of the dependencies are shown relative to the working directory
+	baseDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return err
+	}
 	// Print tables, if scan is true; print the scan tables.
 	if results.ResultType.IsTargetBinary() {
 		err = coreutils.PrintTable(formats.ConvertToVulnerabilityScanTableRow(securityViolationsRows), "Security Violations", "No security violations were found", printExtended)
@@ -57,16 +63,16 @@ func PrintViolationsTable(violations []services.Violation, results *Results, mul
 			return coreutils.PrintTable(formats.ConvertToOperationalRiskViolationScanTableRow(operationalRiskViolationsRows), "Operational Risk Violations", "No operational risk violations were found", printExtended)
 		}
 	} else {
-		err = coreutils.PrintTable(formats.ConvertToVulnerabilityTableRow(securityViolationsRows), "Security Violations", "No security violations were found", printExtended)
+		err = coreutils.PrintTable(formats.ConvertToVulnerabilityTableRow(securityViolationsRows, baseDir), "Security Violations", "No security violations were found", printExtended)
 		if err != nil {
 			return err
 		}
-		err = coreutils.PrintTable(formats.ConvertToLicenseViolationTableRow(licenseViolationsRows), "License Compliance Violations", "No license compliance violations were found", printExtended)
+		err = coreutils.PrintTable(formats.ConvertToLicenseViolationTableRow(licenseViolationsRows, baseDir), "License Compliance Violations", "No license compliance violations were found", printExtended)
 		if err != nil {
 			return err
 		}
 		if len(operationalRiskViolationsRows) > 0 {
-			return coreutils.PrintTable(formats.ConvertToOperationalRiskViolationTableRow(operationalRiskViolationsRows), "Operational Risk Violations", "No operational risk violations were found", printExtended)
+			return coreutils.PrintTable(formats.ConvertToOperationalRiskViolationTableRow(operationalRiskViolationsRows, baseDir), "Operational Risk Violations", "No operational risk violations were found", printExtended)
 		}
 	}
 	return nil
@@ -84,20 +90,25 @@ func prepareViolations(violations []services.Violation, results *Results, multip
 	var securityViolationsRows []formats.VulnerabilityOrViolationRow
 	var licenseViolationsRows []formats.LicenseRow
 	var operationalRiskViolationsRows []formats.OperationalRiskViolationRow
+	workspaceMembers := results.GetWorkspaceMembers()
+	dependenciesScopes := results.GetDependenciesScopes()
+	dependenciesDeclarations := results.GetDependenciesDeclarations()
 	for _, violation := range violations {
-		impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes, fixedVersions, components, impactPaths, err := splitComponents(violation.Components)
+		impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes, fixedVersions, components, impactPaths, scopes, err := splitComponents(violation.Components, dependenciesScopes, dependenciesDeclarations)
 		if err != nil {
 			return nil, nil, nil, err
 		}
 		switch violation.ViolationType {
 		case ViolationTypeSecurity.String():
 			cves := convertCves(violation.Cves)
-			if results.ExtendedScanResults.EntitledForJas {
+			applicabilityScanned := results.ExtendedScanResults.IsApplicabilityScanned(violation.Components)
+			applicabilityScanResults := results.ExtendedScanResults.GetApplicabilityScanResults(violation.Components)
+			if applicabilityScanned {
 				for i := range cves {
-					cves[i].Applicability = getCveApplicabilityField(cves[i].Id, results.ExtendedScanResults.ApplicabilityScanResults, violation.Components)
+					cves[i].Applicability = getCveApplicabilityField(cves[i].Id, applicabilityScanResults, violation.Components)
 				}
 			}
-			applicabilityStatus := getApplicableCveStatus(results.ExtendedScanResults.EntitledForJas, results.ExtendedScanResults.ApplicabilityScanResults, cves)
+			applicabilityStatus := getApplicableCveStatus(applicabilityScanned, applicabilityScanResults, cves)
 			currSeverity, err := severityutils.ParseSeverity(violation.Severity, false)
 			if err != nil {
 				return nil, nil, nil, err
@@ -113,6 +124,8 @@ func prepareViolations(violations []services.Violation, results *Results, multip
 							ImpactedDependencyVersion: impactedPackagesVersions[compIndex],
 							ImpactedDependencyType:    impactedPackagesTypes[compIndex],
 							Components:                components[compIndex],
+							Scopes:                    scopes[compIndex],
+							WorkspaceMembers:          getImpactedWorkspaceMembers(impactPaths[compIndex], workspaceMembers),
 						},
 						FixedVersions:            fixedVersions[compIndex],
 						Cves:                     cves,
@@ -133,13 +146,16 @@ func prepareViolations(violations []services.Violation, results *Results, multip
 			for compIndex := 0; compIndex < len(impactedPackagesNames); compIndex++ {
 				licenseViolationsRows = append(licenseViolationsRows,
 					formats.LicenseRow{
-						LicenseKey: violation.LicenseKey,
+						LicenseKey:  violation.LicenseKey,
+						ImpactPaths: impactPaths[compIndex],
 						ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
 							SeverityDetails:           severityutils.GetAsDetails(currSeverity, jasutils.NotScanned, isTable),
 							ImpactedDependencyName:    impactedPackagesNames[compIndex],
 							ImpactedDependencyVersion: impactedPackagesVersions[compIndex],
 							ImpactedDependencyType:    impactedPackagesTypes[compIndex],
 							Components:                components[compIndex],
+							Scopes:                    scopes[compIndex],
+							WorkspaceMembers:          getImpactedWorkspaceMembers(impactPaths[compIndex], workspaceMembers),
 						},
 					},
 				)
@@ -158,6 +174,8 @@ func prepareViolations(violations []services.Violation, results *Results, multip
 						ImpactedDependencyVersion: impactedPackagesVersions[compIndex],
 						ImpactedDependencyType:    impactedPackagesTypes[compIndex],
 						Components:                components[compIndex],
+						Scopes:                    scopes[compIndex],
+						WorkspaceMembers:          getImpactedWorkspaceMembers(impactPaths[compIndex], workspaceMembers),
 					},
 					IsEol:         violationOpRiskData.isEol,
 					Cadence:       violationOpRiskData.cadence,
@@ -207,7 +225,12 @@ func PrintVulnerabilitiesTable(vulnerabilities []services.Vulnerability, results
 	} else {
 		emptyTableMessage = coreutils.PrintYellow("🔧 Couldn't determine a package manager or build tool used by this project 🔧")
 	}
-	return coreutils.PrintTable(formats.ConvertToVulnerabilityTableRow(vulnerabilitiesRows), "Vulnerable Dependencies", emptyTableMessage, printExtended)
+	// The declarations of the dependencies are shown relative to the working directory
+	baseDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return err
+	}
+	return coreutils.PrintTable(formats.ConvertToVulnerabilityTableRow(vulnerabilitiesRows, baseDir), "Vulnerable Dependencies", emptyTableMessage, printExtended)
 }
 
 // Prepare vulnerabilities for all non-table formats (without style or emoji)
@@ -220,18 +243,23 @@ func prepareVulnerabilities(vulnerabilities []services.Vulnerability, results *R
 		vulnerabilities = simplifyVulnerabilities(vulnerabilities, multipleRoots)
 	}
 	var vulnerabilitiesRows []formats.VulnerabilityOrViolationRow
+	workspaceMembers := results.GetWorkspaceMembers()
+	dependenciesScopes := results.GetDependenciesScopes()
+	dependenciesDeclarations := results.GetDependenciesDeclarations()
 	for _, vulnerability := range vulnerabilities {
-		impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes, fixedVersions, components, impactPaths, err := splitComponents(vulnerability.Components)
+		impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes, fixedVersions, components, impactPaths, scopes, err := splitComponents(vulnerability.Components, dependenciesScopes, dependenciesDeclarations)
 		if err != nil {
 			return nil, err
 		}
 		cves := convertCves(vulnerability.Cves)
-		if results.ExtendedScanResults.EntitledForJas {
+		applicabilityScanned := results.ExtendedScanResults.IsApplicabilityScanned(vulnerability.Components)
+		applicabilityScanResults := results.ExtendedScanResults.GetApplicabilityScanResults(vulnerability.Components)
+		if applicabilityScanned {
 			for i := range cves {
-				cves[i].Applicability = getCveApplicabilityField(cves[i].Id, results.ExtendedScanResults.ApplicabilityScanResults, vulnerability.Components)
+				cves[i].Applicability = getCveApplicabilityField(cves[i].Id, applicabilityScanResults, vulnerability.Components)
 			}
 		}
-		applicabilityStatus := getApplicableCveStatus(results.ExtendedScanResults.EntitledForJas, results.ExtendedScanResults.ApplicabilityScanResults, cves)
+		applicabilityStatus := getApplicableCveStatus(applicabilityScanned, applicabilityScanResults, cves)
 		currSeverity, err := severityutils.ParseSeverity(vulnerability.Severity, false)
 		if err != nil {
 			return nil, err
@@ -247,6 +275,8 @@ func prepareVulnerabilities(vulnerabilities []services.Vulnerability, results *R
 						ImpactedDependencyVersion: impactedPackagesVersions[compIndex],
 						ImpactedDependencyType:    impactedPackagesTypes[compIndex],
 						Components:                components[compIndex],
+						Scopes:                    scopes[compIndex],
+						WorkspaceMembers:          getImpactedWorkspaceMembers(impactPaths[compIndex], workspaceMembers),
 					},
 					FixedVersions:            fixedVersions[compIndex],
 					Cves:                     cves,
@@ -300,21 +330,22 @@ func getJfrogResearchPriority(vulnerabilityOrViolation formats.VulnerabilityOrVi
 // In case multipleRoots is true, the field Component will show the root of each impact path, otherwise it will show the root's child.
 // Set printExtended to true to print fields with 'extended' tag.
 // If the scan argument is set to true, print the scan tables.
-func PrintLicensesTable(licenses []services.License, printExtended bool, scanType CommandType) error {
-	licensesRows, err := PrepareLicenses(licenses)
+func PrintLicensesTable(licenses []services.License, dependenciesScopes map[string][]string, printExtended bool, scanType CommandType) error {
+	licensesRows, err := PrepareLicenses(licenses, dependenciesScopes)
 	if err != nil {
 		return err
 	}
 	if scanType.IsTargetBinary() {
 		return coreutils.PrintTable(formats.ConvertToLicenseScanTableRow(licensesRows), "Licenses", "No licenses were found", printExtended)
 	}
-	return coreutils.PrintTable(formats.ConvertToLicenseTableRow(licensesRows), "Licenses", "No licenses were found", printExtended)
+	return coreutils.PrintTable(formats.ConvertToLicenseTableRow(licensesRows, ""), "Licenses", "No licenses were found", printExtended)
 }
 
-func PrepareLicenses(licenses []services.License) ([]formats.LicenseRow, error) {
+// dependenciesScopes maps a component ID to its scopes in the project (runtime, dev or test), if known.
+func PrepareLicenses(licenses []services.License, dependenciesScopes map[string][]string) ([]formats.LicenseRow, error) {
 	var licensesRows []formats.LicenseRow
 	for _, license := range licenses {
-		impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes, _, components, impactPaths, err := splitComponents(license.Components)
+		impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes, _, components, impactPaths, scopes, err := splitComponents(license.Components, dependenciesScopes, DependenciesDeclarations{})
 		if err != nil {
 			return nil, err
 		}
@@ -328,6 +359,7 @@ func PrepareLicenses(licenses []services.License) ([]formats.LicenseRow, error)
 						ImpactedDependencyVersion: impactedPackagesVersions[compIndex],
 						ImpactedDependencyType:    impactedPackagesTypes[compIndex],
 						Components:                components[compIndex],
+						Scopes:                    scopes[compIndex],
 					},
 				},
 			)
@@ -364,6 +396,7 @@ func prepareSecrets(secrets []*sarif.Run, isTable bool) []formats.SourceCodeRow
 							EndColumn:   sarifutils.GetLocationEndColumn(location),
 							Snippet:     sarifutils.GetLocationSnippet(location),
 						},
+						Commit: getSecretCommitDetails(secretResult),
 					},
 				)
 			}
@@ -377,6 +410,19 @@ func prepareSecrets(secrets []*sarif.Run, isTable bool) []formats.SourceCodeRow
 	return secretsRows
 }
 
+// Returns the details of the commit that added the secret, if it was found in the Git history.
+func getSecretCommitDetails(secretResult *sarif.Result) *formats.CommitDetails {
+	sha := sarifutils.GetResultProperty(jasutils.CommitShaProperty, secretResult)
+	if sha == "" {
+		return nil
+	}
+	return &formats.CommitDetails{
+		Sha:    sha,
+		Author: sarifutils.GetResultProperty(jasutils.CommitAuthorProperty, secretResult),
+		Date:   sarifutils.GetResultProperty(jasutils.CommitDateProperty, secretResult),
+	}
+}
+
 func PrintSecretsTable(secrets []*sarif.Run, entitledForSecretsScan bool) error {
 	if entitledForSecretsScan {
 		secretsRows := prepareSecrets(secrets, true)
@@ -543,7 +589,27 @@ func convertJfrogResearchInformation(extendedInfo *services.ExtendedInformation)
 	}
 }
 
-func splitComponents(impactedPackages map[string]services.Component) (impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes []string, fixedVersions [][]string, directComponents [][]formats.ComponentRow, impactPaths [][][]formats.ComponentRow, err error) {
+// Returns the names of the workspace members that are the roots of the given impact paths.
+// workspaceMembers are the component IDs of the workspace members in the scanned projects.
+func getImpactedWorkspaceMembers(impactPaths [][]formats.ComponentRow, workspaceMembers []string) (membersNames []string) {
+	if len(workspaceMembers) == 0 {
+		return
+	}
+	for _, impactPath := range impactPaths {
+		if len(impactPath) == 0 || slices.Contains(membersNames, impactPath[0].Name) {
+			continue
+		}
+		for _, memberId := range workspaceMembers {
+			if name, version, _ := SplitComponentId(memberId); name == impactPath[0].Name && version == impactPath[0].Version {
+				membersNames = append(membersNames, name)
+				break
+			}
+		}
+	}
+	return
+}
+
+func splitComponents(impactedPackages map[string]services.Component, dependenciesScopes map[string][]string, dependenciesDeclarations DependenciesDeclarations) (impactedPackagesNames, impactedPackagesVersions, impactedPackagesTypes []string, fixedVersions [][]string, directComponents [][]formats.ComponentRow, impactPaths [][][]formats.ComponentRow, scopes [][]string, err error) {
 	if len(impactedPackages) == 0 {
 		err = errorutils.CheckErrorf("failed while parsing the response from Xray: violation doesn't have any components")
 		return
@@ -554,9 +620,10 @@ func splitComponents(impactedPackages map[string]services.Component) (impactedPa
 		impactedPackagesVersions = append(impactedPackagesVersions, currCompVersion)
 		impactedPackagesTypes = append(impactedPackagesTypes, currCompType)
 		fixedVersions = append(fixedVersions, currComp.FixedVersions)
-		currDirectComponents, currImpactPaths := getDirectComponentsAndImpactPaths(currComp.ImpactPaths)
+		currDirectComponents, currImpactPaths := getDirectComponentsAndImpactPaths(currComp.ImpactPaths, dependenciesDeclarations)
 		directComponents = append(directComponents, currDirectComponents)
 		impactPaths = append(impactPaths, currImpactPaths)
+		scopes = append(scopes, dependenciesScopes[currCompId])
 	}
 	return
 }
@@ -642,7 +709,8 @@ func SplitComponentId(componentId string) (string, string, string) {
 }
 
 // Gets a slice of the direct dependencies or packages of the scanned component, that depends on the vulnerable package, and converts the impact paths.
-func getDirectComponentsAndImpactPaths(impactPaths [][]services.ImpactPathNode) (components []formats.ComponentRow, impactPathsRows [][]formats.ComponentRow) {
+// The direct dependencies include where their versions are declared, if known.
+func getDirectComponentsAndImpactPaths(impactPaths [][]services.ImpactPathNode, dependenciesDeclarations DependenciesDeclarations) (components []formats.ComponentRow, impactPathsRows [][]formats.ComponentRow) {
 	componentsMap := make(map[string]formats.ComponentRow)
 
 	// The first node in the impact path is the scanned component itself. The second one is the direct dependency.
@@ -655,7 +723,7 @@ func getDirectComponentsAndImpactPaths(impactPaths [][]services.ImpactPathNode)
 		componentId := impactPath[impactPathIndex].ComponentId
 		if _, exist := componentsMap[componentId]; !exist {
 			compName, compVersion, _ := SplitComponentId(componentId)
-			componentsMap[componentId] = formats.ComponentRow{Name: compName, Version: compVersion}
+			componentsMap[componentId] = formats.ComponentRow{Name: compName, Version: compVersion, Declaration: dependenciesDeclarations.Get(impactPath[0].ComponentId, componentId)}
 		}
 
 		// Convert the impact path
@@ -909,8 +977,8 @@ func convertCves(cves []services.Cve) []formats.CveRow {
 	return cveRows
 }
 
-func getApplicableCveStatus(entitledForJas bool, applicabilityScanResults []*sarif.Run, cves []formats.CveRow) jasutils.ApplicabilityStatus {
-	if !entitledForJas || len(applicabilityScanResults) == 0 {
+func getApplicableCveStatus(applicabilityScanned bool, applicabilityScanResults []*sarif.Run, cves []formats.CveRow) jasutils.ApplicabilityStatus {
+	if !applicabilityScanned || len(applicabilityScanResults) == 0 {
 		return jasutils.NotScanned
 	}
 	if len(cves) == 0 {
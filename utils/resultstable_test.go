@@ -78,7 +78,7 @@ func TestGetDirectComponents(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		actualComponentRows, actualConvImpactPaths := getDirectComponentsAndImpactPaths(test.impactPaths)
+		actualComponentRows, actualConvImpactPaths := getDirectComponentsAndImpactPaths(test.impactPaths, DependenciesDeclarations{})
 		assert.ElementsMatch(t, test.expectedComponentRows, actualComponentRows)
 		assert.ElementsMatch(t, test.expectedConvImpactPaths, actualConvImpactPaths)
 	}
@@ -1289,6 +1289,17 @@ func TestGetFinalApplicabilityStatus(t *testing.T) {
 	}
 }
 
+func TestGetImpactedWorkspaceMembers(t *testing.T) {
+	impactPaths := [][]formats.ComponentRow{
+		{{Name: "root", Version: "1.0.0"}, {Name: "lodash", Version: "4.17.21"}},
+		{{Name: "@scope/web", Version: "0.1.0"}, {Name: "lodash", Version: "4.17.21"}},
+		{{Name: "api", Version: "0.1.0"}, {Name: "express", Version: "4.18.2"}, {Name: "lodash", Version: "4.17.21"}},
+		{{Name: "api", Version: "0.1.0"}, {Name: "lodash", Version: "4.17.21"}},
+	}
+	assert.Empty(t, getImpactedWorkspaceMembers(impactPaths, nil))
+	assert.Equal(t, []string{"@scope/web", "api"}, getImpactedWorkspaceMembers(impactPaths, []string{"npm://api:0.1.0", "npm://@scope/web:0.1.0"}))
+}
+
 func newBoolPtr(v bool) *bool {
 	return &v
 }
@@ -15,7 +15,10 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-security/formats"
 	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/descriptorutils"
+	"github.com/jfrog/jfrog-cli-security/utils/dockerutils"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/licenseutils"
 	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	clientUtils "github.com/jfrog/jfrog-client-go/utils"
@@ -169,13 +172,13 @@ func (rw *ResultsWriter) printScanResultsTables() (err error) {
 			}
 		}
 		if rw.includeLicenses {
-			if err = PrintLicensesTable(licenses, rw.printExtended, rw.results.ResultType); err != nil {
+			if err = PrintLicensesTable(licenses, rw.results.GetDependenciesScopes(), rw.printExtended, rw.results.ResultType); err != nil {
 				return
 			}
 		}
 	}
 	if shouldPrintTable(rw.subScansPreformed, SecretsScan, rw.results.ResultType) {
-		if err = PrintSecretsTable(rw.results.ExtendedScanResults.SecretsScanResults, rw.results.ExtendedScanResults.EntitledForJas); err != nil {
+		if err = PrintSecretsTable(rw.results.ExtendedScanResults.SecretsScanResults, rw.results.ExtendedScanResults.IsSecretsScanned()); err != nil {
 			return
 		}
 	}
@@ -245,13 +248,16 @@ func convertXrayResponsesToSarifRun(results *Results, isMultipleRoots, includeLi
 }
 
 func extractXrayIssuesToSarifRun(results *Results, run *sarif.Run, xrayJson formats.SimpleJsonResults) error {
+	locator := descriptorutils.NewDependenciesLocator()
+	// The descriptors of each technology in the working directory, which are searched once for all the issues
+	descriptorsPaths := map[techutils.Technology][]string{}
 	for _, vulnerability := range xrayJson.Vulnerabilities {
-		if err := addXrayCveIssueToSarifRun(results, vulnerability, run); err != nil {
+		if err := addXrayCveIssueToSarifRun(results, vulnerability, run, locator, descriptorsPaths); err != nil {
 			return err
 		}
 	}
 	for _, violation := range xrayJson.SecurityViolations {
-		if err := addXrayCveIssueToSarifRun(results, violation, run); err != nil {
+		if err := addXrayCveIssueToSarifRun(results, violation, run, locator, descriptorsPaths); err != nil {
 			return err
 		}
 	}
@@ -263,7 +269,7 @@ func extractXrayIssuesToSarifRun(results *Results, run *sarif.Run, xrayJson form
 	return nil
 }
 
-func addXrayCveIssueToSarifRun(results *Results, issue formats.VulnerabilityOrViolationRow, run *sarif.Run) (err error) {
+func addXrayCveIssueToSarifRun(results *Results, issue formats.VulnerabilityOrViolationRow, run *sarif.Run, locator *descriptorutils.DependenciesLocator, techDescriptorsPaths map[techutils.Technology][]string) (err error) {
 	maxCveScore, err := findMaxCVEScore(issue.Cves)
 	if err != nil {
 		return
@@ -272,6 +278,23 @@ func addXrayCveIssueToSarifRun(results *Results, issue formats.VulnerabilityOrVi
 	if err != nil {
 		return
 	}
+	descriptorsPaths, found := techDescriptorsPaths[issue.Technology]
+	if !found {
+		if descriptorsPaths, err = getDescriptorsFullPaths(issue.Technology, run); err != nil {
+			return
+		}
+		techDescriptorsPaths[issue.Technology] = descriptorsPaths
+	}
+	getComponentLocation := func(directDependency formats.ComponentRow) *sarif.Location {
+		if directDependency.Declaration != nil {
+			// The version of the direct dependency is declared in a known location
+			return getXrayIssueRegionLocation(directDependency.Declaration.Location)
+		}
+		if dependencyLocation := locator.GetDependencyLocation(directDependency.Name, descriptorsPaths...); dependencyLocation != nil {
+			return getXrayIssueRegionLocation(*dependencyLocation)
+		}
+		return location
+	}
 	formattedDirectDependencies, err := getDirectDependenciesFormatted(issue.Components)
 	if err != nil {
 		return
@@ -289,7 +312,7 @@ func addXrayCveIssueToSarifRun(results *Results, issue formats.VulnerabilityOrVi
 		getXrayIssueSarifHeadline(issue.ImpactedDependencyName, issue.ImpactedDependencyVersion, cveId),
 		markdownDescription,
 		issue.Components,
-		location,
+		getComponentLocation,
 		run,
 	)
 	return
@@ -311,13 +334,13 @@ func addXrayLicenseViolationToSarifRun(results *Results, license formats.License
 		getXrayLicenseSarifHeadline(license.ImpactedDependencyName, license.ImpactedDependencyVersion, license.LicenseKey),
 		getLicenseViolationMarkdown(license.ImpactedDependencyName, license.ImpactedDependencyVersion, license.LicenseKey, formattedDirectDependencies),
 		license.Components,
-		getXrayIssueLocation(""),
+		func(formats.ComponentRow) *sarif.Location { return getXrayIssueLocation("") },
 		run,
 	)
 	return
 }
 
-func addXrayIssueToSarifRun(resultType CommandType, issueId, impactedDependencyName, impactedDependencyVersion string, severity severityutils.Severity, severityScore, summary, title, markdownDescription string, components []formats.ComponentRow, location *sarif.Location, run *sarif.Run) {
+func addXrayIssueToSarifRun(resultType CommandType, issueId, impactedDependencyName, impactedDependencyVersion string, severity severityutils.Severity, severityScore, summary, title, markdownDescription string, components []formats.ComponentRow, getComponentLocation func(directDependency formats.ComponentRow) *sarif.Location, run *sarif.Run) {
 	// Add rule if not exists
 	ruleId := getXrayIssueSarifRuleId(impactedDependencyName, impactedDependencyVersion, issueId)
 	if rule, _ := run.GetRuleById(ruleId); rule == nil {
@@ -326,7 +349,7 @@ func addXrayIssueToSarifRun(resultType CommandType, issueId, impactedDependencyN
 	// Add result for each component
 	for _, directDependency := range components {
 		msg := getXrayIssueSarifHeadline(directDependency.Name, directDependency.Version, issueId)
-		if result := run.CreateResultForRule(ruleId).WithMessage(sarif.NewTextMessage(msg)).WithLevel(severityutils.SeverityToSarifSeverityLevel(severity).String()); location != nil {
+		if result, location := run.CreateResultForRule(ruleId).WithMessage(sarif.NewTextMessage(msg)).WithLevel(severityutils.SeverityToSarifSeverityLevel(severity).String()), getComponentLocation(directDependency); location != nil {
 			if resultType == DockerImage {
 				algorithm, layer := getLayerContentFromComponentId(directDependency.Name)
 				if layer != "" {
@@ -360,6 +383,29 @@ func getDescriptorFullPath(tech techutils.Technology, run *sarif.Run) (string, e
 	return "", nil
 }
 
+// Get the paths of the descriptors in which the direct dependencies of the technology are declared.
+// Descriptors that are identified by their extension, such as .csproj files, are searched in the working directory and its direct subdirectories.
+func getDescriptorsFullPaths(tech techutils.Technology, run *sarif.Run) (descriptorsPaths []string, err error) {
+	descriptorPath, err := getDescriptorFullPath(tech, run)
+	if err != nil {
+		return
+	}
+	if descriptorPath != "" {
+		descriptorsPaths = append(descriptorsPaths, descriptorPath)
+	}
+	for _, descriptor := range tech.GetPackageDescriptor() {
+		if !strings.HasPrefix(descriptor, ".") {
+			continue
+		}
+		var paths []string
+		if paths, err = descriptorutils.FindDescriptorsByExtension(sarifutils.GetFullLocationFileName(".", run.Invocations), descriptor); err != nil {
+			return
+		}
+		descriptorsPaths = append(descriptorsPaths, paths...)
+	}
+	return
+}
+
 // Get the descriptor location with the Xray issues if exists.
 func getXrayIssueLocationIfValidExists(tech techutils.Technology, run *sarif.Run) (location *sarif.Location, err error) {
 	descriptorPath, err := getDescriptorFullPath(tech, run)
@@ -376,6 +422,13 @@ func getXrayIssueLocation(filePath string) *sarif.Location {
 	return sarif.NewLocation().WithPhysicalLocation(sarif.NewPhysicalLocation().WithArtifactLocation(sarif.NewArtifactLocation().WithUri("file://" + filePath)))
 }
 
+// Get the location of the dependency declaration in the descriptor, with its region
+func getXrayIssueRegionLocation(location formats.Location) *sarif.Location {
+	physicalLocation := sarifutils.NewPhysicalLocationWithRegion("file://"+location.File, location.StartLine, location.EndLine, location.StartColumn, location.EndColumn)
+	physicalLocation.Region.WithSnippet(sarif.NewArtifactContent().WithText(location.Snippet))
+	return sarif.NewLocation().WithPhysicalLocation(physicalLocation)
+}
+
 func addXrayRule(ruleId, ruleDescription, maxCveScore, summary, markdownDescription string, run *sarif.Run) {
 	rule := run.AddRule(ruleId)
 
@@ -403,7 +456,7 @@ func ConvertXrayScanToSimpleJson(results *Results, isMultipleRoots, includeLicen
 		jsonTable.Vulnerabilities = vulJsonTable
 	}
 	if includeLicenses || len(allowedLicenses) > 0 {
-		licJsonTable, err := PrepareLicenses(licenses)
+		licJsonTable, err := PrepareLicenses(licenses, results.GetDependenciesScopes())
 		if err != nil {
 			return formats.SimpleJsonResults{}, err
 		}
@@ -429,8 +482,9 @@ func GetViolatedLicenses(allowedLicenses []string, licenses []formats.LicenseRow
 	if len(allowedLicenses) == 0 {
 		return
 	}
+	policy := licenseutils.NewLicensePolicy(allowedLicenses, nil)
 	for _, license := range licenses {
-		if !slices.Contains(allowedLicenses, license.LicenseKey) {
+		if !policy.IsCompliant(license.LicenseKey) {
 			violatedLicenses = append(violatedLicenses, license)
 		}
 	}
@@ -550,6 +604,8 @@ func patchRules(subScanType SubScanType, cmdResults *Results, rules ...*sarif.Re
 		if rule.Name != nil && rule.ID == *rule.Name {
 			rule.Name = nil
 		}
+		// Custom rules define their severity and CWEs in their metadata
+		patchCustomRuleMetadata(rule)
 		if cmdResults.ResultType.IsTargetBinary() && subScanType == SecretsScan {
 			// Patch the rule name in case of binary scan
 			sarifutils.SetRuleShortDescriptionText(fmt.Sprintf("[Secret in Binary found] %s", sarifutils.GetRuleShortDescriptionText(rule)), rule)
@@ -559,6 +615,23 @@ func patchRules(subScanType SubScanType, cmdResults *Results, rules ...*sarif.Re
 	return
 }
 
+// Sets the security severity score of a custom rule by its severity, and tags it with its CWEs.
+func patchCustomRuleMetadata(rule *sarif.ReportingDescriptor) {
+	if rule.Properties == nil {
+		return
+	}
+	if severity, ok := rule.Properties[jasutils.CustomRuleSeverityProperty].(string); ok {
+		rule.Properties[severityutils.SarifSeverityRuleProperty] = fmt.Sprintf("%.1f", severityutils.GetSeverityScore(severityutils.GetSeverity(severity), jasutils.Applicable))
+	}
+	if cwes, ok := rule.Properties[jasutils.CustomRuleCweProperty].([]string); ok {
+		tags := []string{"security"}
+		for _, cwe := range cwes {
+			tags = append(tags, "external/cwe/"+strings.ToLower(cwe))
+		}
+		rule.Properties["tags"] = tags
+	}
+}
+
 func patchResults(subScanType SubScanType, cmdResults *Results, run *sarif.Run, results ...*sarif.Result) (patched []*sarif.Result) {
 	patched = []*sarif.Result{}
 	for _, result := range results {
@@ -643,21 +716,29 @@ func convertBinaryPhysicalLocations(cmdResults *Results, run *sarif.Run, result
 		for _, location := range result.Locations {
 			// Patch the location - Reset the uri and region
 			location.PhysicalLocation = sarifutils.NewPhysicalLocation(patchedLocation)
+			if layerInfo := getDockerLayerInfo(cmdResults, location); layerInfo != nil && layerInfo.Instruction != nil && patchedLocation == cmdResults.DockerImageLayers.DockerfilePath {
+				// Point to the Dockerfile instruction that added the layer (the FROM instruction for base image layers)
+				location.PhysicalLocation.Region = &sarif.Region{StartLine: &layerInfo.Instruction.StartLine, EndLine: &layerInfo.Instruction.EndLine}
+			}
 		}
 	}
 }
 
 func getPatchedBinaryLocation(cmdResults *Results, run *sarif.Run) (patchedLocation string) {
 	if cmdResults.ResultType == DockerImage {
-		if patchedLocation = getDockerfileLocationIfExists(run); patchedLocation != "" {
+		if cmdResults.DockerImageLayers != nil && cmdResults.DockerImageLayers.DockerfilePath != "" {
+			return cmdResults.DockerImageLayers.DockerfilePath
+		}
+		if patchedLocation = GetDockerfileLocationIfExists(run.Invocations); patchedLocation != "" {
 			return
 		}
 	}
 	return getWorkflowFileLocationIfExists()
 }
 
-func getDockerfileLocationIfExists(run *sarif.Run) string {
-	potentialLocations := []string{filepath.Clean("Dockerfile"), sarifutils.GetFullLocationFileName("Dockerfile", run.Invocations)}
+// Returns the path of the Dockerfile in the current directory, the working directory of the invocations or the GitHub workspace, or an empty string if none exists.
+func GetDockerfileLocationIfExists(invocations []*sarif.Invocation) string {
+	potentialLocations := []string{filepath.Clean("Dockerfile"), sarifutils.GetFullLocationFileName("Dockerfile", invocations)}
 	for _, location := range potentialLocations {
 		if exists, err := fileutils.IsFileExists(location, false); err == nil && exists {
 			return location
@@ -747,7 +828,7 @@ func getBaseBinaryDescriptionMarkdown(subScanType SubScanType, cmdResults *Resul
 	if len(result.Locations) > 0 {
 		location = result.Locations[0]
 	}
-	return content + getBinaryLocationMarkdownString(cmdResults.ResultType, subScanType, location)
+	return content + getBinaryLocationMarkdownString(cmdResults.ResultType, subScanType, location) + getDockerLayerOriginMarkdownString(cmdResults, location)
 }
 
 func getDockerImageTag(cmdResults *Results) string {
@@ -791,6 +872,31 @@ func getBinaryLocationMarkdownString(commandType CommandType, subScanType SubSca
 	return
 }
 
+// If the layer was attributed to the Dockerfile, prepare the markdown string for its origin:
+// * Inherited from base image: <IMAGE> (Dockerfile line <LINE>)
+// * Added by instruction (Dockerfile line <LINE>): <INSTRUCTION>
+func getDockerLayerOriginMarkdownString(cmdResults *Results, location *sarif.Location) string {
+	layerInfo := getDockerLayerInfo(cmdResults, location)
+	if layerInfo == nil || layerInfo.Instruction == nil {
+		return ""
+	}
+	switch layerInfo.Origin {
+	case dockerutils.BaseImageLayer:
+		return fmt.Sprintf("\nInherited from base image: %s (Dockerfile line %d)", layerInfo.BaseImage, layerInfo.Instruction.StartLine)
+	case dockerutils.InstructionLayer:
+		return fmt.Sprintf("\nAdded by instruction (Dockerfile line %d): %s", layerInfo.Instruction.StartLine, layerInfo.Instruction.String())
+	}
+	return ""
+}
+
+func getDockerLayerInfo(cmdResults *Results, location *sarif.Location) *dockerutils.LayerInfo {
+	if cmdResults.ResultType != DockerImage || cmdResults.DockerImageLayers == nil || location == nil {
+		return nil
+	}
+	layer, _ := getDockerLayer(location)
+	return cmdResults.DockerImageLayers.GetLayer(layer)
+}
+
 func getDockerLayer(location *sarif.Location) (layer, algorithm string) {
 	// If location has logical location with kind "layer" return it
 	if logicalLocation := sarifutils.GetLogicalLocation("layer", location); logicalLocation != nil && logicalLocation.Name != nil {
@@ -1042,7 +1148,7 @@ func getScanViolationsSummary(extendedScanResults *ExtendedScanResults, scaResul
 				if violationType == ViolationTypeSecurity {
 					applicableRuns := []*sarif.Run{}
 					if extendedScanResults != nil {
-						applicableRuns = append(applicableRuns, extendedScanResults.ApplicabilityScanResults...)
+						applicableRuns = append(applicableRuns, extendedScanResults.GetApplicabilityScanResults(violation.Components)...)
 					}
 					violationsUniqueFindings[violationType][severity] = mergeMaps(violationsUniqueFindings[violationType][severity], getSecuritySummaryFindings(violation.Cves, violation.IssueId, violation.Components, applicableRuns...))
 				} else {
@@ -1088,7 +1194,7 @@ func getScanSecurityVulnerabilitiesSummary(extendedScanResults *ExtendedScanResu
 				severity := severityutils.GetSeverity(vulnerability.Severity).String()
 				applicableRuns := []*sarif.Run{}
 				if extendedScanResults != nil {
-					applicableRuns = append(applicableRuns, extendedScanResults.ApplicabilityScanResults...)
+					applicableRuns = append(applicableRuns, extendedScanResults.GetApplicabilityScanResults(vulnerability.Components)...)
 				}
 				vulnerabilities.ScaResults.Security[severity] = mergeMaps(vulnerabilities.ScaResults.Security[severity], getSecuritySummaryFindings(vulnerability.Cves, vulnerability.IssueId, vulnerability.Components, applicableRuns...))
 			}
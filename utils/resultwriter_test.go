@@ -10,13 +10,17 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	"github.com/jfrog/jfrog-cli-security/formats"
 	"github.com/jfrog/jfrog-cli-security/formats/sarifutils"
+	"github.com/jfrog/jfrog-cli-security/utils/descriptorutils"
+	"github.com/jfrog/jfrog-cli-security/utils/dockerutils"
 	"github.com/jfrog/jfrog-cli-security/utils/jasutils"
+	"github.com/jfrog/jfrog-cli-security/utils/severityutils"
 	"github.com/jfrog/jfrog-cli-security/utils/techutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	clientTests "github.com/jfrog/jfrog-client-go/utils/tests"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	"github.com/owenrumney/go-sarif/v2/sarif"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetVulnerabilityOrViolationSarifHeadline(t *testing.T) {
@@ -225,6 +229,35 @@ func TestGetXrayIssueLocationIfValidExists(t *testing.T) {
 	}
 }
 
+func TestAddXrayCveIssueToSarifRunWithDependenciesLocations(t *testing.T) {
+	testDir, cleanup := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer cleanup()
+	assert.NoError(t, os.WriteFile(filepath.Join(testDir, "package.json"), []byte("{\n  \"dependencies\": {\n    \"lodash\": \"4.17.0\"\n  }\n}\n"), 0644))
+	run := sarif.NewRunWithInformationURI("JFrog Xray Scanner", BaseDocumentationURL+"sca").WithInvocations([]*sarif.Invocation{sarif.NewInvocation().WithWorkingDirectory(sarif.NewSimpleArtifactLocation(testDir))})
+	issue := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyDetails: formats.ImpactedDependencyDetails{
+			SeverityDetails:           formats.SeverityDetails{Severity: "High"},
+			ImpactedDependencyName:    "lodash",
+			ImpactedDependencyVersion: "4.17.0",
+			Components:                []formats.ComponentRow{{Name: "lodash", Version: "4.17.0"}, {Name: "express", Version: "4.18.0"}},
+		},
+		Cves:       []formats.CveRow{{Id: "CVE-2021-23337"}},
+		Technology: techutils.Npm,
+	}
+	assert.NoError(t, addXrayCveIssueToSarifRun(&Results{}, issue, run, descriptorutils.NewDependenciesLocator(), map[techutils.Technology][]string{}))
+	require.Len(t, run.Results, 2)
+	// The declared dependency is located at its declaration
+	location := run.Results[0].Locations[0]
+	assert.Equal(t, "file://"+filepath.Join(testDir, "package.json"), sarifutils.GetLocationFileName(location))
+	assert.Equal(t, 3, sarifutils.GetLocationStartLine(location))
+	assert.Equal(t, 5, sarifutils.GetLocationStartColumn(location))
+	assert.Equal(t, `"lodash": "4.17.0"`, sarifutils.GetLocationSnippet(location))
+	// Otherwise, at the descriptor
+	location = run.Results[1].Locations[0]
+	assert.Equal(t, "file://"+filepath.Join(testDir, "package.json"), sarifutils.GetLocationFileName(location))
+	assert.Nil(t, location.PhysicalLocation.Region)
+}
+
 func TestConvertXrayScanToSimpleJson(t *testing.T) {
 	vulnerabilities := []services.Vulnerability{
 		{
@@ -611,6 +644,57 @@ func TestGetLayerContentFromComponentId(t *testing.T) {
 	}
 }
 
+func TestDockerLayerAttribution(t *testing.T) {
+	from := &dockerutils.DockerfileInstruction{Command: "FROM", Value: "alpine:3.19", StartLine: 1, EndLine: 1}
+	run := &dockerutils.DockerfileInstruction{Command: "RUN", Value: "apk add curl", StartLine: 3, EndLine: 4}
+	cmdResults := &Results{ResultType: DockerImage, DockerImageLayers: &dockerutils.ImageLayers{
+		DockerfilePath: "Dockerfile",
+		BaseImage:      "alpine:3.19",
+		Layers: []*dockerutils.LayerInfo{
+			{Digests: []string{"aaaa"}, Algorithm: "sha256", Origin: dockerutils.BaseImageLayer, BaseImage: "alpine:3.19", Instruction: from},
+			{Digests: []string{"bbbb"}, Algorithm: "sha256", Origin: dockerutils.InstructionLayer, Instruction: run},
+			{Digests: []string{"cccc"}, Algorithm: "sha256"},
+		},
+	}}
+	testCases := []struct {
+		name             string
+		layer            string
+		expectedMarkdown string
+		expectedRegion   *sarif.Region
+	}{
+		{
+			name:             "Base image layer",
+			layer:            "aaaa",
+			expectedMarkdown: "\nInherited from base image: alpine:3.19 (Dockerfile line 1)",
+			expectedRegion:   &sarif.Region{StartLine: &from.StartLine, EndLine: &from.EndLine},
+		},
+		{
+			name:             "Instruction layer",
+			layer:            "bbbb",
+			expectedMarkdown: "\nAdded by instruction (Dockerfile line 3): RUN apk add curl",
+			expectedRegion:   &sarif.Region{StartLine: &run.StartLine, EndLine: &run.EndLine},
+		},
+		{
+			name:  "Not attributed layer",
+			layer: "cccc",
+		},
+		{
+			name:  "Unknown layer",
+			layer: "dddd",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			location := sarifutils.CreateDummyLocationWithPathAndLogicalLocation("image.tar", tc.layer, "layer", "algorithm", "sha256")
+			result := &sarif.Result{Locations: []*sarif.Location{location}}
+			assert.Equal(t, tc.expectedMarkdown, getDockerLayerOriginMarkdownString(cmdResults, location))
+			convertBinaryPhysicalLocations(cmdResults, &sarif.Run{}, result)
+			assert.Equal(t, "Dockerfile", sarifutils.GetLocationFileName(location))
+			assert.Equal(t, tc.expectedRegion, location.PhysicalLocation.Region)
+		})
+	}
+}
+
 func preparePatchTestEnv(t *testing.T) (string, string, func()) {
 	currentWd, err := os.Getwd()
 	assert.NoError(t, err)
@@ -863,3 +947,20 @@ func getDummyScaTestResults(vulnerability, violation bool) (responses []services
 	responses = append(responses, response)
 	return
 }
+
+func TestPatchCustomRuleMetadata(t *testing.T) {
+	rule := sarif.NewRule("internal-token").WithProperties(sarif.Properties{
+		severityutils.SarifSeverityRuleProperty: "6.9",
+		jasutils.CustomRuleSeverityProperty:     "Critical",
+		jasutils.CustomRuleCweProperty:          []string{"CWE-798", "CWE-259"},
+	})
+	patched := patchRules(SecretsScan, &Results{ResultType: SourceCode}, rule)
+	require.Len(t, patched, 1)
+	assert.Equal(t, "10.0", patched[0].Properties[severityutils.SarifSeverityRuleProperty])
+	assert.Equal(t, []string{"security", "external/cwe/cwe-798", "external/cwe/cwe-259"}, patched[0].Properties["tags"])
+
+	// The rules of the scanners aren't changed
+	rule = sarif.NewRule("aws-access-key-id").WithProperties(sarif.Properties{severityutils.SarifSeverityRuleProperty: "8.9"})
+	patched = patchRules(SecretsScan, &Results{ResultType: SourceCode}, rule)
+	assert.Equal(t, sarif.Properties{severityutils.SarifSeverityRuleProperty: "8.9"}, patched[0].Properties)
+}
@@ -33,6 +33,8 @@ const (
 	Pip    Technology = "pip"
 	Pipenv Technology = "pipenv"
 	Poetry Technology = "poetry"
+	Uv     Technology = "uv"
+	Pdm    Technology = "pdm"
 	Nuget  Technology = "nuget"
 	Dotnet Technology = "dotnet"
 	Docker Technology = "docker"
@@ -52,6 +54,15 @@ const (
 	CPP        CodeLanguage = "C++"
 )
 
+// The scopes of the project dependencies, as resolved by the package managers.
+const (
+	RuntimeScope = "runtime"
+	DevScope     = "dev"
+	TestScope    = "test"
+)
+
+var DependencyScopes = []string{RuntimeScope, DevScope, TestScope}
+
 // Associates a technology with project type (used in config commands for the package-managers).
 // Docker is not present, as there is no docker-config command and, consequently, no docker.yaml file we need to operate on.
 var TechToProjectType = map[Technology]project.ProjectType{
@@ -63,6 +74,9 @@ var TechToProjectType = map[Technology]project.ProjectType{
 	Pip:    project.Pip,
 	Pipenv: project.Pipenv,
 	Poetry: project.Poetry,
+	// uv and PDM resolve their dependencies from PyPI repositories, configured using the pip config command.
+	Uv:     project.Pip,
+	Pdm:    project.Pip,
 	Nuget:  project.Nuget,
 	Dotnet: project.Dotnet,
 }
@@ -80,6 +94,8 @@ type TechData struct {
 	exclude []string
 	// The files that handle the project's dependencies.
 	packageDescriptors []string
+	// The files that lock the resolved versions of the project's dependencies.
+	lockFiles []string
 	// Formal name of the technology
 	formal string
 	// The executable name of the technology
@@ -102,11 +118,13 @@ var technologiesData = map[Technology]TechData{
 	Gradle: {
 		indicators:         []string{"build.gradle", "build.gradle.kts"},
 		packageDescriptors: []string{"build.gradle", "build.gradle.kts"},
+		lockFiles:          []string{"gradle.lockfile", "settings.gradle", "settings.gradle.kts", filepath.Join("gradle", "libs.versions.toml")},
 	},
 	Npm: {
 		indicators:                 []string{"package.json", "package-lock.json", "npm-shrinkwrap.json"},
 		exclude:                    []string{"pnpm-lock.yaml", ".yarnrc.yml", "yarn.lock", ".yarn"},
 		packageDescriptors:         []string{"package.json"},
+		lockFiles:                  []string{"package-lock.json", "npm-shrinkwrap.json"},
 		formal:                     string(Npm),
 		packageVersionOperator:     "@",
 		packageInstallationCommand: "install",
@@ -115,6 +133,7 @@ var technologiesData = map[Technology]TechData{
 		indicators:                 []string{"pnpm-lock.yaml"},
 		exclude:                    []string{".yarnrc.yml", "yarn.lock", ".yarn"},
 		packageDescriptors:         []string{"package.json"},
+		lockFiles:                  []string{"pnpm-lock.yaml"},
 		packageVersionOperator:     "@",
 		packageInstallationCommand: "update",
 	},
@@ -122,11 +141,13 @@ var technologiesData = map[Technology]TechData{
 		indicators:             []string{".yarnrc.yml", "yarn.lock", ".yarn", ".yarnrc"},
 		exclude:                []string{"pnpm-lock.yaml"},
 		packageDescriptors:     []string{"package.json"},
+		lockFiles:              []string{"yarn.lock"},
 		packageVersionOperator: "@",
 	},
 	Go: {
-		indicators:                 []string{"go.mod"},
+		indicators:                 []string{"go.mod", "go.work"},
 		packageDescriptors:         []string{"go.mod"},
+		lockFiles:                  []string{"go.sum", "go.work.sum", filepath.Join("vendor", "modules.txt")},
 		packageVersionOperator:     "@v",
 		packageInstallationCommand: "get",
 	},
@@ -135,12 +156,13 @@ var technologiesData = map[Technology]TechData{
 		indicators:         []string{"pyproject.toml", "setup.py", "requirements.txt"},
 		validators:         map[string]ContentValidator{"pyproject.toml": pyProjectTomlIndicatorContent(Pip)},
 		packageDescriptors: []string{"setup.py", "requirements.txt", "pyproject.toml"},
-		exclude:            []string{"Pipfile", "Pipfile.lock", "poetry.lock"},
+		exclude:            []string{"Pipfile", "Pipfile.lock", "poetry.lock", "uv.lock", "pdm.lock"},
 	},
 	Pipenv: {
 		packageType:                Pypi,
 		indicators:                 []string{"Pipfile", "Pipfile.lock"},
 		packageDescriptors:         []string{"Pipfile"},
+		lockFiles:                  []string{"Pipfile.lock"},
 		packageVersionOperator:     "==",
 		packageInstallationCommand: "install",
 	},
@@ -149,12 +171,33 @@ var technologiesData = map[Technology]TechData{
 		indicators:                 []string{"pyproject.toml", "poetry.lock"},
 		validators:                 map[string]ContentValidator{"pyproject.toml": pyProjectTomlIndicatorContent(Poetry)},
 		packageDescriptors:         []string{"pyproject.toml"},
+		lockFiles:                  []string{"poetry.lock"},
+		packageInstallationCommand: "add",
+		packageVersionOperator:     "==",
+	},
+	Uv: {
+		packageType:                Pypi,
+		indicators:                 []string{"uv.lock"},
+		packageDescriptors:         []string{"pyproject.toml"},
+		lockFiles:                  []string{"uv.lock"},
+		formal:                     "uv",
+		packageInstallationCommand: "add",
+		packageVersionOperator:     "==",
+	},
+	Pdm: {
+		packageType:                Pypi,
+		indicators:                 []string{"pdm.lock"},
+		exclude:                    []string{"uv.lock"},
+		packageDescriptors:         []string{"pyproject.toml"},
+		lockFiles:                  []string{"pdm.lock"},
+		formal:                     "PDM",
 		packageInstallationCommand: "add",
 		packageVersionOperator:     "==",
 	},
 	Nuget: {
 		indicators:         []string{".sln", ".csproj"},
 		packageDescriptors: []string{".sln", ".csproj"},
+		lockFiles:          []string{"packages.lock.json"},
 		formal:             "NuGet",
 		// .NET CLI is used for NuGet projects
 		execCommand:                "dotnet",
@@ -176,27 +219,16 @@ var technologiesData = map[Technology]TechData{
 	},
 }
 
-var (
-	// [tool.poetry] section
-	pyProjectTomlPoetryRegex = regexp.MustCompile(`(?ms)^\[tool\.poetry\]`)
-	// `hatchling` in the [build-system] section
-	pyProjectTomlHatchRegex = regexp.MustCompile(`(?ms)^\[build-system\].*requires\s*=\s*\[.*"hatchling".*]`)
-	// `flit_core` in the [build-system] section
-	pyProjectTomlFlitRegex = regexp.MustCompile(`(?ms)^\[build-system\].*requires\s*=\s*\[.*"flit_core[^\]]*.*]`)
-	// `pdm-pep517` in the [build-system] section
-	pyProjectTomlPdmRegex = regexp.MustCompile(`(?ms)^\[build-system\].*requires\s*=\s*\[.*"pdm-pep517".*]`)
-)
+// [tool.poetry] section
+var pyProjectTomlPoetryRegex = regexp.MustCompile(`(?ms)^\[tool\.poetry\]`)
 
 func pyProjectTomlIndicatorContent(tech Technology) ContentValidator {
 	return func(content []byte) bool {
 		if pyProjectTomlPoetryRegex.Match(content) {
 			return tech == Poetry
 		}
-		if pyProjectTomlHatchRegex.Match(content) || pyProjectTomlFlitRegex.Match(content) || pyProjectTomlPdmRegex.Match(content) {
-			// Not supported yet
-			return false
-		}
-		// Default to Pip
+		// Default to Pip. PEP 621 projects that are built with other backends (such as Hatch, Flit or PDM) are installed by Pip as well.
+		// uv and PDM projects with a lock file are detected by it.
 		return tech == Pip
 	}
 }
@@ -207,6 +239,8 @@ func TechnologyToLanguage(technology Technology) CodeLanguage {
 		Pip:    Python,
 		Poetry: Python,
 		Pipenv: Python,
+		Uv:     Python,
+		Pdm:    Python,
 		Go:     GoLang,
 		Maven:  Java,
 		Gradle: Java,
@@ -247,6 +281,10 @@ func (tech Technology) GetPackageDescriptor() []string {
 	return technologiesData[tech].packageDescriptors
 }
 
+func (tech Technology) GetLockFiles() []string {
+	return technologiesData[tech].lockFiles
+}
+
 func (tech Technology) GetPackageVersionOperator() string {
 	return technologiesData[tech].packageVersionOperator
 }
@@ -312,7 +350,10 @@ func DetectTechnologiesDescriptors(path string, recursive bool, requestedTechs [
 	} else if len(workingDirectoryToIndicators) > 0 {
 		log.Debug(fmt.Sprintf("mapped %d working directories with indicators/descriptors:\n%s", len(workingDirectoryToIndicators), strJson))
 	}
-	technologiesDetected, err = mapWorkingDirectoriesToTechnologies(workingDirectoryToIndicators, excludedTechAtWorkingDir, ToTechnologies(requestedTechs), requestedDescriptors)
+	if technologiesDetected, err = mapWorkingDirectoriesToTechnologies(workingDirectoryToIndicators, excludedTechAtWorkingDir, ToTechnologies(requestedTechs), requestedDescriptors); err != nil {
+		return
+	}
+	removeWorkspacesMembers(technologiesDetected)
 	if len(technologiesDetected) > 0 {
 		log.Debug(fmt.Sprintf("Detected %d technologies at %s: %s.", len(technologiesDetected), path, maps.Keys(technologiesDetected)))
 	}
@@ -98,6 +98,20 @@ func TestMapFilesToRelevantWorkingDirectories(t *testing.T) {
 			expectedWorkingDir:   map[string][]string{filepath.Join("users", "test", "package"): {filepath.Join("users", "test", "package", "Pipfile")}},
 			expectedExcluded:     map[string][]Technology{filepath.Join("users", "test", "package"): {Pip}},
 		},
+		{
+			name:                 "uvTest",
+			paths:                []string{filepath.Join("dir", "uv.lock")},
+			requestedDescriptors: noRequest,
+			expectedWorkingDir:   map[string][]string{"dir": {filepath.Join("dir", "uv.lock")}},
+			expectedExcluded:     map[string][]Technology{"dir": {Pip, Pdm}},
+		},
+		{
+			name:                 "pdmTest",
+			paths:                []string{filepath.Join("dir", "pdm.lock")},
+			requestedDescriptors: noRequest,
+			expectedWorkingDir:   map[string][]string{"dir": {filepath.Join("dir", "pdm.lock")}},
+			expectedExcluded:     map[string][]Technology{"dir": {Pip}},
+		},
 		{
 			name:                 "gradleTest",
 			paths:                []string{filepath.Join("users", "test", "package", "build.gradle"), filepath.Join("dir", "build.gradle.kts"), filepath.Join("dir", "file")},
@@ -523,6 +537,8 @@ func TestTechnologyToLanguage(t *testing.T) {
 		{name: "Pip to Python", technology: Pip, language: Python},
 		{name: "Pipenv to Python", technology: Pipenv, language: Python},
 		{name: "Poetry to Python", technology: Poetry, language: Python},
+		{name: "Uv to Python", technology: Uv, language: Python},
+		{name: "Pdm to Python", technology: Pdm, language: Python},
 		{name: "Nuget to CSharp", technology: Nuget, language: CSharp},
 		{name: "Dotnet to CSharp", technology: Dotnet, language: CSharp},
 	}
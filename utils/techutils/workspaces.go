@@ -0,0 +1,256 @@
+package techutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PnpmWorkspaceFileName = "pnpm-workspace.yaml"
+	packageJsonFileName   = "package.json"
+	GoWorkFileName        = "go.work"
+)
+
+// The technologies that share the package.json descriptor and support workspaces
+var javaScriptWorkspacesTechnologies = []Technology{Npm, Yarn, Pnpm}
+
+// The directories that are never workspace members
+var workspaceSkippedDirs = []string{"node_modules", ".git"}
+
+// The workspaces of a package.json file can be an array of patterns, or an object with the patterns under 'packages' (Yarn)
+type packageJsonWorkspaces struct {
+	Workspaces json.RawMessage `json:"workspaces,omitempty"`
+}
+
+type pnpmWorkspace struct {
+	Packages []string `yaml:"packages"`
+}
+
+// Returns the directories of the members of the npm, Yarn or pnpm workspace in the given directory, sorted.
+// The members are declared by glob patterns in the 'workspaces' field of the package.json file, or in the pnpm-workspace.yaml file. Patterns that start with '!' exclude members.
+// Returns an empty list if the directory isn't a workspace root.
+func GetJavaScriptWorkspaceMembers(rootDir string) (members []string, err error) {
+	patterns, err := getWorkspacePatterns(rootDir)
+	if err != nil || len(patterns) == 0 {
+		return
+	}
+	var includes, excludes []*regexp.Regexp
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+		if exclude := strings.HasPrefix(pattern, "!"); exclude {
+			excludes = append(excludes, workspacePatternToRegex(strings.TrimPrefix(pattern, "!")))
+		} else if pattern != "" {
+			includes = append(includes, workspacePatternToRegex(pattern))
+		}
+	}
+	err = filepath.WalkDir(rootDir, func(path string, entry os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !entry.IsDir() || path == rootDir {
+			return nil
+		}
+		if slices.Contains(workspaceSkippedDirs, entry.Name()) {
+			return filepath.SkipDir
+		}
+		relativePath, relErr := filepath.Rel(rootDir, path)
+		if relErr != nil {
+			return relErr
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if matchesAny(includes, relativePath) && !matchesAny(excludes, relativePath) && fileutils.IsPathExists(filepath.Join(path, packageJsonFileName), false) {
+			members = append(members, path)
+		}
+		return nil
+	})
+	if err = errorutils.CheckError(err); err != nil {
+		return nil, err
+	}
+	sort.Strings(members)
+	return
+}
+
+func getWorkspacePatterns(rootDir string) (patterns []string, err error) {
+	pnpmWorkspacePath := filepath.Join(rootDir, PnpmWorkspaceFileName)
+	if fileutils.IsPathExists(pnpmWorkspacePath, false) {
+		content, readErr := os.ReadFile(pnpmWorkspacePath)
+		if readErr != nil {
+			return nil, errorutils.CheckError(readErr)
+		}
+		workspace := pnpmWorkspace{}
+		if err = yaml.Unmarshal(content, &workspace); err != nil {
+			return nil, errorutils.CheckErrorf("failed to parse %s: %s", pnpmWorkspacePath, err.Error())
+		}
+		return workspace.Packages, nil
+	}
+	packageJsonPath := filepath.Join(rootDir, packageJsonFileName)
+	if !fileutils.IsPathExists(packageJsonPath, false) {
+		return
+	}
+	content, err := os.ReadFile(packageJsonPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	packageJson := packageJsonWorkspaces{}
+	if err = json.Unmarshal(content, &packageJson); err != nil || len(packageJson.Workspaces) == 0 {
+		// Invalid package.json files are reported by the package managers
+		return nil, nil
+	}
+	if err = json.Unmarshal(packageJson.Workspaces, &patterns); err == nil {
+		return
+	}
+	yarnWorkspaces := struct {
+		Packages []string `json:"packages"`
+	}{}
+	if err = json.Unmarshal(packageJson.Workspaces, &yarnWorkspaces); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the workspaces of %s: %s", packageJsonPath, err.Error())
+	}
+	return yarnWorkspaces.Packages, nil
+}
+
+// Converts a workspace glob pattern to a regular expression. '**' matches any number of directories and '*' matches a single path element.
+func workspacePatternToRegex(pattern string) *regexp.Regexp {
+	var regex strings.Builder
+	regex.WriteString("^")
+	pattern = strings.TrimSuffix(pattern, "/")
+	for i := 0; i < len(pattern); i++ {
+		switch char := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			regex.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			regex.WriteString(".*")
+			i++
+		case char == '*':
+			regex.WriteString("[^/]*")
+		case char == '?':
+			regex.WriteString("[^/]")
+		default:
+			regex.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	regex.WriteString("$")
+	return regexp.MustCompile(regex.String())
+}
+
+func matchesAny(regexes []*regexp.Regexp, path string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the directories of the modules of the Go workspace in the given directory, sorted. The modules are declared by the 'use' directives of the go.work file.
+// Returns an empty list if the directory isn't a workspace root.
+func GetGoWorkspaceMembers(rootDir string) (members []string, err error) {
+	workFile, err := ParseGoWorkFile(rootDir)
+	if err != nil || workFile == nil {
+		return
+	}
+	for _, use := range workFile.Use {
+		if member := filepath.Join(rootDir, filepath.FromSlash(use.Path)); member != filepath.Clean(rootDir) {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+	return
+}
+
+// Parses the go.work file in the directory. Returns nil if the directory isn't a Go workspace root.
+func ParseGoWorkFile(rootDir string) (*modfile.WorkFile, error) {
+	workFilePath := filepath.Join(rootDir, GoWorkFileName)
+	if !fileutils.IsPathExists(workFilePath, false) {
+		return nil, nil
+	}
+	content, err := os.ReadFile(workFilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	workFile, err := modfile.ParseWork(workFilePath, content, nil)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse %s: %s", workFilePath, err.Error())
+	}
+	return workFile, nil
+}
+
+// The members of a workspace are scanned with the workspace root.
+// The members' directories are removed from the detected working directories, and their descriptors are added to the root.
+func removeWorkspacesMembers(technologiesDetected map[Technology]map[string][]string) {
+	removeJavaScriptWorkspacesMembers(technologiesDetected)
+	removeGoWorkspacesMembers(technologiesDetected)
+}
+
+// The root of a JavaScript workspace is detected as one of the JavaScript technologies, and its members may be detected as any of them.
+func removeJavaScriptWorkspacesMembers(technologiesDetected map[Technology]map[string][]string) {
+	membersToRoot := map[string]string{}
+	rootsTechnologies := map[string]Technology{}
+	for _, tech := range javaScriptWorkspacesTechnologies {
+		for wd := range technologiesDetected[tech] {
+			members, err := GetJavaScriptWorkspaceMembers(wd)
+			if err != nil {
+				log.Warn(fmt.Sprintf("Couldn't get the workspace members of %s, they will be scanned separately: %s", wd, err.Error()))
+				continue
+			}
+			for _, member := range members {
+				membersToRoot[member] = wd
+			}
+			if len(members) > 0 {
+				rootsTechnologies[wd] = tech
+			}
+		}
+	}
+	for _, tech := range javaScriptWorkspacesTechnologies {
+		removed := false
+		for wd, descriptors := range technologiesDetected[tech] {
+			root, isMember := membersToRoot[wd]
+			if !isMember {
+				continue
+			}
+			log.Debug(fmt.Sprintf("The directory %s is a member of the workspace in %s and will be scanned with it", wd, root))
+			rootTech := rootsTechnologies[root]
+			technologiesDetected[rootTech][root] = append(technologiesDetected[rootTech][root], descriptors...)
+			delete(technologiesDetected[tech], wd)
+			removed = true
+		}
+		if removed && len(technologiesDetected[tech]) == 0 {
+			delete(technologiesDetected, tech)
+		}
+	}
+}
+
+func removeGoWorkspacesMembers(technologiesDetected map[Technology]map[string][]string) {
+	membersToRoot := map[string]string{}
+	for wd := range technologiesDetected[Go] {
+		members, err := GetGoWorkspaceMembers(wd)
+		if err != nil {
+			log.Warn(fmt.Sprintf("Couldn't get the modules of the Go workspace in %s, they will be scanned separately: %s", wd, err.Error()))
+			continue
+		}
+		for _, member := range members {
+			membersToRoot[member] = wd
+		}
+	}
+	for wd, descriptors := range technologiesDetected[Go] {
+		root, isMember := membersToRoot[wd]
+		if !isMember {
+			continue
+		}
+		log.Debug(fmt.Sprintf("The directory %s is a module of the Go workspace in %s and will be scanned with it", wd, root))
+		technologiesDetected[Go][root] = append(technologiesDetected[Go][root], descriptors...)
+		delete(technologiesDetected[Go], wd)
+	}
+}
@@ -0,0 +1,115 @@
+package techutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createWorkspace(t *testing.T, rootFiles map[string]string, membersDirs ...string) string {
+	rootDir := t.TempDir()
+	for fileName, content := range rootFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, fileName), []byte(content), 0644))
+	}
+	for _, memberDir := range membersDirs {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, memberDir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, memberDir, packageJsonFileName), []byte(`{"name": "`+filepath.Base(memberDir)+`"}`), 0644))
+	}
+	return rootDir
+}
+
+func TestGetJavaScriptWorkspaceMembers(t *testing.T) {
+	tests := []struct {
+		name            string
+		rootFiles       map[string]string
+		membersDirs     []string
+		expectedMembers []string
+	}{
+		{
+			name:        "not a workspace",
+			rootFiles:   map[string]string{packageJsonFileName: `{"name": "root"}`},
+			membersDirs: []string{filepath.Join("packages", "a")},
+		},
+		{
+			name:            "npm workspaces",
+			rootFiles:       map[string]string{packageJsonFileName: `{"name": "root", "workspaces": ["packages/*", "tools/cli"]}`},
+			membersDirs:     []string{filepath.Join("packages", "b"), filepath.Join("packages", "a"), filepath.Join("tools", "cli"), filepath.Join("tools", "other")},
+			expectedMembers: []string{filepath.Join("packages", "a"), filepath.Join("packages", "b"), filepath.Join("tools", "cli")},
+		},
+		{
+			name:            "yarn workspaces object",
+			rootFiles:       map[string]string{packageJsonFileName: `{"name": "root", "workspaces": {"packages": ["packages/**"], "nohoist": ["**/react"]}}`},
+			membersDirs:     []string{filepath.Join("packages", "a"), filepath.Join("packages", "nested", "b")},
+			expectedMembers: []string{filepath.Join("packages", "a"), filepath.Join("packages", "nested", "b")},
+		},
+		{
+			name: "pnpm workspace with exclusion",
+			rootFiles: map[string]string{
+				packageJsonFileName:   `{"name": "root"}`,
+				PnpmWorkspaceFileName: "packages:\n  - 'packages/*'\n  - '!packages/test'\n",
+			},
+			membersDirs:     []string{filepath.Join("packages", "a"), filepath.Join("packages", "test"), filepath.Join("packages", "node_modules")},
+			expectedMembers: []string{filepath.Join("packages", "a")},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rootDir := createWorkspace(t, test.rootFiles, test.membersDirs...)
+			// A directory without a package.json isn't a member
+			require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "packages", "empty"), 0755))
+			members, err := GetJavaScriptWorkspaceMembers(rootDir)
+			require.NoError(t, err)
+			var expected []string
+			for _, member := range test.expectedMembers {
+				expected = append(expected, filepath.Join(rootDir, member))
+			}
+			assert.Equal(t, expected, members)
+		})
+	}
+}
+
+func TestRemoveWorkspacesMembers(t *testing.T) {
+	rootDir := createWorkspace(t, map[string]string{packageJsonFileName: `{"name": "root", "workspaces": ["packages/*"]}`}, filepath.Join("packages", "a"), filepath.Join("packages", "b"))
+	otherDir := filepath.Join(rootDir, "other")
+	rootDescriptor := filepath.Join(rootDir, packageJsonFileName)
+	memberADescriptor := filepath.Join(rootDir, "packages", "a", packageJsonFileName)
+	memberBDescriptor := filepath.Join(rootDir, "packages", "b", packageJsonFileName)
+	technologiesDetected := map[Technology]map[string][]string{
+		Yarn: {rootDir: {rootDescriptor}, otherDir: {filepath.Join(otherDir, packageJsonFileName)}},
+		Npm:  {filepath.Join(rootDir, "packages", "a"): {memberADescriptor}},
+		Pnpm: {filepath.Join(rootDir, "packages", "b"): {memberBDescriptor}},
+	}
+	removeWorkspacesMembers(technologiesDetected)
+	assert.Equal(t, map[Technology]map[string][]string{
+		Yarn: {rootDir: {rootDescriptor, memberADescriptor, memberBDescriptor}, otherDir: {filepath.Join(otherDir, packageJsonFileName)}},
+	}, technologiesDetected)
+}
+
+func TestDetectGoWorkspace(t *testing.T) {
+	rootDir := t.TempDir()
+	workspaceDir := filepath.Join(rootDir, "app")
+	for _, file := range []struct{ path, content string }{
+		{filepath.Join("app", GoWorkFileName), "go 1.21\n\nuse (\n\t./api\n\t../lib\n)\n"},
+		{filepath.Join("app", "api", "go.mod"), "module example.com/api\n\ngo 1.21\n"},
+		{filepath.Join("lib", "go.mod"), "module example.com/lib\n\ngo 1.21\n"},
+		// Not used by the workspace
+		{filepath.Join("other", "go.mod"), "module example.com/other\n\ngo 1.21\n"},
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(rootDir, file.path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, file.path), []byte(file.content), 0644))
+	}
+
+	members, err := GetGoWorkspaceMembers(workspaceDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(workspaceDir, "api"), filepath.Join(rootDir, "lib")}, members)
+
+	// The workspace modules are audited once, with the workspace
+	technologiesDetected, err := DetectTechnologiesDescriptors(rootDir, true, []string{Go.String()}, map[Technology][]string{}, "")
+	require.NoError(t, err)
+	assert.Len(t, technologiesDetected[Go], 2)
+	assert.ElementsMatch(t, []string{filepath.Join(workspaceDir, "api", "go.mod"), filepath.Join(rootDir, "lib", "go.mod")}, technologiesDetected[Go][workspaceDir])
+	assert.Equal(t, []string{filepath.Join(rootDir, "other", "go.mod")}, technologiesDetected[Go][filepath.Join(rootDir, "other")])
+}
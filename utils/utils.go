@@ -2,9 +2,15 @@ package utils
 
 import (
 	"crypto"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 )
 
 const (
@@ -82,6 +88,22 @@ func toHash(hash crypto.Hash, values ...string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
+// Returns the SHA-256 checksum of the file content, as a hex string
+func GetFileSha256(filePath string) (checksum string, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(file.Close()))
+	}()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // map[string]string to []string (key=value format)
 func ToCommandEnvVars(envVarsMap map[string]string) (converted []string) {
 	converted = make([]string, 0, len(envVarsMap))
@@ -9,17 +9,20 @@ const maxUniqueAppearances = 10
 type DepTreeNode struct {
 	Classifier *string   `json:"classifier"`
 	Types      *[]string `json:"types"`
-	Children   []string  `json:"children"`
+	// The Maven scopes or Gradle configurations of the dependency
+	Configurations *[]string `json:"configurations"`
+	Children       []string  `json:"children"`
 }
 
 func toNodeTypesMap(depMap map[string]DepTreeNode) map[string]*DepTreeNode {
 	mapOfTypes := map[string]*DepTreeNode{}
 	for nodId, value := range depMap {
 		mapOfTypes[nodId] = nil
-		if value.Types != nil || value.Classifier != nil {
+		if value.Types != nil || value.Classifier != nil || value.Configurations != nil {
 			mapOfTypes[nodId] = &DepTreeNode{
-				Classifier: value.Classifier,
-				Types:      value.Types,
+				Classifier:     value.Classifier,
+				Types:          value.Types,
+				Configurations: value.Configurations,
 			}
 		}
 	}
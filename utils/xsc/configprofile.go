@@ -1,11 +1,19 @@
 package xsc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xsc/services"
+	"gopkg.in/yaml.v3"
 )
 
 func GetConfigProfile(serverDetails *config.ServerDetails, profileName string) (*services.ConfigProfile, error) {
@@ -30,3 +38,57 @@ func GetConfigProfile(serverDetails *config.ServerDetails, profileName string) (
 	}
 	return configProfile, err
 }
+
+// Loads a config profile from a JSON or YAML file, with the same schema as the config profiles of the XSC service.
+func LoadConfigProfileFile(filePath string) (*services.ConfigProfile, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to read the config profile file: %s", err.Error())
+	}
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+	case ".yaml", ".yml":
+		// The YAML profile is converted to JSON, so it's decoded by the JSON keys of the schema
+		var yamlProfile any
+		if err = yaml.Unmarshal(content, &yamlProfile); err != nil {
+			return nil, errorutils.CheckErrorf("failed to parse the config profile file %s: %s", filePath, err.Error())
+		}
+		if content, err = json.Marshal(yamlProfile); err != nil {
+			return nil, errorutils.CheckErrorf("failed to parse the config profile file %s: %s", filePath, err.Error())
+		}
+	default:
+		return nil, errorutils.CheckErrorf("unsupported config profile file %s, expected a JSON or YAML file", filePath)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	var configProfile services.ConfigProfile
+	if err = decoder.Decode(&configProfile); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the config profile file %s: %s", filePath, err.Error())
+	}
+	if err = validateConfigProfile(&configProfile); err != nil {
+		return nil, errorutils.CheckErrorf("invalid config profile file %s: %s", filePath, err.Error())
+	}
+	log.Debug(fmt.Sprintf("Loaded the config profile '%s' from %s", configProfile.ProfileName, filePath))
+	return &configProfile, nil
+}
+
+func validateConfigProfile(configProfile *services.ConfigProfile) error {
+	if configProfile.ProfileName == "" {
+		return fmt.Errorf("'profile_name' is required")
+	}
+	if len(configProfile.Modules) == 0 {
+		return fmt.Errorf("at least one module is required in 'modules'")
+	}
+	for i, module := range configProfile.Modules {
+		if module.ModuleName == "" {
+			return fmt.Errorf("'module_name' is required in module %d", i+1)
+		}
+		if module.PathFromRoot == "" {
+			return fmt.Errorf("'path_from_root' is required in module '%s'", module.ModuleName)
+		}
+		if module.ScanConfig.ScanTimeout < 0 {
+			return fmt.Errorf("'scan_timeout' of module '%s' must be a positive number of seconds", module.ModuleName)
+		}
+	}
+	return nil
+}
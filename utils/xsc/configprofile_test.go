@@ -5,7 +5,9 @@ import (
 	"github.com/jfrog/jfrog-cli-security/utils"
 	"github.com/jfrog/jfrog-client-go/xsc/services"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -34,3 +36,65 @@ func TestGetConfigProfile_TooLowXscVersion_FailureExpected(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, configProfile)
 }
+
+func TestLoadConfigProfileFile(t *testing.T) {
+	jsonProfilePath := filepath.Join("..", "..", "tests", "testdata", "other", "configProfile", "configProfileExample.json")
+	profileFileContent, err := os.ReadFile(jsonProfilePath)
+	require.NoError(t, err)
+	var expectedConfigProfile services.ConfigProfile
+	require.NoError(t, json.Unmarshal(profileFileContent, &expectedConfigProfile))
+
+	configProfile, err := LoadConfigProfileFile(jsonProfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, &expectedConfigProfile, configProfile)
+
+	tempDir := t.TempDir()
+	yamlProfilePath := filepath.Join(tempDir, "profile.yaml")
+	require.NoError(t, os.WriteFile(yamlProfilePath, []byte(`profile_name: local-profile
+modules:
+  - module_name: backend
+    path_from_root: backend
+    exclude_paths: ["**/.git/**"]
+    scan_config:
+      scan_timeout: 600
+      enable_sca_scan: true
+      secrets_scanner_config:
+        enable_secrets_scan: true
+`), 0644))
+	configProfile, err = LoadConfigProfileFile(yamlProfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, &services.ConfigProfile{
+		ProfileName: "local-profile",
+		Modules: []services.Module{{
+			ModuleName:   "backend",
+			PathFromRoot: "backend",
+			ExcludePaths: []string{"**/.git/**"},
+			ScanConfig: services.ScanConfig{
+				ScanTimeout:          600,
+				EnableScaScan:        true,
+				SecretsScannerConfig: services.SecretsScannerConfig{EnableSecretsScan: true},
+			},
+		}},
+	}, configProfile)
+
+	invalidProfiles := map[string]string{
+		"unknown.yml":     "profile_name: p\nmodules:\n  - module_name: m\n    path_from_root: .\n    enable_sca_scan: true\n",
+		"no-modules.json": `{"profile_name": "p"}`,
+		"no-path.json":    `{"profile_name": "p", "modules": [{"module_name": "m"}]}`,
+		"no-name.yaml":    "modules:\n  - module_name: m\n    path_from_root: .\n",
+		"profile.txt":     "profile_name: p\n",
+	}
+	expectedErrors := map[string]string{
+		"unknown.yml":     `unknown field "enable_sca_scan"`,
+		"no-modules.json": "at least one module is required",
+		"no-path.json":    "'path_from_root' is required in module 'm'",
+		"no-name.yaml":    "'profile_name' is required",
+		"profile.txt":     "expected a JSON or YAML file",
+	}
+	for fileName, content := range invalidProfiles {
+		profilePath := filepath.Join(tempDir, fileName)
+		require.NoError(t, os.WriteFile(profilePath, []byte(content), 0644))
+		_, err = LoadConfigProfileFile(profilePath)
+		assert.ErrorContains(t, err, expectedErrors[fileName], fileName)
+	}
+}